@@ -16,11 +16,20 @@ import (
 	"io"
 	"github.com/syntelos/go-endian"
 	"math"
-	"math/big"
-	"reflect"
+	"net/netip"
+	"time"
 )
 /*
  * Encoded data set content object.
+ *
+ * The objects produced by encoding, reading and decoding are
+ * new allocations, which refer to neither the values nor the
+ * buffers of the caller, so that an object that is not
+ * modified may be retained and shared among goroutines.  The
+ * exceptions are the subslices of the receiver documented by
+ * <Object#Items>, <Object#Entries>, <Object#RawItem>,
+ * <Object#Bytes>, <Object#Query> and <Index#Get>.  See
+ * <Object#Clone>.
  */
 type Object []byte
 /*
@@ -58,33 +67,110 @@ var MajorMap Major    = Major(5)
 var MajorTagged Major = Major(6)
 var MajorSimple Major = Major(7)
 /*
- * A package external struct type can extend this package by
- * implementing this interface.
+ * Major type seven simple value (0-19, 32-255).  Values 20-23
+ * are "false", "true", "null", and "undefined", and values 24-31
+ * are reserved, failing their encoding with <ErrorInvalidSimple>.
+ * See Section 3.3 [RFC8949].
+ */
+type SimpleValue uint8
+/*
+ * Simple value "undefined" (0xF7), distinct from "null" (0xF6)
+ * which is represented by nil.
+ */
+type Undefined struct{}
+/*
+ * A package external type can extend this package by
+ * implementing this interface with pointer receivers.  A type
+ * registered with <RegisterCoder> is tagged on encode, and
+ * decoded from its tag.
  */
 type Coder interface {
 	/*
-	 * The member type category produces a CBOR Object
-	 * may perform byte encoding by calling
-	 * "cbor.Encode" on a GOPL primitive, i.e. "map".
+	 * Produce the encoding of the receiver, which may be
+	 * performed by calling "cbor.Encode" on a GOPL
+	 * primitive, i.e. "map".
 	 */
-	Encode() (Object)
+	Encode() (Object, error)
 	/*
-	 * The member type category consumes a CBOR binary
-	 * by calling "cbor.Decode" to yield a GOPL
-	 * primitive, i.e. "map".
+	 * Populate the receiver from its encoding, which may be
+	 * performed by calling "cbor.Unmarshal" or
+	 * "Object.Decode".
 	 */
-	Decode(Object) (any)
+	Decode(Object) (error)
+}
+/*
+ * The "break" stop code (0xFF) read in place of a data item,
+ * which terminates an indefinite length data item.
+ */
+type BreakError struct{}
+/*
+ */
+func (this BreakError) Error() (string) {
+	return "CBOR Break"
+}
+/*
+ * Sentinel of <Object#Read> for the "break" stop code, for
+ * comparison by "errors.Is".
+ */
+var Break error = BreakError{}
+/*
+ * Value of <Object#Decode> for the "break" stop code, which is
+ * not a data item.
+ */
+type BreakMarker struct{}
+/*
+ * The end of data within a data item, which is also
+ * "io.ErrUnexpectedEOF" for "errors.Is".
+ */
+type TruncatedError struct{}
+/*
+ */
+func (this TruncatedError) Error() (string) {
+	return "CBOR data item truncated"
 }
 /*
- * Internal CBOR Break
+ * Identify the error with "io.ErrUnexpectedEOF".
  */
-var Break error = errors.New("CBOR Break")
+func (this TruncatedError) Is(target error) (bool) {
+	return io.ErrUnexpectedEOF == target
+}
 /*
- * Validation errors produced by <Object#Read>.
+ * Validation errors produced by <Object#Read>.  The classes of
+ * failure of reading and decoding, for comparison by
+ * "errors.Is", are
+ *
+ *   <ErrorTruncated> end of data within a data item,
+ *   <ErrorReservedHead> reserved additional information, or
+ *       indefinite length of a major type having none,
+ *   <ErrorInvalidUTF8> text string not UTF-8,
+ *   <ErrorUnexpectedBreak> break outside of an indefinite
+ *       length data item,
+ *   <ErrorDepthExceeded> nesting deeper than <DecOptions>
+ *       "MaxDepth",
+ *   <ErrorSizeExceeded> data item larger than <DecOptions>
+ *       "MaxSize", or than the maximum of a frame or message,
+ *   <ErrorDuplicateKey> map key repeated, under <DecOptions>
+ *       "RejectDuplicateKeys".
  */
 const ErrorWrapRead string = "CBOR Data: %w"
+var ErrorTruncated error = TruncatedError{}
+var ErrorReservedHead error = errors.New("Reserved CBOR Head")
+var ErrorInvalidUTF8 error = errors.New("CBOR text string is not UTF-8")
+var ErrorSizeExceeded error = errors.New("CBOR data item size exceeded")
 var ErrorUnrecognizedTag error = errors.New("Unrecognized CBOR Tag")
-var ErrorMissingData error = errors.New("Missing CBOR Data")
+var ErrorInvalidSimple error = errors.New("Invalid CBOR Simple Value")
+var ErrorNotText error = errors.New("CBOR Object is not text")
+var ErrorUnexpectedBreak error = errors.New("Unexpected CBOR Break")
+/*
+ * Deprecated: use <ErrorTruncated>, which is the same error.
+ */
+var ErrorMissingData error = ErrorTruncated
+/*
+ * Deprecated: use <ErrorReservedHead>, which is the same error.
+ */
+var ErrorReservedAdditionalInfo error = ErrorReservedHead
+var ErrorMapIncomplete error = errors.New("CBOR indefinite length map break follows key without value")
+var ErrorChunk error = errors.New("CBOR indefinite length string chunk is not a definite length string of the same type")
 /*
  */
 func (this Object) Write(w io.Writer) (e error){
@@ -92,1051 +178,93 @@ func (this Object) Write(w io.Writer) (e error){
 	return e
 }
 /*
+ * Write the object, as "io.WriterTo", returning the count of
+ * octets written.
+ */
+func (this Object) WriteTo(w io.Writer) (n int64, e error){
+	var z int
+	z, e = w.Write(this)
+	if nil == e && len(this) != z {
+		e = io.ErrShortWrite
+	}
+	return int64(z), e
+}
+/*
+ * Read one data item, as <Object#Read>, returning the count
+ * of octets read from the reader, which is the length of the
+ * data item, or the octets read before a failure.
+ */
+func ReadFrom(r io.Reader) (Object, int64, error){
+	var counted *limitReader = &limitReader{r, math.MaxInt64, 0}
+	var o, e = walk(counted,nil)
+	return o, counted.n, e
+}
+/*
+ * Read one data item.  End of stream before the first byte of
+ * the data item is "io.EOF", while end of stream within the
+ * data item is "io.ErrUnexpectedEOF".
  */
 func (this Object) Read(r io.Reader) (Object, error){
-	var tag []byte = make([]byte,1)
-	var m, n int
-	var e error
-
-	n, e = r.Read(tag)
-	if nil != e {
-		return nil, e
-	} else if 1 != n {
-		return nil, fmt.Errorf("Read (%d) expected (1).",n)
+	return walk(r,nil)
+}
+/*
+ * Validate the well formedness of the (first) data item of the
+ * object, as for <Object#Read>.
+ */
+func (this Object) Valid() (error) {
+	var _, e = this.ItemLen()
+	return e
+}
+/*
+ * Determine the octet count of the (first) data item of the
+ * object, such that octets following it are trailing data.
+ */
+func (this Object) ItemLen() (int, error) {
+	var item, e = this.walk(nil)
+	if io.EOF == e {
+		return 0, ErrorTruncated
+	} else if nil != e {
+		return 0, e
 	} else {
-		var d []byte
-		var t byte = tag[0]
-		var a, b Object
-
-		switch t {
-		case 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17:
-			/* unsigned integer 0x00..0x17 (0..23)
-			 */
-			this = tag
-			return this, nil
-
-		case 0x18:
-			/* unsigned integer (one-byte uint8_t follows)
-			 */
-			this = tag
-			d = make([]byte,1)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 1 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				return this, nil
-			}
-
-		case 0x19:
-			/* unsigned integer (two-byte uint16_t follows)
-			 */
-			this = tag
-			d = make([]byte,2)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 2 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				return this, nil
-			}
-
-		case 0x1A:
-			/* unsigned integer (four-byte uint32_t follows)
-			 */
-			this = tag
-			d = make([]byte,4)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 4 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				return this, nil
-			}
-
-		case 0x1B:
-			/* unsigned integer (eight-byte uint64_t follows)
-			 */
-			this = tag
-			d = make([]byte,8)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 8 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				return this, nil
-			}
-
-		case 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2A, 0x2B, 0x2C, 0x2D, 0x2E, 0x2F, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37:
-			/* negative integer -1-0x00..-1-0x17 (-1..-24)
-			 */
-			this = tag
-			return this, nil
-
-		case 0x38:
-			/* negative integer -1-n (one-byte uint8_t for n follows)
-			 */
-			this = tag
-			d = make([]byte,1)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 1 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z int = int(d[0])
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != n {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(p)
-					return this, nil
-				}	
-			}
-
-		case 0x39:
-			/* negative integer -1-n (two-byte uint16_t for n follows)
-			 */
-			this = tag
-			d = make([]byte,2)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 2 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z int = int(endian.BigEndian.DecodeUint16(d))
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != n {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(p)
-					return this, nil
-				}	
-			}
-
-		case 0x3A:
-			/* negative integer -1-n (four-byte uint32_t for n follows)
-			 */
-			this = tag
-			d = make([]byte,4)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 4 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z uint32 = endian.BigEndian.DecodeUint32(d)
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != uint32(n) {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(p)
-					return this, nil
-				}	
-			}
-
-		case 0x3B:
-			/* negative integer -1-n (eight-byte uint64_t for n follows)
-			 */
-			this = tag
-			d = make([]byte,8)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 8 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z uint64 = endian.BigEndian.DecodeUint64(d)
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != uint64(n) {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(p)
-					return this, nil
-				}	
-			}
-
-		case 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4A, 0x4B, 0x4C, 0x4D, 0x4E, 0x4F, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57:
-			/* byte string (0x00..0x17 bytes follow)
-			 */
-			this = tag
-			m = int(t-0x40)
-			d = make([]byte,m)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if m != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				return this, nil
-			}
-
-		case 0x58:
-			/* byte string (one-byte uint8_t for n, and then n bytes follow)
-			 */
-			this = tag
-			d = make([]byte,1)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 1 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z int = int(d[0])
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != n {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(p)
-					return this, nil
-				}	
-			}
-
-		case 0x59:
-			/* byte string (two-byte uint16_t for n, and then n bytes follow)
-			 */
-			this = tag
-			d = make([]byte,2)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 2 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z int = int(endian.BigEndian.DecodeUint16(d))
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != n {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(p)
-					return this, nil
-				}	
-			}
-
-		case 0x5A:
-			/* byte string (four-byte uint32_t for n, and then n bytes follow)
-			 */
-			this = tag
-			d = make([]byte,4)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 4 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z uint32 = endian.BigEndian.DecodeUint32(d)
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != uint32(n) {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(p)
-					return this, nil
-				}	
-			}
-
-		case 0x5B:
-			/* byte string (eight-byte uint64_t for n, and then n bytes follow)
-			 */
-			this = tag
-			d = make([]byte,8)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 8 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z uint64 = endian.BigEndian.DecodeUint64(d)
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != uint64(n) {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(p)
-					return this, nil
-				}	
-			}
-
-		case 0x5F:
-			/* byte string, byte strings follow, terminated by 'break'
-			 */
-			this = tag
-			for nil == e {
-				a = Object{}
-				a, e = a.Read(r)
-				if nil == e {
-					this = this.Concatenate(a)
-				} else if Break == e {
-					e = nil
-					break
-				} else {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				}
-			}
-			return this, nil
-
-		case 0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6A, 0x6B, 0x6C, 0x6D, 0x6E, 0x6F, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77:
-			/* UTF-8 string (0x00..0x17 bytes follow)
-			 */
-			this = tag
-			m = int(t-0x60)
-			d = make([]byte,m)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if m != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				return this, nil
-			}
-
-		case 0x78:
-			/* UTF-8 string (one-byte uint8_t for n, and then n bytes follow)
-			 */
-			this = tag
-			d = make([]byte,1)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 1 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z int = int(d[0])
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != n {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(p)
-					return this, nil
-				}	
-			}
-
-		case 0x79:
-			/* UTF-8 string (two-byte uint16_t for n, and then n bytes follow)
-			 */
-			this = tag
-			d = make([]byte,2)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 2 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z int = int(endian.BigEndian.DecodeUint16(d))
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != n {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(p)
-					return this, nil
-				}	
-			}
-
-		case 0x7A:
-			/* UTF-8 string (four-byte uint32_t for n, and then n bytes follow)
-			 */
-			this = tag
-			d = make([]byte,4)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 4 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z uint32 = endian.BigEndian.DecodeUint32(d)
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != uint32(n) {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(d)
-					return this, nil
-				}
-			}
-
-		case 0x7B:
-			/* UTF-8 string (eight-byte uint64_t for n, and then n bytes follow)
-			 */
-			this = tag
-			d = make([]byte,8)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 8 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z uint64 = endian.BigEndian.DecodeUint64(d)
-				var p []byte = make([]byte,z)
-				n, e = r.Read(p)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else if z != uint64(n) {
-					return nil, ErrorMissingData
-				} else {
-					this = this.Concatenate(d)
-					return this, nil
-				}	
-			}
-
-		case 0x7F:
-			/* UTF-8 string, UTF-8 strings follow, terminated by 'break'
-			 */
-			this = tag
-			for nil == e {
-				a = Object{}
-				a, e = a.Read(r)
-				if nil == e {
-					this = this.Concatenate(a)
-				} else if Break == e {
-					e = nil
-					break
-				} else {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				}
-			}
-			return this, nil
-
-		case 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8A, 0x8B, 0x8C, 0x8D, 0x8E, 0x8F, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97:
-			/* array (0x00..0x17 data items follow)
-			 */
-			this = tag
-			m = int(t-0x80)
-			for n = 0; n < m; n++ {
-				a = Object{}
-				a, e = a.Read(r)
-				if nil == e {
-					this = this.Concatenate(a)
-				} else {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				}
-			}
-			return this, nil
-
-		case 0x98:
-			/* array (one-byte uint8_t for n, and then n data items follow)
-			 */
-			this = tag
-			d = make([]byte,1)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 1 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var z int = int(d[0])
-				for n = 0; n < z; n++ {
-					a = Object{}
-					a, e = a.Read(r)
-					if nil == e {
-						this = this.Concatenate(a)
-					} else {
-						return nil, fmt.Errorf(ErrorWrapRead,e)
-					}
-				}
-				return this, nil
-			}
-
-		case 0x99:
-			/* array (two-byte uint16_t for n, and then n data items follow)
-			 */
-			this = tag
-			d = make([]byte,2)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 2 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var x, z uint16 = 0, endian.BigEndian.DecodeUint16(d)
-				for ; x < z; x++ {
-					a = Object{}
-					a, e = a.Read(r)
-					if nil == e {
-						this = this.Concatenate(a)
-					} else {
-						return nil, fmt.Errorf(ErrorWrapRead,e)
-					}
-				}
-				return this, nil
-			}
-
-		case 0x9A:
-			/* array (four-byte uint32_t for n, and then n data items follow)
-			 */
-			this = tag
-			d = make([]byte,4)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 4 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var x, z uint32 = 0, endian.BigEndian.DecodeUint32(d)
-				for ; x < z; x++ {
-					a = Object{}
-					a, e = a.Read(r)
-					if nil == e {
-						this = this.Concatenate(a)
-					} else {
-						return nil, fmt.Errorf(ErrorWrapRead,e)
-					}
-				}
-				return this, nil
-			}
-
-		case 0x9B:
-			/* array (eight-byte uint64_t for n, and then n data items follow)
-			 */
-			this = tag
-			d = make([]byte,8)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 8 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var x, z uint64 = 0, endian.BigEndian.DecodeUint64(d)
-				for ; x < z; x++ {
-					a = Object{}
-					a, e = a.Read(r)
-					if nil == e {
-						this = this.Concatenate(a)
-					} else {
-						return nil, fmt.Errorf(ErrorWrapRead,e)
-					}
-				}
-				return this, nil
-			}
-
-		case 0x9F:
-			/* array, data items follow, terminated by 'break'
-			 */
-			this = tag
-			for nil == e {
-				a = Object{}
-				a, e = a.Read(r)
-				if nil == e {
-					this = this.Concatenate(a)
-				} else if Break == e {
-					e = nil
-					break
-				} else {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				}
-			}
-			return this, nil
-
-		case 0xA0, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7, 0xA8, 0xA9, 0xAA, 0xAB, 0xAC, 0xAD, 0xAE, 0xAF, 0xB0, 0xB1, 0xB2, 0xB3, 0xB4, 0xB5, 0xB6, 0xB7:
-			/* map (0x00..0x17 pairs of data items follow)
-			 */
-			this = tag
-			m, n = 0, int(t-0xA0)
-			for ; m < n; m++ {
-				a = Object{}
-				a, e = a.Read(r)
-				if nil != e {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				} else {
-					this = this.Concatenate(a)
-					b = make([]byte,0)
-					b, e = b.Read(r)
-					if nil != e {
-						return nil, fmt.Errorf(ErrorWrapRead,e)
-					} else {
-						this = this.Concatenate(b)
-					}	
-				}
-			}
-			return this, nil
-
-		case 0xB8:
-			/* map (one-byte uint8_t for n, and then n pairs of data items follow)
-			 */
-			this = tag
-			d = make([]byte,1)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 1 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var x, z uint8 = 0, uint8(d[0])
-				for x = 0; x < z; x++ {
-					a = Object{}
-					a, e = a.Read(r)
-					if nil != e {
-						return nil, fmt.Errorf(ErrorWrapRead,e)
-					} else {
-						this = this.Concatenate(a)
-						b = make([]byte,0)
-						b, e = b.Read(r)
-						if nil != e {
-							return nil, fmt.Errorf(ErrorWrapRead,e)
-						} else {
-							this = this.Concatenate(b)
-						}	
-					}
-				}
-				return this, nil
-			}
-
-		case 0xB9:
-			/* map (two-byte uint16_t for n, and then n pairs of data items follow)
-			 */
-			this = tag
-			d = make([]byte,2)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 2 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var x, z uint16 = 0, endian.BigEndian.DecodeUint16(d)
-				for x = 0; x < z; x++ {
-					a = Object{}
-					a, e = a.Read(r)
-					if nil != e {
-						return nil, fmt.Errorf(ErrorWrapRead,e)
-					} else {
-						this = this.Concatenate(a)
-						b = make([]byte,0)
-						b, e = b.Read(r)
-						if nil != e {
-							return nil, fmt.Errorf(ErrorWrapRead,e)
-						} else {
-							this = this.Concatenate(b)
-						}	
-					}
-				}
-				return this, nil
-			}
-
-		case 0xBA:
-			/* map (four-byte uint32_t for n, and then n pairs of data items follow)
-			 */
-			this = tag
-			d = make([]byte,4)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 4 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var x, z uint32 = 0, endian.BigEndian.DecodeUint32(d)
-				for x = 0; x < z; x++ {
-					a = Object{}
-					a, e = a.Read(r)
-					if nil != e {
-						return nil, fmt.Errorf(ErrorWrapRead,e)
-					} else {
-						this = this.Concatenate(a)
-						b = make([]byte,0)
-						b, e = b.Read(r)
-						if nil != e {
-							return nil, fmt.Errorf(ErrorWrapRead,e)
-						} else {
-							this = this.Concatenate(b)
-						}	
-					}
-				}
-				return this, nil
-			}
-
-		case 0xBB:
-			/* map (eight-byte uint64_t for n, and then n pairs of data items follow)
-			 */
-			this = tag
-			d = make([]byte,8)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 8 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				var x, z uint64 = 0, endian.BigEndian.DecodeUint64(d)
-				for x = 0; x < z; x++ {
-					a = Object{}
-					a, e = a.Read(r)
-					if nil != e {
-						return nil, fmt.Errorf(ErrorWrapRead,e)
-					} else {
-						this = this.Concatenate(a)
-						b = make([]byte,0)
-						b, e = b.Read(r)
-						if nil != e {
-							return nil, fmt.Errorf(ErrorWrapRead,e)
-						} else {
-							this = this.Concatenate(b)
-						}	
-					}
-				}
-				return this, nil
-			}
-
-		case 0xBF:
-			/* map, pairs of data items follow, terminated by 'break'
-			 */
-			this = tag
-
-			for nil == e {
-				a = Object{}
-				a, e = a.Read(r)
-				if nil == e {
-					this = this.Concatenate(a)
-
-					b = make([]byte,0)
-					b, e = b.Read(r)
-					if nil == e {
-						this = this.Concatenate(b)
-					} else {
-						return nil, fmt.Errorf(ErrorWrapRead,e)
-					}
-				} else if Break == e {
-					e = nil
-					break
-				} else {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				}
-			}
-			return this, nil
-
-		case 0xC0, 0xC1:
-			/* date/time (data item follows; see Section 3.4.1 and 3.4.2)
-			 */
-			this = tag
-			a = Object{}
-			a, e = a.Read(r)
-			if nil == e {
-				this = this.Concatenate(a)
-				return this, nil
-			} else {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			}
-
-		case 0xC2:
-			/* unsigned bignum (data item 'byte string' follows)
-			 */
-			this = tag
-			a = Object{}
-			a, e = a.Read(r)
-			if nil == e {
-				this = this.Concatenate(a)
-				return this, nil
-			} else {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			}
-
-		case 0xC3:
-			/* negative bignum (data item 'byte string' follows)
-			 */
-			this = tag
-			a = Object{}
-			a, e = a.Read(r)
-			if nil == e {
-				this = this.Concatenate(a)
-				return this, nil
-			} else {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			}
-
-		case 0xC4:
-			/* decimal Fraction (data item 'array' follows; see Section 3.4.4)
-			 */
-			this = tag
-			a = Object{}
-			a, e = a.Read(r)
-			if nil == e {
-				this = this.Concatenate(a)
-				return this, nil
-			} else {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			}
-
-		case 0xC5:
-			/* bigfloat (data item 'array' follows; see Section 3.4.4)
-			 */
-			this = tag
-			a = Object{}
-			a, e = a.Read(r)
-			if nil == e {
-				this = this.Concatenate(a)
-				return this, nil
-			} else {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			}
-
-		case 0xC6, 0xC7, 0xC8, 0xC9, 0xCA, 0xCB, 0xCC, 0xCD, 0xCE, 0xCF, 0xD0, 0xD1, 0xD2, 0xD3, 0xD4:
-			/* (tag)
-			 */
-			this = tag
-			return this, nil
-
-		case 0xD5, 0xD6, 0xD7:
-			/* expected conversion (data item follows; see Section 3.4.5.2)
-			 */
-			this = tag
-			a = Object{}
-			a, e = a.Read(r)
-			if nil == e {
-				this = this.Concatenate(a)
-				return this, nil
-			} else {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			}
-
-		case 0xD8:
-			/* (more tags; 1/2/4/8 bytes of tag number and then a data item follow)
-			 */
-			this = tag
-			a = make([]byte,1)
-			n, e = r.Read(a)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 1 != n {
-				return nil, fmt.Errorf("Data expected (1) found (%d).",n)
-			} else {
-				this = this.Concatenate(a)
-				b = make([]byte,0)
-				b, e = b.Read(r)
-				if nil == e {
-					this = this.Concatenate(b)
-					return this, nil
-				} else {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				}
-			}
-
-		case 0xD9:
-			/* (more tags; 1/2/4/8 bytes of tag number and then a data item follow)
-			 */
-			this = tag
-			a = make([]byte,2)
-			n, e = r.Read(a)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 2 != n {
-				return nil, fmt.Errorf("Data expected (2) found (%d).",n)
-			} else {
-				this = this.Concatenate(a)
-				b = make([]byte,0)
-				b, e = b.Read(r)
-				if nil == e {
-					this = this.Concatenate(b)
-					return this, nil
-				} else {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				}
-			}
-
-		case 0xDA:
-			/* (more tags; 1/2/4/8 bytes of tag number and then a data item follow)
-			 */
-			this = tag
-			a = make([]byte,4)
-			n, e = r.Read(a)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 4 != n {
-				return nil, fmt.Errorf("Data expected (4) found (%d).",n)
-			} else {
-				this = this.Concatenate(a)
-				b = make([]byte,0)
-				b, e = b.Read(r)
-				if nil == e {
-					this = this.Concatenate(b)
-					return this, nil
-				} else {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				}
-			}
-
-		case 0xDB:
-			/* (more tags; 1/2/4/8 bytes of tag number and then a data item follow)
-			 */
-			this = tag
-			a = make([]byte,8)
-			n, e = r.Read(a)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 8 != n {
-				return nil, fmt.Errorf("Data expected (8) found (%d).",n)
-			} else {
-				this = this.Concatenate(a)
-				b = make([]byte,0)
-				b, e = b.Read(r)
-				if nil == e {
-					this = this.Concatenate(b)
-					return this, nil
-				} else {
-					return nil, fmt.Errorf(ErrorWrapRead,e)
-				}
-			}
-
-		case 0xE0, 0xE1, 0xE2, 0xE3, 0xE4, 0xE5, 0xE6, 0xE7, 0xE8, 0xE9, 0xEA, 0xEB, 0xEC, 0xED, 0xEE, 0xEF, 0xF0, 0xF1, 0xF2, 0xF3:
-			/* (simple value)
-			 */
-			this = tag
-			return this, nil
-
-		case 0xF4:
-			/* "false"
-			 */
-			this = tag
-			return this, nil
-
-		case 0xF5:
-			/* "true"
-			 */
-			this = tag
-			return this, nil
-
-		case 0xF6:
-			/* "null"
-			 */
-			this = tag
-			return this, nil
-
-		case 0xF7:
-			/* "undefined"
-			 */
-			this = tag
-			return this, nil
-
-		case 0xF8:
-			/* (simple value, one byte follows)
-			 */
-			this = tag
-			d = make([]byte,1)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 1 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				return this, nil
-			}
-
-		case 0xF9:
-			/* half-precision float (two-byte IEEE 754)
-			 */
-			this = tag
-			d = make([]byte,2)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 2 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				return this, nil
-			}
-
-		case 0xFA:
-			/* single-precision float (four-byte IEEE 754)
-			 */
-			this = tag
-			d = make([]byte,4)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 4 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				return this, nil
-			}
-
-		case 0xFB:
-			/* double-precision float (eight-byte IEEE 754)
-			 */
-			this = tag
-			d = make([]byte,8)
-			n, e = r.Read(d)
-			if nil != e {
-				return nil, fmt.Errorf(ErrorWrapRead,e)
-			} else if 8 != n {
-				return nil, ErrorMissingData
-			} else {
-				this = this.Concatenate(d)
-				return this, nil
-			}
-
-		case 0xFF:
-			/* 'break' stop code"
-			 */
-			this = tag
-			return nil, Break
-
-		default:
-			return nil, ErrorUnrecognizedTag
+		return len(item), nil
+	}
+}
+/*
+ * Read the argument or content of the current data item, for
+ * which end of stream is truncation.
+ */
+func readFull(r io.Reader, d []byte) (error){
+	var _, e = io.ReadFull(r,d)
+	if io.EOF == e || io.ErrUnexpectedEOF == e {
+		return fmt.Errorf(ErrorWrapRead,ErrorTruncated)
+	} else if nil != e {
+		return fmt.Errorf(ErrorWrapRead,e)
+	} else {
+		return nil
+	}
+}
+/*
+ * Represent the (first) data item of the object in diagnostic
+ * notation, for logs.  A malformed object is represented by its
+ * major type and the failure.  See <Object#Diagnostic>.
+ */
+func (this Object) String() string {
+	if 0 == len(this) {
+		return ""
+	} else {
+		var text, e = this.Diagnostic()
+		if nil != e {
+			return fmt.Sprintf("%s (%v)",this.MajorString(),e)
+		} else {
+			return text
 		}
 	}
 }
 /*
+ * Describe the head (initial byte) of the object, as in the
+ * table of Appendix B [RFC8949].
  */
-func (this *Object) String() string {
+func (this Object) HeadString() string {
 	if this.HasTag() {
 		var tag Tag = this.Tag()
 		switch tag {
@@ -1316,50 +444,97 @@ func (this Object) HasText() bool {
 	return (this.HasTag() && MajorText == this.Major())
 }
 /*
- * Resolve text object content.
+ * Resolve text object content, or fail for an object that is
+ * not a text string.
  */
-func (this Object) Text() (s string) {
-	var a any = this.Decode()
-	if nil != a {
-		return a.(string)
-	} else {
-		return ""
+func (this Object) Text() (string, error) {
+	if this.HasText() {
+		var s, ok = this.Decode().(string)
+		if ok {
+			return s, nil
+		}
 	}
+	return "", ErrorNotText
 }
 /*
+ * Resolve text object content, panicking for an object that is
+ * not a text string.
  */
-func copier(dst []byte, dx, dz int, src []byte, sx, sz int) ([]byte) {
-	for dx < dz && sx < sz {
-
-		dst[dx] = src[sx]
-		dx += 1
-		sx += 1
+func (this Object) MustText() (string) {
+	var s, e = this.Text()
+	if nil != e {
+		panic(e)
+	} else {
+		return s
 	}
-	return dst
 }
-func (this Object) Concatenate(b []byte) (Object) {
-	var a []byte = this
-	var a_len int = len(a)
-	if 0 == a_len {
-		this = b
+/*
+ * Objects have identical deterministic encodings, as by
+ * <Object#Compare>.
+ */
+func (this Object) Equal(that Object) (bool) {
+	return (0 == this.Compare(that))
+}
+/*
+ * Bytewise lexicographic order of the deterministic encodings
+ * of the objects, as for map keys in deterministic encoding
+ * (Section 4.2.1 [RFC8949]).  An object that is not exactly one
+ * well formed data item, having trailing data, is compared as it
+ * is.  Returns negative, zero, or positive as this object orders
+ * before, with, or after that object.
+ */
+func (this Object) Compare(that Object) (int) {
+	if bytes.Equal(this,that) {
+		return 0
 	} else {
-		var b_len int = len(b)
-		if 0 == b_len {
-			this = b
-		} else {
-			var c_len int = (a_len+b_len)
-
-			var c []byte = make([]byte,c_len)
-
-			c = copier(c,0,c_len,a,0,a_len)
-
-			c = copier(c,a_len,c_len,b,0,b_len)
-
-			this = c
+		return bytes.Compare(this.ordering(),that.ordering())
+	}
+}
+/*
+ * Define the encoding of the object ordered by <Object#Compare>,
+ * for sorting many objects by their orderings computed once.
+ */
+func (this Object) ordering() (Object) {
+	var z, e = this.ItemLen()
+	if nil == e && len(this) == z {
+		var ordering Object
+		ordering, e = this.Canonical()
+		if nil == e {
+			return ordering
 		}
 	}
 	return this
 }
+/*
+ * Define the object followed by b, in a new allocation shared
+ * with neither.
+ */
+func (this Object) Concatenate(b []byte) (Object) {
+	var c Object = make(Object,0,len(this)+len(b))
+	c = append(c,this...)
+	return append(c,b...)
+}
+/*
+ * Define a copy of the object, in a new allocation, for
+ * retaining an object that refers to a buffer of the caller.
+ * The clone of nil is nil.
+ */
+func (this Object) Clone() (Object) {
+	if nil == this {
+		return nil
+	} else {
+		var c Object = make(Object,len(this))
+		copy(c,this)
+		return c
+	}
+}
+/*
+ * Append the object to dst, returning the extended slice as
+ * for "append".
+ */
+func (this Object) AppendTo(dst []byte) ([]byte) {
+	return append(dst,this...)
+}
 /*
  * Define object as major type tag.
  */
@@ -1462,96 +637,115 @@ func (this Object) Refine(size uint64) (Object) {
 			this[0] = 0xBB
 		}
 		return this
+
+	case MajorTagged:
+		if 0x17 >= size {
+			this[0] = byte(size)+0xC0
+		} else if 0xFF >= size {
+			this[0] = 0xD8
+		} else if 0xFFFF >= size {
+			this[0] = 0xD9
+		} else if 0xFFFFFFFF >= size {
+			this[0] = 0xDA
+		} else {
+			this[0] = 0xDB
+		}
+		return this
+	}
+	return this
+}
+/*
+ * Define object as major type tag refined by argument value,
+ * followed by the argument octets when the argument exceeds
+ * the immediate range (0..23).
+ */
+func define(m Major, arg uint64) (this Object) {
+
+	this = Define(m).Refine(arg)
+
+	switch this.Tag() & 0x1F {
+	case 0x18:
+		this = this.Concatenate([]byte{uint8(arg)})
+	case 0x19:
+		this = this.Concatenate(endian.BigEndian.EncodeUint16(uint16(arg)))
+	case 0x1A:
+		this = this.Concatenate(endian.BigEndian.EncodeUint32(uint32(arg)))
+	case 0x1B:
+		this = this.Concatenate(endian.BigEndian.EncodeUint64(arg))
 	}
 	return this
 }
 /*
- * Define object content.
+ * Define object content.  A nested <Object> or <RawMessage> is
+ * a data item encoded in advance, which is included verbatim,
+ * so that documents are composed of encoded fragments without
+ * decoding them.
  */
 func Encode(a any) (this Object) {
+	return encode(a,&encoding{})
+}
+/*
+ * Determine the octet count of the encoding of the value, for
+ * the allocation of transport buffers.
+ */
+func EncodedLen(a any) (int, error) {
+	var o, e = EncOptions{}.Encode(a)
+	if nil != e {
+		return 0, e
+	} else {
+		return len(o), nil
+	}
+}
+/*
+ * Define object content under encoding options.
+ */
+func encode(a any, state *encoding) (this Object) {
+	var key, cycle = state.enter(a)
+	defer state.exit(key)
+	if cycle {
+		var undefined Object = Object{0xF7}
+		return undefined
+	}
 	if nil != a {
 		switch a.(type) {
 
 		case uint8: // (eq byte)
-			this = Define(MajorUint).Refine(1)
-			var bbo []byte = []byte{a.(byte)}
-
-			this = this.Concatenate(bbo)
+			this = define(MajorUint,uint64(a.(uint8)))
 		case uint16:
-			this = Define(MajorUint).Refine(2)
-			var bbo []byte = endian.BigEndian.EncodeUint16(a.(uint16))
-			this = this.Concatenate(bbo)
+			this = define(MajorUint,uint64(a.(uint16)))
 		case uint32:
-			this = Define(MajorUint).Refine(4)
-			var bbo []byte = endian.BigEndian.EncodeUint32(a.(uint32))
-			this = this.Concatenate(bbo)
+			this = define(MajorUint,uint64(a.(uint32)))
 		case uint64:
-			this = Define(MajorUint).Refine(8)
-			var bbo []byte = endian.BigEndian.EncodeUint64(a.(uint64))
-			this = this.Concatenate(bbo)
+			this = define(MajorUint,a.(uint64))
 
 		case int8:
-			this = Define(MajorSint).Refine(1)
-			var bbo []byte = []byte{a.(byte)}
-			this = this.Concatenate(bbo)
+			this = EncodeInt64(nil,int64(a.(int8)))
 		case int16:
-			this = Define(MajorSint).Refine(2)
-			var bbo []byte = endian.BigEndian.EncodeUint16(a.(uint16))
-			this = this.Concatenate(bbo)
+			this = EncodeInt64(nil,int64(a.(int16)))
 		case int32:
-			this = Define(MajorSint).Refine(4)
-			var bbo []byte = endian.BigEndian.EncodeUint32(a.(uint32))
-			this = this.Concatenate(bbo)
+			this = EncodeInt64(nil,int64(a.(int32)))
 		case int64:
-			this = Define(MajorSint).Refine(8)
-			var bbo []byte = endian.BigEndian.EncodeUint64(a.(uint64))
-			this = this.Concatenate(bbo)
-
+			this = EncodeInt64(nil,a.(int64))
 		case int:
-			var val int = a.(int)
-			var typ reflect.Type = reflect.TypeOf(a)
-			var siz uint64 = uint64(typ.Size())
-			switch siz {
-			case 4:
-				this = Define(MajorSint).Refine(siz)
-				var bbo []byte = endian.BigEndian.EncodeUint32(uint32(val))
-				this = this.Concatenate(bbo)
-			case 8:
-				this = Define(MajorSint).Refine(siz)
-				var bbo []byte = endian.BigEndian.EncodeUint64(uint64(val))
-				this = this.Concatenate(bbo)
-			}
+			this = EncodeInt64(nil,int64(a.(int)))
 
 		case uint:
-			var val uint = a.(uint)
-			var typ reflect.Type = reflect.TypeOf(a)
-			var siz uint64 = uint64(typ.Size())
-			switch siz {
-			case 4:
-				this = Define(MajorUint).Refine(siz)
-				var bbo []byte = endian.BigEndian.EncodeUint32(uint32(val))
-				this = this.Concatenate(bbo)
-			case 8:
-				this = Define(MajorUint).Refine(siz)
-				var bbo []byte = endian.BigEndian.EncodeUint64(uint64(val))
-				this = this.Concatenate(bbo)
-			}
+			this = define(MajorUint,uint64(a.(uint)))
 
 		case uintptr:
-			var val uintptr = a.(uintptr)
-			var typ reflect.Type = reflect.TypeOf(a)
-			var siz uint64 = uint64(typ.Size())
-			switch siz {
-			case 4:
-				this = Define(MajorUint).Refine(siz)
-				var bbo []byte = endian.BigEndian.EncodeUint32(uint32(val))
-				this = this.Concatenate(bbo)
-			case 8:
-				this = Define(MajorUint).Refine(siz)
-				var bbo []byte = endian.BigEndian.EncodeUint64(uint64(val))
-				this = this.Concatenate(bbo)
-			}
+			this = define(MajorUint,uint64(a.(uintptr)))
+
+		case float32:
+			this = encodeFloatShortest(float64(a.(float32)))
+		case float64:
+			this = encodeFloatShortest(a.(float64))
 
+		case bool:
+			if a.(bool) {
+				this = Object{0xF5}
+			} else {
+				this = Object{0xF4}
+			}
 
 		case []byte:
 			this = Define(MajorBlob)
@@ -1618,6 +812,10 @@ func Encode(a any) (this Object) {
 		case []any:
 			this = Define(MajorArray)
 			var ary []any = a.([]any)
+			if nil == ary && state.options.NilContainers {
+				var null Object = Object{0xF6}
+				return null
+			}
 			var arz uint64 = uint64(len(ary))
 			this = this.Refine(arz)
 			switch this.Tag() {
@@ -1639,13 +837,17 @@ func Encode(a any) (this Object) {
 				this = this.Concatenate(arc)
 			}
 			for _, v := range ary {
-				var vo Object = Encode(v)
-				this = this.Concatenate([]byte(vo))
+				var vo Object = encode(v,state)
+				this = vo.AppendTo(this)
 			}
 
 		case map[string]any:
 			this = Define(MajorMap)
 			var mmm map[string]any = a.(map[string]any)
+			if nil == mmm && state.options.NilContainers {
+				var null Object = Object{0xF6}
+				return null
+			}
 			var mmz uint64 = uint64(len(mmm))
 			this = this.Refine(mmz)
 			switch this.Tag() {
@@ -1667,20 +869,105 @@ func Encode(a any) (this Object) {
 				this = this.Concatenate(mmc)
 			}
 			for k, v := range mmm {
-				var ko Object = Encode(k)
-				this = this.Concatenate([]byte(ko))
+				var ko Object = encode(k,state)
+				this = ko.AppendTo(this)
 
-				var vo Object = Encode(v)
-				this = this.Concatenate([]byte(vo))
+				var vo Object = encode(v,state)
+				this = vo.AppendTo(this)
 			}
 
-		case Coder:
-			var coder Coder = a.(Coder)
-			this = coder.Encode()
+		case time.Time:
+			this = encodeTime(a.(time.Time))
 
-		default:
+		case Date:
+			var date Date = a.(Date)
+			this = tagging(TagDate,encode(date.String(),state))
+
+		case Days:
+			var days Days = a.(Days)
+			this = tagging(TagDays,EncodeInt64(nil,int64(days)))
+
+		case netip.Addr:
+			this = encodeAddr(a.(netip.Addr))
+
+		case netip.Prefix:
+			this = encodePrefix(a.(netip.Prefix))
+
+		case Embedded:
+			var embedded []byte = a.(Embedded)
+			this = tagging(TagEmbedded,encode(embedded,state))
+
+		case UUID:
+			var uuid UUID = a.(UUID)
+			this = tagging(TagUUID,encode(uuid[:],state))
+
+		case [16]byte:
+			var uuid [16]byte = a.([16]byte)
+			this = tagging(TagUUID,encode(uuid[:],state))
+
+		case Tagged:
+			var tagged Tagged = a.(Tagged)
+			this = tagging(tagged.Number,encode(tagged.Content,state))
+
+		case Undefined:
 			var undefined Object = Object{0xF7}
 			this = undefined
+
+		case SimpleValue:
+			var val SimpleValue = a.(SimpleValue)
+			if 24 > val {
+				this = Object{0xE0+byte(val)}
+			} else if 32 > val {
+				/* reserved (24-31) have no well formed encoding
+				 */
+				state.fail(ErrorInvalidSimple)
+				this = Object{0xF7}
+			} else {
+				this = Object{0xF8,byte(val)}
+			}
+
+		case Marshaler:
+			this = marshal(a.(Marshaler),state)
+
+		case Coder:
+			this = encodeCoder(a.(Coder),state)
+
+		case OrderedMap:
+			var list OrderedMap = a.(OrderedMap)
+			this = define(MajorMap,uint64(len(list)))
+			for _, entry := range list {
+				this = encode(entry.Key,state).AppendTo(this)
+				this = encode(entry.Value,state).AppendTo(this)
+			}
+
+		case RawMessage:
+			var raw RawMessage = a.(RawMessage)
+			if 0 == len(raw) {
+				this = Object{0xF6}
+			} else {
+				this = encodeRaw(Object(raw),state)
+			}
+
+		case Object:
+			/*
+			 * Pre-encoded data item, as <RawMessage>.
+			 */
+			var item Object = a.(Object)
+			if 0 == len(item) {
+				this = Object{0xF6}
+			} else {
+				this = encodeRaw(item,state)
+			}
+
+		default:
+			var ok bool
+			this, ok = encodeTag(a,state)
+			if !ok {
+				this, ok = encodeBignum(a)
+			}
+			if !ok {
+				this = encodeReflect(a,state)
+			}
 		}
 	} else {
 		var null Object = Object{0xF6}
@@ -1689,843 +976,27 @@ func Encode(a any) (this Object) {
 	return this
 }
 /*
- * Resolve object content.
+ * Resolve object content.  A standard or epoch date and time
+ * (tags 0 and 1) is "time.Time", as <Object#Time>.
  */
-func (this Object) Decode() (a any) {
-	if this.HasTag() {
-		var tag Tag = this.Tag()
-		switch tag {
-		case 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17:
-			return uint8(tag)
-		case 0x18:
-			var cnt uint8 = this[1]
-			var text []byte = this[2:(2+cnt)]
-			switch cnt {
-			case 2:
-				return endian.BigEndian.DecodeUint16(text)
-			case 4:
-				return endian.BigEndian.DecodeUint32(text)
-			case 8:
-				return endian.BigEndian.DecodeUint64(text)
-			default:
-				var value big.Int
-				value.SetBytes(text)
-				return value
-			}
-		case 0x19:
-			var cnt_ary []byte = this[1:3]
-			var cnt uint16 = endian.BigEndian.DecodeUint16(cnt_ary)
-			var text []byte = this[3:(3+cnt)]
-			var value big.Int
-			value.SetBytes(text)
-			return value
-		case 0x1A:
-			var cnt_ary []byte = this[1:5]
-			var cnt uint32 = endian.BigEndian.DecodeUint32(cnt_ary)
-			var text []byte = this[5:(5+cnt)]
-			var value big.Int
-			value.SetBytes(text)
-			return value
-		case 0x1B:
-			var cnt_ary []byte = this[1:9]
-			var cnt uint64 = endian.BigEndian.DecodeUint64(cnt_ary)
-			var text []byte = this[9:(9+cnt)]
-			var value big.Int
-			value.SetBytes(text)
-			return value
-		case 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2A, 0x2B, 0x2C, 0x2D, 0x2E, 0x2F, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37:
-			var delta int = (int(tag)-0x20)
-			return (-1-delta)
-		case 0x38:
-			var cnt uint8 = this[1]
-			var text []byte = this[2:(2+cnt)]
-			switch cnt {
-			case 2:
-				var value uint16 = endian.BigEndian.DecodeUint16(text)
-				return int16(value)
-			case 4:
-				var value uint32 = endian.BigEndian.DecodeUint32(text)
-				return int32(value)
-			case 8:
-				var value uint64 = endian.BigEndian.DecodeUint64(text)
-				return int64(value)
-			default:
-				var value big.Int
-				value.SetBytes(text)
-				return value
-			}
-		case 0x39:
-			var cnt_ary []byte = this[1:3]
-			var cnt uint16 = endian.BigEndian.DecodeUint16(cnt_ary)
-			var text []byte = this[3:(3+cnt)]
-			var value big.Int
-			value.SetBytes(text)
-			return value
-		case 0x3A:
-			var cnt_ary []byte = this[1:5]
-			var cnt uint32 = endian.BigEndian.DecodeUint32(cnt_ary)
-			var text []byte = this[5:(5+cnt)]
-			var value big.Int
-			value.SetBytes(text)
-			return value
-		case 0x3B:
-			var cnt_ary []byte = this[1:9]
-			var cnt uint64 = endian.BigEndian.DecodeUint64(cnt_ary)
-			var text []byte = this[9:(9+cnt)]
-			var value big.Int
-			value.SetBytes(text)
-			return value
-		case 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4A, 0x4B, 0x4C, 0x4D, 0x4E, 0x4F, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57:
-			var m int = int(tag-0x40)
-			var text []byte = this[1:(m+1)]
-			return text
-		case 0x58:
-			var cnt uint8 = this[1]
-			var text []byte = this[2:(2+cnt)]
-			return text
-		case 0x59:
-			var cnt_ary []byte = this[1:3]
-			var cnt uint16 = endian.BigEndian.DecodeUint16(cnt_ary)
-			var text []byte = this[3:(3+cnt)]
-			return text
-		case 0x5A:
-			var cnt_ary []byte = this[1:5]
-			var cnt uint32 = endian.BigEndian.DecodeUint32(cnt_ary)
-			var text []byte = this[5:(5+cnt)]
-			return text
-		case 0x5B:
-			var cnt_ary []byte = this[1:9]
-			var cnt uint64 = endian.BigEndian.DecodeUint64(cnt_ary)
-			var text []byte = this[9:(9+cnt)]
-			return text
-		case 0x5F:
-			var bary Object
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			for true {
-				var o Object = Object{}
-				var e error
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					a = o.Decode()
-					if nil != a {
-						var src []byte = a.([]byte)
-						bary.Concatenate(src)
-					}
-				}
-			}
-			return bary
-		case 0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6A, 0x6B, 0x6C, 0x6D, 0x6E, 0x6F, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77:
-			var m int = int(tag-0x60)
-			var text []byte = this[1:(m+1)]
-			return string(text)
-		case 0x78:
-			var cnt uint8 = this[1]
-			var text []byte = this[2:(2+cnt)]
-			return string(text)
-		case 0x79:
-			var cnt_ary []byte = this[1:3]
-			var cnt uint16 = endian.BigEndian.DecodeUint16(cnt_ary)
-			var text []byte = this[3:(3+cnt)]
-			return string(text)
-		case 0x7A:
-			var cnt_ary []byte = this[1:5]
-			var cnt uint32 = endian.BigEndian.DecodeUint32(cnt_ary)
-			var text []byte = this[5:(5+cnt)]
-			return string(text)
-		case 0x7B:
-			var cnt_ary []byte = this[1:9]
-			var cnt uint64 = endian.BigEndian.DecodeUint64(cnt_ary)
-			var text []byte = this[9:(9+cnt)]
-			return string(text)
-		case 0x7F:
-			var bary Object
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			for true {
-				var o Object = Object{}
-				var e error
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					a = o.Decode()
-					if nil != a {
-						var src []byte = a.([]byte)
-						bary.Concatenate(src)
-					}
-				}
-			}
-			return string(bary)
-		case 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8A, 0x8B, 0x8C, 0x8D, 0x8E, 0x8F, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97:
-			var m, n int = int(tag-0x80), 0
-			var a []any = make([]any,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			var e error
-			for n = 0; n < m; n++ {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					a[n] = o.Decode()
-				}
-			}
-			return a
-		case 0x98:
-			var m, n uint8 = uint8(this[1]), 0
-			var a []any = make([]any,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[2:])
-			var e error
-			for n = 0; n < m; n++ {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					a[n] = o.Decode()
-				}
-			}
-			return a
-		case 0x99:
-			var m, n uint16 = endian.BigEndian.DecodeUint16(this[1:3]), 0
-			var a []any = make([]any,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[3:])
-			var e error
-			for n = 0; n < m; n++ {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					a[n] = o.Decode()
-				}
-			}
-			return a
-		case 0x9A:
-			var m, n uint32 = endian.BigEndian.DecodeUint32(this[1:5]), 0
-			var a []any = make([]any,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[5:])
-			var e error
-			for n = 0; n < m; n++ {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					a[n] = o.Decode()
-				}
-			}
-			return a
-		case 0x9B:
-			var m, n uint64 = endian.BigEndian.DecodeUint64(this[1:9]), 0
-			var a []any = make([]any,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[9:])
-			var e error
-			for n = 0; n < m; n++ {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					a[n] = o.Decode()
-				}
-			}
-			return a
-		case 0x9F:
-			var a []any = make([]any,0)
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			var e error
-			for true {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					a = append(a, o.Decode())
-				}
-			}
-			return a
-		case 0xA0, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7, 0xA8, 0xA9, 0xAA, 0xAB, 0xAC, 0xAD, 0xAE, 0xAF, 0xB0, 0xB1, 0xB2, 0xB3, 0xB4, 0xB5, 0xB6, 0xB7:
-			var m, n int = int(tag-0xA0), 0
-			var o map[string]any = make(map[string]any,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			var e error
-			for n = 0; n < m; n++ {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						a = ko.Decode()
-						if nil != a {
-							var k string = a.(string)
-							o[k] = vo.Decode()
-						}
-					}
-				}
-			}
-			return o
-		case 0xB8:
-			var m, n uint8 = uint8(this[1]), 0
-			var o map[string]any = make(map[string]any,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[2:])
-			var e error
-			for n = 0; n < m; n++ {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						a = ko.Decode()
-						if nil != a {
-							var k string = a.(string)
-							o[k] = vo.Decode()
-						}
-					}
-				}
-			}
-			return o
-		case 0xB9:
-			var m, n uint16 = endian.BigEndian.DecodeUint16(this[1:3]), 0
-			var o map[string]any = make(map[string]any,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[3:])
-			var e error
-			for n = 0; n < m; n++ {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						a = ko.Decode()
-						if nil != a {
-							var k string = a.(string)
-							o[k] = vo.Decode()
-						}
-					}
-				}
-			}
-			return o
-		case 0xBA:
-			var m, n uint32 = endian.BigEndian.DecodeUint32(this[1:5]), 0
-			var o map[string]any = make(map[string]any,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[5:])
-			var e error
-			for n = 0; n < m; n++ {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						a = ko.Decode()
-						if nil != a {
-							var k string = a.(string)
-							o[k] = vo.Decode()
-						}
-					}
-				}
-			}
-			return o
-		case 0xBB:
-			var m, n uint64 = endian.BigEndian.DecodeUint64(this[1:9]), 0
-			var o map[string]any = make(map[string]any,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[9:])
-			var e error
-			for n = 0; n < m; n++ {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						a = ko.Decode()
-						if nil != a {
-							var k string = a.(string)
-							o[k] = vo.Decode()
-						}
-					}
-				}
-			}
-			return o
-		case 0xBF:
-			var o map[string]any = make(map[string]any,1)
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			var e error = nil
-			for nil == e {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						a = ko.Decode()
-						if nil != a {
-							var k string = a.(string)
-							o[k] = vo.Decode()
-						}
-					}
-				}
-			}
-			return o
-		case 0xC0, 0xC1:
-			var a Object = Object{}
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			var e error
-			a, e = a.Read(b)
-			if nil == e {
-				return a.Decode()
-			} 
-		case 0xC2, 0xC3:
-			var a big.Int
-			a.SetBytes(this[1:])
-			return a
-		case 0xC4:
-			// [TODO] rational
-		case 0xC5:
-			// [TODO] bigfloat
-		case 0xC6, 0xC7, 0xC8, 0xC9, 0xCA, 0xCB, 0xCC, 0xCD, 0xCE, 0xCF, 0xD0, 0xD1, 0xD2, 0xD3, 0xD4:
-			// [TODO] tag (content hints)
-		case 0xD5, 0xD6, 0xD7:
-			// [TODO] expected conversion (encoding/base)
-		case 0xD8, 0xD9, 0xDA, 0xDB:
-			// [TODO] tagged data
-		case 0xE0, 0xE1, 0xE2, 0xE3, 0xE4, 0xE5, 0xE6, 0xE7, 0xE8, 0xE9, 0xEA, 0xEB, 0xEC, 0xED, 0xEE, 0xEF, 0xF0, 0xF1, 0xF2, 0xF3:
-			// [TODO] simple value
-		case 0xF4:
-			return false
-		case 0xF5:
-			return true
-		case 0xF6, 0xF7:
-			return nil   // "null" and "undefined"
-		case 0xF8:
-			var a uint8 = this[1]
-			return a
-		case 0xF9:
-			// [TODO] float16
-		case 0xFA:
-			var text []byte = this[1:4]
-			var bits uint32 = endian.BigEndian.DecodeUint32(text)
-			return math.Float32frombits(bits)
-
-		case 0xFB:
-			var text []byte = this[1:8]
-			var bits uint64 = endian.BigEndian.DecodeUint64(text)
-			return math.Float64frombits(bits)
-
-		case 0xFF:
-			return Break
-		}
+func (this Object) Decode() (any) {
+	var decoder walkDecoder
+	var _, e = this.walk(&decoder)
+	if Break == e {
+		return BreakMarker{}
+	} else {
+		return decoder.value
 	}
-	return nil
 }
 /*
  * Represent object structure.
  */
 func (this Object) Describe() (string) {
-	if this.HasTag() {
-		var tag Tag = this.Tag()
-		var desc string = fmt.Sprintf("<tag:%s>",this.MajorString())
-		switch tag { 
-		case 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17:
-		case 0x18:
-			var cnt uint8 = this[1]
-			desc = fmt.Sprintf("%s<uint8>",desc)
-			switch cnt {
-			case 2:
-				desc = fmt.Sprintf("%s<uint16>",desc)
-			case 4:
-				desc = fmt.Sprintf("%s<uint32>",desc)
-			case 8:
-				desc = fmt.Sprintf("%s<uint64>",desc)
-			default:
-				desc = fmt.Sprintf("%s<int[%d]>",desc,cnt)
-			}
-		case 0x19:
-			var cnt_ary []byte = this[1:3]
-			var cnt uint16 = endian.BigEndian.DecodeUint16(cnt_ary)
-			desc = fmt.Sprintf("%s<uint16><int[%d]>",desc,cnt)
-		case 0x1A:
-			var cnt_ary []byte = this[1:5]
-			var cnt uint32 = endian.BigEndian.DecodeUint32(cnt_ary)
-			desc = fmt.Sprintf("%s<uint32><byte[%d]>",desc,cnt)
-		case 0x1B:
-			var cnt_ary []byte = this[1:9]
-			var cnt uint64 = endian.BigEndian.DecodeUint64(cnt_ary)
-			desc = fmt.Sprintf("%s<uint64><byte[%d]>",desc,cnt)
-		case 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2A, 0x2B, 0x2C, 0x2D, 0x2E, 0x2F, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37:
-		case 0x38:
-			var cnt uint8 = this[1]
-			switch cnt {
-			case 2:
-				desc = fmt.Sprintf("%s<uint8><uint16>",desc)
-			case 4:
-				desc = fmt.Sprintf("%s<uint8><uint32>",desc)
-			case 8:
-				desc = fmt.Sprintf("%s<uint8><uint64>",desc)
-			default:
-				desc = fmt.Sprintf("%s<uint8><byte[%d]>",desc,cnt)
-			}
-		case 0x39:
-			var cnt_ary []byte = this[1:3]
-			var cnt uint16 = endian.BigEndian.DecodeUint16(cnt_ary)
-			desc = fmt.Sprintf("%s<uint16><byte[%d]>",desc,cnt)
-		case 0x3A:
-			var cnt_ary []byte = this[1:5]
-			var cnt uint32 = endian.BigEndian.DecodeUint32(cnt_ary)
-			desc = fmt.Sprintf("%s<uint32><byte[%d]>",desc,cnt)
-		case 0x3B:
-			var cnt_ary []byte = this[1:9]
-			var cnt uint64 = endian.BigEndian.DecodeUint64(cnt_ary)
-			desc = fmt.Sprintf("%s<uint64><byte[%d]>",desc,cnt)
-		case 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4A, 0x4B, 0x4C, 0x4D, 0x4E, 0x4F, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57:
-			var m int = int(tag-0x40)
-			desc = fmt.Sprintf("%s<byte[%d]>",desc,m)
-		case 0x58:
-			var cnt uint8 = this[1]
-			desc = fmt.Sprintf("%s<uint8><byte[%d]>",desc,cnt)
-		case 0x59:
-			var cnt_ary []byte = this[1:3]
-			var cnt uint16 = endian.BigEndian.DecodeUint16(cnt_ary)
-			desc = fmt.Sprintf("%s<uint16><byte[%d]>",desc,cnt)
-		case 0x5A:
-			var cnt_ary []byte = this[1:5]
-			var cnt uint32 = endian.BigEndian.DecodeUint32(cnt_ary)
-			desc = fmt.Sprintf("%s<uint32><byte[%d]>",desc,cnt)
-		case 0x5B:
-			var cnt_ary []byte = this[1:9]
-			var cnt uint64 = endian.BigEndian.DecodeUint64(cnt_ary)
-			desc = fmt.Sprintf("%s<uint64><byte[%d]>",desc,cnt)
-		case 0x5F:
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			for true {
-				var o Object = Object{}
-				var e error
-				o, e = o.Read(b)
-				if nil != e {
-					if Break == e {
-						desc = fmt.Sprintf("%s<break>",desc)
-					}
-					return desc
-				} else {
-					desc = fmt.Sprintf("%s%s",desc,o.Describe())
-				}
-			}
-			return desc
-
-		case 0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6A, 0x6B, 0x6C, 0x6D, 0x6E, 0x6F, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77:
-			var m int = int(tag-0x60)
-			desc = fmt.Sprintf("%s<byte[%d]>",desc,m)
-		case 0x78:
-			var cnt uint8 = this[1]
-			desc = fmt.Sprintf("%s<uint8><byte[%d]>",desc,cnt)
-		case 0x79:
-			var cnt_ary []byte = this[1:3]
-			var cnt uint16 = endian.BigEndian.DecodeUint16(cnt_ary)
-			desc = fmt.Sprintf("%s<uint16><byte[%d]>",desc,cnt)
-		case 0x7A:
-			var cnt_ary []byte = this[1:5]
-			var cnt uint32 = endian.BigEndian.DecodeUint32(cnt_ary)
-			desc = fmt.Sprintf("%s<uint32><byte[%d]>",desc,cnt)
-		case 0x7B:
-			var cnt_ary []byte = this[1:9]
-			var cnt uint64 = endian.BigEndian.DecodeUint64(cnt_ary)
-			desc = fmt.Sprintf("%s<uint64><byte[%d]>",desc,cnt)
-		case 0x7F:
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			for true {
-				var o Object = Object{}
-				var e error
-				o, e = o.Read(b)
-				if nil != e {
-					desc = fmt.Sprintf(desc,"<break>")
-					break
-				} else {
-					desc = fmt.Sprintf("%s%s",desc,o.Describe())
-				}
-			}
-			return desc
-		case 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8A, 0x8B, 0x8C, 0x8D, 0x8E, 0x8F, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97:
-			var m, n int = int(tag-0x80), 0
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			var e error
-			for n = 0; n < m; n++ {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					desc = fmt.Sprintf("%s%s",desc,o.Describe())
-				}
-			}
-			return desc
-		case 0x98:
-			var m, n uint8 = uint8(this[1]), 0
-			desc = fmt.Sprintf("%s<uint8[%d]>",desc,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[2:])
-			var e error
-			for n = 0; n < m; n++ {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					desc = fmt.Sprintf("%s%s",desc,o.Describe())
-				}
-			}
-			return desc
-		case 0x99:
-			var m, n uint16 = endian.BigEndian.DecodeUint16(this[1:3]), 0
-			desc = fmt.Sprintf("%s<uint16[%d]>",desc,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[3:])
-			var e error
-			for n = 0; n < m; n++ {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					desc = fmt.Sprintf("%s%s",desc,o.Describe())
-				}
-			}
-			return desc
-		case 0x9A:
-			var m, n uint32 = endian.BigEndian.DecodeUint32(this[1:5]), 0
-			desc = fmt.Sprintf("%s<uint32[%d]>",desc,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[5:])
-			var e error
-			for n = 0; n < m; n++ {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					desc = fmt.Sprintf("%s%s",desc,o.Describe())
-				}
-			}
-			return desc
-		case 0x9B:
-			var m, n uint64 = endian.BigEndian.DecodeUint64(this[1:9]), 0
-			desc = fmt.Sprintf("%s<uint64[%d]>",desc,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[9:])
-			var e error
-			for n = 0; n < m; n++ {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					break
-				} else {
-					desc = fmt.Sprintf("%s%s",desc,o.Describe())
-				}
-			}
-			return desc
-		case 0x9F:
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			var e error
-			for true {
-				var o Object = Object{}
-				o, e = o.Read(b)
-				if nil != e {
-					desc = fmt.Sprintf("%s<break>",desc)
-					break
-				} else {
-					desc = fmt.Sprintf("%s%s",desc,o.Describe())
-				}
-			}
-			return desc
-		case 0xA0, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7, 0xA8, 0xA9, 0xAA, 0xAB, 0xAC, 0xAD, 0xAE, 0xAF, 0xB0, 0xB1, 0xB2, 0xB3, 0xB4, 0xB5, 0xB6, 0xB7:
-			var m, n int = int(tag-0xA0), 0
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			var e error
-			for n = 0; n < m; n++ {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						desc = fmt.Sprintf("%s%s",desc,ko.Describe())
-
-						desc = fmt.Sprintf("%s%s",desc,vo.Describe())
-					}
-				}
-			}
-			return desc
-		case 0xB8:
-			var m, n uint8 = uint8(this[1]), 0
-			desc = fmt.Sprintf("%s<uint8[%d]>",desc,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[2:])
-			var e error
-			for n = 0; n < m; n++ {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						desc = fmt.Sprintf("%s%s",desc,ko.Describe())
-
-						desc = fmt.Sprintf("%s%s",desc,vo.Describe())
-					}
-				}
-			}
-			return desc
-		case 0xB9:
-			var m, n uint16 = endian.BigEndian.DecodeUint16(this[1:3]), 0
-			desc = fmt.Sprintf("%s<uint16[%d]>",desc,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[3:])
-			var e error
-			for n = 0; n < m; n++ {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						desc = fmt.Sprintf("%s%s",desc,ko.Describe())
-
-						desc = fmt.Sprintf("%s%s",desc,vo.Describe())
-					}
-				}
-			}
-			return desc
-		case 0xBA:
-			var m, n uint32 = endian.BigEndian.DecodeUint32(this[1:5]), 0
-			desc = fmt.Sprintf("%s<uint32[%d]>",desc,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[5:])
-			var e error
-			for n = 0; n < m; n++ {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						desc = fmt.Sprintf("%s%s",desc,ko.Describe())
-
-						desc = fmt.Sprintf("%s%s",desc,vo.Describe())
-					}
-				}
-			}
-			return desc
-		case 0xBB:
-			var m, n uint64 = endian.BigEndian.DecodeUint64(this[1:9]), 0
-			desc = fmt.Sprintf("%s<uint64[%d]>",desc,m)
-			var b *bytes.Buffer = bytes.NewBuffer(this[9:])
-			var e error
-			for n = 0; n < m; n++ {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						desc = fmt.Sprintf("%s%s",desc,ko.Describe())
-
-						desc = fmt.Sprintf("%s%s",desc,vo.Describe())
-					}
-				}
-			}
-			return desc
-		case 0xBF:
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			var e error = nil
-			for nil == e {
-				var ko Object = Object{}
-				ko, e = ko.Read(b)
-				if nil != e {
-					if Break == e {
-						desc = fmt.Sprintf("%s<break>",desc)
-					}
-					break
-				} else {
-					var vo Object = Object{}
-					vo, e = vo.Read(b)
-					if nil != e {
-						break
-					} else {
-						desc = fmt.Sprintf("%s%s",desc,ko.Describe())
-
-						desc = fmt.Sprintf("%s%s",desc,vo.Describe())
-					}
-				}
-			}
-			return desc
-		case 0xC0, 0xC1:
-			var a Object = Object{}
-			var b *bytes.Buffer = bytes.NewBuffer(this[1:])
-			var e error
-			a, e = a.Read(b)
-			if nil == e {
-				desc = fmt.Sprintf("%s%s",desc,a.Describe())
-			}
-			return desc
-		case 0xC2, 0xC3:
-		case 0xC4:
-		case 0xC5:
-		case 0xC6, 0xC7, 0xC8, 0xC9, 0xCA, 0xCB, 0xCC, 0xCD, 0xCE, 0xCF, 0xD0, 0xD1, 0xD2, 0xD3, 0xD4:
-		case 0xD5, 0xD6, 0xD7:
-		case 0xD8, 0xD9, 0xDA, 0xDB:
-		case 0xE0, 0xE1, 0xE2, 0xE3, 0xE4, 0xE5, 0xE6, 0xE7, 0xE8, 0xE9, 0xEA, 0xEB, 0xEC, 0xED, 0xEE, 0xEF, 0xF0, 0xF1, 0xF2, 0xF3:
-		case 0xF4:
-		case 0xF5:
-		case 0xF6, 0xF7:
-		case 0xF8:
-		case 0xF9:
-		case 0xFA:
-		case 0xFB:
-		case 0xFF:
-		}
-
-		return desc
+	var structure walkStructure
+	this.walk(&structure)
+	if nil != structure.root {
+		return structure.root.Describe()
 	} else {
 		return ""
-	}	
+	}
 }
@@ -0,0 +1,123 @@
+/*
+ * CBOR RFC8949 Appendix A Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#appendix-A
+ */
+package cbor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+/*
+ * Appendix A examples for which the encoding of the decoded
+ * value is not the deterministic encoding of the example.
+ */
+var TestAppendixEncodeGap map[string]string = map[string]string{
+	"c074323031332d30332d32315432303a30343a30305a": "decoded to time.Time, encoded as epoch date and time (tag 1)",
+	"d818456449455446": "decoded to tag content",
+}
+/*
+ * Appendix A example from "testdata/rfc8949-appendix-a.txt".
+ */
+type TypeTestAppendix struct {
+
+	code []byte
+
+	diagnostic string
+
+	canonical []byte
+}
+/*
+ * Read the Appendix A examples.
+ */
+func readAppendixCorpus(t *testing.T) (list []TypeTestAppendix) {
+	var file, e = os.Open("testdata/rfc8949-appendix-a.txt")
+	if nil != e {
+		t.Fatal(e)
+	}
+	defer file.Close()
+
+	var scanner *bufio.Scanner = bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line string = scanner.Text()
+		if "" == line || strings.HasPrefix(line,"#") {
+			continue
+		}
+		var fields []string = strings.Split(line,"\t")
+		var example TypeTestAppendix
+		example.code, e = hex.DecodeString(fields[0])
+		if nil != e {
+			t.Fatal(e)
+		}
+		example.diagnostic = fields[1]
+		if 2 < len(fields) {
+			example.canonical, e = hex.DecodeString(fields[2])
+			if nil != e {
+				t.Fatal(e)
+			}
+		} else {
+			example.canonical = example.code
+		}
+		list = append(list,example)
+	}
+	return list
+}
+
+func TestAppendixA(t *testing.T){
+	var corpus []TypeTestAppendix = readAppendixCorpus(t)
+	if 81 != len(corpus) {
+		t.Fatalf("Expected 81 examples, found %d.",len(corpus))
+	}
+	for _, example := range corpus {
+		var code string = hex.EncodeToString(example.code)
+		var o Object = Object{}
+		var r *bytes.Reader = bytes.NewReader(example.code)
+		var e error
+
+		o, e = o.Read(r)
+		if nil != e {
+			t.Errorf("[%s] Read: %v",code,e)
+			continue
+		} else if !bytes.Equal(example.code,o) {
+			t.Errorf("[%s] Read: found '%X'.",code,[]byte(o))
+		} else if _, e = o.Read(r); io.EOF != e {
+			t.Errorf("[%s] Read: expected end of stream, found '%v'.",code,e)
+		}
+
+		var diagnostic string
+		diagnostic, e = o.Diagnostic()
+		if nil != e {
+			t.Errorf("[%s] Diagnostic: %v",code,e)
+		} else if example.diagnostic != diagnostic {
+			t.Errorf("[%s] Diagnostic: expected '%s', found '%s'.",code,example.diagnostic,diagnostic)
+		}
+
+		var canonical Object
+		canonical, e = o.Canonical()
+		if nil != e {
+			t.Errorf("[%s] Canonical: %v",code,e)
+		} else if !bytes.Equal(example.canonical,canonical) {
+			t.Errorf("[%s] Canonical: expected '%X', found '%X'.",code,example.canonical,[]byte(canonical))
+		}
+
+		var _, gap = TestAppendixEncodeGap[code]
+		if !gap {
+			canonical, e = Encode(o.Decode()).Canonical()
+			if nil != e {
+				t.Errorf("[%s] Encode: %v",code,e)
+			} else if !bytes.Equal(example.canonical,canonical) {
+				t.Errorf("[%s] Encode: expected '%X', found '%X'.",code,example.canonical,[]byte(canonical))
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * CBOR RFC8949 request scoped decoding
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949
+ * https://pkg.go.dev/arena
+ * https://github.com/golang/go/issues/51317
+ */
+package cbor
+
+/*
+ * Produce the GOPL value of the data item, as <Object#Decode>,
+ * allocating its text strings and the lists of its arrays, maps
+ * and tags from the arena.  Decoded byte strings refer to the
+ * object, as by <Object#Decode>.
+ *
+ * The GOPL maps of decoded maps are allocated from the heap:
+ * the GOPL runtime allocates the buckets of a map itself, and
+ * neither a chunk nor the GOPL "arena" package can hold them.
+ * Their keys and values are allocated from the arena.
+ *
+ * The value must not be used following <Arena#Free>.
+ */
+func (this Object) DecodeArena(arena *Arena) (any) {
+	var decoder walkDecoder = walkDecoder{arena: arena, limit: len(this)}
+	var _, e = this.walk(&decoder)
+	if Break == e {
+		return BreakMarker{}
+	} else {
+		return decoder.value
+	}
+}
+/*
+ * List of nested values of the data item in the arena, having
+ * capacity for the count of nested data items in its head.  The
+ * count is limited by the length of the object decoded, which
+ * is the greatest count of data items well formed.
+ */
+func (this *walkDecoder) list(head Object) ([]any) {
+	var major, ai, arg, _, e = ParseHead(head)
+	if nil != e || 0x1F == ai {
+		return []any{}
+	} else {
+		switch major {
+		case MajorMap:
+			arg *= 2
+		case MajorTagged:
+			arg = 1
+		}
+		if uint64(this.limit) < arg {
+			arg = uint64(this.limit)
+		}
+		return this.arena.list(int(arg))
+	}
+}
@@ -0,0 +1,120 @@
+//go:build !goexperiment.arenas
+
+/*
+ * CBOR RFC8949 decoding arena of chunks
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://pkg.go.dev/sync#Pool
+ * https://pkg.go.dev/unsafe#String
+ */
+package cbor
+
+import (
+	"sync"
+	"unsafe"
+)
+
+const (
+	/*
+	 * Octets of a chunk of text strings.
+	 */
+	arenaChunkOctets int = 0x8000
+	/*
+	 * Values of a chunk of lists.
+	 */
+	arenaChunkValues int = 0x800
+)
+/*
+ * Request scoped allocator of decoded values.  See
+ * <Object#DecodeArena>.  Text strings and lists are carved from
+ * chunks, recycled by <Arena#Free>.  Under "GOEXPERIMENT=arenas"
+ * the chunks are those of the GOPL "arena" package.
+ *
+ * An arena is not safe for concurrent use.
+ */
+type Arena struct {
+
+	octets [][]byte
+
+	values [][]any
+	/*
+	 * Free space of the last chunks.
+	 */
+	octet, value int
+}
+/*
+ * Chunks recycled by <Arena#Free>.
+ */
+var arenaOctets sync.Pool = sync.Pool{New: func() (any) { return make([]byte,arenaChunkOctets) }}
+
+var arenaValues sync.Pool = sync.Pool{New: func() (any) { return make([]any,arenaChunkValues) }}
+/*
+ */
+func NewArena() (*Arena) {
+	return new(Arena)
+}
+/*
+ * Release the chunks of the arena for reuse, invalidating every
+ * value decoded from the arena.  The arena is empty, and may be
+ * used again.
+ */
+func (this *Arena) Free() {
+	for _, chunk := range this.octets {
+		arenaOctets.Put(chunk)
+	}
+	for _, chunk := range this.values {
+		for n := range chunk {
+			chunk[n] = nil
+		}
+		arenaValues.Put(chunk)
+	}
+	*this = Arena{}
+}
+/*
+ * Text string copied into the arena.  A text string longer than
+ * a quarter chunk is allocated from the heap.
+ */
+func (this *Arena) text(data []byte) (string) {
+	var z int = len(data)
+	if 0 == z {
+		return ""
+	} else if (arenaChunkOctets/4) < z {
+		return string(data)
+	} else {
+		if this.octet < z {
+			this.octets = append(this.octets,arenaOctets.Get().([]byte))
+			this.octet = arenaChunkOctets
+		}
+		var chunk []byte = this.octets[len(this.octets)-1]
+		var start int = arenaChunkOctets-this.octet
+		copy(chunk[start:],data)
+		this.octet -= z
+
+		return unsafe.String(&chunk[start],z)
+	}
+}
+/*
+ * Empty list of capacity in the arena.  A list of capacity
+ * greater than a quarter chunk is allocated from the heap, and
+ * appending beyond capacity reallocates from the heap.
+ */
+func (this *Arena) list(capacity int) ([]any) {
+	if 0 == capacity {
+		return []any{}
+	} else if (arenaChunkValues/4) < capacity {
+		return make([]any,0,capacity)
+	} else {
+		if this.value < capacity {
+			this.values = append(this.values,arenaValues.Get().([]any))
+			this.value = arenaChunkValues
+		}
+		var chunk []any = this.values[len(this.values)-1]
+		var start int = arenaChunkValues-this.value
+		this.value -= capacity
+
+		return chunk[start:start:start+capacity]
+	}
+}
@@ -0,0 +1,74 @@
+//go:build goexperiment.arenas
+
+/*
+ * CBOR RFC8949 decoding arena of GOEXPERIMENT=arenas
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://pkg.go.dev/arena
+ * https://pkg.go.dev/unsafe#String
+ */
+package cbor
+
+import (
+	"arena"
+	"unsafe"
+)
+
+/*
+ * Request scoped allocator of decoded values.  See
+ * <Object#DecodeArena>.  Text strings and lists are allocated
+ * from a GOPL "arena", released by <Arena#Free>.
+ *
+ * An arena is not safe for concurrent use.
+ */
+type Arena struct {
+
+	arena *arena.Arena
+}
+/*
+ */
+func NewArena() (*Arena) {
+	return new(Arena)
+}
+/*
+ * Release the memory of the arena, invalidating every value
+ * decoded from the arena.  The arena is empty, and may be used
+ * again.
+ */
+func (this *Arena) Free() {
+	if nil != this.arena {
+		this.arena.Free()
+		this.arena = nil
+	}
+}
+/*
+ */
+func (this *Arena) allocator() (*arena.Arena) {
+	if nil == this.arena {
+		this.arena = arena.NewArena()
+	}
+	return this.arena
+}
+/*
+ * Text string copied into the arena.
+ */
+func (this *Arena) text(data []byte) (string) {
+	var z int = len(data)
+	if 0 == z {
+		return ""
+	} else {
+		var text []byte = arena.MakeSlice[byte](this.allocator(),z,z)
+		copy(text,data)
+
+		return unsafe.String(&text[0],z)
+	}
+}
+/*
+ * Empty list of capacity in the arena.
+ */
+func (this *Arena) list(capacity int) ([]any) {
+	return arena.MakeSlice[any](this.allocator(),0,capacity)
+}
@@ -0,0 +1,74 @@
+/*
+ * CBOR Request Scoped Decoding Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestDecodeArena(t *testing.T){
+	for _, code := range []Object{{0x80}, {0xA0}, {0x9F, 0xFF}, {0xC1, 0x80}, {0x5F, 0x80, 0xFF}} {
+		var expected, _ = Encode(code.Decode()).Canonical()
+		var found, _ = Encode(code.DecodeArena(NewArena())).Canonical()
+		if !bytes.Equal(expected,found) {
+			t.Errorf("[%X] Expected '%X', found '%X'.",[]byte(code),[]byte(expected),[]byte(found))
+		}
+	}
+
+	var arena *Arena = NewArena()
+	defer arena.Free()
+
+	for _, example := range readAppendixCorpus(t) {
+		var code Object = example.code
+		var expected, _ = Encode(code.Decode()).Canonical()
+		var found, _ = Encode(code.DecodeArena(arena)).Canonical()
+		if !bytes.Equal(expected,found) {
+			t.Errorf("[%X] Expected '%X', found '%X'.",[]byte(code),[]byte(expected),[]byte(found))
+		}
+	}
+
+	var records []any
+	for n := 0; n < 100; n++ {
+		records = append(records,map[string]any{"name": fmt.Sprintf("probe-%d",n), "list": []any{"a", "b"}})
+	}
+	var options EncOptions = EncOptionsCoreDet()
+	var code, _ = options.Encode(records)
+	for n := 0; n < 3; n++ {
+		var check, e = options.Encode(code.DecodeArena(arena))
+		if nil != e {
+			t.Fatal(e)
+		} else if !bytes.Equal(code,check) {
+			t.Fatalf("Expected '%X', found '%X'.",[]byte(code),[]byte(check))
+		}
+		arena.Free()
+	}
+
+	var heap float64 = testing.AllocsPerRun(10,func(){
+		code.Decode()
+	})
+	var allocs float64 = testing.AllocsPerRun(10,func(){
+		code.DecodeArena(arena)
+		arena.Free()
+	})
+	if heap <= allocs {
+		t.Errorf("Expected fewer than %v allocations, found %v.",heap,allocs)
+	}
+}
+
+func BenchmarkDecodeArena(b *testing.B){
+	var records []any
+	for n := 0; n < 100; n++ {
+		records = append(records,map[string]any{"name": fmt.Sprintf("probe-%d",n), "list": []any{"a", "b"}})
+	}
+	var code Object = Encode(records)
+	var arena *Arena = NewArena()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		code.DecodeArena(arena)
+		arena.Free()
+	}
+}
@@ -0,0 +1,149 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR RFC8949 Bignums
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.4.3
+ */
+package cbor
+
+import (
+	"math/big"
+)
+/*
+ * Define the encoding of an integer of any magnitude: an
+ * unsigned or negative integer (major types 0 and 1) when its
+ * magnitude is in the range of the argument of the head,
+ * down to -2^64, and otherwise an unsigned or negative bignum
+ * (tags 2 and 3) of the octets of its magnitude without
+ * leading zeros.  See Section 3.4.3 [RFC8949].
+ */
+func encodeBigInt(value *big.Int) (Object) {
+	if 0 <= value.Sign() {
+		if value.IsUint64() {
+			return define(MajorUint,value.Uint64())
+		} else {
+			return tagging(TagUnsignedBignum,bignumContent(value))
+		}
+	} else {
+		/*
+		 * Magnitude of negative integer or bignum, as
+		 * -1-value.
+		 */
+		var magnitude big.Int
+		magnitude.Add(value,big.NewInt(1))
+		magnitude.Neg(&magnitude)
+		if magnitude.IsUint64() {
+			return define(MajorSint,magnitude.Uint64())
+		} else {
+			return tagging(TagNegativeBignum,bignumContent(&magnitude))
+		}
+	}
+}
+/*
+ * Define the encoding of "big.Int" and "*big.Int" values, when
+ * "ok".
+ */
+func encodeBignum(a any) (this Object, ok bool) {
+	switch a.(type) {
+	case big.Int:
+		var value big.Int = a.(big.Int)
+		return encodeBigInt(&value), true
+
+	case *big.Int:
+		var value *big.Int = a.(*big.Int)
+		if nil == value {
+			var null Object = Object{0xF6}
+			return null, true
+		} else {
+			return encodeBigInt(value), true
+		}
+	default:
+		return nil, false
+	}
+}
+/*
+ * Define the byte string of the octets of a non-negative
+ * value.
+ */
+func bignumContent(value *big.Int) (Object) {
+	var data []byte = value.Bytes()
+	return define(MajorBlob,uint64(len(data))).Concatenate(data)
+}
+/*
+ * Resolve the value of an unsigned or negative integer, or of
+ * an unsigned or negative bignum (tags 2 and 3), of any
+ * magnitude.
+ */
+func (this Object) BigInt() (*big.Int, error) {
+	var major, _, arg, _, e = ParseHead(this)
+	if nil != e {
+		return nil, e
+	}
+	switch major {
+	case MajorUint:
+		var value *big.Int = new(big.Int)
+		return value.SetUint64(arg), nil
+
+	case MajorSint:
+		return negativeValue(arg).(*big.Int), nil
+
+	case MajorTagged:
+		var number, content, ok = this.tagged()
+		if ok && (TagUnsignedBignum == number || TagNegativeBignum == number) {
+			var data []byte
+			data, e = content.Bytes()
+			if nil == e {
+				return bignumValue(TagNegativeBignum == number,data).(*big.Int), nil
+			}
+		}
+	}
+	return nil, &UnmarshalTypeError{this.MajorString(),typeBigInt}
+}
+/*
+ * Produce the value of a negative integer argument beyond the
+ * range of "int64", as "*big.Int".
+ */
+func negativeValue(arg uint64) (any) {
+	var value *big.Int = new(big.Int)
+	value.SetUint64(arg)
+	value.Neg(value)
+	value.Sub(value,big.NewInt(1))
+	return value
+}
+/*
+ * Represent the value of a negative integer argument in
+ * decimal.
+ */
+func negativeString(arg uint64) (string) {
+	return negativeValue(arg).(*big.Int).String()
+}
+/*
+ * Produce the value of unsigned (tag 2) or negative (tag 3)
+ * bignum content, as "*big.Int".
+ */
+func bignumValue(negative bool, content []byte) (any) {
+	var value *big.Int = new(big.Int)
+	value.SetBytes(content)
+	if negative {
+		value.Neg(value)
+		value.Sub(value,big.NewInt(1))
+	}
+	return value
+}
+/*
+ * Represent a bignum value in decimal, when "ok".
+ */
+func bignumString(value any) (text string, ok bool) {
+	var number *big.Int
+	number, ok = value.(*big.Int)
+	if ok {
+		return number.String(), true
+	} else {
+		return "", false
+	}
+}
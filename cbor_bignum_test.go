@@ -0,0 +1,84 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR Bignum Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestBigInt(t *testing.T){
+	var cases = []struct{ value string; code string }{
+		{"0", "00"},
+		{"18446744073709551615", "1bffffffffffffffff"},
+		{"18446744073709551616", "c249010000000000000000"},
+		{"-1", "20"},
+		{"-9223372036854775809", "3b8000000000000000"},
+		{"-18446744073709551616", "3bffffffffffffffff"},
+		{"-18446744073709551617", "c349010000000000000000"},
+	}
+	for _, c := range cases {
+		var value *big.Int = new(big.Int)
+		value.SetString(c.value,10)
+
+		var code string = hex.EncodeToString(Encode(value))
+		if c.code != code {
+			t.Errorf("Expected '%s' for %s, found '%s'.",c.code,c.value,code)
+		}
+		code = hex.EncodeToString(Encode(*value))
+		if c.code != code {
+			t.Errorf("Expected '%s' for %s, found '%s'.",c.code,c.value,code)
+		}
+		var o Object
+		o, _ = hex.DecodeString(c.code)
+		var decoded, e = o.BigInt()
+		if nil != e {
+			t.Error(e)
+		} else if 0 != value.Cmp(decoded) {
+			t.Errorf("Expected %s, found %s.",c.value,decoded)
+		}
+	}
+	if "f6" != hex.EncodeToString(Encode((*big.Int)(nil))) {
+		t.Error("Expected null for nil big.Int.")
+	}
+
+	type TypeTestBig struct {
+		Value big.Int `cbor:"v"`
+		Ref *big.Int `cbor:"r"`
+	}
+	var min *big.Int = new(big.Int)
+	min.SetString("-18446744073709551616",10)
+	var in TypeTestBig = TypeTestBig{*min, min}
+	var out TypeTestBig
+	var e error = Unmarshal(Encode(in),&out)
+	if nil != e {
+		t.Fatal(e)
+	} else if 0 != min.Cmp(&out.Value) || nil == out.Ref || 0 != min.Cmp(out.Ref) {
+		t.Errorf("Expected %s, found %s and %s.",min,&out.Value,out.Ref)
+	}
+	var i int64
+	e = Unmarshal(Encode(min),&i)
+	if nil == e {
+		t.Errorf("Expected error for %s into int64, found %d.",min,i)
+	}
+	_, e = Object{0x61,'a'}.BigInt()
+	if nil == e {
+		t.Error("Expected error for text string.")
+	}
+
+	for _, code := range []Object{
+		{0x3B,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF},
+		{0xC3,0x49,0x01,0x00,0x00,0x00,0x00,0x00,0x00,0x00,0x00},
+	} {
+		var expected, _ = code.BigInt()
+		var decoded, ok = code.Decode().(*big.Int)
+		if !ok || 0 != expected.Cmp(decoded) {
+			t.Errorf("Expected '%s' (*big.Int) for '%X', found '%v' (%T).",expected,[]byte(code),code.Decode(),code.Decode())
+		}
+	}
+}
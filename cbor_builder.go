@@ -0,0 +1,143 @@
+/*
+ * CBOR RFC8949 Builder
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.1
+ */
+package cbor
+
+import (
+	"errors"
+)
+/*
+ * Validation errors produced by <Builder>.
+ */
+var ErrorBuilderState error = errors.New("CBOR Builder containers unbalanced")
+var ErrorBuilderKey error = errors.New("CBOR Builder map key without value")
+/*
+ * Incremental construction of one data item, with arrays and
+ * maps in the order of construction unless the encoding
+ * options are deterministic.  Failures are retained
+ * and reported by <Builder#Build>.
+ */
+type Builder struct {
+
+	state encoding
+
+	stack []*builderFrame
+
+	result []Object
+}
+/*
+ * Container under construction.
+ */
+type builderFrame struct {
+
+	major Major
+	/*
+	 * Count of data items, which is twice the count of map
+	 * entries.
+	 */
+	count uint64
+
+	content Object
+}
+/*
+ * Construct builder under encoding options.
+ */
+func NewBuilder(options EncOptions) (*Builder) {
+	return &Builder{state: encoding{options: options}}
+}
+/*
+ * Open map, as the next data item.
+ */
+func (this *Builder) StartMap() (*Builder) {
+	this.stack = append(this.stack,&builderFrame{major: MajorMap})
+	return this
+}
+/*
+ * Open array, as the next data item.
+ */
+func (this *Builder) StartArray() (*Builder) {
+	this.stack = append(this.stack,&builderFrame{major: MajorArray})
+	return this
+}
+/*
+ * Add map entry.
+ */
+func (this *Builder) KV(key, value any) (*Builder) {
+	var z int = len(this.stack)
+	if 0 == z || MajorMap != this.stack[z-1].major {
+		this.state.fail(ErrorBuilderState)
+		return this
+	} else {
+		return this.Add(key).Add(value)
+	}
+}
+/*
+ * Add the encoding of value as the next data item: an array
+ * element, a map key or value, or the data item built.
+ */
+func (this *Builder) Add(v any) (*Builder) {
+	this.append(encode(v,&this.state))
+	return this
+}
+/*
+ * Close the innermost open array or map.
+ */
+func (this *Builder) End() (*Builder) {
+	var z int = len(this.stack)
+	if 0 == z {
+		this.state.fail(ErrorBuilderState)
+		return this
+	} else {
+		var frame *builderFrame = this.stack[z-1]
+		this.stack = this.stack[0:z-1]
+
+		var count uint64 = frame.count
+		if MajorMap == frame.major {
+			if 0 != (count % 2) {
+				this.state.fail(ErrorBuilderKey)
+			}
+			count /= 2
+		}
+		var o Object = define(frame.major,count)
+		if 0 != len(frame.content) {
+			o = o.Concatenate(frame.content)
+		}
+		this.append(o)
+		return this
+	}
+}
+/*
+ * Produce the data item built, or the first failure of
+ * building.
+ */
+func (this *Builder) Build() (Object, error) {
+	if nil != this.state.e {
+		return nil, this.state.e
+
+	} else if 0 != len(this.stack) || 1 != len(this.result) {
+		return nil, ErrorBuilderState
+
+	} else if this.state.options.deterministic() {
+		return this.result[0].deterministic(&this.state.options,0)
+	} else {
+		return this.result[0], nil
+	}
+}
+/*
+ */
+func (this *Builder) append(o Object) {
+	var z int = len(this.stack)
+	if 0 == z {
+		this.result = append(this.result,o)
+	} else {
+		var frame *builderFrame = this.stack[z-1]
+		frame.content = append(frame.content,o...)
+		frame.count += 1
+	}
+}
@@ -0,0 +1,46 @@
+/*
+ * CBOR Builder Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuilder(t *testing.T){
+	var o, e = NewBuilder(EncOptions{}).
+		StartMap().
+		KV("z",uint8(1)).
+		Add("a").StartArray().Add(uint8(2)).Add("b").End().
+		KV("m",uint8(3)).
+		End().Build()
+
+	var expected []byte = []byte{0xA3,0x61,'z',0x01,0x61,'a',0x82,0x02,0x61,'b',0x61,'m',0x03}
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(expected,o) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(o))
+	}
+
+	o, e = NewBuilder(EncOptions{}).StartArray().End().Build()
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0x80},o) {
+		t.Errorf("Expected encoding '80', found '%X'.",[]byte(o))
+	}
+
+	_, e = NewBuilder(EncOptions{}).StartMap().Add("k").End().Build()
+	if ErrorBuilderKey != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorBuilderKey,e)
+	}
+	_, e = NewBuilder(EncOptions{}).StartArray().KV("k","v").End().Build()
+	if ErrorBuilderState != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorBuilderState,e)
+	}
+	_, e = NewBuilder(EncOptions{}).StartArray().Build()
+	if ErrorBuilderState != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorBuilderState,e)
+	}
+}
@@ -0,0 +1,576 @@
+/*
+ * CBOR RFC8949 Deterministic Encoding
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-4.2
+ */
+package cbor
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+/*
+ * Validation errors produced by <Object#Canonical>.
+ */
+var ErrorDuplicateKey error = errors.New("Duplicate CBOR Map Key")
+var ErrorHashUnavailable error = errors.New("Hash function unavailable")
+/*
+ * Map key order of deterministic encoding.
+ */
+type SortMode uint8
+/*
+ * Map entries retain their order.
+ */
+const SortNone SortMode = 0
+/*
+ * Bytewise lexicographic order of key encodings.  See Section
+ * 4.2.1 [RFC8949].
+ */
+const SortBytewise SortMode = 1
+/*
+ * Shorter key encodings order first, then bytewise
+ * lexicographic order.  See Section 4.2.3 [RFC8949].
+ */
+const SortLengthFirst SortMode = 2
+/*
+ * Lower major types order first, then shorter key encodings,
+ * then bytewise lexicographic order.  See Section 6 [CTAP2].
+ */
+const SortCTAP2 SortMode = 3
+/*
+ * Validation errors produced by deterministic encoding.
+ */
+var ErrorTagForbidden error = errors.New("CBOR Tag forbidden")
+var ErrorDepthExceeded error = errors.New("CBOR nesting depth exceeded")
+var ErrorNotDeterministic error = errors.New("CBOR encoding not deterministic")
+var ErrorFloatNonFinite error = errors.New("CBOR float NaN or infinite")
+/*
+ * Define the deterministic encoding of the (first) data item
+ * of the object.  Integer, length, and tag arguments are
+ * shortest form, lengths are definite, floats are the shortest
+ * preserving their value, bignums are without leading zero
+ * octets, or integers when representable as integers, and map
+ * entries are ordered by the bytewise lexicographic order of
+ * their deterministic key encodings.  See Section 4.2.1 and
+ * Section 3.4.3 [RFC8949].
+ */
+func (this Object) Canonical() (Object, error) {
+	var options EncOptions = EncOptionsCoreDet()
+
+	return this.deterministic(&options,0)
+}
+/*
+ * Validate that the (first) data item of the object satisfies
+ * the Core Deterministic Encoding Requirements.  See Section
+ * 4.2.1 [RFC8949].
+ */
+func (this Object) ConformsCoreDet() (error) {
+	var options EncOptions = EncOptionsCoreDet()
+
+	return this.conforms(&options)
+}
+/*
+ * Determine whether map key encoding "a" orders before "b".
+ */
+func (this SortMode) less(a, b Object) (bool) {
+	switch this {
+	case SortBytewise:
+		return (0 > bytes.Compare(a,b))
+	case SortLengthFirst:
+		if len(a) != len(b) {
+			return (len(a) < len(b))
+		} else {
+			return (0 > bytes.Compare(a,b))
+		}
+	case SortCTAP2:
+		if a.Major() != b.Major() {
+			return (a.Major() < b.Major())
+		} else {
+			return SortLengthFirst.less(a,b)
+		}
+	default:
+		return false
+	}
+}
+/*
+ * Define the deterministic encoding of the (first) data item
+ * of the object under the deterministic options "Sort",
+ * "ShortestFloat", "CanonicalNaN", "ForbidNonFinite",
+ * "NumericReduction", "ForbidTags", and "MaxDepth".  The data
+ * item is traversed once by <walker>, appending its
+ * deterministic encoding to one buffer, such that the time of
+ * re-encoding is linear in the length of the data item, apart
+ * from the reordering of map entries.  The counts of nested
+ * data items of indefinite length data items are resolved by a
+ * preceding traversal, when present.
+ */
+func (this Object) deterministic(options *EncOptions, depth int) (Object, error) {
+	var visitor walkDeterministic = walkDeterministic{options: options, depth: depth}
+	var e error = visitor.run(this)
+	if errorIndefinite == e {
+		var counter walkCount
+		this.walk(&counter)
+		visitor = walkDeterministic{options: options, depth: depth, counts: counter.counts}
+		e = visitor.run(this)
+	}
+	if nil != e {
+		return nil, e
+	} else {
+		return visitor.out, nil
+	}
+}
+/*
+ * Validate that the (first) data item of the object is in its
+ * deterministic encoding under the deterministic options.  Map
+ * entries are not reordered, as entries out of order are not
+ * deterministic, such that the time of validation is linear in
+ * the length of the data item.
+ */
+func (this Object) conforms(options *EncOptions) (error) {
+	var visitor walkDeterministic = walkDeterministic{options: options, conform: true}
+	var e error = visitor.run(this)
+	if errorIndefinite == e {
+		return ErrorNotDeterministic
+	} else if nil != e {
+		return e
+	} else if len(visitor.out) > len(this) || !bytes.Equal(visitor.out,this[0:len(visitor.out)]) {
+		return ErrorNotDeterministic
+	} else {
+		return nil
+	}
+}
+/*
+ * Indefinite length array or map requiring the counts of
+ * <walkCount>.
+ */
+var errorIndefinite error = errors.New("CBOR indefinite length requires count")
+/*
+ * Visitor producing the deterministic encoding of a data item.
+ * See <Object#deterministic>.
+ */
+type walkDeterministic struct {
+
+	options *EncOptions
+
+	out Object
+
+	stack []walkDeterministicFrame
+	/*
+	 * Count of the arrays and maps enclosing the data item.
+	 */
+	depth int
+	/*
+	 * Counts of nested data items of the indefinite length
+	 * arrays and maps, in order of entering, or nil.
+	 */
+	counts []uint64
+
+	indefinite int
+	/*
+	 * Fail on map entries out of order, rather than reorder
+	 * them.  See <Object#conforms>.
+	 */
+	conform bool
+	/*
+	 * First failure, not wrapped by the walker.
+	 */
+	e error
+}
+/*
+ * Array, map, tag, or indefinite length string in the
+ * traversal of <walkDeterministic>.
+ */
+type walkDeterministicFrame struct {
+
+	major Major
+	/*
+	 * Offset of the data item in the output.
+	 */
+	start int
+	/*
+	 * Offsets of the nested data items of a map in the
+	 * output.
+	 */
+	entries []int
+	/*
+	 * Content of an indefinite length string, or nil.
+	 */
+	payload []byte
+}
+/*
+ * Traverse the data item, resolving the error of the
+ * traversal.
+ */
+func (this *walkDeterministic) run(o Object) (error) {
+	var _, e = o.walk(this)
+	if nil != this.e {
+		return this.e
+	} else if Break == e {
+		return ErrorUnexpectedBreak
+	} else if io.EOF == e {
+		return ErrorTruncated
+	} else {
+		return e
+	}
+}
+/*
+ * Retain the first failure.
+ */
+func (this *walkDeterministic) fail(e error) (error) {
+	if nil == this.e {
+		this.e = e
+	}
+	return this.e
+}
+/*
+ * Top of stack, or nil.
+ */
+func (this *walkDeterministic) top() (*walkDeterministicFrame) {
+	var top int = len(this.stack)-1
+	if 0 > top {
+		return nil
+	} else {
+		return &this.stack[top]
+	}
+}
+/*
+ * Write the head of an array, map or tag, and open its frame.
+ */
+func (this *walkDeterministic) enter(head Object, depth int) (error) {
+	if nil != this.e {
+		return this.e
+	}
+	var parent *walkDeterministicFrame = this.top()
+	if nil != parent && nil != parent.payload {
+		/*
+		 * Chunk of indefinite length string.
+		 */
+		return nil
+	} else if nil != parent && MajorMap == parent.major {
+		parent.entries = append(parent.entries,len(this.out))
+	}
+	var major, ai, arg, _, e = ParseHead(head)
+	if nil != e {
+		return this.fail(e)
+	}
+	switch major {
+	case MajorArray, MajorMap:
+		if 0 < this.options.MaxDepth && this.options.MaxDepth <= this.depth {
+			return this.fail(ErrorDepthExceeded)
+		} else if 0x1F == ai {
+			if nil == this.counts {
+				return this.fail(errorIndefinite)
+			} else {
+				arg = this.counts[this.indefinite]
+				this.indefinite += 1
+				if MajorMap == major {
+					arg /= 2
+				}
+			}
+		}
+		this.stack = append(this.stack,walkDeterministicFrame{major: major, start: len(this.out)})
+		this.out = AppendHead(this.out,major,arg)
+		this.depth += 1
+
+	case MajorTagged:
+		if this.options.ForbidTags {
+			return this.fail(ErrorTagForbidden)
+		} else {
+			this.stack = append(this.stack,walkDeterministicFrame{major: major, start: len(this.out)})
+			this.out = AppendHead(this.out,major,arg)
+		}
+	case MajorBlob, MajorText:
+		if 0x1F == ai {
+			this.stack = append(this.stack,walkDeterministicFrame{major: major, start: len(this.out), payload: []byte{}})
+		}
+	}
+	return nil
+}
+/*
+ * Append the deterministic encoding of a data item without
+ * nested data items, or close the frame of the data item.
+ */
+func (this *walkDeterministic) exit(item Object, depth int) (error) {
+	if nil != this.e {
+		return this.e
+	}
+	var major, ai, arg, z, e = ParseHead(item)
+	if nil != e {
+		return this.fail(e)
+	}
+	var frame *walkDeterministicFrame = this.top()
+	if nil != frame && nil != frame.payload && 0x1F != ai {
+		/*
+		 * Chunk of indefinite length string.
+		 */
+		frame.payload = append(frame.payload,item[z:]...)
+		return nil
+	}
+	switch major {
+	case MajorUint, MajorSint:
+		this.out = AppendHead(this.out,major,arg)
+
+	case MajorBlob, MajorText:
+		if 0x1F == ai {
+			this.stack = this.stack[0:len(this.stack)-1]
+			this.out = AppendHead(this.out,major,uint64(len(frame.payload)))
+			this.out = append(this.out,frame.payload...)
+		} else {
+			this.out = AppendHead(this.out,major,arg)
+			this.out = append(this.out,item[z:]...)
+		}
+	case MajorArray:
+		this.stack = this.stack[0:len(this.stack)-1]
+		this.depth -= 1
+
+	case MajorMap:
+		this.stack = this.stack[0:len(this.stack)-1]
+		this.depth -= 1
+		return this.fail(this.entries(frame))
+
+	case MajorTagged:
+		this.stack = this.stack[0:len(this.stack)-1]
+		this.bignum(frame.start)
+
+	default:
+		var simple Object
+		simple, e = item.deterministicSimple(this.options)
+		if nil != e {
+			return this.fail(e)
+		} else {
+			this.out = append(this.out,simple...)
+		}
+	}
+	return nil
+}
+/*
+ * Rewrite the bignum at offset in the output in its preferred
+ * serialization, without leading zero octets, and as an
+ * integer when the integer represents its value.  See Section
+ * 3.4.3 [RFC8949].
+ */
+func (this *walkDeterministic) bignum(start int) {
+	var _, _, number, z, _ = ParseHead(this.out[start:])
+	var content Object = this.out[start+z:]
+	if (TagUnsignedBignum != number && TagNegativeBignum != number) || MajorBlob != content.Major() {
+		return
+	}
+	var _, _, _, y, _ = ParseHead(content)
+	var data []byte = content[y:]
+	for 0 < len(data) && 0 == data[0] {
+		data = data[1:]
+	}
+	if 8 >= len(data) {
+		var arg uint64
+		for _, octet := range data {
+			arg = (arg << 8) | uint64(octet)
+		}
+		var major Major = MajorUint
+		if TagNegativeBignum == number {
+			major = MajorSint
+		}
+		this.out = AppendHead(this.out[0:start],major,arg)
+
+	} else if len(data) != (len(content)-y) {
+		var value []byte = append([]byte{},data...)
+		this.out = AppendHead(this.out[0:start+z],MajorBlob,uint64(len(value)))
+		this.out = append(this.out,value...)
+	}
+}
+/*
+ * Order the entries of the map in the output, and reject
+ * duplicate keys.  Entries in order are not moved.
+ */
+func (this *walkDeterministic) entries(frame *walkDeterministicFrame) (error) {
+	var count int = len(frame.entries)/2
+	var end int = len(this.out)
+	var entries [][2]Object = make([][2]Object,count)
+	for n := 0; n < count; n++ {
+		var key, value, next int = frame.entries[2*n], frame.entries[(2*n)+1], end
+		if (n+1) < count {
+			next = frame.entries[2*(n+1)]
+		}
+		entries[n] = [2]Object{this.out[key:value], this.out[value:next]}
+	}
+	var ordered bool = true
+	if SortNone != this.options.Sort {
+		for n := 1; n < count && ordered; n++ {
+			ordered = !this.options.Sort.less(entries[n][0],entries[n-1][0])
+		}
+		if !ordered && this.conform {
+			return ErrorNotDeterministic
+		} else if !ordered {
+			sort.SliceStable(entries,func(i, j int) bool {
+				return this.options.Sort.less(entries[i][0],entries[j][0])
+			})
+		}
+	}
+	for n := 1; n < count; n++ {
+		if bytes.Equal(entries[n-1][0],entries[n][0]) {
+			return ErrorDuplicateKey
+		}
+	}
+	if !ordered {
+		var content []byte = make([]byte,0,end-frame.entries[0])
+		for _, entry := range entries {
+			content = append(content,entry[0]...)
+			content = append(content,entry[1]...)
+		}
+		copy(this.out[frame.entries[0]:end],content)
+	}
+	return nil
+}
+/*
+ * Visitor counting the nested data items of the indefinite
+ * length arrays and maps of a data item, in order of entering.
+ * See <Object#deterministic>.
+ */
+type walkCount struct {
+
+	counts []uint64
+	/*
+	 * Index of the count of each open data item having nested
+	 * data items, or -1 for a data item not counted.
+	 */
+	stack []int
+}
+/*
+ */
+func (this *walkCount) enter(head Object, depth int) (error) {
+	var top int = len(this.stack)-1
+	if 0 <= top && 0 <= this.stack[top] {
+		this.counts[this.stack[top]] += 1
+	}
+	var major, ai, _, _, _ = ParseHead(head)
+	switch major {
+	case MajorArray, MajorMap:
+		if 0x1F == ai {
+			this.counts = append(this.counts,0)
+			this.stack = append(this.stack,len(this.counts)-1)
+		} else {
+			this.stack = append(this.stack,-1)
+		}
+	case MajorTagged:
+		this.stack = append(this.stack,-1)
+	case MajorBlob, MajorText:
+		if 0x1F == ai {
+			this.stack = append(this.stack,-1)
+		}
+	}
+	return nil
+}
+/*
+ */
+func (this *walkCount) exit(item Object, depth int) (error) {
+	switch item.Major() {
+	case MajorArray, MajorMap, MajorTagged:
+		this.stack = this.stack[0:len(this.stack)-1]
+	case MajorBlob, MajorText:
+		if 0x1F == (item[0] & 0x1F) {
+			this.stack = this.stack[0:len(this.stack)-1]
+		}
+	}
+	return nil
+}
+/*
+ * Define the deterministic encoding of a float or simple
+ * value.
+ */
+func (this Object) deterministicSimple(options *EncOptions) (Object, error) {
+	switch this[0] {
+	case 0xF9, 0xFA, 0xFB:
+		var value, ok = this.float()
+		if !ok {
+			return nil, ErrorTruncated
+
+		} else if options.ForbidNonFinite && (math.IsNaN(value) || math.IsInf(value,0)) {
+			return nil, ErrorFloatNonFinite
+
+		} else if options.CanonicalNaN && math.IsNaN(value) {
+			return Object{0xF9,0x7E,0x00}, nil
+
+		} else if options.NumericReduction && value == math.Trunc(value) && -0x1p64 <= value && 0x1p64 > value {
+			if 0 <= value {
+				return define(MajorUint,uint64(value)), nil
+			} else if -0x1p64 == value {
+				return define(MajorSint,math.MaxUint64), nil
+			} else {
+				return define(MajorSint,uint64(-value)-1), nil
+			}
+		} else if options.ShortestFloat {
+			return encodeFloatShortest(value), nil
+		} else {
+			return this, nil
+		}
+	default:
+		return this, nil
+	}
+}
+/*
+ * Resolve byte or text string content, concatenating the
+ * chunks of an indefinite length string.
+ */
+func (this Object) payload() (content []byte, e error) {
+	var arg uint64
+	var z int
+	arg, z, e = this.head()
+	if nil != e {
+		return nil, e
+	} else if 0x1F == (this[0] & 0x1F) {
+		var r *bytes.Reader = bytes.NewReader(this[z:])
+		content = []byte{}
+		for {
+			var chunk Object = Object{}
+			chunk, e = chunk.Read(r)
+			if Break == e {
+				return content, nil
+			} else if nil != e {
+				return nil, fmt.Errorf(ErrorWrapRead,e)
+			} else if chunk.Major() != this.Major() || 0x1F == (chunk[0] & 0x1F) {
+				return nil, ErrorChunk
+			} else {
+				var data []byte
+				data, e = chunk.payload()
+				if nil != e {
+					return nil, e
+				} else {
+					content = append(content,data...)
+				}
+			}
+		}
+	} else if (uint64(len(this)) - uint64(z)) < arg {
+		return nil, ErrorTruncated
+	} else {
+		return this[z:(uint64(z)+arg)], nil
+	}
+}
+/*
+ * Produce the digest of the deterministic encoding of the
+ * object, so that logically equal data items produce equal
+ * digests independent of their original encoding.
+ */
+func (this Object) Digest(h crypto.Hash) ([]byte, error) {
+	if h.Available() {
+		var canonical Object
+		var e error
+		canonical, e = this.Canonical()
+		if nil != e {
+			return nil, e
+		} else {
+			var hash = h.New()
+			hash.Write(canonical)
+			return hash.Sum(nil), nil
+		}
+	} else {
+		return nil, ErrorHashUnavailable
+	}
+}
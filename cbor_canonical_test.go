@@ -0,0 +1,152 @@
+/*
+ * CBOR Deterministic Encoding Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha256"
+	"testing"
+)
+
+func TestCanonical(t *testing.T){
+	var vector [][2][]byte = [][2][]byte{
+		/* non-preferred unsigned integer */
+		{{0x19,0x00,0x0A},{0x0A}},
+		/* indefinite array */
+		{{0x9F,0x01,0x02,0xFF},{0x82,0x01,0x02}},
+		/* indefinite byte string */
+		{{0x5F,0x42,0x01,0x02,0x41,0x03,0xFF},{0x43,0x01,0x02,0x03}},
+		/* unordered map */
+		{{0xA2,0x61,0x62,0x02,0x61,0x61,0x01},{0xA2,0x61,0x61,0x01,0x61,0x62,0x02}},
+		/* double precision one */
+		{{0xFB,0x3F,0xF0,0x00,0x00,0x00,0x00,0x00,0x00},{0xF9,0x3C,0x00}},
+		/* single precision 100000.0 */
+		{{0xFB,0x40,0xF8,0x6A,0x00,0x00,0x00,0x00,0x00},{0xFA,0x47,0xC3,0x50,0x00}},
+		/* half precision subnormal */
+		{{0xFA,0x33,0x80,0x00,0x00},{0xF9,0x00,0x01}},
+		/* tag argument */
+		{{0xD9,0x00,0x01,0x19,0x00,0x01},{0xC1,0x01}},
+	}
+	for _, v := range vector {
+		var c, e = Object(v[0]).Canonical()
+		if nil != e {
+			t.Errorf("Canonical '%X': %v",v[0],e)
+		} else if !bytes.Equal(v[1],c) {
+			t.Errorf("Expected canonical '%X', found '%X'.",v[1],[]byte(c))
+		}
+	}
+
+	var _, e = Object{0xA2,0x61,0x61,0x01,0x61,0x61,0x02}.Canonical()
+	if ErrorDuplicateKey != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorDuplicateKey,e)
+	}
+}
+
+func TestDigest(t *testing.T){
+	var a, b []byte
+	var e error
+	a, e = Object{0xBF,0x61,0x62,0x9F,0x02,0xFF,0x61,0x61,0x18,0x01,0xFF}.Digest(crypto.SHA256)
+	if nil != e {
+		t.Fatal(e)
+	}
+	b, e = Object{0xA2,0x61,0x61,0x01,0x61,0x62,0x81,0x02}.Digest(crypto.SHA256)
+	if nil != e {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(a,b) {
+		t.Errorf("Expected equal digests, found '%X' and '%X'.",a,b)
+	}
+
+	_, e = Object{0x01}.Digest(crypto.Hash(0))
+	if ErrorHashUnavailable != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorHashUnavailable,e)
+	}
+}
+
+func TestCoreDet(t *testing.T){
+	var table OrderedMap = OrderedMap{
+		{"aa", uint8(1)},
+		{uint16(1000), uint8(2)},
+		{"b", uint8(3)},
+	}
+	var expected []byte = []byte{0xA3,0x19,0x03,0xE8,0x02,0x61,'b',0x03,0x62,'a','a',0x01}
+	var code, e = EncOptionsCoreDet().Encode(table)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(expected,code) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(code))
+	} else if e = code.ConformsCoreDet(); nil != e {
+		t.Errorf("Expected conformance, found '%v'.",e)
+	}
+
+	var long Object = Object{0x18,0x01}
+	e = long.ConformsCoreDet()
+	if ErrorNotDeterministic != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorNotDeterministic,e)
+	}
+	var unsorted Object = Object{0xA2,0x61,'b',0x01,0x61,'a',0x02}
+	e = unsorted.ConformsCoreDet()
+	if ErrorNotDeterministic != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorNotDeterministic,e)
+	}
+
+	var big9 []byte = []byte{0xC2,0x49,0x01,0x00,0x00,0x00,0x00,0x00,0x00,0x00,0x00}
+	for _, bignum := range []struct{ code, expected []byte }{
+		{[]byte{0xC2,0x42,0x00,0x01}, []byte{0x01}},
+		{[]byte{0xC3,0x42,0x01,0x00}, []byte{0x39,0x01,0x00}},
+		{[]byte{0xC2,0x40}, []byte{0x00}},
+		{[]byte{0xC2,0x48,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF}, []byte{0x1B,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF}},
+		{[]byte{0xC2,0x4A,0x00,0x01,0x00,0x00,0x00,0x00,0x00,0x00,0x00,0x00}, big9},
+		{[]byte{0x81,0xC2,0x5F,0x41,0x00,0x41,0x02,0xFF}, []byte{0x81,0x02}},
+	} {
+		var found, e = Object(bignum.code).Canonical()
+		if nil != e {
+			t.Errorf("[%X] %v",bignum.code,e)
+		} else if !bytes.Equal(bignum.expected,found) {
+			t.Errorf("[%X] Expected '%X', found '%X'.",bignum.code,bignum.expected,[]byte(found))
+		}
+		e = Object(bignum.code).ConformsCoreDet()
+		if ErrorNotDeterministic != e {
+			t.Errorf("[%X] Expected '%v', found '%v'.",bignum.code,ErrorNotDeterministic,e)
+		}
+	}
+	e = Object(big9).ConformsCoreDet()
+	if nil != e {
+		t.Errorf("Expected conformance, found '%v'.",e)
+	}
+}
+
+func TestCanonicalDepth(t *testing.T){
+	var depth int = 40000
+	var definite []byte = append(bytes.Repeat([]byte{0x81},depth),0x00)
+	var indefinite []byte = append(append(bytes.Repeat([]byte{0x9F},depth),0x00),bytes.Repeat([]byte{0xFF},depth)...)
+
+	var canonical, e = Object(indefinite).Canonical()
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(definite,canonical) {
+		t.Errorf("Expected '%X...', found '%X...'.",definite[0:8],[]byte(canonical[0:8]))
+	}
+	e = Object(definite).ConformsCoreDet()
+	if nil != e {
+		t.Errorf("Expected conformance, found '%v'.",e)
+	}
+	e = Object(indefinite).ConformsCoreDet()
+	if ErrorNotDeterministic != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorNotDeterministic,e)
+	}
+	/*
+	 * {"b": [{"b": 1, "a": 2}], "a": 0}
+	 */
+	var code []byte = []byte{0xA2, 0x61,'b', 0x81, 0xA2, 0x61,'b', 0x01, 0x61,'a', 0x02, 0x61,'a', 0x00}
+	var expected []byte = []byte{0xA2, 0x61,'a', 0x00, 0x61,'b', 0x81, 0xA2, 0x61,'a', 0x02, 0x61,'b', 0x01}
+	canonical, e = Object(code).Canonical()
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(expected,canonical) {
+		t.Errorf("Expected '%X', found '%X'.",expected,[]byte(canonical))
+	}
+}
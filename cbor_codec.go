@@ -0,0 +1,113 @@
+/*
+ * CBOR RFC8949 GOPL codec
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949
+ */
+package cbor
+
+import (
+	"errors"
+	"fmt"
+)
+/*
+ * Encoded data item passed through the reflection encoder and
+ * decoder verbatim, permitting struct fields to defer decoding
+ * of sub-documents, or to preserve them for re-encoding.  The
+ * encoder fails on content that is not one well formed data
+ * item.
+ */
+type RawMessage []byte
+/*
+ * A type implementing its own encoding to a single data item.
+ */
+type Marshaler interface {
+
+	MarshalCBOR() ([]byte, error)
+}
+/*
+ * A type implementing its own decoding of a single data item
+ * into the receiver, which must be a pointer.  The data item
+ * is a copy, which the implementation may retain.
+ */
+type Unmarshaler interface {
+
+	UnmarshalCBOR([]byte) (error)
+}
+/*
+ * Validation errors produced by <Unmarshal>.  The reflection
+ * codec is excluded from builds having tag "cbor_tiny", which
+ * encode and decode the values of <Object#Decode>, <Object>,
+ * <RawMessage>, <Marshaler>, <Unmarshaler> and <Coder>, but
+ * not structs, slices and maps of other types.
+ */
+var ErrorReflection error = errors.New("CBOR reflection codec excluded by build tag cbor_tiny")
+var ErrorDecodeTarget error = errors.New("CBOR Decode target is not a non-nil pointer")
+const ErrorWrapDecodeType string = "CBOR Decode %s into %s"
+/*
+ */
+func (this *UnmarshalTypeError) Error() (string) {
+	return fmt.Sprintf(ErrorWrapDecodeType,this.Value,this.Type)
+}
+/*
+ * Store object content into the value referenced by pointer,
+ * following the structure of the referenced type.
+ */
+func Unmarshal(data []byte, v any) (error) {
+	return DecOptions{}.Unmarshal(data,v)
+}
+/*
+ * Decode the (first) data item of the object into the value
+ * referenced by pointer, i.e. "*struct", "*map[string]string"
+ * or "*[]float64", by the coercion rules of <Unmarshal>.
+ *
+ * Integers and floats convert to any numeric type that
+ * represents their value exactly, so that an unsigned integer
+ * decodes into "int" when in range, and a float decodes into
+ * an integer type when integral.  Content out of range of the
+ * target type is an <UnmarshalTypeError>.
+ *
+ * Arrays decode into slices and arrays of equal length, and
+ * maps decode into maps and structs.  Null decodes into the
+ * zero value of pointers, interfaces, slices and maps.
+ *
+ * Byte strings decode into byte slices and text strings into
+ * strings.  The conversion of byte strings into strings and of
+ * text strings into byte slices is the option "StringBytes" of
+ * <DecOptions>.
+ */
+func (this Object) DecodeInto(v any) (error) {
+	return Unmarshal(this,v)
+}
+/*
+ * Encode <RawMessage> verbatim when it is one well formed data
+ * item, retaining its failure as undefined.
+ */
+func encodeRaw(raw Object, state *encoding) (Object) {
+	var z, e = raw.ItemLen()
+	if nil != e {
+		state.fail(e)
+		return Object{0xF7}
+	} else if len(raw) != z {
+		state.fail(ErrorTrailingData)
+		return Object{0xF7}
+	} else {
+		return raw.Clone()
+	}
+}
+/*
+ * Encode <Marshaler> when its product is one well formed data
+ * item, as <RawMessage>, retaining its failure as undefined.
+ */
+func marshal(marshaler Marshaler, state *encoding) (Object) {
+	var code, e = marshaler.MarshalCBOR()
+	if nil != e {
+		state.fail(e)
+		return Object{0xF7}
+	} else {
+		return encodeRaw(Object(code),state)
+	}
+}
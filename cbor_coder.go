@@ -0,0 +1,95 @@
+/*
+ * CBOR RFC8949 Coder Registry
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.4
+ * https://www.iana.org/assignments/cbor-tags/cbor-tags.xhtml
+ */
+package cbor
+
+import (
+	"errors"
+	"sync"
+)
+/*
+ * Validation errors produced by <RegisterCoder>.
+ */
+var ErrorCoderFactory error = errors.New("CBOR Coder factory must produce a non-nil pointer")
+var ErrorCoderRegistered error = errors.New("CBOR Coder tag or type registered")
+/*
+ * Validation errors produced by <RegisterType> and
+ * <RegisterTypeKey>.
+ */
+var ErrorTypeFactory error = errors.New("CBOR Type factory must produce a non-nil pointer")
+var ErrorTypeRegistered error = errors.New("CBOR Type tag, key or type registered")
+/*
+ * Coder factories by tag number.  The tag numbers of Coder
+ * types are registered with <RegisterCoder>, which depends on
+ * reflection.
+ */
+var coderLock sync.RWMutex
+var coderFactory map[uint64]func() (Coder) = map[uint64]func() (Coder){}
+/*
+ * Encode <Coder>, tagged when registered, when its product is
+ * one well formed data item, retaining its failure as
+ * undefined.
+ */
+func encodeCoder(coder Coder, state *encoding) (Object) {
+	var code, e = coder.Encode()
+	if nil != e {
+		state.fail(e)
+		return Object{0xF7}
+	}
+	var z int
+	z, e = code.ItemLen()
+	if nil != e {
+		state.fail(e)
+		return Object{0xF7}
+	} else if len(code) != z {
+		state.fail(ErrorTrailingData)
+		return Object{0xF7}
+	} else {
+		var number, ok = coderTag(coder)
+		if ok {
+			return tagging(number,code)
+		} else {
+			return code
+		}
+	}
+}
+/*
+ * Resolve the content of object for Coder, removing its
+ * registered tag.
+ */
+func coderContent(coder Coder, o Object) (Object) {
+	var number, ok = coderTag(coder)
+	if ok {
+		var tag, content, tagged = o.tagged()
+		if tagged && tag == number {
+			return content
+		}
+	}
+	return o
+}
+/*
+ * Decode tagged content with the Coder registered for tag
+ * number, when "ok".  Content that the Coder rejects is not
+ * "ok".
+ */
+func decodeCoder(number uint64, content Object) (coder Coder, ok bool) {
+	coderLock.RLock()
+	var factory func() (Coder)
+	factory, ok = coderFactory[number]
+	coderLock.RUnlock()
+
+	if ok {
+		coder = factory()
+		if nil != coder.Decode(content) {
+			return nil, false
+		}
+	}
+	return coder, ok
+}
@@ -0,0 +1,57 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR RFC8949 Coder Registry
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.4
+ */
+package cbor
+
+import (
+	"reflect"
+)
+/*
+ * Tag numbers by Coder (pointer) type.
+ */
+var coderNumber map[reflect.Type]uint64 = map[reflect.Type]uint64{}
+/*
+ * Register the Coder produced by factory under tag number, so
+ * that its type is tagged on encode and decoded from its tag.
+ * The factory produces a new pointer to a zero value of the
+ * type for each decode.
+ */
+func RegisterCoder(number uint64, factory func() (Coder)) (error) {
+	var prototype Coder = factory()
+	var v reflect.Value = reflect.ValueOf(prototype)
+	if nil == prototype || reflect.Pointer != v.Kind() || v.IsNil() {
+		return ErrorCoderFactory
+	} else {
+		coderLock.Lock()
+		defer coderLock.Unlock()
+
+		var _, tagged = tagRegistry[number]
+		var _, registered = coderFactory[number]
+		var _, typed = coderNumber[v.Type()]
+		if tagged || registered || typed {
+			return ErrorCoderRegistered
+		} else {
+			coderFactory[number] = factory
+			coderNumber[v.Type()] = number
+			return nil
+		}
+	}
+}
+/*
+ * Resolve the tag number of registered Coder type.
+ */
+func coderTag(coder Coder) (number uint64, ok bool) {
+	coderLock.RLock()
+	defer coderLock.RUnlock()
+
+	number, ok = coderNumber[reflect.TypeOf(coder)]
+	return number, ok
+}
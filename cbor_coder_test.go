@@ -0,0 +1,103 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR Coder Registry Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type TypeTestPoint3 struct {
+
+	X, Y, Z uint16
+}
+
+func (this *TypeTestPoint3) Encode() (Object, error) {
+	return Encode([]any{this.X,this.Y,this.Z}), nil
+}
+
+func (this *TypeTestPoint3) Decode(code Object) (error) {
+	var list []uint16
+	var e error = Unmarshal(code,&list)
+	if nil != e {
+		return e
+	} else if 3 != len(list) {
+		return ErrorMissingData
+	} else {
+		this.X, this.Y, this.Z = list[0], list[1], list[2]
+		return nil
+	}
+}
+
+type TypeTestCoded Object
+
+func (this *TypeTestCoded) Encode() (Object, error) {
+	return Object(*this), nil
+}
+
+func (this *TypeTestCoded) Decode(code Object) (error) {
+	*this = TypeTestCoded(code.Clone())
+	return nil
+}
+
+func TestCoderRegistry(t *testing.T){
+	var e error = RegisterCoder(40100,func() (Coder) { return &TypeTestPoint3{} })
+	if nil != e {
+		t.Fatal(e)
+	}
+	e = RegisterCoder(40100,func() (Coder) { return &TypeTestPoint3{} })
+	if ErrorCoderRegistered != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorCoderRegistered,e)
+	}
+	e = RegisterCoder(TagURI,func() (Coder) { return nil })
+	if ErrorCoderFactory != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorCoderFactory,e)
+	}
+
+	var point TypeTestPoint3 = TypeTestPoint3{1,2,3}
+	var expected []byte = []byte{0xD9,0x9C,0xA4,0x83,0x01,0x02,0x03}
+	for _, value := range []any{point,&point} {
+		var code Object = Encode(value)
+		if !bytes.Equal(expected,code) {
+			t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(code))
+		}
+	}
+
+	var code Object = Encode([]any{point,"other"})
+	var list []any = code.Decode().([]any)
+	var decoded, ok = list[0].(*TypeTestPoint3)
+	if !ok {
+		t.Errorf("Expected '*TypeTestPoint3', found '%T'.",list[0])
+	} else if point != *decoded {
+		t.Errorf("Expected '%v', found '%v'.",point,*decoded)
+	}
+
+	var rejected any = Encode(Tagged{40100,"point"}).Decode()
+	if !reflect.DeepEqual(Tagged{40100,"point"},rejected) {
+		t.Errorf("Expected '%v', found '%v'.",Tagged{40100,"point"},rejected)
+	}
+
+	var target TypeTestPoint3
+	e = Unmarshal(Encode(point),&target)
+	if nil != e {
+		t.Fatal(e)
+	} else if point != target {
+		t.Errorf("Expected '%v', found '%v'.",point,target)
+	}
+
+	for _, c := range []struct{ coded TypeTestCoded; expected error }{
+		{TypeTestCoded{0x62,'a'}, ErrorTruncated},
+		{TypeTestCoded{0x01,0x02}, ErrorTrailingData},
+	} {
+		_, e = EncOptions{}.Encode(&c.coded)
+		if !errors.Is(e,c.expected) {
+			t.Errorf("Expected '%v', found '%v'.",c.expected,e)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+/*
+ * CBOR RFC8949 Compressed Data Items
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.4.5.1
+ * https://tools.ietf.org/html/rfc8949#section-9.2
+ * https://tools.ietf.org/html/rfc1950
+ * https://tools.ietf.org/html/rfc1951
+ * https://tools.ietf.org/html/rfc1952
+ */
+package cbor
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"sync"
+)
+/*
+ * Tag number of a compressed data item, having the content
+ * "[algorithm, compressed]" of the text string name of the
+ * compression algorithm and the byte string compression of the
+ * encoded data item.  The tag number is a convention of this
+ * package, in the first come first served range, and is not
+ * registered with IANA, so that compressed data items are
+ * exchanged between peers agreeing on it, and decompressed only
+ * under the option "Decompress" of <DecOptions>.
+ */
+const TagCompressed uint64 = 56502
+/*
+ * Errors of <EncodeCompressed> and of the decoding of
+ * compressed data items.
+ */
+var ErrorCompression error = errors.New("CBOR compression algorithm not registered")
+var ErrorCompressed error = errors.New("CBOR compressed data item malformed")
+/*
+ * Compression algorithm functions.
+ */
+type compression struct {
+
+	compress func([]byte) ([]byte, error)
+
+	decompress func(io.Reader) (io.ReadCloser, error)
+}
+/*
+ * Compression algorithms by name, having "zlib", "gzip" and
+ * "deflate" by default.
+ */
+var compressionLock sync.RWMutex
+var compressions map[string]compression = map[string]compression{
+	"zlib": {compressZlib, decompressZlib},
+	"gzip": {compressGzip, decompressGzip},
+	"deflate": {compressDeflate, decompressDeflate},
+}
+/*
+ * Register the compression algorithm functions under name, for
+ * <EncodeCompressed> and the decoding of compressed data items.
+ * The decompressing reader is read to the maximum of the option
+ * "Decompress" of <DecOptions>, and no further.  An algorithm
+ * outside of the standard library, as "zstd", is registered by
+ * its user.  A name registered again is replaced.
+ */
+func RegisterCompression(name string, compress func([]byte) ([]byte, error), decompress func(io.Reader) (io.ReadCloser, error)) (error) {
+	if nil == compress || nil == decompress {
+		return ErrorCompression
+	} else {
+		compressionLock.Lock()
+		defer compressionLock.Unlock()
+
+		compressions[name] = compression{compress, decompress}
+		return nil
+	}
+}
+/*
+ * Resolve compression algorithm by name.
+ */
+func compressionNamed(name string) (compression, error) {
+	compressionLock.RLock()
+	defer compressionLock.RUnlock()
+
+	var algo, ok = compressions[name]
+	if ok {
+		return algo, nil
+	} else {
+		return algo, ErrorCompression
+	}
+}
+/*
+ * Define the compressed data item of the value, tagged with
+ * <TagCompressed>, which <DecOptions#Unmarshal> decompresses
+ * under the option "Decompress".
+ */
+func EncodeCompressed(v any, algo string) (Object, error) {
+	var c, e = compressionNamed(algo)
+	if nil != e {
+		return nil, e
+	}
+	var compressed []byte
+	compressed, e = c.compress(Encode(v))
+	if nil != e {
+		return nil, e
+	} else {
+		return tagging(TagCompressed,Encode([]any{algo,compressed})), nil
+	}
+}
+/*
+ * Resolve the encoded data item of the content of a compressed
+ * data item, decompressing at most limit octets, and validating
+ * the data item as exactly one data item under policy.
+ */
+func decompress(content Object, limit int, policy walkVisitor) (Object, error) {
+	var list, e = content.Items()
+	if nil != e || 2 != len(list) {
+		return nil, ErrorCompressed
+	}
+	var name string
+	name, e = list[0].Text()
+	if nil != e {
+		return nil, ErrorCompressed
+	}
+	var compressed []byte
+	compressed, e = list[1].Bytes()
+	if nil != e {
+		return nil, ErrorCompressed
+	}
+	var c compression
+	c, e = compressionNamed(name)
+	if nil != e {
+		return nil, e
+	}
+	var r io.ReadCloser
+	r, e = c.decompress(bytes.NewReader(compressed))
+	if nil != e {
+		return nil, e
+	}
+	defer r.Close()
+	var code []byte
+	code, e = io.ReadAll(io.LimitReader(r,int64(limit)+1))
+	if nil != e {
+		return nil, e
+	} else if limit < len(code) {
+		return nil, ErrorSizeExceeded
+	}
+	var item Object
+	item, e = Object(code).walk(policy)
+	if io.EOF == e {
+		return nil, ErrorTruncated
+	} else if nil != e {
+		return nil, e
+	} else if len(item) != len(code) {
+		return nil, ErrorTrailingData
+	} else {
+		return item, nil
+	}
+}
+/*
+ * Resolve the object, or the encoded data item of the object
+ * when it is a compressed data item, under the option
+ * "Decompress".  The data items decompressed by one call share
+ * its maximum, including the compressed data items decompressed
+ * from compressed data items.
+ */
+func (this *decoding) uncompressed(o Object) (Object, error) {
+	var number, content, ok = o.tagged()
+	if ok && TagCompressed == number && 0 < this.options.Decompress {
+		var limit int = (this.options.Decompress-this.decompressed)
+		if 0 < this.options.MaxSize && this.options.MaxSize < limit {
+			limit = this.options.MaxSize
+		}
+		var policy walkVisitor
+		if this.options.restricted() {
+			policy = &walkPolicy{options: &this.options}
+		}
+		var code, e = decompress(content,limit,policy)
+		if nil != e {
+			return nil, e
+		} else {
+			this.decompressed += len(code)
+			return this.uncompressed(code)
+		}
+	} else {
+		return o, nil
+	}
+}
+/*
+ */
+func compressZlib(p []byte) ([]byte, error) {
+	var b bytes.Buffer
+	var w *zlib.Writer = zlib.NewWriter(&b)
+	return compressWith(&b,w,p)
+}
+/*
+ */
+func decompressZlib(r io.Reader) (io.ReadCloser, error) {
+	return zlib.NewReader(r)
+}
+/*
+ */
+func compressGzip(p []byte) ([]byte, error) {
+	var b bytes.Buffer
+	var w *gzip.Writer = gzip.NewWriter(&b)
+	return compressWith(&b,w,p)
+}
+/*
+ */
+func decompressGzip(r io.Reader) (io.ReadCloser, error) {
+	var z, e = gzip.NewReader(r)
+	if nil != e {
+		return nil, e
+	} else {
+		return z, nil
+	}
+}
+/*
+ */
+func compressDeflate(p []byte) ([]byte, error) {
+	var b bytes.Buffer
+	var w, e = flate.NewWriter(&b,flate.DefaultCompression)
+	if nil != e {
+		return nil, e
+	} else {
+		return compressWith(&b,w,p)
+	}
+}
+/*
+ */
+func decompressDeflate(r io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(r), nil
+}
+/*
+ * Write octets to the compressing writer, and produce the
+ * compressed octets of its buffer.
+ */
+func compressWith(b *bytes.Buffer, w io.WriteCloser, p []byte) ([]byte, error) {
+	var _, e = w.Write(p)
+	if nil == e {
+		e = w.Close()
+	}
+	if nil != e {
+		return nil, e
+	} else {
+		return b.Bytes(), nil
+	}
+}
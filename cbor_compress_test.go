@@ -0,0 +1,142 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR Compressed Data Items Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type TypeTestTelemetry struct {
+
+	Device string `cbor:"device"`
+
+	Samples []uint16 `cbor:"samples"`
+}
+
+func TestCompressed(t *testing.T){
+	var telemetry TypeTestTelemetry = TypeTestTelemetry{"sensor",make([]uint16,1000)}
+	for n := range telemetry.Samples {
+		telemetry.Samples[n] = uint16(n % 10)
+	}
+	var plain Object = Encode(telemetry)
+	var options DecOptions = DecOptions{Decompress: len(plain)}
+
+	for _, algo := range []string{"zlib","gzip","deflate"} {
+		var code, e = EncodeCompressed(telemetry,algo)
+		if nil != e {
+			t.Fatal(e)
+		} else if len(code) >= len(plain) {
+			t.Errorf("[%s] Expected compression of %d octets, found %d.",algo,len(plain),len(code))
+		}
+		var number, _, _ = code.tagged()
+		if TagCompressed != number {
+			t.Errorf("[%s] Expected tag %d, found %d.",algo,TagCompressed,number)
+		}
+
+		var check TypeTestTelemetry
+		e = options.Unmarshal(code,&check)
+		if nil != e {
+			t.Fatal(e)
+		} else if !reflect.DeepEqual(telemetry,check) {
+			t.Errorf("[%s] Expected '%v', found '%v'.",algo,telemetry.Device,check.Device)
+		}
+		var value any
+		e = options.Unmarshal(code,&value)
+		if nil != e {
+			t.Fatal(e)
+		} else if !reflect.DeepEqual(plain.Decode(),value) {
+			t.Errorf("[%s] Expected decoding of uncompressed data item.",algo)
+		}
+		/*
+		 * Decompression is the option of the caller, and its
+		 * maximum.
+		 */
+		if _, ok := code.Decode().(Tagged); !ok {
+			t.Errorf("[%s] Expected 'Tagged', found '%T'.",algo,code.Decode())
+		}
+		e = Unmarshal(code,&check)
+		if nil == e {
+			t.Errorf("[%s] Expected error, found success.",algo)
+		}
+		e = DecOptions{Decompress: len(plain)-1}.Unmarshal(code,&check)
+		if ErrorSizeExceeded != e {
+			t.Errorf("[%s] Expected '%v', found '%v'.",algo,ErrorSizeExceeded,e)
+		}
+	}
+
+	var _, e = EncodeCompressed(telemetry,"unknown")
+	if ErrorCompression != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorCompression,e)
+	}
+	e = RegisterCompression("prefix",func(p []byte) ([]byte, error) {
+		return append([]byte{'x'},p...), nil
+	},func(r io.Reader) (io.ReadCloser, error) {
+		var _, e = io.ReadFull(r,make([]byte,1))
+		return io.NopCloser(r), e
+	})
+	if nil != e {
+		t.Fatal(e)
+	}
+	var code Object
+	code, e = EncodeCompressed("text","prefix")
+	if nil != e {
+		t.Fatal(e)
+	}
+	var text string
+	e = options.Unmarshal(code,&text)
+	if nil != e {
+		t.Fatal(e)
+	} else if "text" != text {
+		t.Errorf("Expected 'text', found '%v'.",text)
+	}
+	/*
+	 * Nested compression sharing the maximum, and data other
+	 * than one data item.
+	 */
+	var inner Object = code
+	code, e = EncodeCompressed(RawMessage(inner),"prefix")
+	if nil != e {
+		t.Fatal(e)
+	}
+	var nested DecOptions = DecOptions{Decompress: len(inner)+len(Encode("text"))-1}
+	e = nested.Unmarshal(code,&text)
+	if ErrorSizeExceeded != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorSizeExceeded,e)
+	}
+	nested.Decompress = 100
+	e = nested.Unmarshal(code,&text)
+	if nil != e || "text" != text {
+		t.Errorf("Expected 'text', found '%v' (%v).",text,e)
+	}
+	code = tagging(TagCompressed,Encode([]any{"prefix",[]byte{'x',0x01,0x02}}))
+	var value any
+	e = options.Unmarshal(code,&value)
+	if ErrorTrailingData != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorTrailingData,e)
+	}
+	code = tagging(TagCompressed,Encode([]any{"prefix",[]byte{'x',0xC1,0xF7}}))
+	e = DecOptions{Decompress: 100, AllowedTags: []uint64{TagCompressed}}.Unmarshal(code,&value)
+	if !errors.Is(e,ErrorTagForbidden) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorTagForbidden,e)
+	}
+
+	var malformed Object = tagging(TagCompressed,Encode([]any{"zlib",[]byte{1,2,3}}))
+	var check TypeTestTelemetry
+	e = options.Unmarshal(malformed,&check)
+	if nil == e {
+		t.Error("Expected error, found success.")
+	}
+	var raw RawMessage
+	e = Unmarshal(malformed,&raw)
+	if nil != e || !bytes.Equal(malformed,raw) {
+		t.Errorf("Expected raw compressed data item, found '%X' (%v).",[]byte(raw),e)
+	}
+}
@@ -0,0 +1,442 @@
+/*
+ * CBOR RFC8949 Appendix F Corpus Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#appendix-F
+ * https://tools.ietf.org/html/rfc8949#appendix-F.1
+ */
+package cbor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+/*
+ * Corpus of well-formed and not well-formed data items, as
+ * written by <TestGenerateCorpus>.
+ */
+const TestCorpusFile string = "testdata/rfc8949-appendix-f.txt"
+const TestCorpusWellFormed string = "well-formed"
+const TestCorpusMalformed string = "not well-formed"
+/*
+ * Write the corpus file by <TestGenerateCorpus>, as
+ *
+ *     go test -run TestGenerateCorpus -update
+ */
+var TestCorpusUpdate *bool = flag.Bool("update",false,"write "+TestCorpusFile)
+/*
+ * Examples of data items that are not well-formed, from
+ * Appendix F.1.
+ */
+var TestAppendixFMalformed []string = []string{
+	/*
+	 * End of input in a head.
+	 */
+	"18", "19", "1a", "1b", "1901", "1a0102", "1b01020304050607",
+	"38", "58", "78", "98", "9a01ff00", "b8", "d8", "f8", "f900",
+	"fa0000", "fb000000",
+	/*
+	 * Definite length strings with short data.
+	 */
+	"41", "61", "5affffffff00", "5bffffffffffffffff010203",
+	"7affffffff00", "7b7fffffffffffffff010203",
+	/*
+	 * Definite length maps and arrays not closed with enough
+	 * items.
+	 */
+	"81", "818181818181818181", "8200", "a1", "a20102", "a100",
+	"a2000000",
+	/*
+	 * Tag number not followed by tag content.
+	 */
+	"c0",
+	/*
+	 * Indefinite length strings not closed by a break.
+	 */
+	"5f4100", "7f6100",
+	/*
+	 * Indefinite length maps and arrays not closed by a break.
+	 */
+	"9f", "9f0102", "bf", "bf01020102", "819f", "9f8000",
+	"9f9f9f9f9fffffffff", "9f819f819f9fffffff",
+	/*
+	 * Reserved additional information values.
+	 */
+	"1c", "1d", "1e", "3c", "3d", "3e", "5c", "5d", "5e", "7c",
+	"7d", "7e", "9c", "9d", "9e", "bc", "bd", "be", "dc", "dd",
+	"de", "fc", "fd", "fe",
+	/*
+	 * Reserved two-byte encodings of simple values.
+	 */
+	"f800", "f801", "f818", "f81f",
+	/*
+	 * Indefinite length string chunks not of the correct type.
+	 */
+	"5f00ff", "5f21ff", "5f6100ff", "5f80ff", "5fa0ff", "5fc000ff",
+	"5fe0ff", "7f4100ff",
+	/*
+	 * Indefinite length string chunks not definite length.
+	 */
+	"5f5f4100ffff", "7f7f6100ffff",
+	/*
+	 * Break on its own outside of an indefinite length item.
+	 */
+	"ff",
+	/*
+	 * Break in a definite length array or map or a tag.
+	 */
+	"81ff", "8200ff", "a1ff", "a1ff00", "a100ff", "a20000ff",
+	"9f81ff", "9f829f819f9fffffffff",
+	/*
+	 * Break in an indefinite length map in a value position.
+	 */
+	"bf00ff", "bf000000ff",
+	/*
+	 * Major types 0, 1 and 6 with additional information 31.
+	 */
+	"1f", "3f", "df",
+}
+/*
+ * Head of the major type with the additional information and
+ * argument, encoding the argument in the width of the
+ * additional information, including widths longer than
+ * preferred.
+ */
+func corpusHead(major byte, ai byte, arg uint64) ([]byte) {
+	var head []byte = []byte{(major << 5) | ai}
+	var width int
+	switch ai {
+	case 24:
+		width = 1
+	case 25:
+		width = 2
+	case 26:
+		width = 4
+	case 27:
+		width = 8
+	}
+	for n := width-1; 0 <= n; n-- {
+		head = append(head,byte(arg >> (8*n)))
+	}
+	return head
+}
+/*
+ * Heads of the major type for the argument, in each width
+ * able to represent it.
+ */
+func corpusHeads(major byte, arg uint64) (list [][]byte) {
+	if 24 > arg {
+		list = append(list,corpusHead(major,byte(arg),0))
+	}
+	for ai, limit := range []uint64{0xFF, 0xFFFF, 0xFFFFFFFF, 0xFFFFFFFFFFFFFFFF} {
+		if arg <= limit {
+			list = append(list,corpusHead(major,byte(24+ai),arg))
+		}
+	}
+	return list
+}
+/*
+ * Well-formed data items of the grammar of Appendix F, by
+ * major type, argument width, definite and indefinite length,
+ * and nesting, with the Appendix A examples.
+ */
+func corpusWellFormed(t *testing.T) (list [][]byte) {
+	var arguments []uint64 = []uint64{0, 1, 23, 24, 255, 256, 65535, 65536, 0xFFFFFFFF, 0x100000000, 0xFFFFFFFFFFFFFFFF}
+	/*
+	 * Integers.
+	 */
+	for _, major := range []byte{0, 1} {
+		for _, arg := range arguments {
+			list = append(list,corpusHeads(major,arg)...)
+		}
+	}
+	/*
+	 * Definite and indefinite length strings.
+	 */
+	for _, major := range []byte{2, 3} {
+		for _, size := range []uint64{0, 1, 23, 24, 256} {
+			for _, head := range corpusHeads(major,size) {
+				list = append(list,append(head,bytes.Repeat([]byte{'a'},int(size))...))
+			}
+		}
+		var chunk []byte = []byte{(major << 5) | 2, 'a', 'b'}
+		var empty []byte = []byte{major << 5}
+		list = append(list,[]byte{(major << 5) | 31, 0xFF})
+		list = append(list,concatenate([]byte{(major << 5) | 31},chunk,[]byte{0xFF}))
+		list = append(list,concatenate([]byte{(major << 5) | 31},empty,chunk,empty,[]byte{0xFF}))
+		list = append(list,concatenate([]byte{(major << 5) | 31},corpusHead(major,25,2),[]byte("cd"),[]byte{0xFF}))
+	}
+	list = append(list,concatenate([]byte{0x65},[]byte("ü水")))
+	list = append(list,concatenate([]byte{0x69},[]byte("ü水\U00010151")))
+	/*
+	 * Definite and indefinite length arrays and maps, nested.
+	 */
+	var items [][]byte = [][]byte{{0x00}, {0x20}, {0x41, 0x00}, {0x61, 'a'}, {0xF6}, {0xF9, 0x3C, 0x00}, {0xC1, 0x00}}
+	for _, item := range items {
+		for _, head := range corpusHeads(4,1) {
+			list = append(list,concatenate(head,item))
+		}
+		for _, head := range corpusHeads(5,1) {
+			list = append(list,concatenate(head,item,item))
+		}
+		list = append(list,concatenate([]byte{0x9F},item,[]byte{0xFF}))
+		list = append(list,concatenate([]byte{0xBF},item,item,[]byte{0xFF}))
+	}
+	list = append(list,[]byte{0x80}, []byte{0xA0}, []byte{0x9F, 0xFF}, []byte{0xBF, 0xFF})
+	list = append(list,concatenate([]byte{0x98, 0x19},bytes.Repeat([]byte{0x01},25)))
+	list = append(list,[]byte{0x82, 0x9F, 0x80, 0xBF, 0xFF, 0xFF, 0xA1, 0x9F, 0xFF, 0x5F, 0xFF})
+	list = append(list,[]byte{0xBF, 0x61, 'a', 0x9F, 0x9F, 0x9F, 0xFF, 0xFF, 0xFF, 0x80, 0xA1, 0x00, 0xA0, 0xFF})
+	list = append(list,[]byte{0x9F, 0x81, 0x9F, 0x81, 0x9F, 0x9F, 0xFF, 0xFF, 0xFF, 0xFF})
+	/*
+	 * Tags, nested.
+	 */
+	for _, arg := range arguments {
+		for _, head := range corpusHeads(6,arg) {
+			list = append(list,concatenate(head,[]byte{0x00}))
+		}
+	}
+	list = append(list,[]byte{0xC6, 0xC6, 0xC6, 0x80}, []byte{0xD8, 0x18, 0x42, 0x01, 0x02}, []byte{0xC0, 0x9F, 0xC1, 0x00, 0xFF})
+	list = append(list,[]byte{0xD8, 0x20, 0xD8, 0x20, 0x61, 'x'}, concatenate(bytes.Repeat([]byte{0xCB},64),[]byte{0x1A, 0x00, 0x00, 0x00, 0x0B}))
+	/*
+	 * Simple values and floating point.
+	 */
+	for simple := 0; simple < 24; simple++ {
+		list = append(list,[]byte{0xE0 | byte(simple)})
+	}
+	list = append(list,[]byte{0xF8, 0x20}, []byte{0xF8, 0xFF})
+	list = append(list,[]byte{0xF9, 0x00, 0x00}, []byte{0xF9, 0x7C, 0x00}, []byte{0xF9, 0x7E, 0x00}, []byte{0xF9, 0xFC, 0x00})
+	list = append(list,[]byte{0xFA, 0x47, 0xC3, 0x50, 0x00}, []byte{0xFA, 0x7F, 0xC0, 0x00, 0x00})
+	list = append(list,[]byte{0xFB, 0x3F, 0xF1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9A}, []byte{0xFB, 0x7F, 0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
+
+	for _, example := range readAppendixCorpus(t) {
+		list = append(list,example.code)
+	}
+	return list
+}
+/*
+ * Data items that are not well-formed: the examples of Appendix
+ * F.1, and the proper prefixes of the well-formed data items.
+ */
+func corpusMalformed(t *testing.T, wellFormed [][]byte) (list [][]byte) {
+	for _, code := range TestAppendixFMalformed {
+		var item, e = hex.DecodeString(code)
+		if nil != e {
+			t.Fatal(e)
+		} else {
+			list = append(list,item)
+		}
+	}
+	for _, item := range wellFormed {
+		for n := 1; n < len(item) && n <= 16; n++ {
+			list = append(list,item[0:n])
+		}
+	}
+	return list
+}
+/*
+ * Generate the well-formed and not well-formed data items of
+ * the corpus, validated by <Object#ItemLen>, ordered, and
+ * without duplicates.
+ */
+func corpusGenerate(t *testing.T) (lines []string) {
+	var wellFormed [][]byte = corpusWellFormed(t)
+	var malformed [][]byte = corpusMalformed(t,wellFormed)
+
+	var labels map[string]string = map[string]string{}
+	for _, item := range wellFormed {
+		var z, e = Object(item).ItemLen()
+		if nil != e || len(item) != z {
+			t.Errorf("[%X] Expected well-formed, found '%v'.",item,e)
+		}
+		labels[hex.EncodeToString(item)] = TestCorpusWellFormed
+	}
+	for _, item := range malformed {
+		var code string = hex.EncodeToString(item)
+		var _, exists = labels[code]
+		if exists {
+			continue
+		} else if e := Object(item).Valid(); nil == e {
+			t.Errorf("[%X] Expected not well-formed.",item)
+		}
+		labels[code] = TestCorpusMalformed
+	}
+	for code, label := range labels {
+		lines = append(lines,code+"\t"+label)
+	}
+	sort.Strings(lines)
+	return lines
+}
+/*
+ * Read the corpus of "testdata/rfc8949-appendix-f.txt" for
+ * fuzz targets, as data items with their well formedness.
+ */
+func readCorpus(f testing.TB) (list [][]byte, wellFormed []bool) {
+	var file, e = os.Open(TestCorpusFile)
+	if nil != e {
+		f.Fatal(e)
+	}
+	defer file.Close()
+
+	var scanner *bufio.Scanner = bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line string = scanner.Text()
+		if "" == line || strings.HasPrefix(line,"#") {
+			continue
+		}
+		var fields []string = strings.Split(line,"\t")
+		var item []byte
+		item, e = hex.DecodeString(fields[0])
+		if nil != e {
+			f.Fatal(e)
+		}
+		list = append(list,item)
+		wellFormed = append(wellFormed,TestCorpusWellFormed == fields[1])
+	}
+	return list, wellFormed
+}
+/*
+ * Generate the corpus, and compare it with
+ * "testdata/rfc8949-appendix-f.txt", or write the file under
+ * flag "-update".
+ */
+func TestGenerateCorpus(t *testing.T){
+	var text strings.Builder
+	text.WriteString("# RFC 8949 Appendix F: Well-formed and not well-formed data items\n")
+	text.WriteString("#\n")
+	text.WriteString("# Each line is the encoded data item in hexadecimal, and its\n")
+	text.WriteString("# well formedness, separated by a tab.  Generated by\n")
+	text.WriteString("#\n")
+	text.WriteString("#     go test -run TestGenerateCorpus -update\n")
+	text.WriteString("#\n")
+	for _, line := range corpusGenerate(t) {
+		text.WriteString(line)
+		text.WriteString("\n")
+	}
+	if *TestCorpusUpdate {
+		var e error = os.WriteFile(TestCorpusFile,[]byte(text.String()),0644)
+		if nil != e {
+			t.Fatal(e)
+		} else {
+			t.Logf("Wrote '%s'.",TestCorpusFile)
+		}
+	} else {
+		var existing, e = os.ReadFile(TestCorpusFile)
+		if nil != e {
+			t.Fatal(e)
+		} else if string(existing) != text.String() {
+			t.Errorf("Expected '%s' as generated, found a difference; regenerate with flag '-update'.",TestCorpusFile)
+		}
+	}
+}
+/*
+ * Well formedness by <Object#ItemLen> and <Object#Read> agree,
+ * and decoding of well-formed data items does not fail.
+ */
+func FuzzWellFormed(f *testing.F){
+	var list, _ = readCorpus(f)
+	for _, item := range list {
+		f.Add(item)
+	}
+	f.Fuzz(func(t *testing.T, data []byte){
+		var z, e = Object(data).ItemLen()
+		var item, re = Object{}.Read(bytes.NewReader(data))
+		if nil == e {
+			if 0 >= z || len(data) < z {
+				t.Fatalf("[%X] Item length %d.",data,z)
+			} else if nil != re || !bytes.Equal(data[0:z],item) {
+				t.Fatalf("[%X] Read '%X' '%v'.",data,[]byte(item),re)
+			}
+			Object(data[0:z]).Decode()
+			Object(data[0:z]).Diagnostic()
+		} else if nil == re {
+			t.Fatalf("[%X] Read '%X' of item failing '%v'.",data,[]byte(item),e)
+		}
+	})
+}
+/*
+ * Data items accepted by <Object#Read> are accepted by
+ * <Object#Valid>, and their canonical encoding is the canonical
+ * encoding of their decoded value.
+ */
+func FuzzDifferential(f *testing.F){
+	var list, _ = readCorpus(f)
+	for _, item := range list {
+		f.Add(item)
+	}
+	f.Fuzz(func(t *testing.T, data []byte){
+		var item, e = Object{}.Read(bytes.NewReader(data))
+		if nil != e {
+			return
+		} else if e = item.Valid(); nil != e {
+			t.Fatalf("[%X] Valid '%v' of item read.",data,e)
+		} else if z, _ := item.ItemLen(); len(item) != z {
+			t.Fatalf("[%X] Item length %d of item read '%X'.",data,z,[]byte(item))
+		}
+		var canonical Object
+		canonical, e = item.Canonical()
+		if nil != e || differentialGap(item) {
+			return
+		}
+		var options EncOptions = EncOptionsCoreDet()
+		var encoded Object
+		encoded, e = options.Encode(item.Decode())
+		if nil != e {
+			t.Fatalf("[%X] Encode: %v",data,e)
+		} else if !bytes.Equal(canonical,encoded) {
+			t.Fatalf("[%X] Expected '%X', found '%X'.",data,[]byte(canonical),[]byte(encoded))
+		}
+	})
+}
+/*
+ * Data item contains a tag decoded to its content, rather than
+ * to <Tagged>, which does not encode to the tag, or a standard
+ * or float epoch date and time decoded to "time.Time", which
+ * encodes to an integer epoch date and time when it has no
+ * fractional seconds.  See <TestAppendixEncodeGap>.
+ */
+func differentialGap(item Object) bool {
+	switch item.Major() {
+	case MajorArray:
+		var list, _ = item.Items()
+		for _, member := range list {
+			if differentialGap(member) {
+				return true
+			}
+		}
+	case MajorMap:
+		var list, _ = item.Entries()
+		for _, entry := range list {
+			if differentialGap(entry[0]) || differentialGap(entry[1]) {
+				return true
+			}
+		}
+	case MajorTagged:
+		var number, content, _ = item.tagged()
+		var _, date = item.Decode().(time.Time)
+		if date {
+			return (TagDateTimeString == number || MajorSimple == content.Major())
+		}
+		var tagged, ok = item.Decode().(Tagged)
+		return !ok || number != tagged.Number || differentialGap(content)
+	}
+	return false
+}
+/*
+ * Concatenate octets.
+ */
+func concatenate(list ...[]byte) (code []byte) {
+	for _, p := range list {
+		code = append(code,p...)
+	}
+	return code
+}
@@ -0,0 +1,126 @@
+/*
+ * CBOR COSE To Be Signed and To Be MACed Structures
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc9052#section-4.4
+ * https://tools.ietf.org/html/rfc9052#section-6.3
+ * https://tools.ietf.org/html/rfc9338#section-3.3
+ */
+package cbor
+
+import (
+	"errors"
+)
+/*
+ * Context strings of <SigStructure> and <MACStructure>.
+ */
+const ContextSignature string = "Signature"
+const ContextSignature1 string = "Signature1"
+const ContextCounterSignature string = "CounterSignature"
+const ContextMAC string = "MAC"
+const ContextMAC0 string = "MAC0"
+/*
+ * Validation errors of <SigStructure>, <MACStructure> and
+ * <Protected>.
+ */
+var ErrorCOSEContext error = errors.New("CBOR COSE context string unrecognized")
+var ErrorCOSEProtected error = errors.New("CBOR COSE protected header is not empty or one encoded map")
+/*
+ * Encode protected header parameters, as the map of header
+ * labels to values, deterministically, for the protected
+ * header of a COSE structure.  An empty map is the empty byte
+ * string.  See Section 3 [RFC9052].
+ */
+func Protected(headers any) ([]byte, error) {
+	var options EncOptions = EncOptionsCoreDet()
+	var code, e = options.Encode(headers)
+	if nil != e {
+		return nil, e
+	} else if MajorMap != code.Major() {
+		return nil, ErrorCOSEProtected
+	} else if 0xA0 == code[0] {
+		return []byte{}, nil
+	} else {
+		return code, nil
+	}
+}
+/*
+ * Produce the "Sig_structure" of Section 4.4 [RFC9052], the
+ * octets to be signed or verified, from the serialized
+ * protected headers of the body and of the signer, the
+ * external additional authenticated data, and the (detached
+ * or embedded) payload.  The protected headers of the signer
+ * are included for the contexts "Signature" and
+ * "CounterSignature", and are ignored for "Signature1".
+ */
+func SigStructure(context string, bodyProtected, signProtected, externalAAD, payload []byte) (Object, error) {
+	var list []any
+	switch context {
+	case ContextSignature, ContextCounterSignature:
+		list = []any{context,bstr(bodyProtected),bstr(signProtected),bstr(externalAAD),bstr(payload)}
+	case ContextSignature1:
+		list = []any{context,bstr(bodyProtected),bstr(externalAAD),bstr(payload)}
+	default:
+		return nil, ErrorCOSEContext
+	}
+	var e error = protectedValid(bodyProtected)
+	if nil == e {
+		e = protectedValid(signProtected)
+	}
+	if nil != e {
+		return nil, e
+	} else {
+		return Encode(list), nil
+	}
+}
+/*
+ * Produce the "MAC_structure" of Section 6.3 [RFC9052], the
+ * octets to be MACed or verified, from the serialized
+ * protected headers, the external additional authenticated
+ * data, and the (detached or embedded) payload, for the
+ * contexts "MAC" and "MAC0".
+ */
+func MACStructure(context string, protected, externalAAD, payload []byte) (Object, error) {
+	switch context {
+	case ContextMAC, ContextMAC0:
+		var e error = protectedValid(protected)
+		if nil != e {
+			return nil, e
+		} else {
+			return Encode([]any{context,bstr(protected),bstr(externalAAD),bstr(payload)}), nil
+		}
+	default:
+		return nil, ErrorCOSEContext
+	}
+}
+/*
+ * Validate serialized protected headers as empty or as one
+ * encoded map.
+ */
+func protectedValid(protected []byte) (error) {
+	if 0 == len(protected) {
+		return nil
+	} else if MajorMap != Object(protected).Major() {
+		return ErrorCOSEProtected
+	} else {
+		var z, e = Object(protected).ItemLen()
+		if nil != e || len(protected) != z {
+			return ErrorCOSEProtected
+		} else {
+			return nil
+		}
+	}
+}
+/*
+ * Byte string of octets, encoding nil as the empty byte string.
+ */
+func bstr(p []byte) ([]byte) {
+	if nil == p {
+		return []byte{}
+	} else {
+		return p
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * CBOR COSE Structures Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSigStructure(t *testing.T){
+	var protected, e = Protected(OrderedMap{{1,-7}})
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0xA1,0x01,0x26},protected) {
+		t.Errorf("Expected 'A10126', found '%X'.",protected)
+	}
+	var payload []byte = []byte("This is the content.")
+	/*
+	 * RFC9052 Appendix C.2.1 (sign1-pass-01) ToBeSign.
+	 */
+	var expected string = "846a5369676e61747572653143a101264054546869732069732074686520636f6e74656e742e"
+	var code Object
+	code, e = SigStructure(ContextSignature1,protected,nil,nil,payload)
+	if nil != e {
+		t.Fatal(e)
+	} else if expected != hex.EncodeToString(code) {
+		t.Errorf("Expected '%s', found '%x'.",expected,[]byte(code))
+	}
+
+	code, e = SigStructure(ContextSignature,nil,protected,[]byte{0x11},payload)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var list []Object
+	list, e = code.Items()
+	if nil != e {
+		t.Fatal(e)
+	} else if 5 != len(list) || !bytes.Equal([]byte{0x40},list[1]) || !bytes.Equal([]byte{0x43,0xA1,0x01,0x26},list[2]) || !bytes.Equal([]byte{0x41,0x11},list[3]) {
+		t.Errorf("Expected Sig_structure, found '%s'.",code)
+	}
+
+	code, e = MACStructure(ContextMAC0,protected,nil,payload)
+	if nil != e {
+		t.Fatal(e)
+	} else if "MAC0" != code.Decode().([]any)[0] {
+		t.Errorf("Expected 'MAC0', found '%s'.",code)
+	}
+
+	var empty []byte
+	empty, e = Protected(OrderedMap{})
+	if nil != e || nil == empty || 0 != len(empty) {
+		t.Errorf("Expected empty protected header, found '%X'.",empty)
+	}
+	_, e = SigStructure("Signature2",nil,nil,nil,payload)
+	if ErrorCOSEContext != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorCOSEContext,e)
+	}
+	_, e = MACStructure(ContextMAC,[]byte{0xA1,0x01},nil,payload)
+	if ErrorCOSEProtected != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorCOSEProtected,e)
+	}
+	_, e = Protected([]any{1})
+	if ErrorCOSEProtected != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorCOSEProtected,e)
+	}
+}
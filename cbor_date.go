@@ -0,0 +1,147 @@
+/*
+ * CBOR RFC8943 Dates
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8943
+ * https://tools.ietf.org/html/rfc3339#section-5.6
+ */
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+/*
+ * Date as the integer count of days since 1970-01-01 (tag
+ * 100), and as the RFC3339 full-date text string (tag 1004).
+ */
+const TagDays uint64 = 100
+const TagDate uint64 = 1004
+/*
+ * Decoding error of <Object#Date>.
+ */
+var ErrorDate error = errors.New("CBOR date is not a count of days or a full-date")
+/*
+ * Calendar date without time of day or location, the content
+ * of tag 1004, as "2006-01-02".
+ */
+type Date struct {
+
+	Year int
+
+	Month time.Month
+
+	Day int
+}
+/*
+ * Date as the count of days since 1970-01-01, negative
+ * before, the content of tag 100.
+ */
+type Days int64
+/*
+ * Limit of the magnitude of <Days>, beyond which a count of
+ * days is not a "time.Time".
+ */
+const daysMax int64 = (1 << 40)
+/*
+ * Calendar date of the time, in its location.
+ */
+func DateOf(t time.Time) (Date) {
+	var year, month, day = t.Date()
+	return Date{year, month, day}
+}
+/*
+ * Time at midnight UTC on the date.
+ */
+func (this Date) Time() (time.Time) {
+	return time.Date(this.Year,this.Month,this.Day,0,0,0,0,time.UTC)
+}
+/*
+ * Count of days since 1970-01-01.
+ */
+func (this Date) Days() (Days) {
+	return Days(this.Time().Unix()/86400)
+}
+/*
+ * Represent the date as an RFC3339 full-date.
+ */
+func (this Date) String() (string) {
+	return fmt.Sprintf("%04d-%02d-%02d",this.Year,int(this.Month),this.Day)
+}
+/*
+ * Calendar date of the count of days.
+ */
+func (this Days) Date() (Date) {
+	return DateOf(time.Unix(int64(this)*86400,0).UTC())
+}
+/*
+ * Resolve the date of a count of days (tag 100) or of a
+ * full-date (tag 1004), or of their untagged content.
+ */
+func (this Object) Date() (Date, error) {
+	var o Object = this
+	var number, content, ok = this.tagged()
+	if ok {
+		switch number {
+		case TagDays:
+			if MajorText == content.Major() {
+				return Date{}, ErrorDate
+			}
+		case TagDate:
+			if MajorText != content.Major() {
+				return Date{}, ErrorDate
+			}
+		default:
+			return Date{}, ErrorDate
+		}
+		o = content
+	}
+	switch o.Major() {
+	case MajorText:
+		var text, e = o.Text()
+		var t time.Time
+		if nil == e {
+			t, e = time.Parse(time.DateOnly,text)
+		}
+		if nil != e {
+			return Date{}, fmt.Errorf("%w: %w",ErrorDate,e)
+		} else {
+			return DateOf(t), nil
+		}
+	case MajorUint, MajorSint:
+		var days, e = o.Int()
+		if nil != e || daysMax < days || -daysMax > days {
+			return Date{}, ErrorDate
+		} else {
+			return Days(days).Date(), nil
+		}
+	default:
+		return Date{}, ErrorDate
+	}
+}
+/*
+ */
+func decodeDate(content Object) (any) {
+	var text, ok = content.Decode().(string)
+	if ok {
+		var t, e = time.Parse(time.DateOnly,text)
+		if nil == e {
+			return DateOf(t)
+		}
+	}
+	return nil
+}
+/*
+ */
+func decodeDays(content Object) (any) {
+	var days, e = content.Int()
+	if nil == e {
+		return Days(days)
+	} else {
+		return nil
+	}
+}
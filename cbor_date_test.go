@@ -0,0 +1,81 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR Dates Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T){
+	/*
+	 * Section 4 [RFC8943].
+	 */
+	var birth Date = Date{1940,time.October,9}
+	var death Date = Date{1980,time.December,8}
+	for _, c := range []struct{ value any; code string; date Date }{
+		{birth, "d903ec6a313934302d31302d3039", birth},
+		{birth.Days(), "d8643929b3", birth},
+		{death, "d903ec6a313938302d31322d3038", death},
+		{death.Days(), "d864190f9a", death},
+	} {
+		var o Object = Encode(c.value)
+		if c.code != hex.EncodeToString(o) {
+			t.Errorf("Expected '%s' for '%v', found '%X'.",c.code,c.value,[]byte(o))
+		}
+		if c.value != o.Decode() {
+			t.Errorf("Expected '%v' for '%s', found '%v'.",c.value,c.code,o.Decode())
+		}
+		var date, e = o.Date()
+		if nil != e || c.date != date {
+			t.Errorf("Expected '%v' for '%s', found '%v' '%v'.",c.date,c.code,date,e)
+		}
+		var midnight time.Time
+		e = Unmarshal(o,&midnight)
+		if nil != e || !c.date.Time().Equal(midnight) || time.UTC != midnight.Location() {
+			t.Errorf("Expected '%v' for '%s', found '%v' '%v'.",c.date.Time(),c.code,midnight,e)
+		}
+	}
+	if -10676 != birth.Days() || birth != Days(-10676).Date() || "1940-10-09" != birth.String() {
+		t.Errorf("Expected -10676 days of 1940-10-09, found %d of %v.",birth.Days(),Days(-10676).Date())
+	}
+
+	type TypeTestLicense struct {
+		Issued Date `cbor:"issued"`
+		Expires Days `cbor:"expires"`
+	}
+	var license TypeTestLicense
+	var e error = Unmarshal(Encode(TypeTestLicense{birth,death.Days()}),&license)
+	if nil != e {
+		t.Fatal(e)
+	} else if birth != license.Issued || death.Days() != license.Expires {
+		t.Errorf("Expected '%v' and %d, found '%v' and %d.",birth,death.Days(),license.Issued,license.Expires)
+	}
+	/*
+	 * Tag 100 decodes into a date, and tag 1004 into days.
+	 */
+	var crossed TypeTestLicense
+	e = Unmarshal(Encode(map[string]any{"issued": birth.Days(), "expires": death}),&crossed)
+	if nil != e || license != crossed {
+		t.Errorf("Expected '%v', found '%v' '%v'.",license,crossed,e)
+	}
+
+	for _, o := range []Object{
+		tagging(TagDate,Encode("1940-13-09")),
+		tagging(TagDate,Encode(3994)),
+		tagging(TagDays,Encode("1980-12-08")),
+		tagging(TagDays,Encode(int64(1) << 50)),
+		Encode(1.5),
+	} {
+		_, e = o.Date()
+		if !errors.Is(e,ErrorDate) {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",ErrorDate,[]byte(o),e)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+/*
+ * CBOR RFC8949 Diagnostic Notation
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-8
+ * https://tools.ietf.org/html/rfc8949#appendix-A
+ */
+package cbor
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+/*
+ * Data item having nested data items, in the traversal of
+ * <walkDiagnostic>.
+ */
+type diagnosticFrame struct {
+
+	head Object
+	/*
+	 * Count of nested data items.
+	 */
+	count int
+	/*
+	 * Offset of the data item in the notation.
+	 */
+	start int
+}
+/*
+ * Visitor producing the diagnostic notation of a data item.
+ */
+type walkDiagnostic struct {
+
+	text strings.Builder
+
+	stack []*diagnosticFrame
+}
+/*
+ * Produce the diagnostic notation of the (first) data item of
+ * the object, as in Appendix A [RFC8949].  Indefinite length
+ * data items are marked with an underscore, and unsigned and
+ * negative bignums (tags 2 and 3) are represented by their
+ * decimal value.
+ */
+func (this Object) Diagnostic() (string, error) {
+	var diagnostic walkDiagnostic
+	var _, e = this.walk(&diagnostic)
+	if nil != e {
+		return "", e
+	} else {
+		return diagnostic.text.String(), nil
+	}
+}
+/*
+ * Write the separator from the preceding sibling, and the
+ * opening of a data item having nested data items.
+ */
+func (this *walkDiagnostic) enter(head Object, depth int) (error) {
+	var top int = len(this.stack)-1
+	if 0 <= top {
+		var parent *diagnosticFrame = this.stack[top]
+		if 0 < parent.count {
+			if MajorMap == parent.head.Major() && 1 == (parent.count & 1) {
+				this.text.WriteString(": ")
+			} else {
+				this.text.WriteString(", ")
+			}
+		}
+		parent.count += 1
+	}
+	var major, ai, arg, _, _ = ParseHead(head)
+	var indefinite bool = (0x1F == ai)
+	var frame *diagnosticFrame = &diagnosticFrame{head, 0, this.text.Len()}
+	switch major {
+	case MajorBlob, MajorText:
+		if indefinite {
+			this.text.WriteString("(_ ")
+			this.stack = append(this.stack,frame)
+		}
+	case MajorArray:
+		if indefinite {
+			this.text.WriteString("[_ ")
+		} else {
+			this.text.WriteString("[")
+		}
+		this.stack = append(this.stack,frame)
+	case MajorMap:
+		if indefinite {
+			this.text.WriteString("{_ ")
+		} else {
+			this.text.WriteString("{")
+		}
+		this.stack = append(this.stack,frame)
+	case MajorTagged:
+		this.text.WriteString(strconv.FormatUint(arg,10))
+		this.text.WriteString("(")
+		this.stack = append(this.stack,frame)
+	}
+	return nil
+}
+/*
+ * Write a data item without nested data items, or the closing
+ * of a data item having nested data items.
+ */
+func (this *walkDiagnostic) exit(item Object, depth int) (error) {
+	var major, ai, arg, z, e = ParseHead(item)
+	if nil != e {
+		return e
+	}
+	var indefinite bool = (0x1F == ai)
+	var frame *diagnosticFrame
+	switch major {
+	case MajorBlob, MajorText:
+		if indefinite {
+			frame, this.stack = this.stack[len(this.stack)-1], this.stack[0:len(this.stack)-1]
+		}
+	case MajorArray, MajorMap, MajorTagged:
+		frame, this.stack = this.stack[len(this.stack)-1], this.stack[0:len(this.stack)-1]
+	}
+
+	switch major {
+	case MajorUint:
+		this.text.WriteString(strconv.FormatUint(arg,10))
+
+	case MajorSint:
+		this.text.WriteString(negativeString(arg))
+
+	case MajorBlob:
+		if !indefinite {
+			this.text.WriteString("h'")
+			this.text.WriteString(hex.EncodeToString(item[z:]))
+			this.text.WriteString("'")
+		} else if 0 == frame.count {
+			this.reset(frame,"''_")
+		} else {
+			this.text.WriteString(")")
+		}
+
+	case MajorText:
+		if !indefinite {
+			this.text.WriteString(diagnosticText(item[z:]))
+		} else if 0 == frame.count {
+			this.reset(frame,"\"\"_")
+		} else {
+			this.text.WriteString(")")
+		}
+
+	case MajorArray:
+		this.text.WriteString("]")
+
+	case MajorMap:
+		this.text.WriteString("}")
+
+	case MajorTagged:
+		var value string
+		var ok bool
+		if 2 == arg || 3 == arg {
+			value, ok = bignumString(item.Decode())
+		}
+		if ok {
+			this.reset(frame,value)
+		} else {
+			this.text.WriteString(")")
+		}
+
+	default:
+		switch item[0] {
+		case 0xF4:
+			this.text.WriteString("false")
+		case 0xF5:
+			this.text.WriteString("true")
+		case 0xF6:
+			this.text.WriteString("null")
+		case 0xF7:
+			this.text.WriteString("undefined")
+		case 0xF9, 0xFA, 0xFB:
+			var value, _ = item.float()
+			this.text.WriteString(diagnosticFloat(value))
+		default:
+			fmt.Fprintf(&this.text,"simple(%d)",arg)
+		}
+	}
+	return nil
+}
+/*
+ * Replace the notation of the data item begun by frame.
+ */
+func (this *walkDiagnostic) reset(frame *diagnosticFrame, text string) {
+	var prefix string = this.text.String()[0:frame.start]
+	this.text.Reset()
+	this.text.WriteString(prefix)
+	this.text.WriteString(text)
+}
+/*
+ * Quote text string content with the escapes of JSON.
+ */
+func diagnosticText(content []byte) (string) {
+	var text strings.Builder
+	text.WriteByte('"')
+	for _, r := range string(content) {
+		switch r {
+		case '"':
+			text.WriteString("\\\"")
+		case '\\':
+			text.WriteString("\\\\")
+		case '\b':
+			text.WriteString("\\b")
+		case '\f':
+			text.WriteString("\\f")
+		case '\n':
+			text.WriteString("\\n")
+		case '\r':
+			text.WriteString("\\r")
+		case '\t':
+			text.WriteString("\\t")
+		default:
+			if 0x20 > r {
+				fmt.Fprintf(&text,"\\u%04x",r)
+			} else {
+				text.WriteRune(r)
+			}
+		}
+	}
+	text.WriteByte('"')
+	return text.String()
+}
+/*
+ * Represent floating point value as in Appendix A: in decimal
+ * notation within the range of 1e-7 through 1e21, otherwise in
+ * exponential notation, and always having a fraction.
+ */
+func diagnosticFloat(value float64) (string) {
+	if math.IsNaN(value) {
+		return "NaN"
+	} else if math.IsInf(value,1) {
+		return "Infinity"
+	} else if math.IsInf(value,-1) {
+		return "-Infinity"
+	} else {
+		var magnitude float64 = math.Abs(value)
+		if 0 == magnitude || (1e-7 <= magnitude && 1e21 > magnitude) {
+			var text string = strconv.FormatFloat(value,'f',-1,64)
+			if !strings.Contains(text,".") {
+				text += ".0"
+			}
+			return text
+		} else {
+			var text string = strconv.FormatFloat(value,'e',-1,64)
+			var x int = strings.IndexByte(text,'e')
+			var mantissa, exponent string = text[0:x], text[x+1:]
+			if !strings.Contains(mantissa,".") {
+				mantissa += ".0"
+			}
+			var sign string = exponent[0:1]
+			exponent = strings.TrimLeft(exponent[1:],"0")
+			return mantissa+"e"+sign+exponent
+		}
+	}
+}
@@ -0,0 +1,34 @@
+/*
+ * CBOR Diagnostic Notation Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestDiagnostic(t *testing.T){
+	var cases = []struct{ code string; diag string }{
+		{"3903e7", "-1000"},
+		{"f90001", "5.960464477539063e-8"},
+		{"fb7e37e43c8800759c", "1.0e+300"},
+		{"f97e00", "NaN"},
+		{"c249010000000000000000", "18446744073709551616"},
+		{"d82076687474703a2f2f7777772e6578616d706c652e636f6d", "32(\"http://www.example.com\")"},
+		{"5f42010243030405ff", "(_ h'0102', h'030405')"},
+		{"bf61610161629f0203ffff", "{_ \"a\": 1, \"b\": [_ 2, 3]}"},
+		{"a26161016162820203", "{\"a\": 1, \"b\": [2, 3]}"},
+		{"f0", "simple(16)"},
+	}
+	for _, c := range cases {
+		var code, _ = hex.DecodeString(c.code)
+		var diag, e = Object(code).Diagnostic()
+		if nil != e {
+			t.Errorf("Expected '%s' for '%s', found '%v'.",c.diag,c.code,e)
+		} else if c.diag != diag {
+			t.Errorf("Expected '%s' for '%s', found '%s'.",c.diag,c.code,diag)
+		}
+	}
+}
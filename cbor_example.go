@@ -1,3 +1,5 @@
+//go:build !cbor_tiny
+
 /*
  * GOPL type struct object coding requires type binding.
  */
@@ -45,27 +47,33 @@ func (this TypeExampleCoder) String() (string) {
 		return ""
 	}
 }
-func (this TypeExampleCoder) Encode() (code Object) {
+func (this *TypeExampleCoder) Encode() (Object, error) {
 	var text map[string]any = map[string]any{ "source": this.source, "target": this.target}
 
-	code = Encode(text)
-	return code
+	return EncOptions{}.Encode(text)
 }
-func (this TypeExampleCoder) Decode(cbor Object) (TypeExampleCoder) {
+func (this *TypeExampleCoder) Decode(cbor Object) (error) {
 	this.source = ""
 	this.target = nil
 
-	var text map[string]any = cbor.Decode().(map[string]any)
-
-	this.source = text["source"].(string)
-	this.target = text["target"].([]byte)
-
-	return this
+	var text, isMap = cbor.Decode().(map[string]any)
+	if !isMap {
+		return ErrorMissingData
+	}
+	var source, isSource = text["source"].(string)
+	var target, isTarget = text["target"].([]byte)
+	if isSource && isTarget {
+		this.source = source
+		this.target = target
+		return nil
+	} else {
+		return ErrorMissingData
+	}
 }
 func ExampleDescribe(){
 	var text TypeExampleCoder = TypeExampleCoderObject
 
-	var code Object = text.Encode()
+	var code, _ = text.Encode()
 
 	var content string = code.String()
 
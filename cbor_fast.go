@@ -0,0 +1,75 @@
+/*
+ * CBOR RFC8949 Integer, Float and String Fast Paths
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.1
+ * https://tools.ietf.org/html/rfc8949#section-4.2.2
+ */
+package cbor
+
+import (
+	"math"
+)
+/*
+ * Append the encoding of an unsigned integer, without the
+ * boxing and type dispatch of <Encode>.
+ */
+func EncodeUint64(dst []byte, v uint64) ([]byte) {
+	return AppendHead(dst,MajorUint,v)
+}
+/*
+ * Append the encoding of a signed integer, as an unsigned
+ * integer (major type 0) when non negative, and as a negative
+ * integer (major type 1) otherwise, in shortest form.
+ */
+func EncodeInt64(dst []byte, v int64) ([]byte) {
+	if 0 <= v {
+		return AppendHead(dst,MajorUint,uint64(v))
+	} else {
+		return AppendHead(dst,MajorSint,uint64(-1-v))
+	}
+}
+/*
+ * Append the encoding of a text string.
+ */
+func EncodeString(dst []byte, v string) ([]byte) {
+	return append(AppendHead(dst,MajorText,uint64(len(v))),v...)
+}
+/*
+ * Append the encoding of a byte string.
+ */
+func EncodeBytes(dst []byte, v []byte) ([]byte) {
+	return append(AppendHead(dst,MajorBlob,uint64(len(v))),v...)
+}
+/*
+ * Append the encoding of a boolean.
+ */
+func EncodeBool(dst []byte, v bool) ([]byte) {
+	if v {
+		return append(dst,byte(HeadTrue))
+	} else {
+		return append(dst,byte(HeadFalse))
+	}
+}
+/*
+ * Append the shortest float encoding that preserves the value,
+ * as <Encode>.
+ */
+func EncodeFloat64(dst []byte, v float64) ([]byte) {
+	var half, exact = float64to16(v)
+	if exact {
+		return append(dst,byte(HeadFloat16),byte(half >> 8),byte(half))
+
+	} else if float64(float32(v)) == v {
+		var bits uint32 = math.Float32bits(float32(v))
+
+		return append(dst,byte(HeadFloat32),byte(bits >> 24),byte(bits >> 16),byte(bits >> 8),byte(bits))
+	} else {
+		var bits uint64 = math.Float64bits(v)
+
+		return append(dst,byte(HeadFloat64),byte(bits >> 56),byte(bits >> 48),byte(bits >> 40),byte(bits >> 32),byte(bits >> 24),byte(bits >> 16),byte(bits >> 8),byte(bits))
+	}
+}
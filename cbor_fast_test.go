@@ -0,0 +1,86 @@
+/*
+ * CBOR Integer and String Fast Paths Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestEncodeFast(t *testing.T){
+	for _, v := range []struct {
+		code []byte
+		expected []byte
+	}{
+		{EncodeUint64(nil,0), []byte{0x00}},
+		{EncodeUint64(nil,23), []byte{0x17}},
+		{EncodeUint64(nil,24), []byte{0x18,0x18}},
+		{EncodeUint64(nil,256), []byte{0x19,0x01,0x00}},
+		{EncodeUint64(nil,65536), []byte{0x1A,0x00,0x01,0x00,0x00}},
+		{EncodeUint64(nil,math.MaxUint64), []byte{0x1B,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF}},
+		{EncodeInt64(nil,-1), []byte{0x20}},
+		{EncodeInt64(nil,-100), []byte{0x38,0x63}},
+		{EncodeInt64(nil,-1000), []byte{0x39,0x03,0xE7}},
+		{EncodeInt64(nil,math.MinInt64), []byte{0x3B,0x7F,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF}},
+		{EncodeInt64(nil,1000000), []byte{0x1A,0x00,0x0F,0x42,0x40}},
+		{EncodeString(nil,"IETF"), []byte{0x64,'I','E','T','F'}},
+		{EncodeBytes(nil,[]byte{1,2,3,4}), []byte{0x44,1,2,3,4}},
+		{EncodeString(EncodeUint64([]byte{0x82},1),""), []byte{0x82,0x01,0x60}},
+		{EncodeBool(nil,true), []byte{0xF5}},
+		{EncodeFloat64(nil,1.5), []byte{0xF9,0x3E,0x00}},
+		{EncodeFloat64(nil,100000.0), []byte{0xFA,0x47,0xC3,0x50,0x00}},
+		{EncodeFloat64(nil,1.1), []byte{0xFB,0x3F,0xF1,0x99,0x99,0x99,0x99,0x99,0x9A}},
+	} {
+		if !bytes.Equal(v.expected,v.code) {
+			t.Errorf("Expected '%X', found '%X'.",v.expected,v.code)
+		}
+	}
+
+	var dst []byte = make([]byte,0,64)
+	var allocs float64 = testing.AllocsPerRun(100,func(){
+		dst = EncodeUint64(dst[0:0],0x100000000)
+		dst = EncodeInt64(dst,-500)
+		dst = EncodeString(dst,"temperature")
+		dst = EncodeBytes(dst,[]byte{0x01})
+		dst = EncodeFloat64(dst,1.1)
+	})
+	if 0 != allocs {
+		t.Errorf("Expected 0 allocations, found %v.",allocs)
+	}
+}
+
+func BenchmarkEncodeUint64(b *testing.B){
+	var dst []byte = make([]byte,0,16)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		dst = EncodeUint64(dst[0:0],uint64(n))
+	}
+}
+
+func BenchmarkEncodeInt64(b *testing.B){
+	var dst []byte = make([]byte,0,16)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		dst = EncodeInt64(dst[0:0],-int64(n))
+	}
+}
+
+func BenchmarkEncodeString(b *testing.B){
+	var dst []byte = make([]byte,0,64)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		dst = EncodeString(dst[0:0],"temperature")
+	}
+}
+
+func BenchmarkEncodeBytes(b *testing.B){
+	var dst []byte = make([]byte,0,64)
+	var data []byte = []byte("temperature")
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		dst = EncodeBytes(dst[0:0],data)
+	}
+}
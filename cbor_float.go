@@ -0,0 +1,118 @@
+/*
+ * CBOR RFC8949 Floating-Point Numbers
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.3
+ * https://tools.ietf.org/html/rfc8949#appendix-D
+ */
+package cbor
+
+import (
+	"math"
+	"github.com/syntelos/go-endian"
+)
+/*
+ * Resolve IEEE 754 half-precision (binary16) bits.  See
+ * Appendix D [RFC8949].
+ */
+func float16to64(bits uint16) (value float64) {
+	var exp uint16 = ((bits >> 10) & 0x1F)
+	var mant uint16 = (bits & 0x3FF)
+
+	if 0 == exp {
+		value = math.Ldexp(float64(mant),-24)
+	} else if 31 != exp {
+		value = math.Ldexp(float64(mant+1024),int(exp)-25)
+	} else if 0 == mant {
+		value = math.Inf(1)
+	} else {
+		return math.NaN()
+	}
+
+	if 0 != (bits & 0x8000) {
+		return -value
+	} else {
+		return value
+	}
+}
+/*
+ * Define IEEE 754 half-precision (binary16) bits when the value
+ * is exactly representable in half precision.  Every NaN maps
+ * to the canonical quiet NaN (0x7E00).
+ */
+func float64to16(value float64) (bits uint16, exact bool) {
+	if math.IsNaN(value) {
+		return 0x7E00, true
+	} else {
+		if math.Signbit(value) {
+			bits = 0x8000
+			value = -value
+		}
+
+		if 0 == value {
+			return bits, true
+
+		} else if math.IsInf(value,0) {
+			return (bits | 0x7C00), true
+
+		} else if math.Ldexp(1,-14) > value {
+			var mant float64 = math.Ldexp(value,24)
+			if mant == math.Trunc(mant) {
+				return (bits | uint16(mant)), true
+			}
+		} else {
+			var frac, exp = math.Frexp(value)
+			var e int = (exp+14)
+			var mant float64 = ((2*frac)-1)*1024
+			if 1 <= e && 30 >= e && mant == math.Trunc(mant) {
+				return (bits | uint16(e << 10) | uint16(mant)), true
+			}
+		}
+		return 0, false
+	}
+}
+/*
+ * Define the shortest float encoding that preserves the value.
+ * See Section 4.2.2 [RFC8949].
+ */
+func encodeFloatShortest(value float64) (Object) {
+	var half, exact = float64to16(value)
+	if exact {
+		return Object{0xF9,byte(half >> 8),byte(half)}
+
+	} else if float64(float32(value)) == value {
+		var bits []byte = endian.BigEndian.EncodeUint32(math.Float32bits(float32(value)))
+
+		return Object{0xFA,bits[0],bits[1],bits[2],bits[3]}
+	} else {
+		var bits []byte = endian.BigEndian.EncodeUint64(math.Float64bits(value))
+
+		return Object{0xFB}.Concatenate(bits)
+	}
+}
+/*
+ * Resolve float object value.
+ */
+func (this Object) float() (value float64, ok bool) {
+	var z int = len(this)
+	if 0 < z {
+		switch this[0] {
+		case 0xF9:
+			if 3 <= z {
+				return float16to64(endian.BigEndian.DecodeUint16(this[1:3])), true
+			}
+		case 0xFA:
+			if 5 <= z {
+				return float64(math.Float32frombits(endian.BigEndian.DecodeUint32(this[1:5]))), true
+			}
+		case 0xFB:
+			if 9 <= z {
+				return math.Float64frombits(endian.BigEndian.DecodeUint64(this[1:9])), true
+			}
+		}
+	}
+	return 0, false
+}
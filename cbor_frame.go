@@ -0,0 +1,81 @@
+/*
+ * CBOR RFC8949 Framing
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949
+ */
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+/*
+ * Upper bound on the payload octet count of a frame, guarding
+ * the frame reader against allocating for hostile length
+ * prefixes.
+ */
+var FrameSizeMax uint64 = (16*1024*1024)
+/*
+ * Validation errors produced by <ReadFrame> and <WriteFrame>.
+ */
+var ErrorFrameSize error = fmt.Errorf("CBOR Frame exceeds size maximum: %w",ErrorSizeExceeded)
+var ErrorFrameType error = errors.New("CBOR Frame is not a definite length byte string")
+var ErrorFrameContent error = errors.New("CBOR Frame content is not one data item")
+/*
+ * Write object as the payload of a definite length byte string,
+ * for transports (i.e. TCP) that require explicit item
+ * boundaries.
+ */
+func WriteFrame(w io.Writer, o Object) (e error) {
+	var z uint64 = uint64(len(o))
+	if FrameSizeMax < z {
+		return ErrorFrameSize
+	} else {
+		var frame Object = Encode([]byte(o))
+
+		return frame.Write(w)
+	}
+}
+/*
+ * Read one frame produced by <WriteFrame>, returning the
+ * object found in its payload.  The payload length is checked
+ * against <FrameSizeMax> before any payload is read.
+ */
+func ReadFrame(r io.Reader) (Object, error) {
+	var head, e = readHead(r,true)
+	if nil != e {
+		return nil, e
+	} else {
+		var major, ai, z, _, _ = ParseHead(head)
+		if MajorBlob != major || 0x1B < ai {
+			return nil, ErrorFrameType
+		} else if FrameSizeMax < z {
+			return nil, ErrorFrameSize
+		} else {
+			var p []byte = make([]byte,z)
+
+			_, e = io.ReadFull(r,p)
+			if nil != e {
+				return nil, fmt.Errorf(ErrorWrapRead,ErrorTruncated)
+			} else {
+				var b *bytes.Reader = bytes.NewReader(p)
+				var o Object = Object{}
+
+				o, e = o.Read(b)
+				if nil != e {
+					return nil, fmt.Errorf("%w: %w",ErrorFrameContent,e)
+				} else if 0 != b.Len() {
+					return nil, ErrorFrameContent
+				} else {
+					return o, nil
+				}
+			}
+		}
+	}
+}
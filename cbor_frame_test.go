@@ -0,0 +1,68 @@
+/*
+ * CBOR Framing Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestFrame(t *testing.T){
+	var b bytes.Buffer
+	var o Object = Encode(TestStringDatum)
+
+	var e error = WriteFrame(&b,o)
+	if nil != e {
+		t.Fatal(e)
+	}
+	e = WriteFrame(&b,o)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	for n := 0; n < 2; n++ {
+		var f Object
+		f, e = ReadFrame(&b)
+		if nil != e {
+			t.Fatal(e)
+		} else if TestStringDatum != f.MustText() {
+			t.Errorf("Expected '%s', found '%s'.",TestStringDatum,f.MustText())
+		}
+	}
+}
+
+func TestFrameSizeMax(t *testing.T){
+	var b bytes.Buffer
+	var o Object = Encode(TestStringDatum)
+
+	var e error = WriteFrame(&b,o)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	var max uint64 = FrameSizeMax
+	defer func(){ FrameSizeMax = max }()
+
+	FrameSizeMax = 4
+
+	_, e = ReadFrame(&b)
+	if !errors.Is(e,ErrorFrameSize) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorFrameSize,e)
+	}
+	e = WriteFrame(&b,o)
+	if !errors.Is(e,ErrorFrameSize) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorFrameSize,e)
+	}
+}
+
+func TestFrameContent(t *testing.T){
+	var b *bytes.Reader = bytes.NewReader([]byte{0x43,0x61,0x61,0x61})
+
+	var _, e = ReadFrame(b)
+	if !errors.Is(e,ErrorFrameContent) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorFrameContent,e)
+	}
+}
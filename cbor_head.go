@@ -0,0 +1,107 @@
+/*
+ * CBOR RFC8949 Data Item Head
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3
+ */
+package cbor
+
+//go:generate go run doc/cbor-rfc8949-table.go generate cbor_table.go
+
+import (
+	"github.com/syntelos/go-endian"
+)
+/*
+ * Parse the head of the data item at the start of the octets:
+ * the major type, the additional information (low five bits of
+ * the initial byte), the argument, and the head octet count.
+ * The argument is the immediate value for additional
+ * information 0..23, the following 1, 2, 4 or 8 octets for
+ * 24..27, and zero for indefinite length (31).  Additional
+ * information 28..30 is <ErrorReservedHead>, and a
+ * truncated head is <ErrorTruncated>.  See Section 3
+ * [RFC8949].
+ */
+func ParseHead(b []byte) (major Major, ai byte, arg uint64, headLen int, err error) {
+	if 0 == len(b) {
+		return 0, 0, 0, 0, ErrorTruncated
+	}
+	major = Major(b[0] >> 5)
+	ai = (b[0] & 0x1F)
+	switch ai {
+	case 0x18:
+		headLen = 2
+	case 0x19:
+		headLen = 3
+	case 0x1A:
+		headLen = 5
+	case 0x1B:
+		headLen = 9
+	case 0x1C, 0x1D, 0x1E:
+		return major, ai, 0, 0, ErrorReservedHead
+	case 0x1F:
+		return major, ai, 0, 1, nil
+	default:
+		return major, ai, uint64(ai), 1, nil
+	}
+	if headLen > len(b) {
+		return major, ai, 0, 0, ErrorTruncated
+	} else {
+		switch headLen {
+		case 2:
+			arg = uint64(b[1])
+		case 3:
+			arg = uint64(endian.BigEndian.DecodeUint16(b[1:3]))
+		case 5:
+			arg = uint64(endian.BigEndian.DecodeUint32(b[1:5]))
+		default:
+			arg = endian.BigEndian.DecodeUint64(b[1:9])
+		}
+		return major, ai, arg, headLen, nil
+	}
+}
+/*
+ * Resolve head argument and head octet count of object.  The
+ * indefinite length argument (31) resolves to zero.
+ */
+func (this Object) head() (arg uint64, z int, e error) {
+	_, _, arg, z, e = ParseHead(this)
+	return arg, z, e
+}
+/*
+ * Append the shortest head of major type and argument to the
+ * octets.  The head is composed on the stack, so that
+ * appending to octets of sufficient capacity does not
+ * allocate.
+ */
+func AppendHead(dst []byte, major Major, arg uint64) ([]byte) {
+	var head [9]byte
+	var z int
+	head[0] = (byte(major) << 5)
+	if 24 > arg {
+		head[0] |= byte(arg)
+		z = 1
+	} else if 0xFF >= arg {
+		head[0] |= 0x18
+		head[1] = byte(arg)
+		z = 2
+	} else if 0xFFFF >= arg {
+		head[0] |= 0x19
+		head[1], head[2] = byte(arg >> 8), byte(arg)
+		z = 3
+	} else if 0xFFFFFFFF >= arg {
+		head[0] |= 0x1A
+		head[1], head[2], head[3], head[4] = byte(arg >> 24), byte(arg >> 16), byte(arg >> 8), byte(arg)
+		z = 5
+	} else {
+		head[0] |= 0x1B
+		for n := 1; n < 9; n++ {
+			head[n] = byte(arg >> (8*(8-n)))
+		}
+		z = 9
+	}
+	return append(dst,head[0:z]...)
+}
@@ -0,0 +1,116 @@
+/*
+ * CBOR Data Item Head Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+func TestParseHead(t *testing.T){
+	var cases = []struct{ code []byte; major Major; ai byte; arg uint64; z int }{
+		{[]byte{0x17}, MajorUint, 23, 23, 1},
+		{[]byte{0x38,0x63}, MajorSint, 24, 99, 2},
+		{[]byte{0x59,0x01,0x00}, MajorBlob, 25, 256, 3},
+		{[]byte{0x7A,0x00,0x01,0x00,0x00}, MajorText, 26, 65536, 5},
+		{[]byte{0x9B,0x00,0x00,0x00,0x01,0x00,0x00,0x00,0x00}, MajorArray, 27, 4294967296, 9},
+		{[]byte{0xBF}, MajorMap, 31, 0, 1},
+		{[]byte{0xD8,0x20}, MajorTagged, 24, 32, 2},
+		{[]byte{0xF9,0x3C,0x00}, MajorSimple, 25, 0x3C00, 3},
+	}
+	for _, c := range cases {
+		var major, ai, arg, z, e = ParseHead(c.code)
+		if nil != e {
+			t.Errorf("Expected head for '%X', found '%v'.",c.code,e)
+		} else if c.major != major || c.ai != ai || c.arg != arg || c.z != z {
+			t.Errorf("Expected '%d %d %d %d' for '%X', found '%d %d %d %d'.",c.major,c.ai,c.arg,c.z,c.code,major,ai,arg,z)
+		}
+	}
+
+	var _, _, _, _, e = ParseHead([]byte{0x19,0x01})
+	if ErrorMissingData != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorMissingData,e)
+	}
+	_, _, _, _, e = ParseHead([]byte{0x5C})
+	if ErrorReservedAdditionalInfo != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorReservedAdditionalInfo,e)
+	}
+}
+
+func TestHeadConstants(t *testing.T){
+	for _, c := range []struct{ head Tag; major Major; ai byte }{
+		{HeadUint8Follow, MajorUint, 24},
+		{HeadSint64Follow, MajorSint, 27},
+		{HeadBlobIndefinite, MajorBlob, 31},
+		{HeadText, MajorText, 0},
+		{HeadArray16Follow, MajorArray, 25},
+		{HeadMapIndefinite, MajorMap, 31},
+		{HeadTag32Follow, MajorTagged, 26},
+		{HeadSimple8Follow, MajorSimple, 24},
+		{HeadFloat64, MajorSimple, 27},
+		{HeadBreak, MajorSimple, 31},
+	} {
+		if c.major != Major(c.head >> 5) || c.ai != byte(c.head & 0x1F) {
+			t.Errorf("Expected major %d and additional information %d, found '%02X'.",c.major,c.ai,byte(c.head))
+		}
+	}
+	if tagging(TagNegativeBignum,Object{0x40})[0] != byte(HeadTag+3) {
+		t.Errorf("Expected tag 3 head 'C3'.")
+	}
+}
+
+func TestIntegerArguments(t *testing.T){
+	/*
+	 * Appendix A [RFC8949].
+	 */
+	var cases = []struct{ code string; value int64; z int }{
+		{"1818", 24, 2},
+		{"1819", 25, 2},
+		{"1864", 100, 2},
+		{"1903e8", 1000, 3},
+		{"1a000f4240", 1000000, 5},
+		{"1b000000e8d4a51000", 1000000000000, 9},
+		{"3863", -100, 2},
+		{"3903e7", -1000, 3},
+		{"3a000f423f", -1000000, 5},
+		{"3b000000e8d4a50fff", -1000000000000, 9},
+	}
+	for _, c := range cases {
+		var code, _ = hex.DecodeString(c.code)
+		var o Object = Object(code)
+		var _, _, _, z, e = ParseHead(o)
+		if nil != e || c.z != z {
+			t.Errorf("Expected head of %d octets for '%s', found %d '%v'.",c.z,c.code,z,e)
+		}
+		var value int64
+		value, e = o.Int()
+		if nil != e || c.value != value {
+			t.Errorf("Expected (%d) for '%s', found (%d) '%v'.",c.value,c.code,value,e)
+		}
+		var decoded string = fmt.Sprint(o.Decode())
+		if fmt.Sprint(c.value) != decoded {
+			t.Errorf("Expected (%d) for '%s', found (%s).",c.value,c.code,decoded)
+		}
+		var length int
+		length, e = o.ItemLen()
+		if nil != e || len(code) != length {
+			t.Errorf("Expected %d octets for '%s', found %d '%v'.",len(code),c.code,length,e)
+		}
+	}
+	for _, c := range []struct{ code string; number uint64 }{
+		{"c100", 1},
+		{"d82000", 32},
+		{"d9010000", 256},
+		{"da0001000000", 65536},
+		{"db000000010000000000", 4294967296},
+	} {
+		var code, _ = hex.DecodeString(c.code)
+		var number, content, ok = Object(code).tagged()
+		if !ok || c.number != number || !content.Equal(Object{0x00}) {
+			t.Errorf("Expected tag %d for '%s', found %d '%X'.",c.number,c.code,number,[]byte(content))
+		}
+	}
+}
@@ -0,0 +1,300 @@
+/*
+ * CBOR RFC8949 Random Access Index
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949
+ */
+package cbor
+
+import (
+	"errors"
+	"fmt"
+)
+/*
+ * Validation errors produced by <Index>.
+ */
+var ErrorIndexPath error = errors.New("CBOR Index path not found")
+/*
+ * Offsets of every data item of an object, for repeated access
+ * by path without walking the object.
+ */
+type Index struct {
+
+	object Object
+
+	table map[int]*indexEntry
+}
+/*
+ * Extent of one data item, and offsets of its content.
+ */
+type indexEntry struct {
+
+	end int
+	/*
+	 * Offset of tag content, or zero.
+	 */
+	content int
+	/*
+	 * Offsets of array elements.
+	 */
+	elements []int
+	/*
+	 * Offsets of map values by key encoding.
+	 */
+	entries map[string]int
+}
+/*
+ * Construct the index of the (first) data item of the object
+ * in one pass.
+ */
+func NewIndex(o Object) (*Index, error) {
+	var index *Index = &Index{o, map[int]*indexEntry{}}
+	var _, e = index.build(0)
+	if nil != e {
+		return nil, e
+	} else {
+		return index, nil
+	}
+}
+/*
+ * Data item having nested data items, in the construction of
+ * <Index>.
+ */
+type indexFrame struct {
+
+	off int
+
+	entry *indexEntry
+
+	major Major
+
+	indefinite bool
+	/*
+	 * Count of nested data items (map entries) of a definite
+	 * length data item, and of nested data items recorded.
+	 */
+	count, n uint64
+	/*
+	 * Offset of the current map key.
+	 */
+	key int
+}
+/*
+ * Determine whether the nested data items of a definite length
+ * data item have been recorded.
+ */
+func (this *indexFrame) complete() (bool) {
+	if MajorMap == this.major {
+		return 0 == (this.n & 1) && this.count == (this.n >> 1)
+	} else {
+		return this.count == this.n
+	}
+}
+/*
+ * Record the data item at offset, returning the offset
+ * following it.  Nested data items are recorded with an
+ * explicit stack rather than by recursion, as for <walker>.
+ */
+func (this *Index) build(off int) (end int, e error) {
+	var stack []indexFrame
+	var p int = off
+	for {
+		if p >= len(this.object) {
+			return 0, ErrorTruncated
+		}
+		var start int = p
+		var o Object = this.object[p:]
+		var arg uint64
+		var z int
+		arg, z, e = o.head()
+		if nil != e {
+			return 0, e
+		}
+		var entry *indexEntry = &indexEntry{}
+		var indefinite bool = (0x1F == (o[0] & 0x1F))
+		var open bool = false
+		p += z
+
+		switch o.Major() {
+		case MajorBlob, MajorText:
+			if indefinite {
+				open = true
+			} else if uint64(len(o) - z) < arg {
+				return 0, ErrorTruncated
+			} else {
+				p += int(arg)
+			}
+
+		case MajorArray:
+			open = true
+
+		case MajorMap:
+			entry.entries = map[string]int{}
+			open = true
+
+		case MajorTagged:
+			entry.content = p
+			indefinite = false
+			arg = 1
+			open = true
+
+		case MajorSimple:
+			if 0xFF == o[0] {
+				return 0, ErrorUnexpectedBreak
+			}
+		}
+		var complete bool = false
+		if open {
+			stack = append(stack,indexFrame{start,entry,o.Major(),indefinite,arg,0,0})
+		} else {
+			entry.end = p
+			this.table[start] = entry
+			complete = true
+		}
+		/*
+		 * Exit the data items having all of their nested data
+		 * items recorded, and enter the next nested data item.
+		 */
+		for {
+			var top int = len(stack)-1
+			if 0 > top {
+				return p, nil
+			}
+			var frame *indexFrame = &stack[top]
+			if complete {
+				if MajorMap == frame.major && 0 == (frame.n & 1) {
+					frame.entry.entries[indexKey(this.object[frame.key:p])] = p
+				}
+				frame.n += 1
+			}
+			var closed bool
+			if frame.indefinite {
+				if MajorMap == frame.major && 0 != (frame.n & 1) {
+					closed = false
+				} else if p >= len(this.object) {
+					return 0, ErrorTruncated
+				} else if 0xFF == this.object[p] {
+					p += 1
+					closed = true
+				}
+			} else {
+				closed = frame.complete()
+			}
+
+			if closed {
+				frame.entry.end = p
+				this.table[frame.off] = frame.entry
+				stack = stack[0:top]
+				complete = true
+			} else {
+				switch frame.major {
+				case MajorArray:
+					frame.entry.elements = append(frame.entry.elements,p)
+				case MajorMap:
+					if 0 == (frame.n & 1) {
+						frame.key = p
+					}
+				}
+				break
+			}
+		}
+	}
+}
+/*
+ * Resolve the data item found by following path from the
+ * indexed data item.  Integer path elements select array
+ * elements, and all other path elements select map values by
+ * key.  Tags are transparent to path elements.
+ */
+func (this *Index) Get(path ...any) (Object, error) {
+	var off int = 0
+	for _, step := range path {
+		var entry *indexEntry = this.table[off]
+		for 0 != entry.content {
+			off = entry.content
+			entry = this.table[off]
+		}
+		var ok bool = false
+		if nil != entry.entries {
+			off, ok = entry.entries[indexKey(Encode(indexStep(step)))]
+
+		} else if nil != entry.elements {
+			var n uint64
+			n, ok = indexElement(step)
+			if ok && n < uint64(len(entry.elements)) {
+				off = entry.elements[n]
+			} else {
+				ok = false
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %v",ErrorIndexPath,step)
+		}
+	}
+	return this.object[off:this.table[off].end], nil
+}
+/*
+ * Identify map key by the deterministic encoding of the key
+ * data item, when available.
+ */
+func indexKey(key Object) (string) {
+	var canonical, e = key.Canonical()
+	if nil == e {
+		return string(canonical)
+	} else {
+		return string(key)
+	}
+}
+/*
+ * Non negative integer path elements are unsigned integer map
+ * keys.
+ */
+func indexStep(step any) (any) {
+	switch step.(type) {
+	case int, int8, int16, int32, int64:
+		var n, ok = indexElement(step)
+		if ok {
+			return n
+		}
+	}
+	return step
+}
+/*
+ * Resolve a non negative integer path element as an array
+ * index.
+ */
+func indexElement(step any) (n uint64, ok bool) {
+	var i int64
+	switch step.(type) {
+	case int:
+		i = int64(step.(int))
+	case int8:
+		i = int64(step.(int8))
+	case int16:
+		i = int64(step.(int16))
+	case int32:
+		i = int64(step.(int32))
+	case int64:
+		i = step.(int64)
+	case uint:
+		return uint64(step.(uint)), true
+	case uint8:
+		return uint64(step.(uint8)), true
+	case uint16:
+		return uint64(step.(uint16)), true
+	case uint32:
+		return uint64(step.(uint32)), true
+	case uint64:
+		return step.(uint64), true
+	default:
+		return 0, false
+	}
+	if 0 <= i {
+		return uint64(i), true
+	} else {
+		return 0, false
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * CBOR Random Access Index Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestIndex(t *testing.T){
+	var document OrderedMap = OrderedMap{
+		{"devices", []any{
+			map[string]any{"id": "a"},
+			map[string]any{"id": "b"},
+		}},
+		{uint8(10), Tagged{6,[]any{uint8(1),uint8(2)}}},
+	}
+	var index, e = NewIndex(Encode(document))
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	var o Object
+	o, e = index.Get("devices",1,"id")
+	if nil != e {
+		t.Fatal(e)
+	} else if "b" != o.MustText() {
+		t.Errorf("Expected 'b', found '%s'.",o.MustText())
+	}
+
+	o, e = index.Get(10,1)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0x02},o) {
+		t.Errorf("Expected '02', found '%X'.",[]byte(o))
+	}
+
+	_, e = index.Get("devices",2)
+	if !errors.Is(e,ErrorIndexPath) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorIndexPath,e)
+	}
+
+	index, e = NewIndex(Object{0x9F,0x01,0x7F,0x61,'a',0x61,'b',0xFF,0x03,0xFF})
+	if nil != e {
+		t.Fatal(e)
+	}
+	o, e = index.Get(1)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0x7F,0x61,'a',0x61,'b',0xFF},o) {
+		t.Errorf("Expected '7F61616162FF', found '%X'.",[]byte(o))
+	}
+	o, e = index.Get(2)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0x03},o) {
+		t.Errorf("Expected '03', found '%X'.",[]byte(o))
+	}
+
+	_, e = NewIndex(Object{0x82,0x01})
+	if ErrorMissingData != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorMissingData,e)
+	}
+}
+
+func TestIndexNested(t *testing.T){
+	var depth int = 1000000
+	var o Object = make(Object,depth+1)
+	for n := 0; n < depth; n++ {
+		o[n] = 0x81
+	}
+	var index, e = NewIndex(o)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var path []any = make([]any,depth)
+	for n := range path {
+		path[n] = 0
+	}
+	var found Object
+	found, e = index.Get(path...)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0x00},found) {
+		t.Errorf("Expected '00', found '%X'.",[]byte(found))
+	}
+
+	for _, c := range []struct{ invalid Object; expected error }{
+		{Object{0x81}, ErrorTruncated},
+		{Object{0x9F,0x01}, ErrorTruncated},
+		{Object{0xBF,0x01,0xFF}, ErrorUnexpectedBreak},
+		{Object{0x7F,0x61,'a'}, ErrorTruncated},
+	} {
+		_, e = NewIndex(c.invalid)
+		if c.expected != e {
+			t.Errorf("Expected '%v', found '%v'.",c.expected,e)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR Interoperability Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://github.com/fxamacker/cbor
+ * https://tools.ietf.org/html/rfc8949#section-4.2.1
+ */
+package cbor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+/*
+ * Struct having the same field tags in both implementations.
+ */
+type TypeTestInterop struct {
+
+	A int `cbor:"a"`
+
+	B string `cbor:"b,omitempty"`
+
+	C []byte `cbor:"c"`
+}
+/*
+ * Fixture "testdata/interop-fxamacker.txt" values by name, as
+ * encoded by fxamacker/cbor under its core deterministic
+ * encoding options.
+ */
+var TestInteropCorpus map[string]any = map[string]any{
+	"uint 0": uint64(0),
+	"uint 23": uint64(23),
+	"uint 24": uint64(24),
+	"uint 255": uint64(255),
+	"uint 256": uint64(256),
+	"uint 65535": uint64(65535),
+	"uint 65536": uint64(65536),
+	"uint 4294967295": uint64(4294967295),
+	"uint 4294967296": uint64(4294967296),
+	"uint max": uint64(math.MaxUint64),
+	"int -1": int64(-1),
+	"int -24": int64(-24),
+	"int -25": int64(-25),
+	"int -256": int64(-256),
+	"int -257": int64(-257),
+	"int -65536": int64(-65536),
+	"int -65537": int64(-65537),
+	"int -4294967296": int64(-4294967296),
+	"int -4294967297": int64(-4294967297),
+	"int min": int64(math.MinInt64),
+	"int8 -128": int8(-128),
+	"int16 -1000": int16(-1000),
+	"int32 -100000": int32(-100000),
+	"int 1000000": int(1000000),
+	"float 0.0": float64(0),
+	"float -0.0": math.Copysign(0,-1),
+	"float 1.5": float64(1.5),
+	"float 65504": float64(65504),
+	"float 100000": float64(100000),
+	"float 1.1": float64(1.1),
+	"float 1e300": float64(1e300),
+	"float 5.960464477539063e-8": float64(5.960464477539063e-8),
+	"float +Inf": math.Inf(1),
+	"float -Inf": math.Inf(-1),
+	"float NaN": math.NaN(),
+	"float32 3.4028234663852886e+38": float32(3.4028234663852886e+38),
+	"bool false": false,
+	"bool true": true,
+	"null": nil,
+	"text empty": "",
+	"text a": "a",
+	"text IETF": "IETF",
+	"text escapes": "\"\\",
+	"text u+00fc": "ü",
+	"text u+6c34": "水",
+	"bytes empty": []byte{},
+	"bytes 01020304": []byte{1,2,3,4},
+	"array empty": []any{},
+	"array 1 2 3": []any{uint64(1),uint64(2),uint64(3)},
+	"array nested": []any{uint64(1),[]any{uint64(2),uint64(3)},[]any{uint64(4),uint64(5)}},
+	"map empty": map[string]any{},
+	"map text keys": map[string]any{"b": uint64(2), "a": uint64(1), "aa": uint64(3), "c": []any{uint64(1)}},
+	"map int keys": map[int64]string{10: "x", -1: "y", 100: "z", 0: "w"},
+	"struct": TypeTestInterop{A: -5, C: []byte{0xFF}},
+	"struct full": TypeTestInterop{A: 7, B: "bee"},
+	"slice of int16": []int16{-1,0,1,300},
+	"slice of string": []string{"x","yy"},
+}
+/*
+ * Fixtures for which the encoding of this package differs from
+ * the fixture, by design.
+ */
+var TestInteropGap map[string]string = map[string]string{
+	"struct full": "nil byte slice encoding, see EncOptions.NilContainers",
+}
+
+func TestInteropFxamacker(t *testing.T){
+	var file, e = os.Open("testdata/interop-fxamacker.txt")
+	if nil != e {
+		t.Fatal(e)
+	}
+	defer file.Close()
+
+	var options EncOptions = EncOptionsCoreDet()
+	var count int
+	var scanner *bufio.Scanner = bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line string = scanner.Text()
+		if "" == line || strings.HasPrefix(line,"#") {
+			continue
+		}
+		var fields []string = strings.Split(line,"\t")
+		var value, ok = TestInteropCorpus[fields[0]]
+		if !ok {
+			t.Errorf("Fixture '%s' missing from corpus.",fields[0])
+			continue
+		}
+		count += 1
+		var code Object
+		code, e = options.Encode(value)
+		if nil != e {
+			t.Errorf("[%s] %v",fields[0],e)
+		} else if _, gap := TestInteropGap[fields[0]]; gap {
+			if fields[1] == hex.EncodeToString(code) {
+				t.Errorf("[%s] Gap closed, remove from TestInteropGap.",fields[0])
+			}
+		} else if fields[1] != hex.EncodeToString(code) {
+			t.Errorf("[%s] Expected '%s', found '%s'.",fields[0],fields[1],hex.EncodeToString(code))
+		}
+		/*
+		 * Decode the fixture, and encode its value.
+		 */
+		var fixture []byte
+		fixture, e = hex.DecodeString(fields[1])
+		if nil != e {
+			t.Fatal(e)
+		}
+		code, e = options.Encode(Object(fixture).Decode())
+		if nil != e {
+			t.Errorf("[%s] %v",fields[0],e)
+		} else if !bytes.Equal(fixture,code) {
+			t.Errorf("[%s] Expected '%s' from decoding, found '%s'.",fields[0],fields[1],hex.EncodeToString(code))
+		}
+	}
+	if len(TestInteropCorpus) != count {
+		t.Errorf("Expected %d fixtures, found %d.",len(TestInteropCorpus),count)
+	}
+}
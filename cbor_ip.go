@@ -0,0 +1,221 @@
+/*
+ * CBOR RFC9164 IP Addresses and Prefixes
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc9164
+ */
+package cbor
+
+import (
+	"errors"
+	"net/netip"
+	"strconv"
+)
+/*
+ * IPv4 and IPv6 addresses, prefixes, and interface addresses.
+ */
+const TagIPv4 uint64 = 52
+const TagIPv6 uint64 = 54
+/*
+ * Decoding error of <Object#Addr> and <Object#Prefix>.
+ */
+var ErrorIP error = errors.New("CBOR IP address or prefix malformed")
+/*
+ * Define the address (tag 52 or 54) as its byte string, or, for
+ * an IPv6 address having a zone, as the interface format
+ * "[address, null, zone]".  An invalid address is null.
+ */
+func encodeAddr(addr netip.Addr) (Object) {
+	if !addr.IsValid() {
+		return Object{0xF6}
+	} else {
+		var data []byte = addr.AsSlice()
+		var address Object = define(MajorBlob,uint64(len(data))).Concatenate(data)
+		if "" != addr.Zone() {
+			return tagging(TagIPv6,define(MajorArray,3).Concatenate(address).Concatenate(Object{0xF6}).Concatenate(EncodeString(nil,addr.Zone())))
+		} else {
+			return tagging(ipTag(addr),address)
+		}
+	}
+}
+/*
+ * Define the prefix (tag 52 or 54) in the prefix format
+ * "[length, address]" of the address without trailing zero
+ * octets, or, for a prefix having an address with bits beyond
+ * its length, in the interface format "[address, length]".  An
+ * invalid prefix is null.
+ */
+func encodePrefix(prefix netip.Prefix) (Object) {
+	if !prefix.IsValid() {
+		return Object{0xF6}
+	} else {
+		var data []byte = prefix.Addr().AsSlice()
+		var length Object = define(MajorUint,uint64(prefix.Bits()))
+		if prefix.Masked() == prefix {
+			var z int = len(data)
+			for 0 < z && 0 == data[z-1] {
+				z -= 1
+			}
+			var address Object = define(MajorBlob,uint64(z)).Concatenate(data[0:z])
+			return tagging(ipTag(prefix.Addr()),define(MajorArray,2).Concatenate(length).Concatenate(address))
+		} else {
+			var address Object = define(MajorBlob,uint64(len(data))).Concatenate(data)
+			return tagging(ipTag(prefix.Addr()),define(MajorArray,2).Concatenate(address).Concatenate(length))
+		}
+	}
+}
+/*
+ * Tag number of the address family.
+ */
+func ipTag(addr netip.Addr) (uint64) {
+	if addr.Is4() {
+		return TagIPv4
+	} else {
+		return TagIPv6
+	}
+}
+/*
+ * Resolve the address of tag 52 or 54, in the address format,
+ * or in the interface format with a zone and without a prefix
+ * length.
+ */
+func (this Object) Addr() (netip.Addr, error) {
+	var value, e = this.ip()
+	if nil != e {
+		return netip.Addr{}, e
+	} else if addr, ok := value.(netip.Addr); ok {
+		return addr, nil
+	} else {
+		return netip.Addr{}, ErrorIP
+	}
+}
+/*
+ * Resolve the prefix of tag 52 or 54, in the prefix format, or
+ * in the interface format, of which the address retains the
+ * bits beyond the prefix length.
+ */
+func (this Object) Prefix() (netip.Prefix, error) {
+	var value, e = this.ip()
+	if nil != e {
+		return netip.Prefix{}, e
+	} else if prefix, ok := value.(netip.Prefix); ok {
+		return prefix, nil
+	} else {
+		return netip.Prefix{}, ErrorIP
+	}
+}
+/*
+ * Resolve the address or prefix of the tagged object.
+ */
+func (this Object) ip() (any, error) {
+	var number, content, ok = this.tagged()
+	if ok && (TagIPv4 == number || TagIPv6 == number) {
+		return decodeIP(number,content)
+	} else {
+		return nil, ErrorIP
+	}
+}
+/*
+ * Resolve the address or prefix of tag content, as "netip.Addr"
+ * or "netip.Prefix".  The address of the prefix format has no
+ * trailing zero octets, and no bits beyond the prefix length.
+ */
+func decodeIP(number uint64, content Object) (any, error) {
+	var width int = 16
+	if TagIPv4 == number {
+		width = 4
+	}
+	switch content.Major() {
+	case MajorBlob:
+		var data, e = content.Bytes()
+		if nil != e || width != len(data) {
+			return nil, ErrorIP
+		} else {
+			var addr, _ = netip.AddrFromSlice(data)
+			return addr, nil
+		}
+	case MajorArray:
+		var list, e = content.Items()
+		if nil != e || 2 > len(list) || 3 < len(list) {
+			return nil, ErrorIP
+		}
+		if MajorUint == list[0].Major() && 2 == len(list) {
+			/*
+			 * Prefix format.
+			 */
+			var bits uint64
+			var data []byte
+			bits, e = list[0].Uint()
+			if nil == e {
+				data, e = list[1].Bytes()
+			}
+			if nil != e || uint64(width*8) < bits || width < len(data) || (0 < len(data) && 0 == data[len(data)-1]) {
+				return nil, ErrorIP
+			}
+			var full []byte = make([]byte,width)
+			copy(full,data)
+			var addr, _ = netip.AddrFromSlice(full)
+			var prefix netip.Prefix = netip.PrefixFrom(addr,int(bits))
+			if prefix.Masked() != prefix {
+				return nil, ErrorIP
+			} else {
+				return prefix, nil
+			}
+		} else {
+			/*
+			 * Interface format.
+			 */
+			var data []byte
+			data, e = list[0].Bytes()
+			if nil != e || width != len(data) {
+				return nil, ErrorIP
+			}
+			var addr, _ = netip.AddrFromSlice(data)
+			if 3 == len(list) {
+				var zone string
+				zone, e = ipZone(list[2])
+				if nil != e || !list[1].IsNull() || addr.Is4() {
+					return nil, ErrorIP
+				} else {
+					return addr.WithZone(zone), nil
+				}
+			}
+			var bits uint64
+			bits, e = list[1].Uint()
+			if nil != e || uint64(width*8) < bits {
+				return nil, ErrorIP
+			} else {
+				return netip.PrefixFrom(addr,int(bits)), nil
+			}
+		}
+	default:
+		return nil, ErrorIP
+	}
+}
+/*
+ * Resolve the zone identifier of the interface format, as text
+ * or as an interface index.
+ */
+func ipZone(o Object) (string, error) {
+	if MajorUint == o.Major() {
+		var index, e = o.Uint()
+		return strconv.FormatUint(index,10), e
+	} else {
+		return o.Text()
+	}
+}
+/*
+ */
+func decodeIPv4(content Object) (any) {
+	var value, _ = decodeIP(TagIPv4,content)
+	return value
+}
+/*
+ */
+func decodeIPv6(content Object) (any) {
+	var value, _ = decodeIP(TagIPv6,content)
+	return value
+}
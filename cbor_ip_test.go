@@ -0,0 +1,76 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR IP Addresses and Prefixes Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestIP(t *testing.T){
+	/*
+	 * Section 3 [RFC9164].
+	 */
+	for _, c := range []struct{ value any; code string }{
+		{netip.MustParseAddr("2001:db8:1234:deed:beef:cafe:face:feed"), "d8365020010db81234deedbeefcafefacefeed"},
+		{netip.MustParsePrefix("2001:db8:1234::/48"), "d8368218304620010db81234"},
+		{netip.MustParsePrefix("2001:db8:1234:deed:beef:cafe:face:feed/56"), "d836825020010db81234deedbeefcafefacefeed1838"},
+		{netip.MustParseAddr("192.0.2.1"), "d83444c0000201"},
+		{netip.MustParsePrefix("192.0.2.0/24"), "d83482181843c00002"},
+		{netip.MustParsePrefix("192.0.2.1/24"), "d8348244c00002011818"},
+		{netip.MustParsePrefix("0.0.0.0/0"), "d834820040"},
+		{netip.MustParseAddr("fe80::202:2ff:fffe:303%eth0"), "d8368350fe80000000000000020202fffffe0303f66465746830"},
+	} {
+		var o Object = Encode(c.value)
+		if c.code != hex.EncodeToString(o) {
+			t.Errorf("Expected '%s' for '%v', found '%X'.",c.code,c.value,[]byte(o))
+		}
+		if c.value != o.Decode() {
+			t.Errorf("Expected '%v' for '%s', found '%v'.",c.value,c.code,o.Decode())
+		}
+	}
+
+	type TypeTestRoute struct {
+		Gateway netip.Addr `cbor:"gw"`
+		Network netip.Prefix `cbor:"net"`
+		Next *netip.Addr `cbor:"next"`
+	}
+	var next netip.Addr = netip.MustParseAddr("::1")
+	var route TypeTestRoute = TypeTestRoute{netip.MustParseAddr("10.0.0.1"),netip.MustParsePrefix("10.0.0.0/8"),&next}
+	var decoded TypeTestRoute
+	var e error = Unmarshal(Encode(route),&decoded)
+	if nil != e {
+		t.Fatal(e)
+	} else if route.Gateway != decoded.Gateway || route.Network != decoded.Network || nil == decoded.Next || next != *decoded.Next {
+		t.Errorf("Expected '%v', found '%v'.",route,decoded)
+	}
+	e = Unmarshal(Encode(map[string]any{"gw": route.Network}),&decoded)
+	var te *UnmarshalTypeError
+	if !errors.As(e,&te) {
+		t.Errorf("Expected type error of prefix into address, found '%v'.",e)
+	}
+	/*
+	 * Malformed content.
+	 */
+	for _, code := range []string{
+		"d83443c00002",
+		"d83482181844c0000200",
+		"d834821043c00003",
+		"d83482182143c00002",
+		"d8368350fe80000000000000020202fffffe030318406465746830",
+		"d83482f643c00002",
+	} {
+		var o, _ = hex.DecodeString(code)
+		var _, e = Object(o).Addr()
+		var _, f = Object(o).Prefix()
+		if !errors.Is(e,ErrorIP) || !errors.Is(f,ErrorIP) {
+			t.Errorf("Expected '%v' for '%s', found '%v' and '%v'.",ErrorIP,code,e,f)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * CBOR RFC8949 Map Key Compaction
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.1
+ * https://json-ld.github.io/cbor-ld-spec/#compression
+ */
+package cbor
+
+import (
+	"errors"
+)
+/*
+ * Translation of text string map keys to and from unsigned
+ * integers, by a dictionary shared by encoder and decoder, for
+ * the compaction of documents without changing the structs
+ * that they decode into, as the term compression of CBOR-LD.
+ * A key without translation is unchanged.  See <KeyTable>.
+ */
+type KeyMapper interface {
+	/*
+	 * Translate text string key to unsigned integer.
+	 */
+	CompactKey(key string) (uint64, bool)
+	/*
+	 * Translate unsigned integer key to text string.
+	 */
+	ExpandKey(key uint64) (string, bool)
+}
+/*
+ * Validation error of <NewKeyTable>.
+ */
+var ErrorKeyTable error = errors.New("CBOR key table maps two keys to one integer")
+/*
+ * Dictionary of text string keys and their integers.
+ */
+type KeyTable struct {
+
+	compact map[string]uint64
+
+	expand map[uint64]string
+}
+/*
+ * Define the <KeyMapper> of the dictionary, which maps each
+ * key to a distinct integer.
+ */
+func NewKeyTable(dictionary map[string]uint64) (*KeyTable, error) {
+	var table *KeyTable = &KeyTable{map[string]uint64{}, map[uint64]string{}}
+	for key, value := range dictionary {
+		var _, duplicate = table.expand[value]
+		if duplicate {
+			return nil, ErrorKeyTable
+		} else {
+			table.compact[key] = value
+			table.expand[value] = key
+		}
+	}
+	return table, nil
+}
+/*
+ */
+func (this *KeyTable) CompactKey(key string) (uint64, bool) {
+	var value, ok = this.compact[key]
+	return value, ok
+}
+/*
+ */
+func (this *KeyTable) ExpandKey(key uint64) (string, bool) {
+	var value, ok = this.expand[key]
+	return value, ok
+}
+/*
+ * Define the (first) data item of the object with the text
+ * string keys of its maps, at any depth, replaced by their
+ * integers.  See <EncOptions> "KeyMapper".
+ */
+func (this Object) CompactKeys(mapper KeyMapper) (Object, error) {
+	return this.keymap(mapper,true)
+}
+/*
+ * Define the (first) data item of the object with the unsigned
+ * integer keys of its maps, at any depth, replaced by their
+ * text strings.  See <DecOptions> "KeyMapper".
+ */
+func (this Object) ExpandKeys(mapper KeyMapper) (Object, error) {
+	return this.keymap(mapper,false)
+}
+/*
+ * Define the data item with the keys of its maps translated.
+ */
+func (this Object) keymap(mapper KeyMapper, compact bool) (Object, error) {
+	var _, z, e = this.head()
+	if nil != e {
+		return nil, e
+	}
+	var indefinite bool = (0x1F == (this[0] & 0x1F))
+	switch this.Major() {
+	case MajorArray, MajorMap:
+		var list []Object
+		list, e = this.items()
+		if nil != e {
+			return nil, e
+		}
+		var major Major = this.Major()
+		var content Object = Object{}.Concatenate(this[0:z])
+		for n, item := range list {
+			if MajorMap == major && 0 == (n & 1) {
+				item = item.key(mapper,compact)
+			}
+			item, e = item.keymap(mapper,compact)
+			if nil != e {
+				return nil, e
+			} else {
+				content = item.AppendTo(content)
+			}
+		}
+		if indefinite {
+			content = append(content,0xFF)
+		}
+		return content, nil
+
+	case MajorTagged:
+		if indefinite || z >= len(this) {
+			return nil, ErrorTruncated
+		}
+		var content Object
+		content, e = this[z:].keymap(mapper,compact)
+		if nil != e {
+			return nil, e
+		} else {
+			return Object{}.Concatenate(this[0:z]).Concatenate(content), nil
+		}
+
+	default:
+		var n int
+		n, e = this.ItemLen()
+		if nil != e {
+			return nil, e
+		} else {
+			return this[0:n].Clone(), nil
+		}
+	}
+}
+/*
+ * Translate a map key, or produce it unchanged.
+ */
+func (this Object) key(mapper KeyMapper, compact bool) (Object) {
+	if compact && MajorText == this.Major() {
+		var text, e = this.payload()
+		if nil == e {
+			var value, ok = mapper.CompactKey(string(text))
+			if ok {
+				return define(MajorUint,value)
+			}
+		}
+	} else if !compact && MajorUint == this.Major() {
+		var value, e = this.Uint()
+		if nil == e {
+			var text, ok = mapper.ExpandKey(value)
+			if ok {
+				return EncodeString(nil,text)
+			}
+		}
+	}
+	return this
+}
@@ -0,0 +1,69 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR Map Key Compaction Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"testing"
+)
+
+type TypeTestKeyed struct {
+
+	Name string `cbor:"name"`
+
+	Temperature float32 `cbor:"temperature"`
+
+	Location map[string]any `cbor:"location"`
+}
+
+func TestKeyMapper(t *testing.T){
+	var table, e = NewKeyTable(map[string]uint64{"name": 1, "temperature": 2, "location": 3, "lat": 4})
+	if nil != e {
+		t.Fatal(e)
+	}
+	var value TypeTestKeyed = TypeTestKeyed{"probe",21.5,map[string]any{"lat": uint8(45), "alt": uint8(3)}}
+
+	var options EncOptions = EncOptionsCoreDet()
+	options.KeyMapper = table
+	var code Object
+	code, e = options.Encode(value)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var expected []byte = []byte{0xA3,0x01,0x65,'p','r','o','b','e',0x02,0xF9,0x4D,0x60,0x03,0xA2,0x04,0x18,0x2D,0x63,'a','l','t',0x03}
+	if !bytes.Equal(expected,code) {
+		t.Errorf("Expected '%X', found '%X'.",expected,[]byte(code))
+	} else if len(code) >= len(Encode(value)) {
+		t.Errorf("Expected compaction of %d octets, found %d.",len(Encode(value)),len(code))
+	}
+
+	var check TypeTestKeyed
+	e = DecOptions{KeyMapper: table}.Unmarshal(code,&check)
+	if nil != e {
+		t.Fatal(e)
+	} else if value.Name != check.Name || value.Temperature != check.Temperature || uint8(45) != check.Location["lat"] {
+		t.Errorf("Expected '%v', found '%v'.",value,check)
+	}
+
+	var expanded Object
+	expanded, e = code.ExpandKeys(table)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var compacted Object
+	compacted, e = expanded.CompactKeys(table)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(code,compacted) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(code),[]byte(compacted))
+	}
+
+	_, e = NewKeyTable(map[string]uint64{"a": 1, "b": 1})
+	if ErrorKeyTable != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorKeyTable,e)
+	}
+}
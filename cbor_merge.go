@@ -0,0 +1,178 @@
+/*
+ * CBOR RFC8949 Merge Patch
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc7396
+ */
+package cbor
+
+/*
+ * Apply merge patch to target, with the semantics of JSON
+ * Merge Patch over CBOR maps.  A patch map replaces the value
+ * of each of its keys in the target map, recursively, and a
+ * "null" patch value removes its key from the target.  A patch
+ * that is not a map replaces the target.
+ */
+func Merge(target, patch Object) (Object, error) {
+	var e error
+	patch, e = patch.first()
+	if nil != e {
+		return nil, e
+
+	} else if MajorMap == patch.Major() && 0 != len(target) {
+		target, e = target.first()
+		if nil != e {
+			return nil, e
+		}
+	}
+	return merge(target,patch)
+}
+/*
+ * Apply merge patch to target, each being one data item.
+ */
+func merge(target, patch Object) (Object, error) {
+	if MajorMap != patch.Major() {
+		return patch, nil
+	} else {
+		var list, changes [][2]Object
+		var e error
+		if 0 != len(target) && MajorMap == target.Major() {
+			list, e = target.Entries()
+			if nil != e {
+				return nil, e
+			}
+		}
+		changes, e = patch.Entries()
+		if nil != e {
+			return nil, e
+		}
+		var _, index = entryKeys(list)
+		var removed bool = false
+		for _, change := range changes {
+			var key string = indexKey(change[0])
+			var n, ok = index[key]
+			if change[1].IsNull() {
+				if ok {
+					list[n][1] = nil
+					delete(index,key)
+					removed = true
+				}
+			} else if ok {
+				list[n][1], e = merge(list[n][1],change[1])
+				if nil != e {
+					return nil, e
+				}
+			} else {
+				var value Object
+				value, e = merge(nil,change[1])
+				if nil != e {
+					return nil, e
+				} else {
+					index[key] = len(list)
+					list = append(list,[2]Object{change[0],value})
+				}
+			}
+		}
+		if removed {
+			var retained [][2]Object = make([][2]Object,0,len(list))
+			for _, entry := range list {
+				if nil != entry[1] {
+					retained = append(retained,entry)
+				}
+			}
+			list = retained
+		}
+		return defineEntries(list), nil
+	}
+}
+/*
+ * Define the merge patch that transforms "a" into "b", such
+ * that "Merge(a,Diff(a,b))" is equal to "b".  As for JSON
+ * Merge Patch, a "null" map value in "b" is not representable
+ * when "a" is a map.
+ */
+func Diff(a, b Object) (Object, error) {
+	var e error
+	a, e = a.first()
+	if nil != e {
+		return nil, e
+	}
+	b, e = b.first()
+	if nil != e {
+		return nil, e
+	}
+	return diff(a,b)
+}
+/*
+ * Define the merge patch from "a" to "b", each being one data
+ * item.
+ */
+func diff(a, b Object) (Object, error) {
+	if MajorMap != a.Major() || MajorMap != b.Major() {
+		return b, nil
+	} else {
+		var before, after, patch [][2]Object
+		var e error
+		before, e = a.Entries()
+		if nil != e {
+			return nil, e
+		}
+		after, e = b.Entries()
+		if nil != e {
+			return nil, e
+		}
+		var beforeKeys, beforeIndex = entryKeys(before)
+		var afterKeys, afterIndex = entryKeys(after)
+		for n, entry := range before {
+			var _, ok = afterIndex[beforeKeys[n]]
+			if !ok {
+				patch = append(patch,[2]Object{entry[0],Object{0xF6}})
+			}
+		}
+		for n, entry := range after {
+			var m, ok = beforeIndex[afterKeys[n]]
+			if !ok {
+				patch = append(patch,entry)
+
+			} else if !before[m][1].Equal(entry[1]) {
+				var change Object
+				change, e = diff(before[m][1],entry[1])
+				if nil != e {
+					return nil, e
+				} else {
+					patch = append(patch,[2]Object{entry[0],change})
+				}
+			}
+		}
+		return defineEntries(patch), nil
+	}
+}
+/*
+ * Define a map from key and value pairs.
+ */
+func defineEntries(list [][2]Object) (this Object) {
+	this = define(MajorMap,uint64(len(list)))
+	for _, entry := range list {
+		this = entry[0].AppendTo(this)
+		this = entry[1].AppendTo(this)
+	}
+	return this
+}
+/*
+ * Identify the keys of the entries by <indexKey>, once, with
+ * the position of the first entry having each key.
+ */
+func entryKeys(list [][2]Object) (keys []string, index map[string]int) {
+	keys = make([]string,len(list))
+	index = make(map[string]int,len(list))
+	for n, entry := range list {
+		keys[n] = indexKey(entry[0])
+		if _, ok := index[keys[n]]; !ok {
+			index[keys[n]] = n
+		}
+	}
+	return keys, index
+}
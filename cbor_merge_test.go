@@ -0,0 +1,89 @@
+/*
+ * CBOR Merge Patch Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMerge(t *testing.T){
+	var cases = []struct{ target, patch, expected any }{
+		{map[string]any{"a": "b"}, map[string]any{"a": "c"}, map[string]any{"a": "c"}},
+		{map[string]any{"a": "b"}, map[string]any{"b": "c"}, map[string]any{"a": "b", "b": "c"}},
+		{map[string]any{"a": "b"}, map[string]any{"a": nil}, map[string]any{}},
+		{map[string]any{"a": "b", "b": "c"}, map[string]any{"a": nil}, map[string]any{"b": "c"}},
+		{map[string]any{"a": []any{"b"}}, map[string]any{"a": "c"}, map[string]any{"a": "c"}},
+		{map[string]any{"a": "c"}, map[string]any{"a": []any{"b"}}, map[string]any{"a": []any{"b"}}},
+		{map[string]any{"a": map[string]any{"b": "c"}}, map[string]any{"a": map[string]any{"b": "d", "c": nil}}, map[string]any{"a": map[string]any{"b": "d"}}},
+		{map[string]any{"a": "foo"}, "bar", "bar"},
+		{map[string]any{"e": nil}, map[string]any{"a": uint8(1)}, map[string]any{"e": nil, "a": uint8(1)}},
+		{[]any{"a","b"}, map[string]any{"a": "b"}, map[string]any{"a": "b"}},
+		{map[string]any{}, map[string]any{"a": map[string]any{"bb": map[string]any{"ccc": nil}}}, map[string]any{"a": map[string]any{"bb": map[string]any{}}}},
+	}
+	for _, c := range cases {
+		var target, patch, expected Object = Encode(c.target), Encode(c.patch), Encode(c.expected)
+		var merged, e = Merge(target,patch)
+		if nil != e {
+			t.Fatal(e)
+		} else if indexKey(expected) != indexKey(merged) {
+			t.Errorf("Expected '%X', found '%X'.",[]byte(expected),[]byte(merged))
+		}
+	}
+}
+
+func TestDiff(t *testing.T){
+	var cases = []struct{ a, b any }{
+		{map[string]any{"a": "b"}, map[string]any{"a": "c"}},
+		{map[string]any{"a": "b", "b": "c"}, map[string]any{"b": "c", "c": uint8(1)}},
+		{map[string]any{"a": map[string]any{"b": "c", "d": "e"}}, map[string]any{"a": map[string]any{"b": "d"}}},
+		{map[string]any{"a": "b"}, []any{"a"}},
+		{map[string]any{"a": "b"}, map[string]any{"a": "b"}},
+	}
+	for _, c := range cases {
+		var a, b Object = Encode(c.a), Encode(c.b)
+		var patch, e = Diff(a,b)
+		if nil != e {
+			t.Fatal(e)
+		}
+		var merged Object
+		merged, e = Merge(a,patch)
+		if nil != e {
+			t.Fatal(e)
+		} else if indexKey(b) != indexKey(merged) {
+			t.Errorf("Expected '%X', found '%X'.",[]byte(b),[]byte(merged))
+		}
+	}
+}
+
+func TestMergeLarge(t *testing.T){
+	var target, patch map[string]any = map[string]any{}, map[string]any{}
+	for n := 0; n < 20000; n++ {
+		var key string = fmt.Sprintf("k%d",n)
+		target[key] = uint16(n)
+		if 0 == (n & 1) {
+			patch[key] = nil
+		}
+	}
+	var merged, e = Merge(Encode(target),Encode(patch))
+	if nil != e {
+		t.Fatal(e)
+	}
+	var entries [][2]Object
+	entries, e = merged.Entries()
+	if nil != e {
+		t.Fatal(e)
+	} else if 10000 != len(entries) {
+		t.Errorf("Expected '%d', found '%d'.",10000,len(entries))
+	}
+
+	var change Object
+	change, e = Diff(Encode(target),merged)
+	if nil != e {
+		t.Fatal(e)
+	} else if indexKey(Encode(patch)) != indexKey(change) {
+		t.Errorf("Expected '%d', found '%d'.",len(Encode(patch)),len(change))
+	}
+}
@@ -0,0 +1,608 @@
+/*
+ * CBOR RFC8949 Encoding Options
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949
+ * https://datatracker.ietf.org/doc/draft-mcnally-deterministic-cbor/
+ * https://fidoalliance.org/specs/fido-v2.0-ps-20190130/fido-client-to-authenticator-protocol-v2.0-ps-20190130.html#ctap2-canonical-cbor-encoding-form
+ */
+package cbor
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+/*
+ * Validation errors produced by <DecOptions#Unmarshal>.
+ */
+var ErrorTrailingData error = errors.New("CBOR trailing data follows data item")
+var ErrorSimpleForbidden error = errors.New("CBOR simple value forbidden")
+var ErrorFloatForbidden error = errors.New("CBOR float forbidden")
+/*
+ * Struct field errors of decoding options and field tag option
+ * "default", wrapped with the name of the field or map key.
+ */
+var ErrorUnknownField error = errors.New("CBOR map key has no struct field")
+var ErrorMissingField error = errors.New("CBOR struct field absent from map")
+var ErrorFieldDefault error = errors.New("CBOR struct field default invalid for field type")
+const ErrorWrapField string = "%w %s"
+/*
+ * Target type of <NewPlan> is neither a slice of struct nor a
+ * struct of slices.
+ */
+var ErrorPlanTarget error = errors.New("CBOR plan target is neither a slice of struct nor a struct of slices")
+/*
+ * Pointer, map or slice refers to itself, such that encoding
+ * would not terminate.
+ */
+var ErrorCycle error = errors.New("CBOR encoding of value refers to itself")
+/*
+ * Encoding options.  The zero value is the behavior of
+ * <Encode>.
+ */
+type EncOptions struct {
+	/*
+	 * Omit empty struct fields (false, zero, empty string,
+	 * nil pointer or interface, and zero length array, slice
+	 * or map) as for field tag option "omitempty".
+	 */
+	OmitEmpty bool
+	/*
+	 * Encode nil slices and maps as "null" rather than as
+	 * empty arrays and maps.
+	 */
+	NilContainers bool
+	/*
+	 * Map key order of deterministic re-encoding.  Any
+	 * deterministic option re-encodes the data item with
+	 * shortest arguments and definite lengths.
+	 */
+	Sort SortMode
+	/*
+	 * Re-encode floats in the shortest form preserving their
+	 * value.
+	 */
+	ShortestFloat bool
+	/*
+	 * Re-encode every NaN as the canonical quiet NaN
+	 * (0xF97E00), whatever its width, sign and payload.
+	 */
+	CanonicalNaN bool
+	/*
+	 * Reject NaN and infinite floats.
+	 */
+	ForbidNonFinite bool
+	/*
+	 * Re-encode integral floats as integers when in the range
+	 * of major types 0 and 1, and negative zero as zero.
+	 */
+	NumericReduction bool
+	/*
+	 * Reject tagged data items.
+	 */
+	ForbidTags bool
+	/*
+	 * Maximum nesting depth of arrays and maps, or zero for no
+	 * limit.
+	 */
+	MaxDepth int
+	/*
+	 * Enclose the data item in a string reference namespace
+	 * (tag 256), replacing repeated strings with references
+	 * (tag 25).  See <Object#StringRefs>.
+	 */
+	StringRefs bool
+	/*
+	 * Replace the text string keys of maps with the integers
+	 * of the dictionary, before deterministic re-encoding.
+	 * See <Object#CompactKeys>.
+	 */
+	KeyMapper KeyMapper
+}
+/*
+ * Core Deterministic Encoding: shortest arguments, definite
+ * lengths, shortest floats preserving value, the canonical NaN,
+ * and map keys ordered bytewise.  See Section 4.2.1 and 4.2.2
+ * [RFC8949].
+ */
+func EncOptionsCoreDet() (EncOptions) {
+	return EncOptions{Sort: SortBytewise, ShortestFloat: true, CanonicalNaN: true}
+}
+/*
+ * FIDO CTAP2 canonical CBOR: shortest integer and length
+ * arguments, definite lengths, map keys ordered by major type,
+ * then shorter first, then bytewise, floats unchanged but
+ * finite, no tags, and nesting limited to four levels.  See
+ * Section 6 [CTAP2].
+ */
+func EncOptionsCTAP2() (EncOptions) {
+	return EncOptions{Sort: SortCTAP2, ForbidTags: true, ForbidNonFinite: true, MaxDepth: 4}
+}
+/*
+ * Gordian dCBOR: Core Deterministic Encoding with numeric
+ * reduction, such that a numeric value has exactly one
+ * encoding.  See Section 2.3 [dCBOR].
+ */
+func EncOptionsDCBOR() (EncOptions) {
+	var options EncOptions = EncOptionsCoreDet()
+	options.NumericReduction = true
+	return options
+}
+/*
+ * Encoding state of one call to <EncOptions#Encode>.
+ */
+type encoding struct {
+
+	options EncOptions
+
+	e error
+	/*
+	 * Nesting level of <encode>, and the references being
+	 * encoded beyond <encodeCycleLevel>.
+	 */
+	level int
+
+	visiting map[any]bool
+}
+/*
+ * Nesting level from which the references being encoded are
+ * tracked, as for "encoding/json", such that shallow values
+ * are encoded without the cost of cycle detection.
+ */
+const encodeCycleLevel int = 1000
+/*
+ * Define object content under encoding options.
+ */
+func (this EncOptions) Encode(a any) (Object, error) {
+	var state encoding = encoding{options: this}
+
+	var o Object = encode(a,&state)
+	if nil != state.e {
+		return nil, state.e
+	}
+	if nil != this.KeyMapper {
+		var e error
+		o, e = o.CompactKeys(this.KeyMapper)
+		if nil != e {
+			return nil, e
+		}
+	}
+	if this.deterministic() {
+		var e error
+		o, e = o.deterministic(&this,0)
+		if nil != e {
+			return nil, e
+		}
+	}
+	if this.StringRefs {
+		return o.StringRefs()
+	} else {
+		return o, nil
+	}
+}
+/*
+ * Determine whether encoding requires deterministic
+ * re-encoding.
+ */
+func (this EncOptions) deterministic() (bool) {
+	return (SortNone != this.Sort || this.ShortestFloat || this.CanonicalNaN || this.ForbidNonFinite || this.NumericReduction || this.ForbidTags || 0 < this.MaxDepth)
+}
+/*
+ * Decoding options.  The zero value is the behavior of
+ * <Unmarshal>.
+ */
+type DecOptions struct {
+	/*
+	 * Decode byte strings into GOPL strings, and text strings
+	 * into byte slices.
+	 */
+	StringBytes bool
+	/*
+	 * Reject data following the first data item, such that
+	 * the data is exactly one data item.
+	 */
+	RejectTrailingBytes bool
+	/*
+	 * Resolve string reference namespaces (tag 256) and
+	 * references (tag 25) before decoding.  See
+	 * <Object#ResolveStringRefs>.
+	 */
+	StringRefs bool
+	/*
+	 * Reject a data item that is not in the encoding of
+	 * <EncOptionsDCBOR>, with <ErrorNotDeterministic>.
+	 */
+	DCBOR bool
+	/*
+	 * Tag numbers permitted, rejecting any other tag with
+	 * <ErrorTagForbidden>.  A nil list permits every tag, and
+	 * an empty list none.
+	 */
+	AllowedTags []uint64
+	/*
+	 * Reject simple values other than false, true and null,
+	 * with <ErrorSimpleForbidden>.
+	 */
+	ForbidSimple bool
+	/*
+	 * Reject floats, with <ErrorFloatForbidden>.
+	 */
+	ForbidFloats bool
+	/*
+	 * Maximum nesting depth of data items, or zero for no
+	 * limit, rejecting deeper data items with
+	 * <ErrorDepthExceeded>.  The first data item is at depth
+	 * zero, and the data items nested within it at depth one.
+	 */
+	MaxDepth int
+	/*
+	 * Maximum encoded size of a data item in octets, or zero
+	 * for no limit, rejecting larger data items with
+	 * <ErrorSizeExceeded>.
+	 */
+	MaxSize int
+	/*
+	 * Reject a map having two keys of the same canonical
+	 * encoding (<Object#Canonical>), with <ErrorDuplicateKey>,
+	 * rather than decoding the last of their values.
+	 */
+	RejectDuplicateKeys bool
+	/*
+	 * Replace the integer keys of maps with the text strings
+	 * of the dictionary, before decoding.  See
+	 * <Object#ExpandKeys>.
+	 */
+	KeyMapper KeyMapper
+	/*
+	 * Reject a map lacking an entry for a struct field, with
+	 * <ErrorMissingField>, excepting fields tagged with
+	 * options "omitempty", "unknown" or "default".
+	 */
+	RequireAllFields bool
+	/*
+	 * Reject a map entry matching no struct field, with
+	 * <ErrorUnknownField>, excepting the entries received by
+	 * a field tagged with option "unknown".
+	 */
+	ErrorOnUnknownField bool
+	/*
+	 * Treatment of the fractional seconds of a date and time
+	 * decoded into "time.Time".  See <Object#Time>.
+	 */
+	Subseconds SubsecondMode
+	/*
+	 * Location of a date and time decoded into "time.Time",
+	 * or nil for the offset of a date and time string, and
+	 * UTC for an epoch date and time.
+	 */
+	TimeLocation *time.Location
+	/*
+	 * Maximum length in octets of the definite length text
+	 * strings decoded as shared string instances, or zero for
+	 * none.  Text strings of equal octets decoded by one call
+	 * to <DecOptions#Unmarshal> are one string, such that the
+	 * keys repeated by many maps are allocated once.
+	 */
+	InternStrings int
+	/*
+	 * Maximum size in octets of the data items decompressed
+	 * from compressed data items (<TagCompressed>), or zero for
+	 * no decompression.  The data items decompressed by one call
+	 * to <DecOptions#Unmarshal> share the maximum, rejecting a
+	 * larger decompression with <ErrorSizeExceeded>.  See
+	 * <EncodeCompressed>.
+	 */
+	Decompress int
+}
+/*
+ * Gordian dCBOR: reject data that is not exactly one data item
+ * in deterministic encoding with numeric reduction.  See
+ * Section 2.4 [dCBOR].
+ */
+func DecOptionsDCBOR() (DecOptions) {
+	return DecOptions{RejectTrailingBytes: true, DCBOR: true}
+}
+/*
+ * Decoding state of one call to <DecOptions#Unmarshal>.
+ */
+type decoding struct {
+
+	options DecOptions
+	/*
+	 * Text strings interned under "InternStrings".
+	 */
+	interned map[string]string
+	/*
+	 * Count of octets decompressed under "Decompress".
+	 */
+	decompressed int
+}
+/*
+ * Produce the GOPL value of the data item, as <Object#Decode>,
+ * interning text strings under "InternStrings".
+ */
+func (this *decoding) decode(o Object) (any) {
+	if 0 < this.options.InternStrings {
+		var decoder walkDecoder = walkDecoder{interning: this}
+		var _, e = o.walk(&decoder)
+		if Break == e {
+			return BreakMarker{}
+		} else {
+			return decoder.value
+		}
+	} else {
+		return o.Decode()
+	}
+}
+/*
+ * Text string of octets, shared by the text strings of equal
+ * octets not longer than "InternStrings".
+ */
+func (this *decoding) intern(text []byte) (string) {
+	if this.options.InternStrings < len(text) {
+		return string(text)
+	} else {
+		var value, ok = this.interned[string(text)]
+		if !ok {
+			if nil == this.interned {
+				this.interned = make(map[string]string)
+			}
+			value = string(text)
+			this.interned[value] = value
+		}
+		return value
+	}
+}
+/*
+ * Store object content into the value referenced by pointer
+ * under decoding options.  Data that is not well formed is
+ * rejected with the error of <Object#Read>.  See
+ * <Object#DecodeInto>.
+ */
+func (this DecOptions) Unmarshal(data []byte, v any) (error) {
+	var e error
+	data, e = this.prepare(data)
+	if nil != e {
+		return e
+	}
+	var state decoding = decoding{options: this}
+
+	return unmarshalPointer(Object(data),v,&state)
+}
+/*
+ * Validate the well formedness of data and its conformance to
+ * decoding policy, and resolve string references and mapped
+ * keys, as <DecOptions#Unmarshal>.
+ */
+func (this DecOptions) prepare(data []byte) ([]byte, error) {
+	/*
+	 * Well formedness, as <Object#Read>, and policy.
+	 */
+	var visitor walkVisitor
+	if this.restricted() {
+		visitor = &walkPolicy{options: &this}
+	}
+	var item, e = Object(data).walk(visitor)
+	if io.EOF == e {
+		return nil, ErrorTruncated
+	} else if nil != e {
+		return nil, e
+	} else if this.RejectTrailingBytes && len(item) != len(data) {
+		return nil, ErrorTrailingData
+	}
+	if this.DCBOR {
+		var options EncOptions = EncOptionsDCBOR()
+		var e error = Object(data).conforms(&options)
+		if nil != e {
+			return nil, e
+		}
+	}
+	if this.StringRefs {
+		var e error
+		data, e = Object(data).ResolveStringRefs()
+		if nil != e {
+			return nil, e
+		}
+	}
+	if nil != this.KeyMapper {
+		var e error
+		data, e = Object(data).ExpandKeys(this.KeyMapper)
+		if nil != e {
+			return nil, e
+		}
+	}
+	return data, nil
+}
+/*
+ * Determine whether decoding requires the validation of tags,
+ * simple values, floats, depth, size or map keys.
+ */
+func (this DecOptions) restricted() (bool) {
+	return (nil != this.AllowedTags || this.ForbidSimple || this.ForbidFloats || 0 < this.MaxDepth || 0 < this.MaxSize || this.RejectDuplicateKeys)
+}
+/*
+ * Visitor rejecting the tags, simple values, floats and depth
+ * excluded by decoding options, by their heads, before their
+ * content is read.
+ */
+type walkPolicy struct {
+
+	options *DecOptions
+	/*
+	 * Data items having nested data items, enclosing the
+	 * current data item, under "RejectDuplicateKeys".
+	 */
+	open []policyFrame
+}
+/*
+ * Keys of a map in canonical form, or nil for other data items,
+ * and count of nested data items traversed.
+ */
+type policyFrame struct {
+
+	keys map[string]bool
+
+	n int
+}
+/*
+ * Validate the head of the data item.
+ */
+func (this *walkPolicy) enter(head Object, depth int) (error) {
+	var major, ai, arg, _, e = ParseHead(head)
+	if nil != e {
+		return e
+	} else if 0 < this.options.MaxDepth && this.options.MaxDepth < depth {
+		return ErrorDepthExceeded
+	} else if this.options.RejectDuplicateKeys && opens(major,ai) {
+		var frame policyFrame
+		if MajorMap == major {
+			frame.keys = make(map[string]bool)
+		}
+		this.open = append(this.open,frame)
+	}
+	switch major {
+	case MajorTagged:
+		if nil != this.options.AllowedTags {
+			for _, number := range this.options.AllowedTags {
+				if number == arg {
+					return nil
+				}
+			}
+			return ErrorTagForbidden
+		}
+	case MajorSimple:
+		switch ai {
+		case 0x19, 0x1A, 0x1B:
+			if this.options.ForbidFloats {
+				return ErrorFloatForbidden
+			}
+		case 0x14, 0x15, 0x16:
+		default:
+			if this.options.ForbidSimple {
+				return ErrorSimpleForbidden
+			}
+		}
+	}
+	return nil
+}
+/*
+ * Validate the size of the data item, and the data item as a
+ * map key in canonical form, as it is traversed.
+ */
+func (this *walkPolicy) exit(item Object, depth int) (error) {
+	if 0 < this.options.MaxSize && this.options.MaxSize < len(item) {
+		return ErrorSizeExceeded
+	} else if this.options.RejectDuplicateKeys {
+		var major, ai, _, _, e = ParseHead(item)
+		if nil != e {
+			return e
+		} else if opens(major,ai) {
+			this.open = this.open[0:len(this.open)-1]
+		}
+		var top int = len(this.open)-1
+		if 0 <= top {
+			var parent *policyFrame = &this.open[top]
+			if nil != parent.keys && 0 == (parent.n & 1) {
+				var key Object
+				key, e = item.Canonical()
+				if nil != e {
+					return e
+				} else if parent.keys[string(key)] {
+					return ErrorDuplicateKey
+				} else {
+					parent.keys[string(key)] = true
+				}
+			}
+			parent.n += 1
+		}
+	}
+	return nil
+}
+/*
+ * Determine whether the head opens nested data items.
+ */
+func opens(major Major, ai byte) (bool) {
+	switch major {
+	case MajorArray, MajorMap, MajorTagged:
+		return true
+	case MajorBlob, MajorText:
+		return (0x1F == ai)
+	default:
+		return false
+	}
+}
+/*
+ * Store the first data item of data into the value referenced
+ * by pointer under decoding options, returning the data
+ * following the first data item.  The option
+ * "RejectTrailingBytes" is not applicable.
+ */
+func (this DecOptions) DecodeFirst(data []byte, v any) (rest []byte, e error) {
+	var z int
+	z, e = Object(data).ItemLen()
+	if nil != e {
+		return data, e
+	} else {
+		this.RejectTrailingBytes = false
+
+		e = this.Unmarshal(data[0:z],v)
+		if nil != e {
+			return data, e
+		} else {
+			return data[z:], nil
+		}
+	}
+}
+/*
+ * Store the first data item of data into the value referenced
+ * by pointer, returning the data following the first data
+ * item.
+ */
+func DecodeFirst(data []byte, v any) (rest []byte, e error) {
+	return DecOptions{}.DecodeFirst(data,v)
+}
+/*
+ * Retain the first failure of encoding.
+ */
+func (this *encoding) fail(e error) {
+	if nil == this.e {
+		this.e = e
+	}
+}
+/*
+ * Enter the encoding of a value, returning its reference when
+ * tracked, or failing with <ErrorCycle> when the value is being
+ * encoded.
+ */
+func (this *encoding) enter(a any) (key any, cycle bool) {
+	this.level += 1
+	if encodeCycleLevel < this.level {
+		var ok bool
+		key, ok = reference(a)
+		if !ok {
+			return nil, false
+		} else if this.visiting[key] {
+			this.fail(ErrorCycle)
+			return nil, true
+		} else {
+			if nil == this.visiting {
+				this.visiting = make(map[any]bool)
+			}
+			this.visiting[key] = true
+		}
+	}
+	return key, false
+}
+/*
+ * Exit the encoding of a value entered with reference key.
+ */
+func (this *encoding) exit(key any) {
+	this.level -= 1
+	if nil != key {
+		delete(this.visiting,key)
+	}
+}
@@ -0,0 +1,159 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR Encoding Options GOPL type reflection Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+type TypeTestSparse struct {
+
+	Name string `cbor:"name,omitempty"`
+
+	List []string `cbor:"list"`
+
+	Table map[string]string `cbor:"table"`
+}
+
+func TestOmitEmpty(t *testing.T){
+	var text TypeTestSparse
+
+	var expected []byte = []byte{0xA2,0x64,'l','i','s','t',0x80,0x65,'t','a','b','l','e',0xA0}
+	var code Object = Encode(text)
+	if !bytes.Equal(expected,code) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(code))
+	}
+
+	var e error
+	code, e = EncOptions{OmitEmpty: true}.Encode(text)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0xA0},code) {
+		t.Errorf("Expected encoding 'A0', found '%X'.",[]byte(code))
+	}
+
+	text.Name = "a"
+	code, e = EncOptions{OmitEmpty: true}.Encode(text)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0xA1,0x64,'n','a','m','e',0x61,'a'},code) {
+		t.Errorf("Expected encoding 'A1646E616D656161', found '%X'.",[]byte(code))
+	}
+}
+
+func TestNilContainers(t *testing.T){
+	var text TypeTestSparse
+
+	var expected []byte = []byte{0xA2,0x64,'l','i','s','t',0xF6,0x65,'t','a','b','l','e',0xF6}
+	var code, e = EncOptions{NilContainers: true}.Encode(text)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(expected,code) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(code))
+	}
+
+	code, e = EncOptions{NilContainers: true}.Encode([]any(nil))
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0xF6},code) {
+		t.Errorf("Expected encoding 'F6', found '%X'.",[]byte(code))
+	}
+
+	var check TypeTestSparse
+	e = Unmarshal(expected,&check)
+	if nil != e {
+		t.Fatal(e)
+	} else if nil != check.List || nil != check.Table {
+		t.Errorf("Expected nil containers, found '%v'.",check)
+	}
+}
+
+type TypeTestFirmwareV1 struct {
+
+	Name string `cbor:"name"`
+
+	Rate uint16 `cbor:"rate,default=10"`
+}
+
+type TypeTestFirmwareV2 struct {
+
+	Name string `cbor:"name"`
+
+	Rate uint16 `cbor:"rate,default=10"`
+
+	Mode string `cbor:"mode,default=normal, low power"`
+
+	Debug bool `cbor:"debug,omitempty"`
+
+	Level int8 `cbor:"level"`
+}
+
+func TestStructEvolution(t *testing.T){
+	var v1 Object = Encode(map[string]any{"name": "probe"})
+	var v2 TypeTestFirmwareV2
+	var e error = Unmarshal(v1,&v2)
+	if nil != e {
+		t.Fatal(e)
+	} else if 10 != v2.Rate || "normal, low power" != v2.Mode || "probe" != v2.Name {
+		t.Errorf("Expected defaults, found '%v'.",v2)
+	}
+	e = DecOptions{RequireAllFields: true}.Unmarshal(v1,&v2)
+	if !errors.Is(e,ErrorMissingField) || !strings.Contains(e.Error(),"level") {
+		t.Errorf("Expected '%v level', found '%v'.",ErrorMissingField,e)
+	}
+	e = DecOptions{RequireAllFields: true}.Unmarshal(Encode(map[string]any{"name": "probe", "level": -1}),&v2)
+	if nil != e {
+		t.Errorf("Expected success, found '%v'.",e)
+	}
+
+	var code Object = Encode(TypeTestFirmwareV2{Name: "probe", Rate: 20, Level: 2})
+	var check TypeTestFirmwareV1
+	e = Unmarshal(code,&check)
+	if nil != e {
+		t.Fatal(e)
+	} else if 20 != check.Rate {
+		t.Errorf("Expected rate 20, found %d.",check.Rate)
+	}
+	e = DecOptions{ErrorOnUnknownField: true}.Unmarshal(code,&check)
+	if !errors.Is(e,ErrorUnknownField) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorUnknownField,e)
+	}
+
+	var invalid struct {
+		Rate uint8 `cbor:"rate,default=1000"`
+	}
+	e = Unmarshal(Encode(map[string]any{}),&invalid)
+	if !errors.Is(e,ErrorFieldDefault) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorFieldDefault,e)
+	}
+}
+
+func TestInternStringsFields(t *testing.T){
+	var options DecOptions = DecOptions{InternStrings: 16}
+
+	var list []TypeTestSparse
+	var e error = options.Unmarshal(Encode([]any{map[string]any{"name": "probe"}, map[string]any{"name": "probe"}}),&list)
+	if nil != e {
+		t.Fatal(e)
+	} else if 2 != len(list) || "probe" != list[1].Name {
+		t.Fatalf("Expected two records, found '%v'.",list)
+	} else if unsafe.StringData(list[0].Name) != unsafe.StringData(list[1].Name) {
+		t.Errorf("Expected shared '%s', found distinct.",list[0].Name)
+	}
+
+	list = nil
+	e = DecOptions{}.Unmarshal(Encode([]any{map[string]any{"name": "probe"}, map[string]any{"name": "probe"}}),&list)
+	if nil != e {
+		t.Fatal(e)
+	} else if unsafe.StringData(list[0].Name) == unsafe.StringData(list[1].Name) {
+		t.Errorf("Expected distinct '%s', found shared.",list[0].Name)
+	}
+}
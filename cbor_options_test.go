@@ -0,0 +1,224 @@
+/*
+ * CBOR Encoding Options Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestCTAP2(t *testing.T){
+	var table OrderedMap = OrderedMap{
+		{"aa", uint8(1)},
+		{uint8(10), uint8(2)},
+		{"b", uint8(3)},
+		{uint16(1000), uint8(4)},
+	}
+	var expected []byte = []byte{0xA4,0x0A,0x02,0x19,0x03,0xE8,0x04,0x61,'b',0x03,0x62,'a','a',0x01}
+	var code, e = EncOptionsCTAP2().Encode(table)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(expected,code) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(code))
+	}
+
+	_, e = EncOptionsCTAP2().Encode(Base64URL("a"))
+	if ErrorTagForbidden != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorTagForbidden,e)
+	}
+
+	_, e = EncOptionsCTAP2().Encode([]any{[]any{[]any{[]any{}}}})
+	if nil != e {
+		t.Fatal(e)
+	}
+	_, e = EncOptionsCTAP2().Encode([]any{[]any{[]any{[]any{[]any{}}}}})
+	if ErrorDepthExceeded != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorDepthExceeded,e)
+	}
+}
+
+func TestFloatPolicy(t *testing.T){
+	var cases = []struct{ code Object; canonical Object }{
+		{Object{0xF9,0x80,0x00}, Object{0xF9,0x80,0x00}},
+		{Object{0xFB,0x80,0,0,0,0,0,0,0}, Object{0xF9,0x80,0x00}},
+		{Object{0xF9,0x7E,0x01}, Object{0xF9,0x7E,0x00}},
+		{Object{0xFA,0xFF,0xC0,0x00,0x01}, Object{0xF9,0x7E,0x00}},
+		{Object{0xFB,0x7F,0xF8,0,0,0,0,0,0x01}, Object{0xF9,0x7E,0x00}},
+		{Object{0xFA,0x7F,0x80,0x00,0x00}, Object{0xF9,0x7C,0x00}},
+	}
+	for _, c := range cases {
+		var canonical, e = c.code.Canonical()
+		if nil != e {
+			t.Errorf("Expected '%X' for '%X', found '%v'.",[]byte(c.canonical),[]byte(c.code),e)
+		} else if !bytes.Equal(c.canonical,canonical) {
+			t.Errorf("Expected '%X' for '%X', found '%X'.",[]byte(c.canonical),[]byte(c.code),[]byte(canonical))
+		}
+	}
+
+	var nan Object = Object{0xFA,0x7F,0xC0,0x00,0x01}
+	var code, e = nan.deterministic(&EncOptions{CanonicalNaN: true},0)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0xF9,0x7E,0x00},code) {
+		t.Errorf("Expected 'F97E00', found '%X'.",[]byte(code))
+	}
+	code, e = nan.deterministic(&EncOptions{Sort: SortBytewise},0)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(nan,code) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(nan),[]byte(code))
+	}
+
+	var options EncOptions = EncOptionsCTAP2()
+	for _, value := range []any{math.NaN(),math.Inf(1),[]any{float32(math.Inf(-1))}} {
+		_, e = options.Encode(value)
+		if ErrorFloatNonFinite != e {
+			t.Errorf("Expected '%v' for '%v', found '%v'.",ErrorFloatNonFinite,value,e)
+		}
+	}
+	code, e = options.Encode(1.1)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0xFB,0x3F,0xF1,0x99,0x99,0x99,0x99,0x99,0x9A},code) {
+		t.Errorf("Expected 'FB3FF199999999999A', found '%X'.",[]byte(code))
+	}
+}
+
+func TestTrailingData(t *testing.T){
+	var data []byte = []byte{0x01,0x61,'a',0xFF}
+	var n uint64
+	var e error = Unmarshal(data,&n)
+	if nil != e || 1 != n {
+		t.Errorf("Expected '1', found '%d' (%v).",n,e)
+	}
+	e = DecOptions{RejectTrailingBytes: true}.Unmarshal(data,&n)
+	if ErrorTrailingData != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorTrailingData,e)
+	}
+	e = DecOptions{RejectTrailingBytes: true}.Unmarshal(data[0:1],&n)
+	if nil != e {
+		t.Errorf("Expected success, found '%v'.",e)
+	}
+
+	var s string
+	var rest []byte
+	rest, e = DecodeFirst(data[1:],&s)
+	if nil != e || "a" != s || !bytes.Equal([]byte{0xFF},rest) {
+		t.Errorf("Expected 'a' and 'FF', found '%s' and '%X' (%v).",s,rest,e)
+	}
+	rest, e = DecodeFirst(rest,&s)
+	if !errors.Is(e,Break) || 1 != len(rest) {
+		t.Errorf("Expected '%v', found '%v'.",Break,e)
+	}
+}
+
+func TestDCBOR(t *testing.T){
+	for _, v := range []struct {
+		value float64
+		expected []byte
+	}{
+		{0.0, []byte{0x00}},
+		{math.Copysign(0,-1), []byte{0x00}},
+		{2.0, []byte{0x02}},
+		{-1.0, []byte{0x20}},
+		{1.5, []byte{0xF9,0x3E,0x00}},
+		{65536.0, []byte{0x1A,0x00,0x01,0x00,0x00}},
+		{0x1p64, []byte{0xFA,0x5F,0x80,0x00,0x00}},
+		{-0x1p64, []byte{0x3B,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF}},
+		{math.NaN(), []byte{0xF9,0x7E,0x00}},
+	} {
+		var code, e = EncOptionsDCBOR().Encode(v.value)
+		if nil != e {
+			t.Fatal(e)
+		} else if !bytes.Equal(v.expected,code) {
+			t.Errorf("Expected encoding '%X', found '%X'.",v.expected,[]byte(code))
+		}
+	}
+
+	var value float64
+	var e error = DecOptionsDCBOR().Unmarshal([]byte{0x02},&value)
+	if nil != e {
+		t.Fatal(e)
+	} else if 2.0 != value {
+		t.Errorf("Expected '2', found '%v'.",value)
+	}
+	for _, data := range [][]byte{
+		{0xF9,0x40,0x00},
+		{0x18,0x02},
+		{0x02,0x02},
+	} {
+		e = DecOptionsDCBOR().Unmarshal(data,&value)
+		if nil == e {
+			t.Errorf("Expected rejection of '%X'.",data)
+		}
+	}
+	/*
+	 * Conformance of deep nesting is linear in its length.
+	 */
+	var nested []byte = append(bytes.Repeat([]byte{0x81},20000),0x00)
+	var v any
+	e = DecOptionsDCBOR().Unmarshal(nested,&v)
+	if nil != e {
+		t.Errorf("Expected success, found '%v'.",e)
+	}
+	nested = append(bytes.Repeat([]byte{0xA2,0x61,'b'},5000),0x00)
+	nested = append(nested,bytes.Repeat([]byte{0x61,'a',0x00},5000)...)
+	e = DecOptionsDCBOR().Unmarshal(nested,&v)
+	if ErrorNotDeterministic != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorNotDeterministic,e)
+	}
+}
+
+func TestDecodePolicy(t *testing.T){
+	var value any
+	for _, c := range []struct{ options DecOptions; data []byte; expected error }{
+		{DecOptions{AllowedTags: []uint64{32}}, []byte{0x81,0xD8,0x20,0x61,'a'}, nil},
+		{DecOptions{AllowedTags: []uint64{32}}, []byte{0x81,0xC1,0x00}, ErrorTagForbidden},
+		{DecOptions{AllowedTags: []uint64{}}, []byte{0xD8,0x20,0x61,'a'}, ErrorTagForbidden},
+		{DecOptions{ForbidSimple: true}, []byte{0x83,0xF4,0xF5,0xF6}, nil},
+		{DecOptions{ForbidSimple: true}, []byte{0xA1,0x61,'a',0xF7}, ErrorSimpleForbidden},
+		{DecOptions{ForbidSimple: true}, []byte{0xF8,0xFF}, ErrorSimpleForbidden},
+		{DecOptions{ForbidSimple: true}, []byte{0xF9,0x3C,0x00}, nil},
+		{DecOptions{ForbidFloats: true}, []byte{0x82,0x01,0xFB,0x3F,0xF1,0x99,0x99,0x99,0x99,0x99,0x9A}, ErrorFloatForbidden},
+		{DecOptions{ForbidFloats: true}, []byte{0x82,0x01,0xF7}, nil},
+	} {
+		var e error = c.options.Unmarshal(c.data,&value)
+		if !errors.Is(e,c.expected) {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",c.expected,c.data,e)
+		}
+	}
+}
+
+func TestInternStrings(t *testing.T){
+	var long string = strings.Repeat("x",40)
+	var code Object = Encode([]any{
+		map[string]any{"name": "probe", "note": long},
+		map[string]any{"name": "probe", "note": long},
+	})
+	var options DecOptions = DecOptions{InternStrings: 16}
+
+	var value any
+	var e = options.Unmarshal(code,&value)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var records, _ = value.([]any)
+	if 2 != len(records) {
+		t.Fatalf("Expected two records, found '%v'.",value)
+	}
+	var a, b map[string]any = records[0].(map[string]any), records[1].(map[string]any)
+	var first, second string = a["name"].(string), b["name"].(string)
+	if "probe" != second || unsafe.StringData(first) != unsafe.StringData(second) {
+		t.Errorf("Expected shared '%s', found distinct.",first)
+	}
+	first, second = a["note"].(string), b["note"].(string)
+	if long != second || unsafe.StringData(first) == unsafe.StringData(second) {
+		t.Errorf("Expected distinct '%s', found shared.",long)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * CBOR RFC8949 Ordered Map
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-5.6
+ */
+package cbor
+
+/*
+ * Map entries in encoding order, for protocols depending on
+ * the order of map keys, and for maps having keys other than
+ * text strings.
+ */
+type OrderedMap []MapEntry
+/*
+ * Map key and value.
+ */
+type MapEntry struct {
+
+	Key any
+
+	Value any
+}
+/*
+ * Resolve the value of the first entry having key.
+ */
+func (this OrderedMap) Get(key any) (value any, ok bool) {
+	for _, entry := range this {
+		if keyEqual(entry.Key,key) {
+			return entry.Value, true
+		}
+	}
+	return nil, false
+}
+/*
+ * Map entries of the values of the keys and values of a map,
+ * in sequence.
+ */
+func orderedEntries(nested []any) (OrderedMap) {
+	var list OrderedMap = make(OrderedMap,0,len(nested)/2)
+	for n := 0; (n+1) < len(nested); n += 2 {
+		list = append(list,MapEntry{nested[n],nested[n+1]})
+	}
+	return list
+}
+/*
+ * Resolve object content as for <Object#Decode>, with every
+ * map as <OrderedMap>.
+ */
+func (this Object) DecodeOrdered() (any) {
+	return this.decodeOrdered()
+}
+/*
+ */
+func (this Object) decodeOrdered() (any) {
+	if this.HasTag() {
+		switch this.Major() {
+		case MajorArray:
+			var items, e = this.items()
+			if nil == e {
+				var list []any = make([]any,len(items))
+				for n, item := range items {
+					list[n] = item.decodeOrdered()
+				}
+				return list
+			}
+			return nil
+
+		case MajorMap:
+			var items, e = this.items()
+			if nil == e {
+				var list OrderedMap = make(OrderedMap,0,len(items)/2)
+				for n := 0; (n+1) < len(items); n += 2 {
+					list = append(list,MapEntry{items[n].decodeOrdered(),items[n+1].decodeOrdered()})
+				}
+				return list
+			}
+			return nil
+		}
+	}
+	return this.Decode()
+}
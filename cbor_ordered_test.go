@@ -0,0 +1,68 @@
+/*
+ * CBOR Ordered Map Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T){
+	var table OrderedMap = OrderedMap{{"z",uint8(1)},{"a",uint8(2)},{uint8(3),"c"}}
+	var expected []byte = []byte{0xA3,0x61,'z',0x01,0x61,'a',0x02,0x03,0x61,'c'}
+	var o Object = Encode(table)
+	if !bytes.Equal(expected,o) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(o))
+	}
+
+	var decoded, ok = o.Decode().(OrderedMap)
+	if !ok {
+		t.Fatalf("Expected 'OrderedMap', found '%T'.",o.Decode())
+	}
+	var value any
+	value, ok = decoded.Get(uint8(3))
+	if !ok || "c" != value {
+		t.Errorf("Expected 'c', found '%v'.",value)
+	}
+
+	var check OrderedMap
+	var e error = Unmarshal(Encode(map[string]any{"k": []any{OrderedMap{{"y",uint8(1)},{"x",uint8(2)}}}}),&check)
+	if nil != e {
+		t.Fatal(e)
+	} else if 1 != len(check) || "k" != check[0].Key {
+		t.Errorf("Expected '[{k ...}]', found '%v'.",check)
+	} else {
+		var inner OrderedMap = check[0].Value.([]any)[0].(OrderedMap)
+		if "y" != inner[0].Key || "x" != inner[1].Key {
+			t.Errorf("Expected '[{y 1} {x 2}]', found '%v'.",inner)
+		}
+	}
+	if !bytes.Equal(Encode(check),Encode(map[string]any{"k": []any{OrderedMap{{"y",uint8(1)},{"x",uint8(2)}}}})) {
+		t.Errorf("Expected re-encoding equal.")
+	}
+}
+
+func TestOrderedMapNested(t *testing.T){
+	/*
+	 * Maps having integer keys, nested to depth 1000, decoding
+	 * in time linear in their length.
+	 */
+	var code []byte
+	for n := 0; n < 1000; n++ {
+		code = append(code,0xA1,0x01)
+	}
+	code = append(code,0x02)
+	var value any = Object(code).Decode()
+	for n := 0; n < 1000; n++ {
+		var table, ok = value.(OrderedMap)
+		if !ok || 1 != len(table) || uint8(1) != table[0].Key {
+			t.Fatalf("Expected 'OrderedMap' at depth %d, found '%v'.",n,value)
+		}
+		value = table[0].Value
+	}
+	if uint8(2) != value {
+		t.Errorf("Expected '2', found '%v'.",value)
+	}
+}
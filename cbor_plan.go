@@ -0,0 +1,218 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR RFC8949 columnar decoding
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949
+ * https://pkg.go.dev/reflect
+ */
+package cbor
+
+import (
+	"fmt"
+	"reflect"
+)
+/*
+ * Precompiled decoding of a CBOR array of maps having the same
+ * keys, the rows of a table, into a slice of struct, or into a
+ * struct of slices, the columns of the table.  The fields of
+ * the row struct, and the slice fields of the column struct,
+ * are named by field tag "cbor" as for <Unmarshal>.  The map
+ * keys of each row are resolved by their encoding, without
+ * decoding, and the values of each row are decoded directly
+ * into their fields, without a map.
+ *
+ * A plan is safe for concurrent use.
+ */
+type Plan struct {
+
+	options DecOptions
+	/*
+	 * Type of the slice of rows, or of the struct of columns.
+	 */
+	target reflect.Type
+
+	columns bool
+
+	fields []field
+	/*
+	 * Position of field in list by the encoding of its name.
+	 */
+	keys map[string]int
+}
+/*
+ * Compile the plan of decoding into the type of the value, or
+ * of the value referenced by pointer, under decoding options.
+ */
+func (this DecOptions) NewPlan(v any) (*Plan, error) {
+	var t reflect.Type = reflect.TypeOf(v)
+	if nil != t && reflect.Pointer == t.Kind() {
+		t = t.Elem()
+	}
+	var plan *Plan = &Plan{options: this, target: t}
+	if nil == t {
+		return nil, ErrorPlanTarget
+
+	} else if reflect.Slice == t.Kind() && reflect.Struct == t.Elem().Kind() {
+		plan.fields = fields(t.Elem())
+
+	} else if reflect.Struct == t.Kind() {
+		plan.columns = true
+		plan.fields = fields(t)
+		for _, f := range plan.fields {
+			if reflect.Slice != t.Field(f.index).Type.Kind() {
+				return nil, fmt.Errorf(ErrorWrapField,ErrorPlanTarget,f.name)
+			}
+		}
+	} else {
+		return nil, ErrorPlanTarget
+	}
+	if 0 == len(plan.fields) {
+		return nil, ErrorPlanTarget
+	} else {
+		plan.keys = make(map[string]int,len(plan.fields))
+		for n, f := range plan.fields {
+			if !f.unknown {
+				plan.keys[string(Encode(f.name))] = n
+			}
+		}
+		return plan, nil
+	}
+}
+/*
+ * Compile the plan of decoding into the type of the value, or
+ * of the value referenced by pointer.
+ */
+func NewPlan(v any) (*Plan, error) {
+	return DecOptions{}.NewPlan(v)
+}
+/*
+ * Store the rows of the array into the slice of struct, or
+ * into the struct of slices, referenced by pointer.  The data
+ * is validated as <DecOptions#Unmarshal> under the options of
+ * the plan.  A map key having no field is subject to
+ * "ErrorOnUnknownField", and a field absent from a map is
+ * subject to its "default" and to "RequireAllFields".
+ */
+func (this *Plan) Unmarshal(data []byte, v any) (e error) {
+	var pointer reflect.Value = reflect.ValueOf(v)
+	if reflect.Pointer != pointer.Kind() || pointer.IsNil() || this.target != pointer.Elem().Type() {
+		return ErrorDecodeTarget
+	}
+	data, e = this.options.prepare(data)
+	if nil != e {
+		return e
+	}
+	var o Object = Object(data)
+	if MajorArray != o.Major() {
+		return &UnmarshalTypeError{o.MajorString(),this.target}
+	}
+	var rows []Object
+	rows, e = o.items()
+	if nil != e {
+		return e
+	}
+	var target reflect.Value = reflect.New(this.target).Elem()
+	if this.columns {
+		for _, f := range this.fields {
+			var column reflect.Value = target.Field(f.index)
+			column.Set(reflect.MakeSlice(column.Type(),len(rows),len(rows)))
+		}
+	} else {
+		target.Set(reflect.MakeSlice(this.target,len(rows),len(rows)))
+	}
+	var state decoding = decoding{options: this.options}
+	var unknown, preserve = fieldUnknown(this.fields)
+	var present []bool = make([]bool,len(this.fields))
+
+	for r, row := range rows {
+		if MajorMap != row.Major() {
+			return &UnmarshalTypeError{row.MajorString(),this.target}
+		}
+		var list []Object
+		list, e = row.items()
+		if nil != e {
+			return e
+		}
+		for n := range present {
+			present[n] = false
+		}
+		for n := 0; n < len(list); n += 2 {
+			var position, ok = this.field(list[n])
+			if ok {
+				present[position] = true
+				e = unmarshal(list[n+1],this.cell(target,r,position),&state)
+				if nil != e {
+					return e
+				}
+			} else if preserve && !this.columns {
+				e = unmarshalEntry(list[n],list[n+1],target.Index(r).Field(unknown.index),&state)
+				if nil != e {
+					return e
+				}
+			} else if this.options.ErrorOnUnknownField {
+				return fmt.Errorf(ErrorWrapField,ErrorUnknownField,list[n].String())
+			}
+		}
+		e = this.absent(target,r,present)
+		if nil != e {
+			return e
+		}
+	}
+	pointer.Elem().Set(target)
+	return nil
+}
+/*
+ * Resolve the position of the field of the map key, by the
+ * encoding of the key, or by its text as <Unmarshal>.
+ */
+func (this *Plan) field(key Object) (position int, ok bool) {
+	position, ok = this.keys[string(key)]
+	if !ok {
+		var name string
+		name, ok = key.Decode().(string)
+		if ok {
+			var f field
+			f, ok = fieldNamed(this.fields,name)
+			if ok {
+				return fieldIndex(this.fields,f), true
+			}
+		}
+	}
+	return position, ok
+}
+/*
+ * Value of the field of the row.
+ */
+func (this *Plan) cell(target reflect.Value, row int, position int) (reflect.Value) {
+	var f field = this.fields[position]
+	if this.columns {
+		return target.Field(f.index).Index(row)
+	} else {
+		return target.Index(row).Field(f.index)
+	}
+}
+/*
+ * Assign the default values of the fields of the row absent
+ * from its map, or fail for an absent field required by
+ * "RequireAllFields".
+ */
+func (this *Plan) absent(target reflect.Value, row int, present []bool) (e error) {
+	for n, f := range this.fields {
+		if present[n] || f.unknown {
+			continue
+		} else if f.defaulted {
+			e = fieldDefault(this.cell(target,row,n),f.value)
+			if nil != e {
+				return fmt.Errorf(ErrorWrapField,e,f.name)
+			}
+		} else if this.options.RequireAllFields && !f.omitempty {
+			return fmt.Errorf(ErrorWrapField,ErrorMissingField,f.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR Columnar Decoding Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type TypeTestReading struct {
+
+	Sensor string `cbor:"sensor"`
+
+	Time int64 `cbor:"time"`
+
+	Value float64 `cbor:"value"`
+
+	Unit string `cbor:"unit,default=C"`
+
+	Extra map[string]any `cbor:",unknown"`
+}
+
+type TypeTestReadings struct {
+
+	Sensor []string `cbor:"sensor"`
+
+	Time []int64 `cbor:"time"`
+
+	Value []float64 `cbor:"value"`
+
+	Unit []string `cbor:"unit,default=C"`
+}
+
+func TestPlan(t *testing.T){
+	var code Object = Encode([]any{
+		map[string]any{"sensor": "a", "time": 1700000000, "value": 20.5},
+		map[string]any{"sensor": "b", "time": 1700000001, "value": 21, "unit": "F", "site": "north"},
+		map[string]any{"Sensor": "c", "time": 1700000002, "value": -1.5},
+	})
+
+	var plan, e = NewPlan(&[]TypeTestReading{})
+	if nil != e {
+		t.Fatal(e)
+	}
+	var rows []TypeTestReading
+	e = plan.Unmarshal(code,&rows)
+	if nil != e {
+		t.Fatal(e)
+	} else if 3 != len(rows) {
+		t.Fatalf("Expected 3 rows, found '%v'.",rows)
+	}
+	if "a" != rows[0].Sensor || 1700000000 != rows[0].Time || 20.5 != rows[0].Value || "C" != rows[0].Unit {
+		t.Errorf("Expected '{a 1700000000 20.5 C}', found '%v'.",rows[0])
+	}
+	if 21 != rows[1].Value || "F" != rows[1].Unit || "north" != rows[1].Extra["site"] {
+		t.Errorf("Expected '{b 1700000001 21 F map[site:north]}', found '%v'.",rows[1])
+	}
+	if "c" != rows[2].Sensor {
+		t.Errorf("Expected sensor 'c', found '%v'.",rows[2])
+	}
+
+	plan, e = NewPlan(TypeTestReadings{})
+	if nil != e {
+		t.Fatal(e)
+	}
+	var columns TypeTestReadings
+	e = plan.Unmarshal(code,&columns)
+	if nil != e {
+		t.Fatal(e)
+	} else if 3 != len(columns.Sensor) || 3 != len(columns.Time) || 3 != len(columns.Value) || 3 != len(columns.Unit) {
+		t.Fatalf("Expected 3 rows, found '%v'.",columns)
+	}
+	if "b" != columns.Sensor[1] || 1700000002 != columns.Time[2] || -1.5 != columns.Value[2] || "C" != columns.Unit[0] || "F" != columns.Unit[1] {
+		t.Errorf("Expected columns, found '%v'.",columns)
+	}
+
+	for _, v := range []any{nil, 1, []int{}, struct{ Name string }{}, struct{}{}} {
+		_, e = NewPlan(v)
+		if !errors.Is(e,ErrorPlanTarget) {
+			t.Errorf("[%T] Expected '%v', found '%v'.",v,ErrorPlanTarget,e)
+		}
+	}
+	e = plan.Unmarshal(code,&rows)
+	if ErrorDecodeTarget != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorDecodeTarget,e)
+	}
+	e = plan.Unmarshal(Encode([]any{1}),&columns)
+	var typeError *UnmarshalTypeError
+	if !errors.As(e,&typeError) {
+		t.Errorf("Expected '%T', found '%v'.",typeError,e)
+	}
+
+	plan, _ = DecOptions{ErrorOnUnknownField: true}.NewPlan(&columns)
+	e = plan.Unmarshal(code,&columns)
+	if !errors.Is(e,ErrorUnknownField) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorUnknownField,e)
+	}
+	plan, _ = DecOptions{RequireAllFields: true}.NewPlan(&columns)
+	e = plan.Unmarshal(Encode([]any{map[string]any{"sensor": "a"}}),&columns)
+	if !errors.Is(e,ErrorMissingField) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorMissingField,e)
+	}
+}
+
+func BenchmarkPlan(b *testing.B){
+	var list []any
+	for n := 0; n < 100; n++ {
+		list = append(list,map[string]any{"sensor": fmt.Sprintf("s%d",n%4), "time": 1700000000+n, "value": float64(n)/4})
+	}
+	var code Object = Encode(list)
+	var plan, _ = DecOptions{InternStrings: 8}.NewPlan(&TypeTestReadings{})
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		var columns TypeTestReadings
+		plan.Unmarshal(code,&columns)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * CBOR RFC8949 Path Query
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc9535
+ */
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+/*
+ * Validation errors produced by <Object#Query>.
+ */
+var ErrorQuerySyntax error = errors.New("CBOR Query syntax")
+/*
+ * One step of a path query.  The key is a text string map key
+ * or an unsigned integer array index or map key.
+ */
+type queryStep struct {
+
+	recursive bool
+
+	wildcard bool
+
+	key any
+}
+/*
+ * Select the data items found by path expression from the
+ * (first) data item of the object.  The path expression begins
+ * with the root "$", followed by member steps ".name" or
+ * "['name']", index steps "[0]", wildcard steps ".*" or "[*]",
+ * and recursive descent steps "..name", "..*" or "..[0]".
+ * Names select map values by text key, indices select array
+ * elements or map values by unsigned integer key, and tags are
+ * transparent.
+ */
+func (this Object) Query(path string) (list []Object, e error) {
+	var steps []queryStep
+	steps, e = parseQuery(path)
+	if nil != e {
+		return nil, e
+	} else {
+		var item Object
+		item, e = this.first()
+		if nil != e {
+			return nil, e
+		}
+		list = []Object{item}
+		for _, step := range steps {
+			var next []Object = []Object{}
+			for _, node := range list {
+				if step.recursive {
+					var descendants []Object
+					descendants, e = node.descendants()
+					if nil != e {
+						return nil, e
+					}
+					for _, descendant := range descendants {
+						next, e = descendant.queryStep(step,next)
+						if nil != e {
+							return nil, e
+						}
+					}
+				} else {
+					next, e = node.queryStep(step,next)
+					if nil != e {
+						return nil, e
+					}
+				}
+			}
+			list = next
+		}
+		return list, nil
+	}
+}
+/*
+ * Resolve the (first) data item of the object.
+ */
+func (this Object) first() (Object, error) {
+	var z, e = this.ItemLen()
+	if nil != e {
+		return nil, e
+	} else {
+		return this[0:z], nil
+	}
+}
+/*
+ * Resolve the content of a tagged object, through any number
+ * of tags.
+ */
+func (this Object) untagged() (Object) {
+	for {
+		var _, content, ok = this.tagged()
+		if ok {
+			this = content
+		} else {
+			return this
+		}
+	}
+}
+/*
+ * Append the data items selected by step from the object to
+ * list.
+ */
+func (this Object) queryStep(step queryStep, list []Object) ([]Object, error) {
+	var node Object = this.untagged()
+	var major Major = node.Major()
+	if MajorArray != major && MajorMap != major {
+		return list, nil
+	}
+	var items, e = node.items()
+	if nil != e {
+		return nil, e
+
+	} else if MajorArray == major {
+		if step.wildcard {
+			list = append(list,items...)
+		} else {
+			var index, ok = step.key.(uint64)
+			if ok && index < uint64(len(items)) {
+				list = append(list,items[index])
+			}
+		}
+		return list, nil
+	} else {
+		var key string
+		if !step.wildcard {
+			key = indexKey(Encode(step.key))
+		}
+		for n := 0; (n+1) < len(items); n += 2 {
+			if step.wildcard || key == indexKey(items[n]) {
+				list = append(list,items[n+1])
+			}
+		}
+		return list, nil
+	}
+}
+/*
+ * Resolve the object and all of its nested array elements and
+ * map values, in document order.
+ */
+func (this Object) descendants() (list []Object, e error) {
+	list = []Object{this}
+	var children []Object
+	children, e = this.queryStep(queryStep{wildcard: true},nil)
+	if nil != e {
+		return nil, e
+	} else {
+		for _, child := range children {
+			var nested []Object
+			nested, e = child.descendants()
+			if nil != e {
+				return nil, e
+			} else {
+				list = append(list,nested...)
+			}
+		}
+		return list, nil
+	}
+}
+/*
+ * Parse path expression into steps.
+ */
+func parseQuery(path string) (steps []queryStep, e error) {
+	if !strings.HasPrefix(path,"$") {
+		return nil, fmt.Errorf("%w: %q",ErrorQuerySyntax,path)
+	}
+	var x, z int = 1, len(path)
+	for x < z {
+		var step queryStep
+		if '.' == path[x] {
+			x += 1
+			if x < z && '.' == path[x] {
+				step.recursive = true
+				x += 1
+			}
+			if x < z && '[' == path[x] && step.recursive {
+				/* "..[...]"
+				 */
+			} else {
+				var end int = x
+				for end < z && '.' != path[end] && '[' != path[end] {
+					end += 1
+				}
+				var name string = path[x:end]
+				if "" == name {
+					return nil, fmt.Errorf("%w: %q",ErrorQuerySyntax,path)
+				} else if "*" == name {
+					step.wildcard = true
+				} else {
+					step.key = name
+				}
+				x = end
+				steps = append(steps,step)
+				continue
+			}
+		}
+		if x < z && '[' == path[x] {
+			var end int = strings.IndexByte(path[x:],']')
+			if 0 > end {
+				return nil, fmt.Errorf("%w: %q",ErrorQuerySyntax,path)
+			}
+			var selector string = path[x+1:x+end]
+			x += end+1
+
+			if "*" == selector {
+				step.wildcard = true
+
+			} else if 2 <= len(selector) && ('\'' == selector[0] || '"' == selector[0]) && selector[0] == selector[len(selector)-1] {
+				step.key = selector[1:len(selector)-1]
+			} else {
+				var index, e = strconv.ParseUint(selector,10,64)
+				if nil != e {
+					return nil, fmt.Errorf("%w: %q",ErrorQuerySyntax,path)
+				} else {
+					step.key = index
+				}
+			}
+			steps = append(steps,step)
+		} else {
+			return nil, fmt.Errorf("%w: %q",ErrorQuerySyntax,path)
+		}
+	}
+	return steps, nil
+}
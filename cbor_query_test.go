@@ -0,0 +1,56 @@
+/*
+ * CBOR Path Query Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuery(t *testing.T){
+	var document map[string]any = map[string]any{
+		"devices": []any{
+			map[string]any{"id": "a", "port": map[string]any{"id": "p"}},
+			map[string]any{"id": "b"},
+		},
+	}
+	var o Object = Encode(document)
+
+	var cases = []struct{ path string; expected []string }{
+		{"$.devices[*].id", []string{"a","b"}},
+		{"$['devices'][1].id", []string{"b"}},
+		{"$.devices[2].id", []string{}},
+		{"$..port.id", []string{"p"}},
+		{"$.devices[0].*.id", []string{"p"}},
+	}
+	for _, c := range cases {
+		var list, e = o.Query(c.path)
+		if nil != e {
+			t.Fatal(e)
+		} else if len(c.expected) != len(list) {
+			t.Errorf("Expected %d for '%s', found %d.",len(c.expected),c.path,len(list))
+		} else {
+			for n, text := range c.expected {
+				if text != list[n].MustText() {
+					t.Errorf("Expected '%s' for '%s', found '%s'.",text,c.path,list[n].MustText())
+				}
+			}
+		}
+	}
+
+	var list, e = o.Query("$..id")
+	if nil != e {
+		t.Fatal(e)
+	} else if 3 != len(list) {
+		t.Errorf("Expected 3 for '$..id', found %d.",len(list))
+	}
+
+	for _, path := range []string{"devices","$.","$[x]","$[0"} {
+		_, e = o.Query(path)
+		if !errors.Is(e,ErrorQuerySyntax) {
+			t.Errorf("Expected '%v' for '%s', found '%v'.",ErrorQuerySyntax,path,e)
+		}
+	}
+}
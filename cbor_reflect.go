@@ -0,0 +1,881 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR RFC8949 GOPL type reflection
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949
+ * https://pkg.go.dev/reflect
+ */
+package cbor
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"net/netip"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+/*
+ * Decoded content not representable by target type, including
+ * numeric content out of range of the target type.
+ */
+type UnmarshalTypeError struct {
+	/*
+	 * Description of CBOR content.
+	 */
+	Value string
+	/*
+	 * GOPL target type.
+	 */
+	Type reflect.Type
+}
+/*
+ * Struct field coding is named by field tag "cbor", i.e.
+ *
+ *     Name string `cbor:"name"`
+ *
+ * A field tagged "-" is excluded.  An untagged field is named
+ * by its GOPL identifier.
+ *
+ * A field tagged with option "omitempty", i.e.
+ *
+ *     Name string `cbor:"name,omitempty"`
+ *
+ * is omitted from encoding when empty.
+ *
+ * A map field tagged with option "unknown", i.e.
+ *
+ *     Extensions map[string]RawMessage `cbor:",unknown"`
+ *
+ * receives the map entries not matching another field on
+ * decode, and contributes its entries on encode, preserving
+ * extension fields across a round trip.
+ *
+ * A boolean, numeric or string field tagged with option
+ * "default", i.e.
+ *
+ *     Rate uint16 `cbor:"rate,default=10"`
+ *
+ * is assigned the value of the option on decode when absent
+ * from the map.  The option is last, and its value extends to
+ * the end of the tag.
+ */
+const fieldTag string = "cbor"
+const fieldOptionOmitEmpty string = "omitempty"
+const fieldOptionUnknown string = "unknown"
+const fieldOptionDefault string = "default="
+/*
+ * Struct field coding.
+ */
+type field struct {
+
+	name string
+
+	index int
+
+	omitempty bool
+
+	unknown bool
+
+	defaulted bool
+
+	value string
+}
+/*
+ * Resolve codable fields of struct type.
+ */
+func fields(t reflect.Type) (list []field) {
+	var n, z int = 0, t.NumField()
+	for ; n < z; n++ {
+		var f reflect.StructField = t.Field(n)
+		if f.IsExported() {
+			var name string = f.Name
+			var omitempty, unknown, defaulted bool = false, false, false
+			var value string
+			var tag string = f.Tag.Get(fieldTag)
+			if "-" == tag {
+				continue
+			} else if "" != tag {
+				var options []string = strings.Split(tag,",")
+				if "" != options[0] {
+					name = options[0]
+				}
+				for o, option := range options[1:] {
+					if fieldOptionOmitEmpty == option {
+						omitempty = true
+					} else if fieldOptionUnknown == option && reflect.Map == f.Type.Kind() {
+						unknown = true
+					} else if strings.HasPrefix(option,fieldOptionDefault) {
+						defaulted = true
+						value = strings.TrimPrefix(strings.Join(options[1+o:],","),fieldOptionDefault)
+						break
+					}
+				}
+			}
+			list = append(list,field{name,n,omitempty,unknown,defaulted,value})
+		}
+	}
+	return list
+}
+/*
+ * Resolve struct field by name, preferring an exact match.
+ */
+func fieldNamed(list []field, name string) (f field, ok bool) {
+	for _, f = range list {
+		if name == f.name && !f.unknown {
+			return f, true
+		}
+	}
+	for _, f = range list {
+		if strings.EqualFold(name,f.name) && !f.unknown {
+			return f, true
+		}
+	}
+	return f, false
+}
+/*
+ * Resolve the position of struct field in list.
+ */
+func fieldIndex(list []field, f field) (int) {
+	for n, g := range list {
+		if f.index == g.index {
+			return n
+		}
+	}
+	return -1
+}
+/*
+ * Assign the default values of the struct fields absent from
+ * the map, or fail for an absent field required by
+ * "RequireAllFields".
+ */
+func unmarshalAbsent(list []field, present []bool, target reflect.Value, state *decoding) (e error) {
+	for n, f := range list {
+		if present[n] || f.unknown {
+			continue
+		} else if f.defaulted {
+			e = fieldDefault(target.Field(f.index),f.value)
+			if nil != e {
+				return fmt.Errorf(ErrorWrapField,e,f.name)
+			}
+		} else if state.options.RequireAllFields && !f.omitempty {
+			return fmt.Errorf(ErrorWrapField,ErrorMissingField,f.name)
+		}
+	}
+	return nil
+}
+/*
+ * Assign the default value of the field tag option to the
+ * field.
+ */
+func fieldDefault(v reflect.Value, value string) (e error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		var b bool
+		b, e = strconv.ParseBool(value)
+		if nil == e {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, e = strconv.ParseInt(value,0,v.Type().Bits())
+		if nil == e {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		u, e = strconv.ParseUint(value,0,v.Type().Bits())
+		if nil == e {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, e = strconv.ParseFloat(value,v.Type().Bits())
+		if nil == e {
+			v.SetFloat(f)
+		}
+	case reflect.String:
+		v.SetString(value)
+	default:
+		return ErrorFieldDefault
+	}
+	if nil != e {
+		return ErrorFieldDefault
+	} else {
+		return nil
+	}
+}
+/*
+ * Resolve struct field receiving unknown map entries.
+ */
+func fieldUnknown(list []field) (f field, ok bool) {
+	for _, f = range list {
+		if f.unknown {
+			return f, true
+		}
+	}
+	return f, false
+}
+/*
+ * Define object content for values not recognized by
+ * <Encode>, by reflection.
+ */
+func encodeReflect(a any, state *encoding) (Object) {
+	return encodeValue(reflect.ValueOf(a),state)
+}
+/*
+ * Identity of a pointer, map or slice being encoded, for
+ * cycle detection.  Slices sharing an array are distinct by
+ * length.
+ */
+type encodeReference struct {
+
+	kind reflect.Type
+
+	pointer uintptr
+
+	length int
+}
+/*
+ * Identify the reference of a non-nil pointer, map or slice.
+ */
+func reference(a any) (any, bool) {
+	var v reflect.Value = reflect.ValueOf(a)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map:
+		if !v.IsNil() {
+			return encodeReference{v.Type(),v.Pointer(),0}, true
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			return encodeReference{v.Type(),v.Pointer(),v.Len()}, true
+		}
+	}
+	return nil, false
+}
+/*
+ * Define object content for values not recognized by
+ * <Encode>, tagged when their type is registered.  See
+ * <RegisterType>.
+ */
+func encodeValue(v reflect.Value, state *encoding) (Object) {
+	if v.IsValid() {
+		var number, ok = typeTag(v.Type())
+		if ok {
+			return tagging(number,encodeContent(v,state))
+		}
+	}
+	return encodeContent(v,state)
+}
+/*
+ * Define object content for values not recognized by
+ * <Encode>, or "undefined".
+ */
+func encodeContent(v reflect.Value, state *encoding) (this Object) {
+	if reflect.Pointer != v.Kind() && v.CanAddr() && v.Addr().Type().Implements(typeMarshaler) {
+		return marshal(v.Addr().Interface().(Marshaler),state)
+
+	} else if reflect.Pointer != v.Kind() && v.IsValid() && reflect.PointerTo(v.Type()).Implements(typeCoder) {
+		var p reflect.Value = reflect.New(v.Type())
+		p.Elem().Set(v)
+		return encodeCoder(p.Interface().(Coder),state)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return Object{0xF5}
+		} else {
+			return Object{0xF4}
+		}
+
+	case reflect.String:
+		return encode(v.String(),state)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return EncodeInt64(nil,v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return define(MajorUint,v.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		return encodeFloatShortest(v.Float())
+
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			var null Object = Object{0xF6}
+			return null
+		} else {
+			return encode(v.Elem().Interface(),state)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if reflect.Slice == v.Kind() && v.IsNil() && state.options.NilContainers {
+			var null Object = Object{0xF6}
+			return null
+		}
+		var n, z int = 0, v.Len()
+		if reflect.Uint8 == v.Type().Elem().Kind() {
+			var data []byte = make([]byte,z)
+			for ; n < z; n++ {
+				data[n] = byte(v.Index(n).Uint())
+			}
+			return encode(data,state)
+		} else {
+			this = define(MajorArray,uint64(z))
+			for ; n < z; n++ {
+				this = encode(v.Index(n).Interface(),state).AppendTo(this)
+			}
+			return this
+		}
+
+	case reflect.Map:
+		if v.IsNil() && state.options.NilContainers {
+			var null Object = Object{0xF6}
+			return null
+		}
+		var entries []encodedEntry = make([]encodedEntry,0,v.Len())
+		var iter *reflect.MapIter = v.MapRange()
+		for iter.Next() {
+			var key Object = encode(iter.Key().Interface(),state)
+			entries = append(entries,encodedEntry{key,encode(iter.Value().Interface(),state),key.ordering()})
+		}
+		/*
+		 * Deterministic (bytewise lexicographic) key order.
+		 */
+		sort.Slice(entries,func(i, j int) bool {
+			return 0 > bytes.Compare(entries[i].ordering,entries[j].ordering)
+		})
+
+		this = define(MajorMap,uint64(len(entries)))
+		for _, entry := range entries {
+			this = entry.key.AppendTo(this)
+			this = entry.value.AppendTo(this)
+		}
+		return this
+
+	case reflect.Struct:
+		var list []field = fields(v.Type())
+		var content Object
+		var count uint64
+
+		for _, f := range list {
+			if (f.omitempty || state.options.OmitEmpty) && empty(v.Field(f.index)) {
+				continue
+			} else if !f.unknown {
+				content = encode(f.name,state).AppendTo(content)
+
+				content = encode(v.Field(f.index).Interface(),state).AppendTo(content)
+
+				count += 1
+			}
+		}
+
+		var unknown, ok = fieldUnknown(list)
+		if ok {
+			var table reflect.Value = v.Field(unknown.index)
+			var entries []encodedEntry = make([]encodedEntry,0,table.Len())
+			var iter *reflect.MapIter = table.MapRange()
+			for iter.Next() {
+				var name, isname = iter.Key().Interface().(string)
+				if isname {
+					if _, ok = fieldNamed(list,name); ok {
+						continue
+					}
+				}
+				var key Object = encode(iter.Key().Interface(),state)
+				entries = append(entries,encodedEntry{key,encode(iter.Value().Interface(),state),key})
+			}
+
+			sort.Slice(entries,func(i, j int) bool {
+				return 0 > bytes.Compare(entries[i].ordering,entries[j].ordering)
+			})
+
+			for _, entry := range entries {
+				content = entry.key.AppendTo(content)
+
+				content = entry.value.AppendTo(content)
+
+				count += 1
+			}
+		}
+
+		this = define(MajorMap,count)
+		if 0 < count {
+			this = this.Concatenate(content)
+		}
+		return this
+
+	default:
+		var undefined Object = Object{0xF7}
+		return undefined
+	}
+}
+/*
+ * Encoded map entry, and the encoding ordering its key, as
+ * computed once before sorting.
+ */
+type encodedEntry struct {
+
+	key, value, ordering Object
+}
+/*
+ * Equality of <OrderedMap> keys.
+ */
+func keyEqual(a, b any) (bool) {
+	return reflect.DeepEqual(a,b)
+}
+/*
+ * Target types of <UnmarshalTypeError>.
+ */
+var typeUint64 reflect.Type = reflect.TypeOf(uint64(0))
+var typeInt64 reflect.Type = reflect.TypeOf(int64(0))
+var typeFloat64 reflect.Type = reflect.TypeOf(float64(0))
+var typeBigInt reflect.Type = reflect.TypeOf(big.Int{})
+var typeTime reflect.Type = reflect.TypeOf(time.Time{})
+var typeDate reflect.Type = reflect.TypeOf(Date{})
+var typeDays reflect.Type = reflect.TypeOf(Days(0))
+var typeAddr reflect.Type = reflect.TypeOf(netip.Addr{})
+var typePrefix reflect.Type = reflect.TypeOf(netip.Prefix{})
+var typeBytes reflect.Type = reflect.TypeOf([]byte{})
+var typeBool reflect.Type = reflect.TypeOf(false)
+var typeItems reflect.Type = reflect.TypeOf([]Object{})
+var typeEntries reflect.Type = reflect.TypeOf([][2]Object{})
+var typeObject reflect.Type = reflect.TypeOf(Object{})
+var typeRawMessage reflect.Type = reflect.TypeOf(RawMessage{})
+var typeOrderedMap reflect.Type = reflect.TypeOf(OrderedMap{})
+var typeMarshaler reflect.Type = reflect.TypeOf((*Marshaler)(nil)).Elem()
+var typeUnmarshaler reflect.Type = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+var typeCoder reflect.Type = reflect.TypeOf((*Coder)(nil)).Elem()
+/*
+ * Store object content into the value referenced by pointer.
+ */
+func unmarshalPointer(o Object, v any, state *decoding) (error) {
+	var pointer reflect.Value = reflect.ValueOf(v)
+	if reflect.Pointer != pointer.Kind() || pointer.IsNil() {
+		return ErrorDecodeTarget
+	} else {
+		return unmarshal(o,pointer.Elem(),state)
+	}
+}
+/*
+ */
+func unmarshal(o Object, target reflect.Value, state *decoding) (e error) {
+	switch target.Type() {
+	case typeObject:
+		target.Set(reflect.ValueOf(append(Object{},o...)))
+		return nil
+
+	case typeRawMessage:
+		target.Set(reflect.ValueOf(append(RawMessage{},o...)))
+		return nil
+	}
+
+	o, e = state.uncompressed(o)
+	if nil != e {
+		return e
+	}
+
+	switch target.Type() {
+	case typeTime:
+		if o.IsNull() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		} else {
+			var value, e = o.time(&state.options)
+			if nil != e {
+				return e
+			} else {
+				target.Set(reflect.ValueOf(value))
+				return nil
+			}
+		}
+	case typeDate, typeDays:
+		if o.IsNull() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		} else {
+			var date, e = o.Date()
+			if nil != e {
+				return e
+			} else if typeDays == target.Type() {
+				target.Set(reflect.ValueOf(date.Days()))
+				return nil
+			} else {
+				target.Set(reflect.ValueOf(date))
+				return nil
+			}
+		}
+	case typeAddr, typePrefix:
+		if o.IsNull() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		} else {
+			var value, e = o.ip()
+			if nil != e {
+				return e
+			} else if reflect.TypeOf(value) != target.Type() {
+				return &UnmarshalTypeError{o.MajorString(),target.Type()}
+			} else {
+				target.Set(reflect.ValueOf(value))
+				return nil
+			}
+		}
+	case typeBigInt:
+		if o.IsNull() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		} else {
+			var value, e = o.BigInt()
+			if nil != e {
+				return e
+			} else {
+				target.Set(reflect.ValueOf(*value))
+				return nil
+			}
+		}
+	case typeOrderedMap:
+		if o.IsNull() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		} else if MajorMap != o.Major() {
+			return &UnmarshalTypeError{o.MajorString(),target.Type()}
+		} else {
+			target.Set(reflect.ValueOf(o.decodeOrdered()))
+			return nil
+		}
+	}
+
+	if reflect.Pointer != target.Kind() && target.CanAddr() && target.Addr().Type().Implements(typeUnmarshaler) {
+		var unmarshaler Unmarshaler = target.Addr().Interface().(Unmarshaler)
+		return unmarshaler.UnmarshalCBOR(append([]byte{},o...))
+	}
+
+	if reflect.Pointer != target.Kind() && target.CanAddr() && target.Addr().Type().Implements(typeCoder) {
+		var coder Coder = target.Addr().Interface().(Coder)
+		return coder.Decode(coderContent(coder,o))
+	}
+
+	o = typeContent(target.Type(),o)
+
+	var ok bool
+	switch target.Kind() {
+	case reflect.Pointer:
+		if o.IsNull() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		} else {
+			if target.IsNil() {
+				target.Set(reflect.New(target.Type().Elem()))
+			}
+			return unmarshal(o,target.Elem(),state)
+		}
+
+	case reflect.Interface:
+		if o.IsNull() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+
+		} else if !target.IsNil() && reflect.Pointer == target.Elem().Kind() && !target.Elem().IsNil() {
+			/*
+			 * Decode into the value referenced by the
+			 * pointer held by the interface.
+			 */
+			return unmarshal(o,target.Elem().Elem(),state)
+		} else {
+			return unmarshalContent(state.decode(o),target,state)
+		}
+
+	case reflect.Struct:
+		if MajorMap != o.Major() {
+			return &UnmarshalTypeError{o.MajorString(),target.Type()}
+		} else {
+			var list []Object
+			list, e = o.items()
+			if nil != e {
+				return e
+			} else {
+				var flist []field = fields(target.Type())
+				var unknown field
+				var preserve bool
+				unknown, preserve = fieldUnknown(flist)
+
+				var present []bool = make([]bool,len(flist))
+				var n, z int = 0, len(list)
+				for ; n < z; n += 2 {
+					var name string
+					name, ok = list[n].Decode().(string)
+					if ok {
+						var f field
+						f, ok = fieldNamed(flist,name)
+						if ok {
+							present[fieldIndex(flist,f)] = true
+							e = unmarshal(list[n+1],target.Field(f.index),state)
+							if nil != e {
+								return e
+							}
+						}
+					}
+					if !ok && preserve {
+						e = unmarshalEntry(list[n],list[n+1],target.Field(unknown.index),state)
+						if nil != e {
+							return e
+						}
+					} else if !ok && state.options.ErrorOnUnknownField {
+						return fmt.Errorf(ErrorWrapField,ErrorUnknownField,list[n].String())
+					}
+				}
+				return unmarshalAbsent(flist,present,target,state)
+			}
+		}
+
+	case reflect.Slice:
+		if reflect.Uint8 == target.Type().Elem().Kind() && MajorArray != o.Major() {
+			return unmarshalContent(state.decode(o),target,state)
+
+		} else if o.IsNull() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+
+		} else if MajorArray != o.Major() {
+			return &UnmarshalTypeError{o.MajorString(),target.Type()}
+		} else {
+			var list []Object
+			list, e = o.items()
+			if nil != e {
+				return e
+			} else {
+				var slice reflect.Value = reflect.MakeSlice(target.Type(),len(list),len(list))
+				for n, item := range list {
+					e = unmarshal(item,slice.Index(n),state)
+					if nil != e {
+						return e
+					}
+				}
+				target.Set(slice)
+				return nil
+			}
+		}
+
+	case reflect.Array:
+		if MajorArray != o.Major() {
+			return &UnmarshalTypeError{o.MajorString(),target.Type()}
+		} else {
+			var list []Object
+			list, e = o.items()
+			if nil != e {
+				return e
+			} else if len(list) != target.Len() {
+				return &UnmarshalTypeError{fmt.Sprintf("array (%d)",len(list)),target.Type()}
+			} else {
+				for n, item := range list {
+					e = unmarshal(item,target.Index(n),state)
+					if nil != e {
+						return e
+					}
+				}
+				return nil
+			}
+		}
+
+	case reflect.Map:
+		if o.IsNull() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+
+		} else if MajorMap != o.Major() {
+			return &UnmarshalTypeError{o.MajorString(),target.Type()}
+		} else {
+			var list []Object
+			list, e = o.items()
+			if nil != e {
+				return e
+			} else {
+				target.Set(reflect.MakeMapWithSize(target.Type(),(len(list)/2)))
+
+				var n, z int = 0, len(list)
+				for ; n < z; n += 2 {
+					e = unmarshalEntry(list[n],list[n+1],target,state)
+					if nil != e {
+						return e
+					}
+				}
+				return nil
+			}
+		}
+
+	default:
+		return unmarshalContent(state.decode(o),target,state)
+	}
+}
+/*
+ * Store map entry into (non nil) map.
+ */
+func unmarshalEntry(k, v Object, table reflect.Value, state *decoding) (e error) {
+	if table.IsNil() {
+		table.Set(reflect.MakeMap(table.Type()))
+	}
+	var key reflect.Value = reflect.New(table.Type().Key()).Elem()
+	var value reflect.Value = reflect.New(table.Type().Elem()).Elem()
+
+	e = unmarshal(k,key,state)
+	if nil != e {
+		return e
+	}
+	e = unmarshal(v,value,state)
+	if nil != e {
+		return e
+	}
+	table.SetMapIndex(key,value)
+	return nil
+}
+/*
+ * Store decoded content into target by assignment or numeric
+ * conversion.  Numeric conversion is exact: content out of
+ * range of the target type is an <UnmarshalTypeError>.  String
+ * and byte slice conversion is subject to "StringBytes".
+ */
+func unmarshalContent(content any, target reflect.Value, state *decoding) (error) {
+	if nil == content {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	} else {
+		var source reflect.Value = reflect.ValueOf(content)
+
+		if source.Type().AssignableTo(target.Type()) {
+			target.Set(source)
+			return nil
+
+		} else if numeric(source.Kind()) && numeric(target.Kind()) {
+			if representable(source,target.Type()) {
+				target.Set(source.Convert(target.Type()))
+				return nil
+			} else {
+				return &UnmarshalTypeError{fmt.Sprintf("%s %v",kindString(source.Kind()),content),target.Type()}
+			}
+
+		} else if source.Type().ConvertibleTo(target.Type()) && source.Kind() == target.Kind() {
+			target.Set(source.Convert(target.Type()))
+			return nil
+
+		} else if state.options.StringBytes && stringBytes(source.Type(),target.Type()) {
+			target.Set(source.Convert(target.Type()))
+			return nil
+
+		} else {
+			return &UnmarshalTypeError{fmt.Sprintf("%s %v",source.Type(),content),target.Type()}
+		}
+	}
+}
+/*
+ * Determine whether source and target types are a string and a
+ * byte slice.
+ */
+func stringBytes(source, target reflect.Type) (bool) {
+	if reflect.String == source.Kind() {
+		return (reflect.Slice == target.Kind() && reflect.Uint8 == target.Elem().Kind())
+
+	} else if reflect.String == target.Kind() {
+		return (reflect.Slice == source.Kind() && reflect.Uint8 == source.Elem().Kind())
+	} else {
+		return false
+	}
+}
+/*
+ * Determine whether numeric source value converts to numeric
+ * target type without loss.
+ */
+func representable(source reflect.Value, t reflect.Type) (bool) {
+	var target reflect.Value = reflect.New(t).Elem()
+
+	switch source.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64 = source.Uint()
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return (math.MaxInt64 >= u && !target.OverflowInt(int64(u)))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return !target.OverflowUint(u)
+		default:
+			return (uint64(float64(u)) == u && !target.OverflowFloat(float64(u)))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64 = source.Int()
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return !target.OverflowInt(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return (0 <= i && !target.OverflowUint(uint64(i)))
+		default:
+			return (int64(float64(i)) == i && !target.OverflowFloat(float64(i)))
+		}
+
+	default:
+		var f float64 = source.Float()
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return (math.Trunc(f) == f && math.MinInt64 <= f && math.MaxInt64 > f && !target.OverflowInt(int64(f)))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return (math.Trunc(f) == f && 0 <= f && math.MaxUint64 > f && !target.OverflowUint(uint64(f)))
+		default:
+			return (math.IsNaN(f) || math.IsInf(f,0) || !target.OverflowFloat(f))
+		}
+	}
+}
+/*
+ */
+func kindString(k reflect.Kind) (string) {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "unsigned integer"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "signed integer"
+	default:
+		return "float"
+	}
+}
+/*
+ */
+func numeric(k reflect.Kind) (bool) {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	case reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+/*
+ * Determine whether struct field value is empty, for
+ * "omitempty".
+ */
+func empty(v reflect.Value) (bool) {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return (0 == v.Len())
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return (0 == v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return (0 == v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return (0 == v.Float())
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
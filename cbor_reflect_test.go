@@ -0,0 +1,525 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR GOPL type reflection Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type TypeTestEnvelope struct {
+
+	Kind string `cbor:"kind"`
+
+	Body RawMessage `cbor:"body"`
+
+	Note string `cbor:"-"`
+}
+
+func TestRawMessage(t *testing.T){
+	var body Object = Encode(map[string]any{"text": TestStringDatum})
+
+	var text TypeTestEnvelope = TypeTestEnvelope{Kind: "greeting", Body: RawMessage(body), Note: "excluded"}
+
+	var code Object = Encode(text)
+
+	var check TypeTestEnvelope
+	var e error = Unmarshal(code,&check)
+	if nil != e {
+		t.Fatal(e)
+	} else if text.Kind != check.Kind {
+		t.Errorf("Expected kind '%s', found '%s'.",text.Kind,check.Kind)
+	} else if !bytes.Equal(body,check.Body) {
+		t.Errorf("Expected body '%X', found '%X'.",[]byte(body),[]byte(check.Body))
+	} else if "" != check.Note {
+		t.Errorf("Expected excluded note, found '%s'.",check.Note)
+	}
+
+	var content map[string]string
+	e = Unmarshal(check.Body,&content)
+	if nil != e {
+		t.Fatal(e)
+	} else if TestStringDatum != content["text"] {
+		t.Errorf("Expected '%s', found '%s'.",TestStringDatum,content["text"])
+	}
+
+	if !bytes.Equal(code,Encode(check)) {
+		t.Errorf("Expected re-encoding '%X', found '%X'.",[]byte(code),[]byte(Encode(check)))
+	}
+
+	for _, c := range []struct{ body RawMessage; expected error }{
+		{RawMessage{0x82,0x01}, ErrorTruncated},
+		{RawMessage{0x01,0x02}, ErrorTrailingData},
+		{RawMessage{0xFF}, Break},
+	} {
+		text.Body = c.body
+		_, e = EncOptions{}.Encode(text)
+		if !errors.Is(e,c.expected) {
+			t.Errorf("[%X] Expected '%v', found '%v'.",[]byte(c.body),c.expected,e)
+		}
+	}
+}
+
+type TypeTestExtensible struct {
+
+	Kind string `cbor:"kind"`
+
+	Extensions map[string]RawMessage `cbor:",unknown"`
+}
+
+func TestUnknownFields(t *testing.T){
+	var code Object = Encode(map[string]any{"kind": "greeting", "x-text": TestStringDatum, "x-flag": true})
+
+	var check TypeTestExtensible
+	var e error = Unmarshal(code,&check)
+	if nil != e {
+		t.Fatal(e)
+	} else if "greeting" != check.Kind {
+		t.Errorf("Expected kind 'greeting', found '%s'.",check.Kind)
+	} else if 2 != len(check.Extensions) {
+		t.Fatalf("Expected two extensions, found (%d).",len(check.Extensions))
+	} else if TestStringDatum != Object(check.Extensions["x-text"]).MustText() {
+		t.Errorf("Expected extension '%s', found '%X'.",TestStringDatum,check.Extensions["x-text"])
+	}
+
+	var recode Object = Encode(check)
+
+	var table map[string]any
+	e = Unmarshal(recode,&table)
+	if nil != e {
+		t.Fatal(e)
+	} else if 3 != len(table) {
+		t.Errorf("Expected three entries, found (%d).",len(table))
+	} else if TestStringDatum != table["x-text"] || true != table["x-flag"] || "greeting" != table["kind"] {
+		t.Errorf("Expected extensions preserved, found '%v'.",table)
+	}
+}
+
+func TestUnmarshalOverflow(t *testing.T){
+	var code Object = Encode(uint16(300))
+
+	var i8 int8
+	var e error = Unmarshal(code,&i8)
+	var te *UnmarshalTypeError
+	if !errors.As(e,&te) {
+		t.Errorf("Expected type error, found '%v'.",e)
+	} else if reflect.TypeOf(i8) != te.Type || "unsigned integer 300" != te.Value {
+		t.Errorf("Expected 'unsigned integer 300' into int8, found '%s' into %v.",te.Value,te.Type)
+	}
+
+	var u8 uint8
+	e = Unmarshal(code,&u8)
+	if !errors.As(e,&te) {
+		t.Errorf("Expected type error, found '%v'.",e)
+	}
+
+	var i16 int16
+	e = Unmarshal(code,&i16)
+	if nil != e {
+		t.Error(e)
+	} else if 300 != i16 {
+		t.Errorf("Expected (300), found (%d).",i16)
+	}
+
+	code = Encode(uint64(math.MaxUint64))
+	var i64 int64
+	e = Unmarshal(code,&i64)
+	if !errors.As(e,&te) {
+		t.Errorf("Expected type error, found '%v'.",e)
+	}
+	var u64 uint64
+	e = Unmarshal(code,&u64)
+	if nil != e {
+		t.Error(e)
+	} else if math.MaxUint64 != u64 {
+		t.Errorf("Expected (%d), found (%d).",uint64(math.MaxUint64),u64)
+	}
+}
+
+func TestUintBoundaries(t *testing.T){
+	var cases = []struct{ value uint64; head int }{
+		{23, 1}, {24, 2},
+		{255, 2}, {256, 3},
+		{65535, 3}, {65536, 5},
+		{math.MaxUint32, 5}, {math.MaxUint32+1, 9},
+		{math.MaxInt64, 9}, {math.MaxInt64+1, 9},
+		{math.MaxUint64, 9},
+	}
+	for _, c := range cases {
+		var code Object = Encode(c.value)
+		if c.head != len(code) || MajorUint != code.Major() {
+			t.Errorf("Expected unsigned head of %d octets for %d, found '%X'.",c.head,c.value,[]byte(code))
+			continue
+		}
+		var decoded reflect.Value = reflect.ValueOf(code.Decode())
+		switch decoded.Kind() {
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if c.value != decoded.Uint() {
+				t.Errorf("Expected (%d), found (%d).",c.value,decoded.Uint())
+			}
+		default:
+			t.Errorf("Expected unsigned integer for %d, found %v.",c.value,decoded.Type())
+		}
+		var u64 uint64
+		var e error = Unmarshal(code,&u64)
+		if nil != e || c.value != u64 {
+			t.Errorf("Expected (%d), found (%d) '%v'.",c.value,u64,e)
+		}
+		var i64 int64
+		e = Unmarshal(code,&i64)
+		if math.MaxInt64 < c.value {
+			var te *UnmarshalTypeError
+			if !errors.As(e,&te) || 0 != i64 {
+				t.Errorf("Expected type error for %d into int64, found (%d) '%v'.",c.value,i64,e)
+			}
+			_, e = code.Int()
+			if nil == e {
+				t.Errorf("Expected error of Int for %d.",c.value)
+			}
+		} else if nil != e || int64(c.value) != i64 {
+			t.Errorf("Expected (%d), found (%d) '%v'.",c.value,i64,e)
+		}
+		var i32 int32
+		e = Unmarshal(code,&i32)
+		if (math.MaxInt32 < c.value) != (nil != e) {
+			t.Errorf("Expected error (%t) for %d into int32, found '%v'.",(math.MaxInt32 < c.value),c.value,e)
+		}
+		var u8 uint8
+		e = Unmarshal(code,&u8)
+		if (math.MaxUint8 < c.value) != (nil != e) {
+			t.Errorf("Expected error (%t) for %d into uint8, found '%v'.",(math.MaxUint8 < c.value),c.value,e)
+		}
+		var v any
+		e = Unmarshal(code,&v)
+		if nil != e {
+			t.Error(e)
+		} else if decoded = reflect.ValueOf(v); !decoded.CanUint() || c.value != decoded.Uint() {
+			t.Errorf("Expected unsigned (%d), found %v (%v).",c.value,decoded.Type(),v)
+		}
+	}
+}
+
+type TypeTestPoint struct {
+
+	X, Y uint16
+}
+
+func TestEncodeSlices(t *testing.T){
+	var strings []string = []string{"a","b","c"}
+	var code Object = Encode(strings)
+	if !bytes.Equal([]byte{0x83,0x61,0x61,0x61,0x62,0x61,0x63},code) {
+		t.Errorf("Expected encoding '836161616261 63', found '%X'.",[]byte(code))
+	}
+	var strings2 []string
+	if e := Unmarshal(code,&strings2); nil != e || !reflect.DeepEqual(strings,strings2) {
+		t.Errorf("Expected '%v', found '%v' (%v).",strings,strings2,e)
+	}
+
+	var blobs [][]byte = [][]byte{{0x01},{0x02,0x03}}
+	code = Encode(blobs)
+	if !bytes.Equal([]byte{0x82,0x41,0x01,0x42,0x02,0x03},code) {
+		t.Errorf("Expected encoding '82410142 0203', found '%X'.",[]byte(code))
+	}
+
+	var points [2]TypeTestPoint = [2]TypeTestPoint{{1,2},{300,400}}
+	code = Encode(points)
+	var points2 [2]TypeTestPoint
+	if e := Unmarshal(code,&points2); nil != e || points != points2 {
+		t.Errorf("Expected '%v', found '%v' (%v).",points,points2,e)
+	}
+
+	var numbers []uint32 = []uint32{1,1000,100000}
+	code = Encode(numbers)
+	if !bytes.Equal([]byte{0x83,0x01,0x19,0x03,0xE8,0x1A,0x00,0x01,0x86,0xA0},code) {
+		t.Errorf("Expected encoding '8301 1903E8 1A000186A0', found '%X'.",[]byte(code))
+	}
+}
+
+func TestEncodeMaps(t *testing.T){
+	var table map[uint16]TypeTestPoint = map[uint16]TypeTestPoint{300: {3,4}, 1: {1,2}, 24: {2,3}}
+
+	var code Object = Encode(table)
+	var expected []byte = []byte{0xA3,
+		0x01,0xA2,0x61,0x58,0x01,0x61,0x59,0x02,
+		0x18,0x18,0xA2,0x61,0x58,0x02,0x61,0x59,0x03,
+		0x19,0x01,0x2C,0xA2,0x61,0x58,0x03,0x61,0x59,0x04}
+	if !bytes.Equal(expected,code) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(code))
+	}
+
+	var check map[uint16]TypeTestPoint
+	var e error = Unmarshal(code,&check)
+	if nil != e {
+		t.Fatal(e)
+	} else if !reflect.DeepEqual(table,check) {
+		t.Errorf("Expected '%v', found '%v'.",table,check)
+	}
+
+	var sets map[string][]string = map[string][]string{"b": {"x"}, "a": {}}
+	code = Encode(sets)
+	expected = []byte{0xA2,0x61,0x61,0x80,0x61,0x62,0x81,0x61,0x78}
+	if !bytes.Equal(expected,code) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(code))
+	}
+}
+
+type TypeTestOptional struct {
+
+	Name *string `cbor:"name"`
+
+	Count *uint32 `cbor:"count"`
+
+	Next *TypeTestOptional `cbor:"next"`
+}
+
+func TestPointers(t *testing.T){
+	var name string = TestStringDatum
+	var count uint32 = 70000
+
+	var text TypeTestOptional = TypeTestOptional{Name: &name, Next: &TypeTestOptional{Count: &count}}
+
+	var code Object = Encode(&text)
+
+	var check TypeTestOptional
+	var e error = Unmarshal(code,&check)
+	if nil != e {
+		t.Fatal(e)
+	} else if nil == check.Name || name != *check.Name {
+		t.Errorf("Expected name '%s', found '%v'.",name,check.Name)
+	} else if nil != check.Count {
+		t.Errorf("Expected null count, found '%v'.",*check.Count)
+	} else if nil == check.Next || nil == check.Next.Count || count != *check.Next.Count {
+		t.Errorf("Expected next count (%d), found '%v'.",count,check.Next)
+	} else if nil != check.Next.Next || nil != check.Next.Name {
+		t.Errorf("Expected null next name and next, found '%v'.",check.Next)
+	}
+
+	var held any = &TypeTestPoint{}
+	e = Unmarshal(Encode(TypeTestPoint{5,6}),&held)
+	if nil != e {
+		t.Fatal(e)
+	} else if point, ok := held.(*TypeTestPoint); !ok || 5 != point.X || 6 != point.Y {
+		t.Errorf("Expected point {5 6}, found '%v'.",held)
+	}
+
+	if !bytes.Equal([]byte{0xF6},Encode((*TypeTestPoint)(nil))) {
+		t.Errorf("Expected null, found '%X'.",[]byte(Encode((*TypeTestPoint)(nil))))
+	}
+}
+
+type TypeTestCelsius struct {
+
+	degrees uint8
+}
+
+func (this TypeTestCelsius) MarshalCBOR() ([]byte, error) {
+	if 100 < this.degrees {
+		return nil, errors.New("boiling")
+	} else {
+		return Encode(fmt.Sprintf("%dC",this.degrees)), nil
+	}
+}
+
+func (this *TypeTestCelsius) UnmarshalCBOR(code []byte) (error) {
+	var text, ok = Object(code).Decode().(string)
+	if ok {
+		var _, e = fmt.Sscanf(text,"%dC",&this.degrees)
+		return e
+	} else {
+		return errors.New("not text")
+	}
+}
+
+type TypeTestWeather struct {
+
+	High TypeTestCelsius `cbor:"high"`
+
+	Low *TypeTestCelsius `cbor:"low"`
+}
+
+type TypeTestMarshaled []byte
+
+func (this TypeTestMarshaled) MarshalCBOR() ([]byte, error) {
+	return this, nil
+}
+
+func TestMarshaler(t *testing.T){
+	var weather TypeTestWeather = TypeTestWeather{TypeTestCelsius{21},&TypeTestCelsius{12}}
+	var code Object = Encode(weather)
+
+	var high, e = code.Query("$.high")
+	if nil != e {
+		t.Fatal(e)
+	} else if 1 != len(high) || "21C" != high[0].MustText() {
+		t.Errorf("Expected '21C', found '%v'.",high)
+	}
+
+	var decoded TypeTestWeather
+	e = Unmarshal(code,&decoded)
+	if nil != e {
+		t.Fatal(e)
+	} else if 21 != decoded.High.degrees || nil == decoded.Low || 12 != decoded.Low.degrees {
+		t.Errorf("Expected '{21 12}', found '%v'.",decoded)
+	}
+
+	e = Unmarshal(Encode(map[string]any{"high": uint8(1)}),&decoded)
+	if nil == e || "not text" != e.Error() {
+		t.Errorf("Expected 'not text', found '%v'.",e)
+	}
+
+	weather.High.degrees = 101
+	_, e = EncOptions{}.Encode(weather)
+	if nil == e || "boiling" != e.Error() {
+		t.Errorf("Expected 'boiling', found '%v'.",e)
+	}
+
+	for _, c := range []struct{ marshaled TypeTestMarshaled; expected error }{
+		{TypeTestMarshaled{}, ErrorTruncated},
+		{TypeTestMarshaled{0x62,'a'}, ErrorTruncated},
+		{TypeTestMarshaled{0x01,0x02}, ErrorTrailingData},
+	} {
+		_, e = EncOptions{}.Encode([]any{c.marshaled})
+		if !errors.Is(e,c.expected) {
+			t.Errorf("Expected '%v', found '%v'.",c.expected,e)
+		}
+	}
+}
+
+func TestDecodeInto(t *testing.T){
+	var table map[string]string
+	var e error = Encode(map[string]string{"a": "A"}).DecodeInto(&table)
+	if nil != e {
+		t.Fatal(e)
+	} else if "A" != table["a"] {
+		t.Errorf("Expected 'A', found '%v'.",table)
+	}
+
+	var list []float64
+	e = Encode([]any{uint8(1),float32(1.5),2.25}).DecodeInto(&list)
+	if nil != e {
+		t.Fatal(e)
+	} else if 3 != len(list) || 1 != list[0] || 1.5 != list[1] || 2.25 != list[2] {
+		t.Errorf("Expected '[1 1.5 2.25]', found '%v'.",list)
+	}
+
+	var n int
+	e = Encode(uint64(300)).DecodeInto(&n)
+	if nil != e || 300 != n {
+		t.Errorf("Expected '300', found '%d' (%v).",n,e)
+	}
+	e = Encode(uint64(math.MaxUint64)).DecodeInto(&n)
+	if nil == e {
+		t.Errorf("Expected error for 2^64-1 into int.")
+	}
+
+	var s string
+	var b []byte
+	e = Encode([]byte("text")).DecodeInto(&s)
+	if nil == e {
+		t.Errorf("Expected error for byte string into string.")
+	}
+	e = DecOptions{StringBytes: true}.Unmarshal(Encode([]byte("text")),&s)
+	if nil != e || "text" != s {
+		t.Errorf("Expected 'text', found '%s' (%v).",s,e)
+	}
+	e = DecOptions{StringBytes: true}.Unmarshal(Encode("text"),&b)
+	if nil != e || "text" != string(b) {
+		t.Errorf("Expected 'text', found '%s' (%v).",b,e)
+	}
+	e = DecOptions{StringBytes: true}.Unmarshal(Encode(uint8(65)),&s)
+	if nil == e {
+		t.Errorf("Expected error for integer into string.")
+	}
+}
+
+type TypeTestRawItem struct {
+	Payload RawMessage `cbor:"payload"`
+	Signature []byte `cbor:"signature"`
+}
+
+func TestRawItemField(t *testing.T){
+	/*
+	 * Payload {"b": 1, "a": 24} with unsorted keys and a non
+	 * shortest argument.
+	 */
+	var payload Object = Object{0xA2,0x61,'b',0x18,0x01,0x61,'a',0x18,0x18}
+
+	var envelope TypeTestRawItem = TypeTestRawItem{RawMessage(payload),[]byte{0x5A}}
+	var code Object = Encode(envelope)
+	var decoded TypeTestRawItem
+	var e error = Unmarshal(code,&decoded)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(payload,decoded.Payload) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(payload),[]byte(decoded.Payload))
+	} else if !bytes.Equal(code,Encode(decoded)) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(code),[]byte(Encode(decoded)))
+	}
+}
+
+type TypeTestInt int16
+
+func TestEncodeNamedInteger(t *testing.T){
+	var found string = fmt.Sprintf("%x",[]byte(Encode(TypeTestInt(-1000))))
+	if "3903e7" != found {
+		t.Errorf("Expected '3903e7', found '%s'.",found)
+	}
+}
+
+func TestEncodeLargeSlice(t *testing.T){
+	var list []int = make([]int,80000)
+	for n := range list {
+		list[n] = n
+	}
+	var code Object = Encode(list)
+	var decoded []int
+	var e error = Unmarshal(code,&decoded)
+	if nil != e {
+		t.Fatal(e)
+	} else if len(list) != len(decoded) || 79999 != decoded[79999] {
+		t.Errorf("Expected '%d', found '%d'.",len(list),len(decoded))
+	}
+}
+
+type TypeTestNode struct {
+
+	Next *TypeTestNode `cbor:"next"`
+}
+
+func TestEncodeCycle(t *testing.T){
+	var node *TypeTestNode = &TypeTestNode{}
+	node.Next = node
+
+	var table map[string]any = map[string]any{}
+	table["table"] = table
+
+	var list []any = make([]any,1)
+	list[0] = list
+
+	var named map[int]any = map[int]any{}
+	named[0] = []any{named}
+
+	for _, value := range []any{node,table,list,named} {
+		var _, e = EncOptions{}.Encode(value)
+		if ErrorCycle != e {
+			t.Errorf("Expected '%v', found '%v'.",ErrorCycle,e)
+		}
+	}
+
+	var chain *TypeTestNode
+	for n := 0; n < 2000; n++ {
+		chain = &TypeTestNode{chain}
+	}
+	var _, e = EncOptions{}.Encode(chain)
+	if nil != e {
+		t.Errorf("Expected '%v', found '%v'.",nil,e)
+	}
+}
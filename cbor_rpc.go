@@ -0,0 +1,196 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR RPC
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://pkg.go.dev/net/rpc
+ */
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+)
+/*
+ * RPC message header, encoded as a map data item preceding
+ * each message body data item.
+ */
+const rpcServiceMethod string = "ServiceMethod"
+const rpcSeq string = "Seq"
+const rpcError string = "Error"
+/*
+ * Validation errors produced by the RPC codecs.
+ */
+var ErrorRPCHeader error = errors.New("CBOR RPC header malformed")
+/*
+ * Server side RPC codec over a connection.
+ */
+type ServerCodec struct {
+
+	c io.Closer
+
+	enc *Encoder
+
+	dec *Decoder
+}
+/*
+ * Client side RPC codec over a connection.
+ */
+type ClientCodec struct {
+
+	c io.Closer
+
+	enc *Encoder
+
+	dec *Decoder
+}
+/*
+ * Construct a CBOR codec for "rpc.ServeCodec".
+ */
+func NewServerCodec(conn io.ReadWriteCloser) (rpc.ServerCodec) {
+	return &ServerCodec{conn, NewEncoder(conn), NewDecoder(conn)}
+}
+/*
+ * Construct a CBOR codec for "rpc.NewClientWithCodec".
+ */
+func NewClientCodec(conn io.ReadWriteCloser) (rpc.ClientCodec) {
+	return &ClientCodec{conn, NewEncoder(conn), NewDecoder(conn)}
+}
+/*
+ * Read request header map.
+ */
+func (this *ServerCodec) ReadRequestHeader(r *rpc.Request) (e error) {
+	var header map[string]any
+	header, e = readHeader(this.dec)
+	if nil != e {
+		return e
+	} else {
+		r.ServiceMethod, r.Seq, _, e = fromHeader(header)
+		return e
+	}
+}
+/*
+ * Read request body into argument, or discard when nil.
+ */
+func (this *ServerCodec) ReadRequestBody(body any) (error) {
+	return this.dec.Decode(body)
+}
+/*
+ * Write response header map and body.
+ */
+func (this *ServerCodec) WriteResponse(r *rpc.Response, body any) (e error) {
+	var header map[string]any = map[string]any{
+		rpcServiceMethod: r.ServiceMethod,
+		rpcSeq: r.Seq,
+	}
+	if "" != r.Error {
+		header[rpcError] = r.Error
+		body = nil
+	}
+	e = this.enc.Encode(header)
+	if nil != e {
+		return e
+	} else {
+		return this.enc.Encode(body)
+	}
+}
+/*
+ */
+func (this *ServerCodec) Close() (error) {
+	return this.c.Close()
+}
+/*
+ * Write request header map and body.
+ */
+func (this *ClientCodec) WriteRequest(r *rpc.Request, body any) (e error) {
+	var header map[string]any = map[string]any{
+		rpcServiceMethod: r.ServiceMethod,
+		rpcSeq: r.Seq,
+	}
+	e = this.enc.Encode(header)
+	if nil != e {
+		return e
+	} else {
+		return this.enc.Encode(body)
+	}
+}
+/*
+ * Read response header map.
+ */
+func (this *ClientCodec) ReadResponseHeader(r *rpc.Response) (e error) {
+	var header map[string]any
+	header, e = readHeader(this.dec)
+	if nil != e {
+		return e
+	} else {
+		r.ServiceMethod, r.Seq, r.Error, e = fromHeader(header)
+		return e
+	}
+}
+/*
+ * Read response body into reply, or discard when nil.
+ */
+func (this *ClientCodec) ReadResponseBody(body any) (error) {
+	return this.dec.Decode(body)
+}
+/*
+ */
+func (this *ClientCodec) Close() (error) {
+	return this.c.Close()
+}
+/*
+ */
+func readHeader(dec *Decoder) (header map[string]any, e error) {
+	var o Object
+	o, e = dec.Read()
+	if nil != e {
+		return nil, e
+	} else if MajorMap != o.Major() {
+		return nil, ErrorRPCHeader
+	} else {
+		var ok bool
+		header, ok = o.Decode().(map[string]any)
+		if ok {
+			return header, nil
+		} else {
+			return nil, ErrorRPCHeader
+		}
+	}
+}
+/*
+ */
+func fromHeader(header map[string]any) (method string, seq uint64, err string, e error) {
+	var ok bool
+
+	method, ok = header[rpcServiceMethod].(string)
+	if !ok {
+		return "", 0, "", fmt.Errorf("%w: %s",ErrorRPCHeader,rpcServiceMethod)
+	}
+
+	switch header[rpcSeq].(type) {
+	case uint8:
+		seq = uint64(header[rpcSeq].(uint8))
+	case uint16:
+		seq = uint64(header[rpcSeq].(uint16))
+	case uint32:
+		seq = uint64(header[rpcSeq].(uint32))
+	case uint64:
+		seq = header[rpcSeq].(uint64)
+	default:
+		return "", 0, "", fmt.Errorf("%w: %s",ErrorRPCHeader,rpcSeq)
+	}
+
+	if nil != header[rpcError] {
+		err, ok = header[rpcError].(string)
+		if !ok {
+			return "", 0, "", fmt.Errorf("%w: %s",ErrorRPCHeader,rpcError)
+		}
+	}
+	return method, seq, err, nil
+}
@@ -0,0 +1,59 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR RPC Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"testing"
+)
+
+type TypeTestEcho struct {
+}
+
+func (this *TypeTestEcho) Say(args *string, reply *string) (error) {
+	*reply = *args
+	return nil
+}
+
+func (this *TypeTestEcho) Fail(args *string, reply *string) (error) {
+	return errors.New(*args)
+}
+
+func TestRPC(t *testing.T){
+	var server *rpc.Server = rpc.NewServer()
+	var e error = server.Register(new(TypeTestEcho))
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	var srv, cli net.Conn = net.Pipe()
+
+	go server.ServeCodec(NewServerCodec(srv))
+
+	var client *rpc.Client = rpc.NewClientWithCodec(NewClientCodec(cli))
+	defer client.Close()
+
+	for n := 0; n < 3; n++ {
+		var reply string
+		e = client.Call("TypeTestEcho.Say",TestStringDatum,&reply)
+		if nil != e {
+			t.Fatal(e)
+		} else if TestStringDatum != reply {
+			t.Errorf("Expected '%s', found '%s'.",TestStringDatum,reply)
+		}
+	}
+
+	var reply string
+	e = client.Call("TypeTestEcho.Fail",TestStringDatum,&reply)
+	if nil == e {
+		t.Error("Expected server error.")
+	} else if TestStringDatum != e.Error() {
+		t.Errorf("Expected '%s', found '%v'.",TestStringDatum,e)
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ * CBOR RFC8949 Simple Values
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.3
+ */
+package cbor
+
+/*
+ * Encodings of the simple values "false" (0xF4), "true"
+ * (0xF5), "null" (0xF6) and "undefined" (0xF7).  These objects
+ * are shared, and are not to be modified.  See <Object#Clone>.
+ */
+var False Object = Object{0xF4}
+var True Object = Object{0xF5}
+var Null Object = Object{0xF6}
+var Undef Object = Object{0xF7}
+/*
+ * Determine whether object is "null".
+ */
+func (this Object) IsNull() (bool) {
+	return (0 != len(this) && 0xF6 == this[0])
+}
+/*
+ * Determine whether object is "undefined".
+ */
+func (this Object) IsUndefined() (bool) {
+	return (0 != len(this) && 0xF7 == this[0])
+}
+/*
+ * Determine whether object is "false" or "true".
+ */
+func (this Object) IsBool() (bool) {
+	return (0 != len(this) && (0xF4 == this[0] || 0xF5 == this[0]))
+}
@@ -0,0 +1,30 @@
+/*
+ * CBOR Simple Values Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"testing"
+)
+
+func TestSimpleValues(t *testing.T){
+	for _, c := range []struct{ o Object; value any; null, undefined, boolean bool }{
+		{False, false, false, false, true},
+		{True, true, false, false, true},
+		{Null, nil, true, false, false},
+		{Undef, Undefined{}, false, true, false},
+		{Object{0x00}, uint8(0), false, false, false},
+		{Object{}, nil, false, false, false},
+	} {
+		if c.null != c.o.IsNull() || c.undefined != c.o.IsUndefined() || c.boolean != c.o.IsBool() {
+			t.Errorf("Expected '%t %t %t' for '%X', found '%t %t %t'.",c.null,c.undefined,c.boolean,[]byte(c.o),c.o.IsNull(),c.o.IsUndefined(),c.o.IsBool())
+		}
+		if 0 != len(c.o) && c.value != c.o.Decode() {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",c.value,[]byte(c.o),c.o.Decode())
+		}
+	}
+	if !Encode([]any{true,nil}).Equal(Object{0x82}.Concatenate(True).Concatenate(Null)) {
+		t.Error("Expected encoding of 'true' and 'null'.")
+	}
+}
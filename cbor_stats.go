@@ -0,0 +1,148 @@
+/*
+ * CBOR RFC8949 Statistics
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3
+ */
+package cbor
+
+import (
+	"sort"
+)
+/*
+ * Number of strings retained by <Stats> as largest.
+ */
+const StatsLargest int = 8
+/*
+ * Profile of an object produced by <Analyze>.
+ */
+type Stats struct {
+	/*
+	 * Count of data items, including indefinite length string
+	 * chunks.
+	 */
+	Items int
+	/*
+	 * Count of data items by major type.
+	 */
+	Count [8]int
+	/*
+	 * Octets by major type, being heads, string payloads and
+	 * breaks, exclusive of nested data items, such that the
+	 * sum is the size of the data item.
+	 */
+	Octets [8]int
+	/*
+	 * Count of tagged data items by tag number.
+	 */
+	Tags map[uint64]int
+	/*
+	 * Octets of tagged data items, inclusive of their content,
+	 * by tag number.
+	 */
+	TagOctets map[uint64]int
+	/*
+	 * Maximum nesting depth, being zero for a data item
+	 * without nested data items.
+	 */
+	Depth int
+	/*
+	 * Largest definite length strings in descending order of
+	 * size, up to <StatsLargest>.
+	 */
+	Largest []*Node
+	/*
+	 * Occurrences of byte and text string payloads occurring
+	 * more than once, by payload.
+	 */
+	Duplicates map[string]int
+	/*
+	 * Payload octets of the repeated occurrences of duplicate
+	 * strings.
+	 */
+	DuplicateOctets int
+}
+/*
+ * Profile the (first) data item of the object: counts and
+ * octets by major type and by tag, nesting depth, largest
+ * strings, and duplicate string payloads.
+ */
+func Analyze(o Object) (Stats, error) {
+	var stats Stats = Stats{Tags: map[uint64]int{}, TagOctets: map[uint64]int{}, Duplicates: map[string]int{}}
+	var root, e = o.Structure()
+	if nil != e {
+		return stats, e
+	} else {
+		stats.analyze(o,root,0)
+
+		for payload, count := range stats.Duplicates {
+			if 1 == count {
+				delete(stats.Duplicates,payload)
+			} else {
+				stats.DuplicateOctets += ((count-1)*len(payload))
+			}
+		}
+		return stats, nil
+	}
+}
+/*
+ * Profile node and its descendants, in the order of their
+ * encoding, with an explicit stack for the depth of the data
+ * item.
+ */
+func (this *Stats) analyze(o Object, root *Node, depth int) {
+	type visit struct {
+		node *Node
+		depth int
+	}
+	var stack []visit = []visit{{root,depth}}
+	for 0 < len(stack) {
+		var top visit = stack[len(stack)-1]
+		stack = stack[0:len(stack)-1]
+
+		var node *Node = top.node
+		this.Items += 1
+		this.Count[node.Major] += 1
+		if top.depth > this.Depth {
+			this.Depth = top.depth
+		}
+		var octets int = node.Length
+		for n := len(node.Children)-1; 0 <= n; n-- {
+			octets -= node.Children[n].Length
+			stack = append(stack,visit{node.Children[n],top.depth+1})
+		}
+		this.Octets[node.Major] += octets
+
+		switch node.Major {
+		case MajorBlob, MajorText:
+			if !node.Indefinite {
+				var payload []byte = o[node.Offset+node.Head:node.Offset+node.Length]
+				this.Duplicates[string(payload)] += 1
+				this.largest(node)
+			}
+		case MajorTagged:
+			this.Tags[node.Argument] += 1
+			this.TagOctets[node.Argument] += node.Length
+		}
+	}
+}
+/*
+ * Retain string node when among the largest.
+ */
+func (this *Stats) largest(node *Node) {
+	var z int = (node.Length-node.Head)
+	var n int = sort.Search(len(this.Largest),func(x int) bool {
+		return z > (this.Largest[x].Length-this.Largest[x].Head)
+	})
+	if n < StatsLargest {
+		this.Largest = append(this.Largest,nil)
+		copy(this.Largest[n+1:],this.Largest[n:])
+		this.Largest[n] = node
+		if StatsLargest < len(this.Largest) {
+			this.Largest = this.Largest[0:StatsLargest]
+		}
+	}
+}
@@ -0,0 +1,39 @@
+/*
+ * CBOR Statistics Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"testing"
+)
+
+func TestAnalyze(t *testing.T){
+	var o Object = Object{0x83,0x63,'a','b','c',0xC1,0x1A,0x51,0x4B,0x67,0xB0,0x9F,0x63,'a','b','c',0x41,0x01,0xFF}
+	var stats, e = Analyze(o)
+	if nil != e {
+		t.Fatal(e)
+	}
+	if 7 != stats.Items || 1 != stats.Count[MajorUint] || 2 != stats.Count[MajorText] || 2 != stats.Count[MajorArray] || 1 != stats.Count[MajorTagged] {
+		t.Errorf("Expected counts of 7 items, found '%v'.",stats.Count)
+	}
+	var sum int
+	for _, octets := range stats.Octets {
+		sum += octets
+	}
+	if len(o) != sum || 8 != stats.Octets[MajorText] || 3 != stats.Octets[MajorArray] {
+		t.Errorf("Expected octets of %d, found '%v'.",len(o),stats.Octets)
+	}
+	if 1 != stats.Tags[1] || 6 != stats.TagOctets[1] {
+		t.Errorf("Expected tag 1 of 6 octets, found '%v' and '%v'.",stats.Tags,stats.TagOctets)
+	}
+	if 2 != stats.Depth {
+		t.Errorf("Expected depth 2, found %d.",stats.Depth)
+	}
+	if 3 != len(stats.Largest) || 1 != stats.Largest[0].Offset || 12 != stats.Largest[1].Offset || 16 != stats.Largest[2].Offset {
+		t.Errorf("Expected largest strings at 1, 12 and 16, found '%v'.",stats.Largest)
+	}
+	if 1 != len(stats.Duplicates) || 2 != stats.Duplicates["abc"] || 3 != stats.DuplicateOctets {
+		t.Errorf("Expected duplicate 'abc', found '%v' (%d).",stats.Duplicates,stats.DuplicateOctets)
+	}
+}
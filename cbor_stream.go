@@ -0,0 +1,850 @@
+/*
+ * CBOR RFC8949 Streaming
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949
+ */
+package cbor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+/*
+ * Validation error of <Encoder#WriteBytesFrom>.
+ */
+var ErrorChunkSize error = errors.New("CBOR chunk size must be positive")
+/*
+ * Callback of <Encoder#OnItem> and <Decoder#OnItem> for each
+ * data item with its major type, its tag number when tagged
+ * (otherwise zero), its encoded size in octets inclusive of
+ * nested data items, and its nesting depth.  Nested data items
+ * are reported before the data item enclosing them.  An error
+ * aborts the encoding or decoding, and is returned wrapped as
+ * for <errors.Is>.
+ */
+type ItemHook func(major Major, tag uint64, size int, depth int) (error)
+/*
+ * Visitor reporting each data item to an <ItemHook>.
+ */
+type walkHook struct {
+
+	hook ItemHook
+}
+/*
+ * Sequential writer of CBOR data items.  The methods of the
+ * encoder are safe for concurrent use, and each data item is
+ * written whole, without the interleaving of another.
+ */
+type Encoder struct {
+
+	lock sync.Mutex
+
+	w io.Writer
+
+	hook ItemHook
+}
+/*
+ * Writer of each write to every one of a list of writers, in
+ * order.
+ */
+type teeWriter []io.Writer
+/*
+ * Sequential reader of CBOR data items.
+ */
+type Decoder struct {
+
+	r io.Reader
+
+	hook ItemHook
+}
+/*
+ * Resumable reader of CBOR data items, for event driven I/O
+ * in which a blocking read is not available.  The zero value
+ * is ready for use.
+ */
+type Parser struct {
+	/*
+	 * Maximum count of octets retained for an incomplete data
+	 * item, or zero for no limit, rejecting a larger data item
+	 * with <ErrorSizeExceeded>.
+	 */
+	MaxSize int
+
+	buffer []byte
+	/*
+	 * Offset of the first octet of the buffer not scanned, and
+	 * the arrays, maps, tags and indefinite length strings
+	 * enclosing it, retained between calls.
+	 */
+	scan int
+
+	open []walkFrame
+
+	e error
+}
+/*
+ */
+func NewEncoder(w io.Writer) (*Encoder) {
+	return &Encoder{w: w}
+}
+/*
+ * Define an encoder writing the same octets to each of the
+ * writers, in order, as to a network connection and to the
+ * <hash.Hash> of a signature, which sees exactly the octets of
+ * the wire.  An error of one writer ends the write, leaving
+ * the writers following it short of the data item.
+ */
+func NewEncoderTee(w ...io.Writer) (*Encoder) {
+	var list teeWriter = make(teeWriter,len(w))
+	copy(list,w)
+	return &Encoder{w: list}
+}
+/*
+ * Report each data item encoded to the hook, for tracing,
+ * metrics or policy.  A nil hook removes the hook.
+ */
+func (this *Encoder) OnItem(hook ItemHook) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.hook = hook
+}
+/*
+ * Write the encoding of value.  A value rejected by the hook of
+ * <Encoder#OnItem> is not written.
+ */
+func (this *Encoder) Encode(v any) (error) {
+	var o Object = Encode(v)
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if nil != this.hook {
+		var _, e = o.walk(walkHook{this.hook})
+		if nil != e {
+			return e
+		}
+	}
+	return o.Write(this.w)
+}
+/*
+ * Write the content of the reader as an indefinite length byte
+ * string of definite length chunks of (at most) chunk octets,
+ * until the end of the reader, without reading the content into
+ * memory.  Each chunk and the byte string are reported to the
+ * hook of <Encoder#OnItem>, after being written.  An error of
+ * the reader, of the writer, or of the hook leaves the byte
+ * string incomplete.
+ */
+func (this *Encoder) WriteBytesFrom(r io.Reader, chunk int) (e error) {
+	if 0 >= chunk {
+		return ErrorChunkSize
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	var size int = 1
+	var indefinite []byte = []byte{byte(HeadBlobIndefinite)}
+	_, e = this.w.Write(indefinite)
+	if nil != e {
+		return e
+	}
+	/*
+	 * Chunk head (at most 9 octets) followed by its
+	 * content, written once.
+	 */
+	var frame []byte = make([]byte,9+chunk)
+	var scratch [9]byte
+	for {
+		var n int
+		n, e = io.ReadFull(r,frame[9:])
+		if 0 < n {
+			var head []byte = AppendHead(scratch[:0],MajorBlob,uint64(n))
+			var start int = (9-len(head))
+			copy(frame[start:9],head)
+
+			var written error = this.write(frame[start:9+n],1)
+			if nil != written {
+				return written
+			}
+			size += (len(head)+n)
+		}
+		if io.EOF == e || io.ErrUnexpectedEOF == e {
+			break
+		} else if nil != e {
+			return e
+		}
+	}
+	_, e = this.w.Write([]byte{byte(HeadBreak)})
+	if nil != e {
+		return e
+	} else if nil != this.hook {
+		return this.hook(MajorBlob,0,size+1,0)
+	} else {
+		return nil
+	}
+}
+/*
+ * Write the encoding of a byte string chunk, and report it to
+ * the hook at depth.
+ */
+func (this *Encoder) write(chunk []byte, depth int) (e error) {
+	_, e = this.w.Write(chunk)
+	if nil != e {
+		return e
+	} else if nil != this.hook {
+		return this.hook(MajorBlob,0,len(chunk),depth)
+	} else {
+		return nil
+	}
+}
+/*
+ * Write the octets to each writer, stopping at the first error.
+ */
+func (this teeWriter) Write(p []byte) (n int, e error) {
+	for _, w := range this {
+		n, e = w.Write(p)
+		if nil != e {
+			return n, e
+		} else if len(p) != n {
+			return n, io.ErrShortWrite
+		}
+	}
+	return len(p), nil
+}
+/*
+ */
+func NewDecoder(r io.Reader) (*Decoder) {
+	return &Decoder{r, nil}
+}
+/*
+ * Report each data item decoded to the hook, for tracing,
+ * metrics or policy.  A nil hook removes the hook.
+ */
+func (this *Decoder) OnItem(hook ItemHook) {
+	this.hook = hook
+}
+/*
+ * Visitor of the hook of <Decoder#OnItem>, when present.
+ */
+func (this *Decoder) visitor() (walkVisitor) {
+	if nil != this.hook {
+		return walkHook{this.hook}
+	} else {
+		return nil
+	}
+}
+/*
+ * Read one data item into the object.
+ */
+func (this *Decoder) Read() (o Object, e error) {
+	return walk(this.r,this.visitor())
+}
+/*
+ * Read one data item into the value referenced by pointer.  A
+ * nil pointer discards the data item.
+ */
+func (this *Decoder) Decode(v any) (e error) {
+	var o Object
+	o, e = this.Read()
+	if nil != e {
+		return e
+	} else if nil == v {
+		return nil
+	} else {
+		return Unmarshal(o,v)
+	}
+}
+/*
+ * Read one byte string, definite or indefinite length, copying
+ * its content to the writer as it is read, without reading the
+ * content into memory, and returning the count of octets
+ * written.  Each chunk and the byte string are reported to the
+ * hook of <Decoder#OnItem>, after being copied.  A data item
+ * other than a byte string is read and discarded, failing with
+ * <UnmarshalTypeError>.  End of stream before the data item is
+ * "io.EOF".
+ */
+func (this *Decoder) ReadBytesTo(w io.Writer) (z int64, e error) {
+	var head Object
+	head, e = readHead(this.r,true)
+	if nil != e {
+		return 0, e
+	} else if MajorBlob != head.Major() {
+		/*
+		 * Read the remainder of the data item.
+		 */
+		var _, skip = walk(io.MultiReader(bytes.NewReader(head),this.r),nil)
+		if nil != skip {
+			return 0, skip
+		} else {
+			return 0, &UnmarshalTypeError{head.MajorString(),typeBytes}
+		}
+	} else if HeadBlobIndefinite != head.Tag() {
+		z, e = this.copy(w,head,0)
+		return z, e
+	} else {
+		var size int = len(head)
+		for {
+			var chunk Object
+			chunk, e = readHead(this.r,false)
+			if nil != e {
+				return z, e
+			} else if HeadBreak == chunk.Tag() {
+				break
+			} else if MajorBlob != chunk.Major() || HeadBlobIndefinite == chunk.Tag() {
+				return z, ErrorChunk
+			}
+			var n int64
+			n, e = this.copy(w,chunk,1)
+			z += n
+			if nil != e {
+				return z, e
+			}
+			size += (len(chunk)+int(n))
+		}
+		if nil != this.hook {
+			e = this.hook(MajorBlob,0,size+1,0)
+		}
+		return z, e
+	}
+}
+/*
+ * Copy the content of a definite length byte string from its
+ * head, and report it to the hook at depth.
+ */
+func (this *Decoder) copy(w io.Writer, head Object, depth int) (z int64, e error) {
+	var arg uint64
+	arg, _, e = head.head()
+	if nil != e {
+		return 0, e
+	} else if math.MaxInt64 < arg {
+		return 0, fmt.Errorf(ErrorWrapRead,ErrorTruncated)
+	}
+	z, e = io.CopyN(w,this.r,int64(arg))
+	if io.EOF == e {
+		return z, fmt.Errorf(ErrorWrapRead,ErrorTruncated)
+	} else if nil != e {
+		return z, e
+	} else if nil != this.hook {
+		return z, this.hook(MajorBlob,0,len(head)+int(z),depth)
+	} else {
+		return z, nil
+	}
+}
+/*
+ * Read the head of a data item, for which end of stream before
+ * the first octet is "io.EOF" when "first", and truncation
+ * otherwise.
+ */
+func readHead(r io.Reader, first bool) (head Object, e error) {
+	head = make(Object,1,9)
+	_, e = io.ReadFull(r,head)
+	if io.EOF == e && first {
+		return nil, e
+	} else if nil != e {
+		return nil, fmt.Errorf(ErrorWrapRead,ErrorTruncated)
+	}
+	switch head[0] & 0x1F {
+	case 0x18, 0x19, 0x1A, 0x1B:
+		head = head[0:1+(1 << ((head[0] & 0x1F)-0x18))]
+		e = readFull(r,head[1:])
+		if nil != e {
+			return nil, e
+		}
+	}
+	return head, nil
+}
+/*
+ */
+func (this walkHook) enter(head Object, depth int) (error) {
+	return nil
+}
+/*
+ * Report the complete data item.
+ */
+func (this walkHook) exit(item Object, depth int) (error) {
+	var major, _, arg, _, e = ParseHead(item)
+	if nil != e {
+		return e
+	} else if MajorTagged != major {
+		arg = 0
+	}
+	return this.hook(major,arg,len(item),depth)
+}
+/*
+ * A reader whose blocking reads are interrupted by a deadline,
+ * as "net.Conn" and "os.File".
+ */
+type deadlineReader interface {
+
+	SetReadDeadline(t time.Time) (error)
+}
+/*
+ * Reader failing with the error of its context once the
+ * context is done.
+ */
+type contextReader struct {
+
+	ctx context.Context
+
+	r io.Reader
+}
+/*
+ * Read from the underlying reader while the context is not
+ * done.
+ */
+func (this contextReader) Read(p []byte) (int, error) {
+	var e error = this.ctx.Err()
+	if nil != e {
+		return 0, e
+	} else {
+		return this.r.Read(p)
+	}
+}
+/*
+ * Read one data item into the value referenced by pointer, as
+ * <Decoder#Decode>, abandoning the read when the context is
+ * cancelled or its deadline passes with the error of the
+ * context.  A reader having "SetReadDeadline" is interrupted
+ * while blocked, and its read deadline is cleared on return.
+ * Any other reader is interrupted between its reads, which
+ * suffices for the chunks and nested data items of an
+ * indefinite length data item arriving from a slow peer.
+ */
+func (this *Decoder) DecodeContext(ctx context.Context, v any) (e error) {
+	var o Object
+	o, e = this.readContext(ctx)
+	if nil != e {
+		return e
+	} else if nil == v {
+		return nil
+	} else {
+		return Unmarshal(o,v)
+	}
+}
+/*
+ * Read one data item, as <Decoder#DecodeContext>.
+ */
+func (this *Decoder) readContext(ctx context.Context) (o Object, e error) {
+	e = ctx.Err()
+	if nil != e {
+		return nil, e
+	}
+	var t, expiring = ctx.Deadline()
+	var deadline, ok = this.r.(deadlineReader)
+	if ok {
+		if expiring {
+			deadline.SetReadDeadline(t)
+		}
+		var done, exited chan struct{} = make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(exited)
+			select {
+			case <-ctx.Done():
+				deadline.SetReadDeadline(time.Unix(1,0))
+			case <-done:
+			}
+		}()
+		defer func() {
+			close(done)
+			<-exited
+			deadline.SetReadDeadline(time.Time{})
+		}()
+	}
+	o, e = walk(contextReader{ctx,this.r},this.visitor())
+	if nil != e {
+		if nil != ctx.Err() {
+			return nil, ctx.Err()
+		} else if ok && expiring && errors.Is(e,os.ErrDeadlineExceeded) {
+			/*
+			 * The read deadline of the context deadline
+			 * may pass before the context reports it.
+			 */
+			return nil, context.DeadlineExceeded
+		} else {
+			return nil, e
+		}
+	} else {
+		return o, nil
+	}
+}
+/*
+ * Write the encoding of each value received from the channel,
+ * in sequence, until the channel is closed, returning nil, or
+ * until the context is done or a write fails, returning that
+ * error.  A value is received only after the encoding of its
+ * predecessor is written, so that a slow writer holds back the
+ * senders on the channel.
+ */
+func EncodeStream(ctx context.Context, w io.Writer, ch <-chan any) (error) {
+	var enc *Encoder = NewEncoder(w)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case v, ok := <-ch:
+			if !ok {
+				return nil
+			} else {
+				var e error = enc.Encode(v)
+				if nil != e {
+					return e
+				}
+			}
+		}
+	}
+}
+/*
+ * Send each data item read from the reader to the channel, in
+ * sequence, until the clean end of the stream, returning nil,
+ * or until the context is done or a read fails, returning that
+ * error, as <Decoder#DecodeContext>.  A data item is read only
+ * after its predecessor is received, so that a slow receiver
+ * holds back the reader.  The channel is closed on return.
+ */
+func DecodeStream(ctx context.Context, r io.Reader, ch chan<- Object) (error) {
+	defer close(ch)
+
+	var dec *Decoder = NewDecoder(r)
+	for {
+		var o, e = dec.readContext(ctx)
+		if io.EOF == e {
+			return nil
+		} else if nil != e {
+			return e
+		} else {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case ch <- o:
+			}
+		}
+	}
+}
+/*
+ * Failure of <ReadLimited> for a data item exceeding the
+ * limit, which is <ErrorSizeExceeded> for "errors.Is".
+ */
+type LimitError struct {
+	/*
+	 * Limit of the data item in octets.
+	 */
+	Limit int64
+	/*
+	 * Octets read from the reader.
+	 */
+	Consumed int64
+}
+/*
+ */
+func (this *LimitError) Error() (string) {
+	return fmt.Sprintf("CBOR data item exceeds limit of %d octets, having read %d",this.Limit,this.Consumed)
+}
+/*
+ */
+func (this *LimitError) Unwrap() (error) {
+	return ErrorSizeExceeded
+}
+/*
+ * Reader counting the octets read, and failing with
+ * <ErrorSizeExceeded> in place of reading beyond the limit.
+ */
+type limitReader struct {
+
+	r io.Reader
+
+	limit, n int64
+}
+/*
+ */
+func (this *limitReader) Read(p []byte) (n int, e error) {
+	if this.n >= this.limit {
+		return 0, ErrorSizeExceeded
+	} else if int64(len(p)) > (this.limit-this.n) {
+		p = p[0:this.limit-this.n]
+	}
+	n, e = this.r.Read(p)
+	this.n += int64(n)
+	return n, e
+}
+/*
+ * Read one data item of at most max octets, as <Object#Read>,
+ * for a request handler reading from an untrusted peer.  The
+ * reader is not read beyond the data item, nor beyond max
+ * octets, so that the octets consumed are those of the data
+ * item, or those of the <LimitError> of a data item exceeding
+ * the limit.
+ */
+func ReadLimited(r io.Reader, max int64) (Object, error) {
+	var limited *limitReader = &limitReader{r, max, 0}
+	var o, e = walk(limited,nil)
+	if errors.Is(e,ErrorSizeExceeded) {
+		return nil, &LimitError{max, limited.n}
+	} else {
+		return o, e
+	}
+}
+/*
+ * Read every data item of the stream in sequence, calling
+ * function with each.  Reading stops at the clean end of the
+ * stream, returning nil, or at the first error of reading or of
+ * the function, returning that error.
+ */
+func ReadAll(r io.Reader, fn func(Object) (error)) (e error) {
+	var o Object
+	for {
+		o = Object{}
+		o, e = o.Read(r)
+		if io.EOF == e {
+			return nil
+		} else if nil != e {
+			return e
+		} else {
+			e = fn(o)
+			if nil != e {
+				return e
+			}
+		}
+	}
+}
+/*
+ * Data item of a sequence, with its index.
+ */
+type sequenceItem struct {
+
+	index int
+
+	item Object
+}
+/*
+ * Read every data item of the stream in sequence, and decode
+ * each on one of a number of goroutines, calling function with
+ * the index of the data item in the stream and its value, as
+ * <Object#Decode>.  Function is called concurrently, in no
+ * particular order of indexes.  A number of workers less than
+ * one is "runtime.GOMAXPROCS".
+ *
+ * Reading stops at the clean end of the stream, returning nil,
+ * or at the first error of reading or of the function,
+ * returning that error after the calls in progress return.
+ */
+func DecodeSequenceParallel(r io.Reader, workers int, fn func(int, any) (error)) (error) {
+	if 1 > workers {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var items chan sequenceItem = make(chan sequenceItem,workers)
+	var done chan struct{} = make(chan struct{})
+	var once sync.Once
+	var failure error
+	var fail func(error) = func(e error){
+		once.Do(func(){
+			failure = e
+			close(done)
+		})
+	}
+	var group sync.WaitGroup
+	for n := 0; n < workers; n++ {
+		group.Add(1)
+		go func(){
+			defer group.Done()
+			for item := range items {
+				select {
+				case <-done:
+				default:
+					var e error = fn(item.index,item.item.Decode())
+					if nil != e {
+						fail(e)
+					}
+				}
+			}
+		}()
+	}
+	for index, reading := 0, true; reading; index++ {
+		var o, e = Object{}.Read(r)
+		if io.EOF == e {
+			reading = false
+		} else if nil != e {
+			fail(e)
+			reading = false
+		} else {
+			select {
+			case <-done:
+				reading = false
+			case items <- sequenceItem{index, o}:
+			}
+		}
+	}
+	close(items)
+	group.Wait()
+
+	return failure
+}
+/*
+ * Read the data item at offset, returning the data item and
+ * its encoded length for the offset of the following data
+ * item.  An offset at the end of the content is "io.EOF".
+ */
+func ReadAt(r io.ReaderAt, off int64) (o Object, z int64, e error) {
+	var section *io.SectionReader = io.NewSectionReader(r,off,math.MaxInt64-off)
+	o = Object{}
+	o, e = o.Read(bufio.NewReader(section))
+	if nil != e {
+		return nil, 0, e
+	} else {
+		return o, int64(len(o)), nil
+	}
+}
+/*
+ * Accumulate octets, and produce the data items completed by
+ * them in sequence.  The octets of an incomplete data item are
+ * retained for the following call, and each octet is scanned
+ * once, in the call receiving it.  A malformed data item is an
+ * error of this and every following call, after the data items
+ * preceding it.  The produced data items do not refer to "p".
+ */
+func (this *Parser) Feed(p []byte) (list []Object, e error) {
+	if nil != this.e {
+		return nil, this.e
+	}
+	this.buffer = append(this.buffer,p...)
+	var offset int = 0
+	for offset < len(this.buffer) && this.scanned() {
+		var item Object
+		item, e = walk(bytes.NewReader(this.buffer[offset:this.scan]),nil)
+		if nil != e {
+			this.e = e
+			break
+		} else {
+			list = append(list,item)
+			offset += len(item)
+			this.scan = offset
+			this.open = this.open[0:0]
+		}
+	}
+	if 0 < offset {
+		this.buffer = append(this.buffer[0:0],this.buffer[offset:]...)
+		this.scan -= offset
+	}
+	if nil == this.e && 0 < this.MaxSize && this.MaxSize < len(this.buffer) {
+		this.e = ErrorSizeExceeded
+	}
+	return list, this.e
+}
+/*
+ * Scan the structure of the data item at the scan offset,
+ * returning whether it is complete at the scan offset.
+ */
+func (this *Parser) scanned() (bool) {
+	for this.scan < len(this.buffer) {
+		var head []byte = this.buffer[this.scan:]
+		var major, ai, arg, z, e = ParseHead(head)
+		if ErrorTruncated == e {
+			return false
+		} else if nil != e {
+			return this.malformed()
+		}
+		var frame walkFrame = walkFrame{major: major, indefinite: (0x1F == ai)}
+		var top int = len(this.open)-1
+		switch major {
+		case MajorBlob, MajorText:
+			if !frame.indefinite {
+				if uint64(len(head)-z) < arg {
+					return false
+				} else if MajorText == major && !utf8.Valid(head[z:z+int(arg)]) {
+					return this.malformed()
+				}
+				z += int(arg)
+			}
+		case MajorArray:
+			frame.count = arg
+		case MajorMap:
+			if (math.MaxUint64/2) < arg {
+				frame.count = math.MaxUint64
+			} else {
+				frame.count = (2*arg)
+			}
+		case MajorTagged:
+			frame.count = 1
+		case MajorSimple:
+			if 0x18 == ai && 32 > arg {
+				return this.malformed()
+			}
+		}
+		this.scan += z
+
+		if frame.indefinite {
+			switch major {
+			case MajorSimple:
+				/*
+				 * Break, completing the indefinite length
+				 * data item enclosing it.
+				 */
+				if 0 > top || !this.open[top].indefinite || (MajorMap == this.open[top].major && 1 == (this.open[top].n & 1)) {
+					return this.malformed()
+				} else {
+					frame = this.open[top]
+					this.open = this.open[0:top]
+				}
+			case MajorUint, MajorSint, MajorTagged:
+				return this.malformed()
+			default:
+				this.open = append(this.open,frame)
+				continue
+			}
+		} else if 0 != frame.count {
+			this.open = append(this.open,frame)
+			continue
+		}
+		/*
+		 * Data item complete, completing the definite length
+		 * data items enclosing it.
+		 */
+		for {
+			top = len(this.open)-1
+			if 0 > top {
+				return true
+			}
+			var parent *walkFrame = &this.open[top]
+			if (MajorBlob == parent.major || MajorText == parent.major) && (frame.major != parent.major || frame.indefinite) {
+				return this.malformed()
+			}
+			parent.n += 1
+			if parent.complete() {
+				frame = *parent
+				this.open = this.open[0:top]
+			} else {
+				break
+			}
+		}
+	}
+	return false
+}
+/*
+ * Complete the malformed data item with the octets of the
+ * buffer, for the error of <walk>.
+ */
+func (this *Parser) malformed() (bool) {
+	this.scan = len(this.buffer)
+	return true
+}
+/*
+ * Count of octets retained for an incomplete data item.
+ */
+func (this *Parser) Buffered() (int) {
+	return len(this.buffer)
+}
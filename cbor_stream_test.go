@@ -0,0 +1,548 @@
+/*
+ * CBOR Streaming Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadAll(t *testing.T){
+	var b bytes.Buffer
+	var enc *Encoder = NewEncoder(&b)
+	for _, text := range []string{"a","b","c"} {
+		var e error = enc.Encode(text)
+		if nil != e {
+			t.Fatal(e)
+		}
+	}
+	var code []byte = b.Bytes()
+
+	var list []string
+	var e error = ReadAll(bufio.NewReader(bytes.NewReader(code)),func(o Object) (error) {
+		list = append(list,o.MustText())
+		return nil
+	})
+	if nil != e {
+		t.Fatal(e)
+	} else if 3 != len(list) || "a" != list[0] || "c" != list[2] {
+		t.Errorf("Expected '[a b c]', found '%v'.",list)
+	}
+
+	var stop error = errors.New("stop")
+	var count int
+	e = ReadAll(bytes.NewReader(code),func(o Object) (error) {
+		count += 1
+		return stop
+	})
+	if stop != e || 1 != count {
+		t.Errorf("Expected '%v' after 1, found '%v' after %d.",stop,e,count)
+	}
+
+	e = ReadAll(bytes.NewReader(code[0:len(code)-1]),func(o Object) (error) {
+		return nil
+	})
+	if !errors.Is(e,io.ErrUnexpectedEOF) {
+		t.Errorf("Expected '%v', found '%v'.",io.ErrUnexpectedEOF,e)
+	}
+}
+
+func TestDecodeSequenceParallel(t *testing.T){
+	var b bytes.Buffer
+	var enc *Encoder = NewEncoder(&b)
+	for n := 0; n < 1000; n++ {
+		var e error = enc.Encode([]any{n, fmt.Sprintf("item-%d",n)})
+		if nil != e {
+			t.Fatal(e)
+		}
+	}
+	var code []byte = b.Bytes()
+
+	var list []any = make([]any,1000)
+	var e error = DecodeSequenceParallel(bytes.NewReader(code),4,func(index int, value any) (error) {
+		list[index] = value
+		return nil
+	})
+	if nil != e {
+		t.Fatal(e)
+	}
+	for n, value := range list {
+		var item, ok = value.([]any)
+		if !ok || 2 != len(item) || fmt.Sprintf("item-%d",n) != item[1] {
+			t.Fatalf("Expected '[%d item-%d]', found '%v'.",n,n,value)
+		}
+	}
+
+	var stop error = errors.New("stop")
+	e = DecodeSequenceParallel(bytes.NewReader(code),0,func(index int, value any) (error) {
+		if 10 == index {
+			return stop
+		} else {
+			return nil
+		}
+	})
+	if stop != e {
+		t.Errorf("Expected '%v', found '%v'.",stop,e)
+	}
+
+	e = DecodeSequenceParallel(bytes.NewReader(code[0:len(code)-1]),4,func(index int, value any) (error) {
+		return nil
+	})
+	if !errors.Is(e,io.ErrUnexpectedEOF) {
+		t.Errorf("Expected '%v', found '%v'.",io.ErrUnexpectedEOF,e)
+	}
+}
+
+func TestReadAt(t *testing.T){
+	var b bytes.Buffer
+	var enc *Encoder = NewEncoder(&b)
+	for _, text := range []string{"a","bb","ccc"} {
+		var e error = enc.Encode(text)
+		if nil != e {
+			t.Fatal(e)
+		}
+	}
+	var r *bytes.Reader = bytes.NewReader(b.Bytes())
+
+	var list []string
+	var off int64
+	for {
+		var o, z, e = ReadAt(r,off)
+		if io.EOF == e {
+			break
+		} else if nil != e {
+			t.Fatal(e)
+		} else {
+			list = append(list,o.MustText())
+			off += z
+		}
+	}
+	if 3 != len(list) || "bb" != list[1] || 9 != off {
+		t.Errorf("Expected '[a bb ccc]' to 9, found '%v' to %d.",list,off)
+	}
+
+	var o, _, e = ReadAt(r,2)
+	if nil != e {
+		t.Fatal(e)
+	} else if "bb" != o.MustText() {
+		t.Errorf("Expected 'bb', found '%s'.",o.MustText())
+	}
+}
+
+func TestDecodeContext(t *testing.T){
+	var text string
+	var dec *Decoder = NewDecoder(bytes.NewReader([]byte{0x61,'a'}))
+	var e error = dec.DecodeContext(context.Background(),&text)
+	if nil != e {
+		t.Fatal(e)
+	} else if "a" != text {
+		t.Errorf("Expected 'a', found '%s'.",text)
+	}
+
+	var cancelled, cancel = context.WithCancel(context.Background())
+	cancel()
+	e = NewDecoder(bytes.NewReader([]byte{0x61,'a'})).DecodeContext(cancelled,&text)
+	if context.Canceled != e {
+		t.Errorf("Expected '%v', found '%v'.",context.Canceled,e)
+	}
+
+	/*
+	 * Indefinite length array of which the peer sends the
+	 * first element, and then nothing.
+	 */
+	var client, server net.Conn = net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	go server.Write([]byte{0x9F,0x01})
+
+	var timeout, stop = context.WithTimeout(context.Background(),20*time.Millisecond)
+	defer stop()
+	var list []int
+	dec = NewDecoder(client)
+	e = dec.DecodeContext(timeout,&list)
+	if context.DeadlineExceeded != e {
+		t.Errorf("Expected '%v', found '%v'.",context.DeadlineExceeded,e)
+	}
+
+	cancelled, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20*time.Millisecond)
+		cancel()
+	}()
+	e = dec.DecodeContext(cancelled,&list)
+	if context.Canceled != e {
+		t.Errorf("Expected '%v', found '%v'.",context.Canceled,e)
+	}
+}
+
+func TestStreamPipeline(t *testing.T){
+	var r, w = io.Pipe()
+	var in chan any = make(chan any)
+	var out chan Object = make(chan Object)
+	var encoded, decoded chan error = make(chan error,1), make(chan error,1)
+	go func() {
+		var e error = EncodeStream(context.Background(),w,in)
+		w.CloseWithError(e)
+		encoded <- e
+	}()
+	go func() {
+		decoded <- DecodeStream(context.Background(),r,out)
+	}()
+	go func() {
+		for n := 0; n < 100; n++ {
+			in <- n
+		}
+		close(in)
+	}()
+	var count int
+	for o := range out {
+		var m int64
+		var e error = Unmarshal(o,&m)
+		if nil != e {
+			t.Fatal(e)
+		} else if int64(count) != m {
+			t.Errorf("Expected %d, found %d.",count,m)
+		}
+		count += 1
+	}
+	if 100 != count {
+		t.Errorf("Expected 100 data items, found %d.",count)
+	}
+	if e := <-encoded; nil != e {
+		t.Error(e)
+	}
+	if e := <-decoded; nil != e {
+		t.Error(e)
+	}
+
+	/*
+	 * Receiver not receiving.
+	 */
+	var cancelled, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20*time.Millisecond)
+		cancel()
+	}()
+	var e error = DecodeStream(cancelled,bytes.NewReader([]byte{0x01,0x02}),make(chan Object))
+	if context.Canceled != e {
+		t.Errorf("Expected '%v', found '%v'.",context.Canceled,e)
+	}
+	e = EncodeStream(cancelled,io.Discard,make(chan any))
+	if context.Canceled != e {
+		t.Errorf("Expected '%v', found '%v'.",context.Canceled,e)
+	}
+	/*
+	 * Truncated stream.
+	 */
+	e = DecodeStream(context.Background(),bytes.NewReader([]byte{0x01,0x62,'a'}),make(chan Object,2))
+	if !errors.Is(e,io.ErrUnexpectedEOF) {
+		t.Errorf("Expected '%v', found '%v'.",io.ErrUnexpectedEOF,e)
+	}
+}
+
+func TestReadLimited(t *testing.T){
+	var r *bytes.Reader = bytes.NewReader([]byte{0x65,'h','e','l','l','o',0x01})
+	var o, e = ReadLimited(r,6)
+	if nil != e {
+		t.Fatal(e)
+	} else if "hello" != o.Decode() || 1 != r.Len() {
+		t.Errorf("Expected 'hello' and one octet following, found '%v' and %d.",o.Decode(),r.Len())
+	}
+
+	r = bytes.NewReader([]byte{0x65,'h','e','l','l','o'})
+	_, e = ReadLimited(r,5)
+	var limit *LimitError
+	if !errors.Is(e,ErrorSizeExceeded) || !errors.As(e,&limit) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorSizeExceeded,e)
+	} else if 5 != limit.Limit || 5 != limit.Consumed || 1 != r.Len() {
+		t.Errorf("Expected 5 of 5 octets consumed, found %d of %d and %d remaining.",limit.Consumed,limit.Limit,r.Len())
+	}
+	/*
+	 * Hostile length prefix.
+	 */
+	var hostile []byte = append([]byte{0x5B,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF},make([]byte,1000)...)
+	_, e = ReadLimited(bytes.NewReader(hostile),100)
+	if !errors.As(e,&limit) || 100 != limit.Consumed {
+		t.Errorf("Expected '%v' having read 100 octets, found '%v'.",ErrorSizeExceeded,e)
+	}
+	_, e = ReadLimited(bytes.NewReader(nil),100)
+	if io.EOF != e {
+		t.Errorf("Expected '%v', found '%v'.",io.EOF,e)
+	}
+}
+
+func TestParserFeed(t *testing.T){
+	var code []byte = []byte{0x61,'a',0x9F,0x01,0x42,0x02,0x03,0xFF,0x19,0x01,0x00}
+	var parser Parser
+	var found []string
+	for _, octet := range code {
+		var list, e = parser.Feed([]byte{octet})
+		if nil != e {
+			t.Fatal(e)
+		}
+		for _, o := range list {
+			found = append(found,hex.EncodeToString(o))
+		}
+	}
+	var expected string = "6161 9f01420203ff 190100"
+	if expected != strings.Join(found," ") {
+		t.Errorf("Expected '%s', found '%s'.",expected,strings.Join(found," "))
+	}
+	if 0 != parser.Buffered() {
+		t.Errorf("Expected empty buffer, found %d.",parser.Buffered())
+	}
+
+	var list, e = parser.Feed([]byte{0x01,0x5A,0x00,0x00})
+	if nil != e {
+		t.Fatal(e)
+	} else if 1 != len(list) || 3 != parser.Buffered() {
+		t.Errorf("Expected one data item and three octets, found %d and %d.",len(list),parser.Buffered())
+	}
+
+	parser = Parser{}
+	list, e = parser.Feed([]byte{0x02,0xFF,0x03})
+	if Break != e || 1 != len(list) {
+		t.Errorf("Expected '%v' after one data item, found '%v' after %d.",Break,e,len(list))
+	}
+	_, e = parser.Feed([]byte{0x04})
+	if Break != e {
+		t.Errorf("Expected '%v', found '%v'.",Break,e)
+	}
+
+	parser = Parser{}
+	list, e = parser.Feed([]byte{0xA2,0x61,0xFF})
+	if !errors.Is(e,ErrorInvalidUTF8) || 0 != len(list) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorInvalidUTF8,e)
+	}
+
+	parser = Parser{}
+	list, e = parser.Feed([]byte{0x5F,0x5F})
+	if nil != e {
+		t.Fatal(e)
+	}
+	list, e = parser.Feed([]byte{0xFF,0xFF})
+	if ErrorChunk != e || 0 != len(list) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorChunk,e)
+	}
+
+	parser = Parser{MaxSize: 4}
+	list, e = parser.Feed([]byte{0x01,0x83,0x01,0x02})
+	if nil != e || 1 != len(list) || 3 != parser.Buffered() {
+		t.Errorf("Expected one data item and three octets, found '%v', %d and %d.",e,len(list),parser.Buffered())
+	}
+	list, e = parser.Feed([]byte{0x03,0x9F,0x01,0x02,0x03,0x04})
+	if ErrorSizeExceeded != e || 1 != len(list) {
+		t.Errorf("Expected '%v' after one data item, found '%v' after %d.",ErrorSizeExceeded,e,len(list))
+	}
+}
+
+func TestOnItem(t *testing.T){
+	var b bytes.Buffer
+	var trace []string
+	var enc *Encoder = NewEncoder(&b)
+	enc.OnItem(func(major Major, tag uint64, size int, depth int) (error) {
+		trace = append(trace,fmt.Sprintf("%d:%d:%d:%d",major,tag,size,depth))
+		return nil
+	})
+	var e error = enc.Encode([]any{uint8(1),Tagged{32,"a"}})
+	if nil != e {
+		t.Fatal(e)
+	}
+	var expected string = "0:0:1:1 3:0:2:2 6:32:4:1 4:0:6:0"
+	if expected != strings.Join(trace," ") {
+		t.Errorf("Expected '%s', found '%s'.",expected,strings.Join(trace," "))
+	}
+
+	var forbidden error = errors.New("tag forbidden")
+	var policy ItemHook = func(major Major, tag uint64, size int, depth int) (error) {
+		if MajorTagged == major && 32 != tag {
+			return forbidden
+		} else {
+			return nil
+		}
+	}
+	var dec *Decoder = NewDecoder(bytes.NewReader(append(b.Bytes(),0x81,0xC1,0x00)))
+	dec.OnItem(policy)
+	var list any
+	e = dec.Decode(&list)
+	if nil != e {
+		t.Fatal(e)
+	}
+	e = dec.Decode(&list)
+	if !errors.Is(e,forbidden) {
+		t.Errorf("Expected '%v', found '%v'.",forbidden,e)
+	}
+
+	enc.OnItem(policy)
+	var z int = b.Len()
+	e = enc.Encode(Tagged{1,uint8(0)})
+	if !errors.Is(e,forbidden) || z != b.Len() {
+		t.Errorf("Expected '%v' without output, found '%v'.",forbidden,e)
+	}
+}
+
+func TestWriteBytesFrom(t *testing.T){
+	var data []byte = make([]byte,10000)
+	for n := range data {
+		data[n] = byte(n)
+	}
+	var b bytes.Buffer
+	var enc *Encoder = NewEncoder(&b)
+	var chunks, total int
+	enc.OnItem(func(major Major, tag uint64, size int, depth int) (error) {
+		if 1 == depth {
+			chunks += 1
+		} else {
+			total = size
+		}
+		return nil
+	})
+	var e error = enc.WriteBytesFrom(bytes.NewReader(data),4096)
+	if nil != e {
+		t.Fatal(e)
+	} else if 3 != chunks {
+		t.Errorf("Expected 3 chunks, found %d.",chunks)
+	} else if b.Len() != total {
+		t.Errorf("Expected size %d, found %d.",b.Len(),total)
+	}
+	var o Object = Object(b.Bytes())
+	var z int
+	z, e = o.ItemLen()
+	if nil != e {
+		t.Fatal(e)
+	} else if len(o) != z {
+		t.Errorf("Expected %d, found %d.",len(o),z)
+	} else if !bytes.Equal(data,o.Decode().([]byte)) {
+		t.Error("Expected content of byte string.")
+	}
+
+	b.Reset()
+	e = NewEncoder(&b).WriteBytesFrom(strings.NewReader(""),16)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0x5F,0xFF},b.Bytes()) {
+		t.Errorf("Expected '5FFF', found '%X'.",b.Bytes())
+	}
+	e = NewEncoder(&b).WriteBytesFrom(strings.NewReader(""),0)
+	if ErrorChunkSize != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorChunkSize,e)
+	}
+}
+
+func TestEncoderTee(t *testing.T){
+	var b bytes.Buffer
+	var h = sha256.New()
+	var enc *Encoder = NewEncoderTee(&b,h)
+	var done chan error = make(chan error)
+	for n := 0; n < 8; n++ {
+		go func(n int){
+			var e error = enc.Encode([]any{n,strings.Repeat("x",n*100)})
+			if nil == e {
+				e = enc.WriteBytesFrom(strings.NewReader(strings.Repeat("y",n*10)),3)
+			}
+			done <- e
+		}(n)
+	}
+	for n := 0; n < 8; n++ {
+		var e error = <-done
+		if nil != e {
+			t.Fatal(e)
+		}
+	}
+	var sum [32]byte = sha256.Sum256(b.Bytes())
+	if !bytes.Equal(sum[:],h.Sum(nil)) {
+		t.Errorf("Expected '%X', found '%X'.",sum[:],h.Sum(nil))
+	}
+	var count int
+	var e error = ReadAll(bytes.NewReader(b.Bytes()),func(o Object) (error) {
+		count += 1
+		return nil
+	})
+	if nil != e {
+		t.Fatal(e)
+	} else if 16 != count {
+		t.Errorf("Expected 16 data items, found %d.",count)
+	}
+}
+
+func TestReadBytesTo(t *testing.T){
+	var data []byte = make([]byte,5000)
+	for n := range data {
+		data[n] = byte(n)
+	}
+	var b bytes.Buffer
+	var enc *Encoder = NewEncoder(&b)
+	var e error = enc.Encode(data)
+	if nil == e {
+		e = enc.WriteBytesFrom(bytes.NewReader(data),1024)
+	}
+	if nil == e {
+		e = enc.Encode("text")
+	}
+	if nil == e {
+		e = enc.Encode([]byte{})
+	}
+	if nil != e {
+		t.Fatal(e)
+	}
+	var dec *Decoder = NewDecoder(&b)
+	var chunks int
+	dec.OnItem(func(major Major, tag uint64, size int, depth int) (error) {
+		if 1 == depth {
+			chunks += 1
+		}
+		return nil
+	})
+	for _, expected := range [][]byte{data,data} {
+		var sink bytes.Buffer
+		var z int64
+		z, e = dec.ReadBytesTo(&sink)
+		if nil != e {
+			t.Fatal(e)
+		} else if int64(len(expected)) != z {
+			t.Errorf("Expected %d, found %d.",len(expected),z)
+		} else if !bytes.Equal(expected,sink.Bytes()) {
+			t.Error("Expected content of byte string.")
+		}
+	}
+	if 5 != chunks {
+		t.Errorf("Expected 5 chunks, found %d.",chunks)
+	}
+	var typed *UnmarshalTypeError
+	_, e = dec.ReadBytesTo(io.Discard)
+	if !errors.As(e,&typed) {
+		t.Errorf("Expected UnmarshalTypeError, found '%v'.",e)
+	}
+	var z int64
+	z, e = dec.ReadBytesTo(io.Discard)
+	if nil != e || 0 != z {
+		t.Errorf("Expected empty byte string, found %d (%v).",z,e)
+	}
+	_, e = dec.ReadBytesTo(io.Discard)
+	if io.EOF != e {
+		t.Errorf("Expected '%v', found '%v'.",io.EOF,e)
+	}
+
+	for _, truncated := range []Object{{0x44,0x01,0x02},{0x5F,0x42,0x01},{0x5F,0x41,0x01},{0x59,0x01}} {
+		_, e = NewDecoder(bytes.NewReader(truncated)).ReadBytesTo(io.Discard)
+		if !errors.Is(e,io.ErrUnexpectedEOF) {
+			t.Errorf("[%X] Expected '%v', found '%v'.",[]byte(truncated),io.ErrUnexpectedEOF,e)
+		}
+	}
+	_, e = NewDecoder(bytes.NewReader([]byte{0x5F,0x61,0x61,0xFF})).ReadBytesTo(io.Discard)
+	if ErrorChunk != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorChunk,e)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+ * CBOR String References
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * http://cbor.schmorp.de/stringref
+ * https://www.iana.org/assignments/cbor-tags/cbor-tags.xhtml
+ */
+package cbor
+
+import (
+	"errors"
+)
+/*
+ * Reference to a string of the enclosing namespace.
+ */
+const TagStringRef uint64 = 25
+/*
+ * Namespace of string references.
+ */
+const TagStringRefNamespace uint64 = 256
+/*
+ * Validation errors produced by <Object#ResolveStringRefs>.
+ */
+var ErrorStringRef error = errors.New("CBOR string reference undefined")
+/*
+ * String table of a namespace (tag 256).  The table of
+ * resolution is a list of string encodings by index, and the
+ * table of emission is an index by major type and payload.
+ */
+type stringRefs struct {
+
+	resolve bool
+
+	table []Object
+
+	index map[string]uint64
+}
+/*
+ * Open the string table of a namespace.
+ */
+func newStringRefs(resolve bool) (*stringRefs) {
+	return &stringRefs{resolve, nil, map[string]uint64{}}
+}
+/*
+ * Minimum payload length of a string entering the table at
+ * index, being the length of its reference, such that a
+ * reference is never longer than the string it replaces.
+ */
+func stringRefMinimum(index uint64) (int) {
+	if 24 > index {
+		return 3
+	} else if 0x100 > index {
+		return 4
+	} else if 0x10000 > index {
+		return 5
+	} else if 0x100000000 > index {
+		return 7
+	} else {
+		return 11
+	}
+}
+/*
+ * Define the (first) data item of the object within a
+ * namespace (tag 256), replacing repeated definite length
+ * strings with references (tag 25) to their first occurrence.
+ * See <EncOptions> "StringRefs".
+ */
+func (this Object) StringRefs() (Object, error) {
+	var content, e = this.stringref(newStringRefs(false))
+	if nil != e {
+		return nil, e
+	} else {
+		return tagging(TagStringRefNamespace,content), nil
+	}
+}
+/*
+ * Define the (first) data item of the object with every
+ * namespace (tag 256) removed and every reference (tag 25)
+ * replaced by the string it refers to.  A reference outside
+ * of a namespace, or beyond the strings of its namespace, is
+ * <ErrorStringRef>.  See <DecOptions> "StringRefs".
+ */
+func (this Object) ResolveStringRefs() (Object, error) {
+	return this.stringref(nil)
+}
+/*
+ * Define the data item under the string table, which is nil
+ * outside of a namespace in resolution.
+ */
+func (this Object) stringref(refs *stringRefs) (Object, error) {
+	var arg, z, e = this.head()
+	if nil != e {
+		return nil, e
+	}
+	var major Major = this.Major()
+	var indefinite bool = (0x1F == (this[0] & 0x1F))
+	switch major {
+	case MajorBlob, MajorText:
+		if indefinite || nil == refs {
+			var n int
+			n, e = this.ItemLen()
+			if nil != e {
+				return nil, e
+			} else {
+				return this[0:n].Clone(), nil
+			}
+		} else {
+			var payload []byte
+			payload, e = this.payload()
+			if nil != e {
+				return nil, e
+			}
+			var literal Object = this[0:z+len(payload)]
+			var key string = string(append([]byte{byte(major)},payload...))
+			var index, ok = refs.index[key]
+			if ok && !refs.resolve {
+				return tagging(TagStringRef,define(MajorUint,index)), nil
+			} else {
+				/*
+				 * In resolution, every literal string of the
+				 * namespace is appended to the table, repeated
+				 * or not, as the encoder may not have referred
+				 * to a repeated string.
+				 */
+				if (!ok || refs.resolve) && len(payload) >= stringRefMinimum(uint64(len(refs.table))) {
+					refs.index[key] = uint64(len(refs.table))
+					refs.table = append(refs.table,literal)
+				}
+				return literal, nil
+			}
+		}
+
+	case MajorArray, MajorMap:
+		var list []Object
+		list, e = this.items()
+		if nil != e {
+			return nil, e
+		}
+		var content Object = Object{}.Concatenate(this[0:z])
+		for _, item := range list {
+			item, e = item.stringref(refs)
+			if nil != e {
+				return nil, e
+			} else {
+				content = item.AppendTo(content)
+			}
+		}
+		if indefinite {
+			content = append(content,0xFF)
+		}
+		return content, nil
+
+	case MajorTagged:
+		if indefinite || z >= len(this) {
+			return nil, ErrorTruncated
+		}
+		var content Object = this[z:]
+		switch {
+		case TagStringRefNamespace == arg:
+			var nested *stringRefs = newStringRefs(nil == refs || refs.resolve)
+			content, e = content.stringref(nested)
+			if nil != e {
+				return nil, e
+			} else if nested.resolve {
+				return content, nil
+			} else {
+				return tagging(arg,content), nil
+			}
+
+		case TagStringRef == arg && (nil == refs || refs.resolve):
+			var index uint64
+			index, e = content.Uint()
+			if nil != e || nil == refs || index >= uint64(len(refs.table)) {
+				return nil, ErrorStringRef
+			} else {
+				return refs.table[index], nil
+			}
+
+		default:
+			content, e = content.stringref(refs)
+			if nil != e {
+				return nil, e
+			} else {
+				return Object{}.Concatenate(this[0:z]).Concatenate(content), nil
+			}
+		}
+
+	default:
+		return this[0:z].Clone(), nil
+	}
+}
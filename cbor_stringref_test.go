@@ -0,0 +1,74 @@
+/*
+ * CBOR String References Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestStringRefResolve(t *testing.T){
+	var o Object = Object{0xD9,0x01,0x00,0x84,0x63,'a','a','a',0xD8,0x19,0x00,0x62,'b','b',0x62,'b','b'}
+	var resolved, e = o.ResolveStringRefs()
+	if nil != e {
+		t.Fatal(e)
+	}
+	var expected string = "846361616163616161626262626262"
+	if expected != hex.EncodeToString(resolved) {
+		t.Errorf("Expected '%s', found '%s'.",expected,hex.EncodeToString(resolved))
+	}
+	/*
+	 * Repeated literal string, occupying a second index.
+	 */
+	o = Object{0xD9,0x01,0x00,0x84,0x63,'a','a','a',0x63,'a','a','a',0x63,'b','b','b',0xD8,0x19,0x02}
+	resolved, e = o.ResolveStringRefs()
+	if nil != e {
+		t.Fatal(e)
+	}
+	expected = "8463616161636161616362626263626262"
+	if expected != hex.EncodeToString(resolved) {
+		t.Errorf("Expected '%s', found '%s'.",expected,hex.EncodeToString(resolved))
+	}
+
+	for _, invalid := range []Object{
+		Object{0xD8,0x19,0x00},
+		Object{0xD9,0x01,0x00,0x82,0x62,'b','b',0xD8,0x19,0x00},
+	} {
+		_, e = invalid.ResolveStringRefs()
+		if ErrorStringRef != e {
+			t.Errorf("Expected '%v', found '%v'.",ErrorStringRef,e)
+		}
+	}
+}
+
+func TestStringRefEncode(t *testing.T){
+	var fleet []any = []any{
+		[]any{"sensor",uint16(20)},[]any{"sensor",uint16(21)},[]any{"sensor",uint16(22)},[]any{"sensor",uint16(23)},
+	}
+	var plain Object = Encode(fleet)
+	var o, e = EncOptions{StringRefs: true}.Encode(fleet)
+	if nil != e {
+		t.Fatal(e)
+	}
+	if 0xD9 != o[0] || len(o) >= len(plain) {
+		t.Errorf("Expected namespace shorter than %d, found %d.",len(plain),len(o))
+	}
+	var resolved Object
+	resolved, e = o.ResolveStringRefs()
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(plain,resolved) {
+		t.Errorf("Expected '%s', found '%s'.",hex.EncodeToString(plain),hex.EncodeToString(resolved))
+	}
+
+	var decoded any
+	e = DecOptions{StringRefs: true}.Unmarshal(o,&decoded)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(plain,Encode(decoded)) {
+		t.Errorf("Expected '%v', found '%v'.",fleet,decoded)
+	}
+}
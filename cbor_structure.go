@@ -0,0 +1,151 @@
+/*
+ * CBOR RFC8949 Structure
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3
+ */
+package cbor
+
+import (
+	"fmt"
+	"strings"
+)
+/*
+ * Data item of the structure tree of an object.
+ */
+type Node struct {
+
+	Major Major
+	/*
+	 * Initial byte of the data item.
+	 */
+	Tag Tag
+	/*
+	 * Head argument: integer value, string length, count of
+	 * array elements or map entries, tag number, or simple
+	 * value or float bits.  Zero for indefinite length.
+	 */
+	Argument uint64
+
+	Indefinite bool
+	/*
+	 * Octet count of the head of the data item.
+	 */
+	Head int
+	/*
+	 * Octet count of the data item encoding.
+	 */
+	Length int
+	/*
+	 * Octet offset of the data item within the object.
+	 */
+	Offset int
+	/*
+	 * Array elements, alternating map keys and values, tag
+	 * content, or indefinite length string chunks.
+	 */
+	Children []*Node
+}
+/*
+ * Visitor producing the structure tree of a data item.
+ */
+type walkStructure struct {
+
+	root *Node
+
+	stack []*Node
+
+	offset int
+}
+/*
+ * Produce the structure tree of the (first) data item of the
+ * object.
+ */
+func (this Object) Structure() (*Node, error) {
+	var structure walkStructure
+	var _, e = this.walk(&structure)
+	if nil != e {
+		return nil, e
+	} else {
+		return structure.root, nil
+	}
+}
+/*
+ * Open the node of a data item.
+ */
+func (this *walkStructure) enter(head Object, depth int) (error) {
+	var major, ai, arg, z, _ = ParseHead(head)
+	var node *Node = &Node{Major: major, Tag: Tag(head[0]), Argument: arg, Indefinite: (0x1F == ai), Head: z, Offset: this.offset}
+
+	var top int = len(this.stack)-1
+	if 0 <= top {
+		this.stack[top].Children = append(this.stack[top].Children,node)
+	} else {
+		this.root = node
+	}
+	this.stack = append(this.stack,node)
+	this.offset += z
+	return nil
+}
+/*
+ * Close the node of a data item.
+ */
+func (this *walkStructure) exit(item Object, depth int) (error) {
+	var top int = len(this.stack)-1
+	var node *Node = this.stack[top]
+	node.Length = len(item)
+	this.offset = (node.Offset+node.Length)
+	this.stack = this.stack[0:top]
+	return nil
+}
+/*
+ * Represent node structure, as for <Object#Describe>.
+ */
+func (this *Node) Describe() (string) {
+	var desc strings.Builder
+	this.describe(&desc)
+	return desc.String()
+}
+/*
+ * Append the description of the node and its children.
+ */
+func (this *Node) describe(desc *strings.Builder) {
+	var width string
+	switch this.Head {
+	case 2:
+		width = "uint8"
+	case 3:
+		width = "uint16"
+	case 5:
+		width = "uint32"
+	case 9:
+		width = "uint64"
+	}
+	fmt.Fprintf(desc,"<tag:%s>",Object{byte(this.Tag)}.MajorString())
+	switch this.Major {
+	case MajorBlob, MajorText:
+		if !this.Indefinite {
+			if "" != width {
+				fmt.Fprintf(desc,"<%s>",width)
+			}
+			fmt.Fprintf(desc,"<byte[%d]>",this.Argument)
+		}
+	case MajorArray, MajorMap:
+		if "" != width {
+			fmt.Fprintf(desc,"<%s[%d]>",width,this.Argument)
+		}
+	default:
+		if "" != width {
+			fmt.Fprintf(desc,"<%s>",width)
+		}
+	}
+	for _, child := range this.Children {
+		child.describe(desc)
+	}
+	if this.Indefinite {
+		desc.WriteString("<break>")
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * CBOR RFC8949 Structure Rendering
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://graphviz.org/doc/info/lang.html
+ * https://html.spec.whatwg.org/multipage/grouping-content.html#the-ul-element
+ */
+package cbor
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+)
+/*
+ * Write the structure tree as a Graphviz DOT digraph, having a
+ * box for each data item labeled with its major type, argument,
+ * size and offset.
+ */
+func (this *Node) WriteDOT(w io.Writer) (e error) {
+	_, e = io.WriteString(w,"digraph cbor {\n\tnode [shape=box];\n")
+	if nil != e {
+		return e
+	}
+	var id int = 0
+	e = this.writeDOT(w,&id)
+	if nil != e {
+		return e
+	}
+	_, e = io.WriteString(w,"}\n")
+	return e
+}
+/*
+ * Write the node and its children, identified by preorder
+ * position.
+ */
+func (this *Node) writeDOT(w io.Writer, id *int) (e error) {
+	var node int = *id
+	*id += 1
+	_, e = fmt.Fprintf(w,"\tn%d [label=%s];\n",node,strconv.Quote(this.label()+"\n"+this.size()))
+	if nil != e {
+		return e
+	}
+	for _, child := range this.Children {
+		_, e = fmt.Fprintf(w,"\tn%d -> n%d;\n",node,*id)
+		if nil != e {
+			return e
+		}
+		e = child.writeDOT(w,id)
+		if nil != e {
+			return e
+		}
+	}
+	return nil
+}
+/*
+ * Write the structure tree as nested HTML lists, having an item
+ * for each data item with its major type, argument, size and
+ * offset.
+ */
+func (this *Node) WriteHTML(w io.Writer) (e error) {
+	_, e = io.WriteString(w,"<ul class=\"cbor\">\n")
+	if nil != e {
+		return e
+	}
+	e = this.writeHTML(w)
+	if nil != e {
+		return e
+	}
+	_, e = io.WriteString(w,"</ul>\n")
+	return e
+}
+/*
+ * Write the list item of the node and its children.
+ */
+func (this *Node) writeHTML(w io.Writer) (e error) {
+	_, e = fmt.Fprintf(w,"<li><span class=\"item\">%s</span> <span class=\"size\">%s</span>",html.EscapeString(this.label()),html.EscapeString(this.size()))
+	if nil != e {
+		return e
+
+	} else if 0 < len(this.Children) {
+		_, e = io.WriteString(w,"\n<ul>\n")
+		if nil != e {
+			return e
+		}
+		for _, child := range this.Children {
+			e = child.writeHTML(w)
+			if nil != e {
+				return e
+			}
+		}
+		_, e = io.WriteString(w,"</ul>\n")
+		if nil != e {
+			return e
+		}
+	}
+	_, e = io.WriteString(w,"</li>\n")
+	return e
+}
+/*
+ * Describe the major type and argument of the node.
+ */
+func (this *Node) label() (string) {
+	var major string = Object{byte(this.Tag)}.MajorString()
+	switch this.Major {
+	case MajorUint:
+		return fmt.Sprintf("%s %d",major,this.Argument)
+	case MajorSint:
+		return fmt.Sprintf("%s %s",major,negativeString(this.Argument))
+	case MajorBlob, MajorText, MajorArray, MajorMap:
+		if this.Indefinite {
+			return fmt.Sprintf("%s [_]",major)
+		} else {
+			return fmt.Sprintf("%s [%d]",major,this.Argument)
+		}
+	case MajorTagged:
+		return fmt.Sprintf("tag %d",this.Argument)
+	default:
+		return major
+	}
+}
+/*
+ * Describe the octet count and offset of the node.
+ */
+func (this *Node) size() (string) {
+	return fmt.Sprintf("%d octets @%d",this.Length,this.Offset)
+}
@@ -0,0 +1,78 @@
+/*
+ * CBOR Structure Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStructure(t *testing.T){
+	var o Object = Object{0xA2,0x61,'a',0x01,0x61,'b',0x9F,0x59,0x00,0x01,0xFF,0xFF}
+	var root, e = o.Structure()
+	if nil != e {
+		t.Fatal(e)
+	} else if MajorMap != root.Major || 2 != root.Argument || len(o) != root.Length || 4 != len(root.Children) {
+		t.Fatalf("Expected map of two entries, found '%v'.",root)
+	}
+	var list *Node = root.Children[3]
+	if MajorArray != list.Major || !list.Indefinite || 6 != list.Offset || 6 != list.Length || 1 != len(list.Children) {
+		t.Errorf("Expected indefinite array at 6, found '%v'.",list)
+	}
+	var blob *Node = list.Children[0]
+	if Tag(0x59) != blob.Tag || 7 != blob.Offset || 3 != blob.Head || 4 != blob.Length || 1 != blob.Argument {
+		t.Errorf("Expected byte string at 7, found '%v'.",blob)
+	}
+	var desc string = "<tag:map><tag:text><byte[1]><tag:unsigned integer><tag:text><byte[1]><tag:array><tag:blob><uint16><byte[1]><break>"
+	if desc != o.Describe() || desc != root.Describe() {
+		t.Errorf("Expected '%s', found '%s'.",desc,o.Describe())
+	}
+
+	_, e = Object{0x82,0x01}.Structure()
+	if nil == e {
+		t.Errorf("Expected error for truncated array.")
+	}
+}
+
+func TestStructureRender(t *testing.T){
+	var root, e = Object{0x82,0x38,0x63,0xD8,0x20,0x61,'<'}.Structure()
+	if nil != e {
+		t.Fatal(e)
+	}
+	var dot strings.Builder
+	e = root.WriteDOT(&dot)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var expected string = "digraph cbor {\n\tnode [shape=box];\n" +
+		"\tn0 [label=\"array [2]\\n7 octets @0\"];\n" +
+		"\tn0 -> n1;\n" +
+		"\tn1 [label=\"signed integer -100\\n2 octets @1\"];\n" +
+		"\tn0 -> n2;\n" +
+		"\tn2 [label=\"tag 32\\n4 octets @3\"];\n" +
+		"\tn2 -> n3;\n" +
+		"\tn3 [label=\"text [1]\\n2 octets @5\"];\n" +
+		"}\n"
+	if expected != dot.String() {
+		t.Errorf("Expected '%s', found '%s'.",expected,dot.String())
+	}
+
+	var text strings.Builder
+	e = root.WriteHTML(&text)
+	if nil != e {
+		t.Fatal(e)
+	}
+	expected = "<ul class=\"cbor\">\n" +
+		"<li><span class=\"item\">array [2]</span> <span class=\"size\">7 octets @0</span>\n<ul>\n" +
+		"<li><span class=\"item\">signed integer -100</span> <span class=\"size\">2 octets @1</span></li>\n" +
+		"<li><span class=\"item\">tag 32</span> <span class=\"size\">4 octets @3</span>\n<ul>\n" +
+		"<li><span class=\"item\">text [1]</span> <span class=\"size\">2 octets @5</span></li>\n" +
+		"</ul>\n</li>\n" +
+		"</ul>\n</li>\n" +
+		"</ul>\n"
+	if expected != text.String() {
+		t.Errorf("Expected '%s', found '%s'.",expected,text.String())
+	}
+}
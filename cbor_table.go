@@ -0,0 +1,245 @@
+// Code generated by doc/cbor-rfc8949-table.go from doc/cbor-rfc8949-table.txt; DO NOT EDIT.
+
+/*
+ * CBOR RFC8949 Head and Tag Number Constants
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#appendix-B
+ */
+package cbor
+
+/*
+ * unsigned integer 0x00..0x17 (0..23)
+ */
+const HeadUint Tag = 0x00
+/*
+ * unsigned integer (one-byte uint8_t follows)
+ */
+const HeadUint8Follow Tag = 0x18
+/*
+ * unsigned integer (two-byte uint16_t follows)
+ */
+const HeadUint16Follow Tag = 0x19
+/*
+ * unsigned integer (four-byte uint32_t follows)
+ */
+const HeadUint32Follow Tag = 0x1A
+/*
+ * unsigned integer (eight-byte uint64_t follows)
+ */
+const HeadUint64Follow Tag = 0x1B
+/*
+ * negative integer -1-0x00..-1-0x17 (-1..-24)
+ */
+const HeadSint Tag = 0x20
+/*
+ * negative integer -1-n (one-byte uint8_t for n follows)
+ */
+const HeadSint8Follow Tag = 0x38
+/*
+ * negative integer -1-n (two-byte uint16_t for n follows)
+ */
+const HeadSint16Follow Tag = 0x39
+/*
+ * negative integer -1-n (four-byte uint32_t for n follows)
+ */
+const HeadSint32Follow Tag = 0x3A
+/*
+ * negative integer -1-n (eight-byte uint64_t for n follows)
+ */
+const HeadSint64Follow Tag = 0x3B
+/*
+ * byte string (0x00..0x17 bytes follow)
+ */
+const HeadBlob Tag = 0x40
+/*
+ * byte string (one-byte uint8_t for n, and then n bytes follow)
+ */
+const HeadBlob8Follow Tag = 0x58
+/*
+ * byte string (two-byte uint16_t for n, and then n bytes follow)
+ */
+const HeadBlob16Follow Tag = 0x59
+/*
+ * byte string (four-byte uint32_t for n, and then n bytes follow)
+ */
+const HeadBlob32Follow Tag = 0x5A
+/*
+ * byte string (eight-byte uint64_t for n, and then n bytes follow)
+ */
+const HeadBlob64Follow Tag = 0x5B
+/*
+ * byte string, byte strings follow, terminated by 'break'
+ */
+const HeadBlobIndefinite Tag = 0x5F
+/*
+ * UTF-8 string (0x00..0x17 bytes follow)
+ */
+const HeadText Tag = 0x60
+/*
+ * UTF-8 string (one-byte uint8_t for n, and then n bytes follow)
+ */
+const HeadText8Follow Tag = 0x78
+/*
+ * UTF-8 string (two-byte uint16_t for n, and then n bytes follow)
+ */
+const HeadText16Follow Tag = 0x79
+/*
+ * UTF-8 string (four-byte uint32_t for n, and then n bytes follow)
+ */
+const HeadText32Follow Tag = 0x7A
+/*
+ * UTF-8 string (eight-byte uint64_t for n, and then n bytes follow)
+ */
+const HeadText64Follow Tag = 0x7B
+/*
+ * UTF-8 string, UTF-8 strings follow, terminated by 'break'
+ */
+const HeadTextIndefinite Tag = 0x7F
+/*
+ * array (0x00..0x17 data items follow)
+ */
+const HeadArray Tag = 0x80
+/*
+ * array (one-byte uint8_t for n, and then n data items follow)
+ */
+const HeadArray8Follow Tag = 0x98
+/*
+ * array (two-byte uint16_t for n, and then n data items follow)
+ */
+const HeadArray16Follow Tag = 0x99
+/*
+ * array (four-byte uint32_t for n, and then n data items follow)
+ */
+const HeadArray32Follow Tag = 0x9A
+/*
+ * array (eight-byte uint64_t for n, and then n data items follow)
+ */
+const HeadArray64Follow Tag = 0x9B
+/*
+ * array, data items follow, terminated by 'break'
+ */
+const HeadArrayIndefinite Tag = 0x9F
+/*
+ * map (0x00..0x17 pairs of data items follow)
+ */
+const HeadMap Tag = 0xA0
+/*
+ * map (one-byte uint8_t for n, and then n pairs of data items follow)
+ */
+const HeadMap8Follow Tag = 0xB8
+/*
+ * map (two-byte uint16_t for n, and then n pairs of data items follow)
+ */
+const HeadMap16Follow Tag = 0xB9
+/*
+ * map (four-byte uint32_t for n, and then n pairs of data items follow)
+ */
+const HeadMap32Follow Tag = 0xBA
+/*
+ * map (eight-byte uint64_t for n, and then n pairs of data items follow)
+ */
+const HeadMap64Follow Tag = 0xBB
+/*
+ * map, pairs of data items follow, terminated by 'break'
+ */
+const HeadMapIndefinite Tag = 0xBF
+/*
+ * tag (data item follows)
+ */
+const HeadTag Tag = 0xC0
+/*
+ * text-based date/time (data item follows; see Section 3.4.1)
+ */
+const TagDateTimeString uint64 = 0
+/*
+ * epoch-based date/time (data item follows; see Section 3.4.2)
+ */
+const TagDateTimeEpoch uint64 = 1
+/*
+ * unsigned bignum (data item 'byte string' follows)
+ */
+const TagUnsignedBignum uint64 = 2
+/*
+ * negative bignum (data item 'byte string' follows)
+ */
+const TagNegativeBignum uint64 = 3
+/*
+ * decimal Fraction (data item 'array' follows; see Section 3.4.4)
+ */
+const TagDecimalFraction uint64 = 4
+/*
+ * bigfloat (data item 'array' follows; see Section 3.4.4)
+ */
+const TagBigfloat uint64 = 5
+/*
+ * expected conversion (data item follows; see Section 3.4.5.2)
+ */
+const TagExpectBase64URL uint64 = 21
+/*
+ * expected conversion (data item follows; see Section 3.4.5.2)
+ */
+const TagExpectBase64 uint64 = 22
+/*
+ * expected conversion (data item follows; see Section 3.4.5.2)
+ */
+const TagExpectBase16 uint64 = 23
+/*
+ * (more tags; 1/2/4/8 bytes of tag number and then a data item follow)
+ */
+const HeadTag8Follow Tag = 0xD8
+/*
+ * (more tags; 1/2/4/8 bytes of tag number and then a data item follow)
+ */
+const HeadTag16Follow Tag = 0xD9
+/*
+ * (more tags; 1/2/4/8 bytes of tag number and then a data item follow)
+ */
+const HeadTag32Follow Tag = 0xDA
+/*
+ * (more tags; 1/2/4/8 bytes of tag number and then a data item follow)
+ */
+const HeadTag64Follow Tag = 0xDB
+/*
+ * (simple value)
+ */
+const HeadSimple Tag = 0xE0
+/*
+ * false
+ */
+const HeadFalse Tag = 0xF4
+/*
+ * true
+ */
+const HeadTrue Tag = 0xF5
+/*
+ * null
+ */
+const HeadNull Tag = 0xF6
+/*
+ * undefined
+ */
+const HeadUndefined Tag = 0xF7
+/*
+ * (simple value, one byte follows)
+ */
+const HeadSimple8Follow Tag = 0xF8
+/*
+ * half-precision float (two-byte IEEE 754)
+ */
+const HeadFloat16 Tag = 0xF9
+/*
+ * single-precision float (four-byte IEEE 754)
+ */
+const HeadFloat32 Tag = 0xFA
+/*
+ * double-precision float (eight-byte IEEE 754)
+ */
+const HeadFloat64 Tag = 0xFB
+/*
+ * 'break' stop code
+ */
+const HeadBreak Tag = 0xFF
@@ -0,0 +1,303 @@
+/*
+ * CBOR RFC8949 Tagged Data Items
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.4
+ * https://www.iana.org/assignments/cbor-tags/cbor-tags.xhtml
+ */
+package cbor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+/*
+ * Encoded CBOR data item.  See Section 3.4.5.1 [RFC8949].
+ */
+const TagEmbedded uint64 = 24
+/*
+ * Text strings.  See Section 3.4.5.3 [RFC8949].
+ */
+const TagURI uint64 = 32
+const TagBase64URL uint64 = 33
+const TagBase64 uint64 = 34
+const TagRegexp uint64 = 35
+const TagMIME uint64 = 36
+/*
+ * Binary UUID.  See RFC4122.
+ */
+const TagUUID uint64 = 37
+/*
+ * Encoded data item content of tag 24.  The encoding of an
+ * embedded object is byte exact, as required for verification
+ * of signatures over the data item.
+ */
+type Embedded Object
+/*
+ * Binary content of tag 33, carried as base64url text.
+ */
+type Base64URL []byte
+/*
+ * Binary content of tag 34, carried as base64 text.
+ */
+type Base64 []byte
+/*
+ * MIME message content of tag 36, including headers.
+ */
+type MIME string
+/*
+ * Binary UUID content of tag 37.
+ */
+type UUID [16]byte
+/*
+ * Tag number and content of any tagged data item, for tag
+ * numbers without a registered decoder.
+ */
+type Tagged struct {
+
+	Number uint64
+
+	Content any
+}
+/*
+ * Represent UUID in RFC4122 hexadecimal form.
+ */
+func (this UUID) String() (string) {
+	return fmt.Sprintf("%x-%x-%x-%x-%x",this[0:4],this[4:6],this[6:8],this[8:10],this[10:16])
+}
+/*
+ * Tag content coding by tag number.  A decoder returns nil for
+ * content that is not valid for the tag, which decodes as
+ * <Tagged>.  An encoder produces
+ * the content of the tag for a value of the Go type of the tag,
+ * when "ok", and is nil for tags encoded otherwise.
+ */
+type tagCoding struct {
+
+	decode func(Object) (any)
+
+	encode func(any) (any, bool)
+}
+var tagRegistry map[uint64]tagCoding
+
+func init(){
+	tagRegistry = map[uint64]tagCoding{
+		TagEmbedded: {decodeEmbedded,nil},
+		TagURI: {decodeURI,encodeURI},
+		TagBase64URL: {decodeBase64URL,encodeBase64URL},
+		TagBase64: {decodeBase64,encodeBase64},
+		TagRegexp: {decodeRegexp,encodeRegexp},
+		TagMIME: {decodeMIME,encodeMIME},
+		TagUUID: {decodeUUID,nil},
+		TagDays: {decodeDays,nil},
+		TagDate: {decodeDate,nil},
+		TagIPv4: {decodeIPv4,nil},
+		TagIPv6: {decodeIPv6,nil},
+	}
+}
+/*
+ * Encode value under the tag number of the registered encoder
+ * accepting it, when "ok".
+ */
+func encodeTag(a any, state *encoding) (Object, bool) {
+	for number, coding := range tagRegistry {
+		if nil != coding.encode {
+			var content, ok = coding.encode(a)
+			if ok {
+				return tagging(number,encode(content,state)), true
+			}
+		}
+	}
+	return nil, false
+}
+/*
+ * Define object as tag number followed by content.
+ */
+func tagging(number uint64, content Object) (this Object) {
+
+	this = define(MajorTagged,number)
+
+	this = this.Concatenate(content)
+
+	return this
+}
+/*
+ * Resolve tag number and content of tagged object.
+ */
+func (this Object) tagged() (number uint64, content Object, ok bool) {
+	var major, ai, arg, z, e = ParseHead(this)
+	if nil == e && MajorTagged == major && 0x1F != ai && z < len(this) {
+		return arg, this[z:], true
+	} else {
+		return 0, nil, false
+	}
+}
+/*
+ * Resolve content of tagged object by registered <Coder>, by
+ * registered type, or by tag number, or as <Tagged> of the
+ * decoded value of its content for tag numbers without a
+ * registered decoder, and for content that the registered
+ * decoder rejects.  Tag number decoders are not applied to
+ * tagged content, which no registered decoder accepts, such
+ * that nested tags are decoded in linear time.
+ */
+func (this Object) decodeTagged(value any) (any) {
+	var number, content, ok = this.tagged()
+	if ok {
+		var coder Coder
+		coder, ok = decodeCoder(number,content)
+		if ok {
+			return coder
+		}
+		var typed any
+		typed, ok = decodeType(number,content)
+		if ok {
+			return typed
+		}
+		var coding tagCoding
+		coding, ok = tagRegistry[number]
+		if ok && MajorTagged != content.Major() {
+			var decoded any = coding.decode(content)
+			if nil != decoded {
+				return decoded
+			}
+		}
+		return Tagged{number,value}
+	}
+	return nil
+}
+/*
+ */
+func decodeEmbedded(content Object) (any) {
+	var embedded, ok = content.Decode().([]byte)
+	if ok {
+		return Object(embedded)
+	} else {
+		return nil
+	}
+}
+/*
+ */
+func decodeURI(content Object) (any) {
+	var text, ok = content.Decode().(string)
+	if ok {
+		var uri, e = url.Parse(text)
+		if nil == e {
+			return uri
+		}
+	}
+	return nil
+}
+/*
+ */
+func decodeBase64URL(content Object) (any) {
+	var text, ok = content.Decode().(string)
+	if ok {
+		var data, e = base64.RawURLEncoding.DecodeString(text)
+		if nil == e {
+			return data
+		}
+	}
+	return nil
+}
+/*
+ */
+func decodeBase64(content Object) (any) {
+	var text, ok = content.Decode().(string)
+	if ok {
+		var data, e = base64.StdEncoding.DecodeString(text)
+		if nil == e {
+			return data
+		}
+	}
+	return nil
+}
+/*
+ */
+func decodeRegexp(content Object) (any) {
+	var text, ok = content.Decode().(string)
+	if ok {
+		var re, e = regexp.Compile(text)
+		if nil == e {
+			return re
+		}
+	}
+	return nil
+}
+/*
+ */
+func decodeMIME(content Object) (any) {
+	var text, ok = content.Decode().(string)
+	if ok {
+		return MIME(text)
+	} else {
+		return nil
+	}
+}
+/*
+ */
+func encodeURI(value any) (any, bool) {
+	var uri, ok = value.(*url.URL)
+	if ok && nil != uri {
+		return uri.String(), true
+	} else {
+		return nil, false
+	}
+}
+/*
+ */
+func encodeBase64URL(value any) (any, bool) {
+	var data, ok = value.(Base64URL)
+	if ok {
+		return base64.RawURLEncoding.EncodeToString(data), true
+	} else {
+		return nil, false
+	}
+}
+/*
+ */
+func encodeBase64(value any) (any, bool) {
+	var data, ok = value.(Base64)
+	if ok {
+		return base64.StdEncoding.EncodeToString(data), true
+	} else {
+		return nil, false
+	}
+}
+/*
+ */
+func encodeRegexp(value any) (any, bool) {
+	var re, ok = value.(*regexp.Regexp)
+	if ok && nil != re {
+		return re.String(), true
+	} else {
+		return nil, false
+	}
+}
+/*
+ */
+func encodeMIME(value any) (any, bool) {
+	var text, ok = value.(MIME)
+	if ok {
+		return string(text), true
+	} else {
+		return nil, false
+	}
+}
+/*
+ */
+func decodeUUID(content Object) (any) {
+	var data, ok = content.Decode().([]byte)
+	if ok && 16 == len(data) {
+		var uuid UUID
+		copy(uuid[:],data)
+		return uuid
+	} else {
+		return nil
+	}
+}
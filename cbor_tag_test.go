@@ -0,0 +1,141 @@
+/*
+ * CBOR Tagged Data Item Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"net/url"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestEmbedded(t *testing.T){
+	var inner Object = Encode(TestStringDatum)
+
+	var o Object = Encode(Embedded(inner))
+
+	var expected []byte = append([]byte{0xD8,0x18,0x4E},inner...)
+	if !bytes.Equal(expected,o) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(o))
+	}
+
+	var check Object
+	var ok bool
+	check, ok = o.Decode().(Object)
+	if !ok {
+		t.Errorf("Expected decoding to object, found '%T'.",o.Decode())
+	} else if !bytes.Equal(inner,check) {
+		t.Errorf("Expected decoding '%X', found '%X'.",[]byte(inner),[]byte(check))
+	} else if TestStringDatum != check.MustText() {
+		t.Errorf("Expected '%s', found '%s'.",TestStringDatum,check.MustText())
+	}
+}
+
+func TestTagText(t *testing.T){
+	var uri *url.URL
+	uri, _ = url.Parse("http://www.example.com")
+
+	var o Object = Encode(uri)
+	var expected []byte = append([]byte{0xD8,0x20,0x76},"http://www.example.com"...)
+	if !bytes.Equal(expected,o) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(o))
+	} else if check, ok := o.Decode().(*url.URL); !ok || uri.String() != check.String() {
+		t.Errorf("Expected decoding '%v', found '%v'.",uri,o.Decode())
+	}
+
+	var data []byte = []byte{0xFB,0xFF,0x01}
+
+	o = Encode(Base64URL(data))
+	if TagBase64URL != uint64(o[1]) || "-_8B" != Object(o[2:]).MustText() {
+		t.Errorf("Expected base64url '-_8B', found '%X'.",[]byte(o))
+	} else if !bytes.Equal(data,o.Decode().([]byte)) {
+		t.Errorf("Expected decoding '%X', found '%v'.",data,o.Decode())
+	}
+
+	o = Encode(Base64(data))
+	if TagBase64 != uint64(o[1]) || "+/8B" != Object(o[2:]).MustText() {
+		t.Errorf("Expected base64 '+/8B', found '%X'.",[]byte(o))
+	} else if !bytes.Equal(data,o.Decode().([]byte)) {
+		t.Errorf("Expected decoding '%X', found '%v'.",data,o.Decode())
+	}
+
+	var re *regexp.Regexp = regexp.MustCompile("^h[a-z]+, w[a-z]+.$")
+	o = Encode(re)
+	if check, ok := o.Decode().(*regexp.Regexp); !ok || !check.MatchString(TestStringDatum) {
+		t.Errorf("Expected decoding '%v', found '%v'.",re,o.Decode())
+	}
+
+	var mime MIME = MIME("Content-Type: text/plain\r\n\r\n"+TestStringDatum)
+	o = Encode(mime)
+	if mime != o.Decode() {
+		t.Errorf("Expected decoding '%v', found '%v'.",mime,o.Decode())
+	}
+}
+
+func TestUUID(t *testing.T){
+	var uuid UUID = UUID{0x55,0x0E,0x84,0x00,0xE2,0x9B,0x41,0xD4,0xA7,0x16,0x44,0x66,0x55,0x44,0x00,0x00}
+
+	if "550e8400-e29b-41d4-a716-446655440000" != uuid.String() {
+		t.Errorf("Expected '550e8400-e29b-41d4-a716-446655440000', found '%s'.",uuid.String())
+	}
+
+	var o Object = Encode(uuid)
+	var expected []byte = append([]byte{0xD8,0x25,0x50},uuid[:]...)
+	if !bytes.Equal(expected,o) {
+		t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(o))
+	} else if uuid != o.Decode() {
+		t.Errorf("Expected decoding '%v', found '%v'.",uuid,o.Decode())
+	} else if !bytes.Equal(o,Encode([16]byte(uuid))) {
+		t.Errorf("Expected encoding '%X', found '%X'.",[]byte(o),[]byte(Encode([16]byte(uuid))))
+	}
+
+	/*
+	 * Content rejected by the tag, retained as <Tagged>.
+	 */
+	for _, c := range []struct{ code Object; value Tagged }{
+		{Object{0xD8,0x25,0x43,0x01,0x02,0x03}, Tagged{TagUUID,[]byte{1,2,3}}},
+		{Object{0xD8,0x20,0x01}, Tagged{TagURI,uint8(1)}},
+		{Object{0xD8,0x34,0x43,0x01,0x02,0x03}, Tagged{TagIPv4,[]byte{1,2,3}}},
+		{Object{0xC2,0x01}, Tagged{2,uint8(1)}},
+	} {
+		var check, ok = c.code.Decode().(Tagged)
+		if !ok || !reflect.DeepEqual(c.value,check) {
+			t.Errorf("Expected '%v', found '%v'.",c.value,c.code.Decode())
+		}
+	}
+}
+
+func TestTagged(t *testing.T){
+	var cases = []struct{ number uint64; head []byte }{
+		{6, []byte{0xC6}},
+		{1000, []byte{0xD9,0x03,0xE8}},
+		{0x10000, []byte{0xDA,0x00,0x01,0x00,0x00}},
+		{0x100000000, []byte{0xDB,0x00,0x00,0x00,0x01,0x00,0x00,0x00,0x00}},
+	}
+	for _, c := range cases {
+		var o Object = Encode(Tagged{c.number,"a"})
+		var expected []byte = append(c.head,0x61,'a')
+		if !bytes.Equal(expected,o) {
+			t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(o))
+		}
+
+		var r Object
+		var e error
+		r, e = r.Read(bytes.NewReader(o))
+		if nil != e {
+			t.Fatal(e)
+		} else if !bytes.Equal(o,r) {
+			t.Errorf("Expected read '%X', found '%X'.",[]byte(o),[]byte(r))
+		}
+
+		var tagged, ok = r.Decode().(Tagged)
+		if !ok {
+			t.Errorf("Expected 'Tagged', found '%T'.",r.Decode())
+		} else if c.number != tagged.Number || "a" != tagged.Content {
+			t.Errorf("Expected '%d(a)', found '%d(%v)'.",c.number,tagged.Number,tagged.Content)
+		}
+	}
+}
@@ -10,7 +10,13 @@
 package cbor
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"strings"
 	"testing"
 )
 
@@ -24,10 +30,10 @@ func TestString(t *testing.T){
 
 	if MajorText == o.Major() {
 
-		if TestStringDatum == o.Text() {
-			fmt.Printf("[%s] \"%s\".\n", o.MajorString(),o.Text())
+		if TestStringDatum == o.MustText() {
+			fmt.Printf("[%s] \"%s\".\n", o.MajorString(),o.MustText())
 		} else {
-			t.Errorf("Expected test vector '%s', found '%s'.",TestStringDatum,o.Text())
+			t.Errorf("Expected test vector '%s', found '%s'.",TestStringDatum,o.MustText())
 		}
 	} else {
 		t.Errorf("Expected major type [text], found '%s'.",o.MajorString())
@@ -58,33 +64,578 @@ func (this TypeTestCoder) Equals(that TypeTestCoder) (bool) {
 	}
 	return false
 }
-func (this TypeTestCoder) Encode() (code Object) {
+func (this *TypeTestCoder) Encode() (Object, error) {
 	var text map[string]any = map[string]any{ "source": this.source, "target": this.target}
 
-	code = Encode(text)
-	return code
+	return EncOptions{}.Encode(text)
 }
-func (this TypeTestCoder) Decode(cbor Object) (TypeTestCoder) {
+func (this *TypeTestCoder) Decode(cbor Object) (error) {
 	this.source = ""
 	this.target = nil
 
-	var text map[string]any = cbor.Decode().(map[string]any)
-
-	this.source = text["source"].(string)
-	this.target = text["target"].([]byte)
-
-	return this
+	var text, isMap = cbor.Decode().(map[string]any)
+	if !isMap {
+		return ErrorMissingData
+	}
+	var source, isSource = text["source"].(string)
+	var target, isTarget = text["target"].([]byte)
+	if isSource && isTarget {
+		this.source = source
+		this.target = target
+		return nil
+	} else {
+		return ErrorMissingData
+	}
 }
 
 func TestCoder(t *testing.T){
 	var text TypeTestCoder = TypeTestCoderObject
 
-	var code Object = text.Encode()
-
-	var check TypeTestCoder = text.Decode(code)
-	
-	if !TypeTestCoderObject.Equals(check) {
+	var code, e = text.Encode()
+	if nil != e {
+		t.Fatal(e)
+	}
+	var check TypeTestCoder
+	e = check.Decode(code)
+	if nil != e {
+		t.Fatal(e)
+	} else if !TypeTestCoderObject.Equals(check) {
 
 		t.Error("Decoding")
 	}
+
+	e = check.Decode(Encode("text"))
+	if nil == e {
+		t.Error("Expected decoding error.")
+	}
+}
+
+func TestSimpleValue(t *testing.T){
+	var vector map[SimpleValue][]byte = map[SimpleValue][]byte{
+		SimpleValue(0): []byte{0xE0},
+		SimpleValue(16): []byte{0xF0},
+		SimpleValue(19): []byte{0xF3},
+		SimpleValue(32): []byte{0xF8,0x20},
+		SimpleValue(255): []byte{0xF8,0xFF},
+	}
+	for value, code := range vector {
+		var o Object = Encode(value)
+		if !bytes.Equal(code,o) {
+			t.Errorf("Expected encoding '%X', found '%X'.",code,[]byte(o))
+		} else if value != o.Decode() {
+			t.Errorf("Expected decoding (%d), found '%v'.",value,o.Decode())
+		}
+	}
+
+	var o Object = Object{}
+	var _, e = o.Read(bytes.NewReader([]byte{0xF8,0x18}))
+	if ErrorInvalidSimple != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorInvalidSimple,e)
+	}
+	for value := SimpleValue(24); value < 32; value++ {
+		_, e = EncOptions{}.Encode(value)
+		if ErrorInvalidSimple != e {
+			t.Errorf("Expected '%v' for (%d), found '%v'.",ErrorInvalidSimple,value,e)
+		}
+	}
+}
+
+func TestUndefined(t *testing.T){
+	var o Object = Encode(Undefined{})
+	if !bytes.Equal([]byte{0xF7},o) {
+		t.Errorf("Expected encoding 'F7', found '%X'.",[]byte(o))
+	} else if (Undefined{}) != o.Decode() {
+		t.Errorf("Expected decoding 'undefined', found '%v'.",o.Decode())
+	}
+
+	o = Encode(nil)
+	if !bytes.Equal([]byte{0xF6},o) {
+		t.Errorf("Expected encoding 'F6', found '%X'.",[]byte(o))
+	} else if nil != o.Decode() {
+		t.Errorf("Expected decoding 'null', found '%v'.",o.Decode())
+	}
+}
+
+func TestCompare(t *testing.T){
+	/*
+	 * Section 4.2.1 [RFC8949] key order
+	 */
+	var order []Object = []Object{
+		Encode(uint8(10)),
+		Encode(uint16(100)),
+		Object{0x20},
+		Encode("z"),
+		Encode("aa"),
+		Encode([]any{uint8(100)}),
+		Object{0x81,0x20},
+		Encode(false),
+	}
+	for n := 1; n < len(order); n++ {
+		if 0 <= order[n-1].Compare(order[n]) {
+			t.Errorf("Expected '%X' before '%X'.",[]byte(order[n-1]),[]byte(order[n]))
+		} else if 0 >= order[n].Compare(order[n-1]) {
+			t.Errorf("Expected '%X' after '%X'.",[]byte(order[n]),[]byte(order[n-1]))
+		} else if order[n].Equal(order[n-1]) || !order[n].Equal(append(Object{},order[n]...)) {
+			t.Errorf("Expected equality of '%X' with itself only.",[]byte(order[n]))
+		}
+	}
+	for _, c := range [][2]Object{
+		{Object{0x18,0x0A}, Object{0x0A}},
+		{Object{0x9F,0x01,0xFF}, Object{0x81,0x01}},
+		{Object{0xA2,0x61,'b',0x01,0x61,'a',0x02}, Object{0xA2,0x61,'a',0x02,0x61,'b',0x01}},
+	} {
+		if !c[0].Equal(c[1]) || 0 != c[1].Compare(c[0]) {
+			t.Errorf("Expected equality of '%X' with '%X'.",[]byte(c[0]),[]byte(c[1]))
+		}
+	}
+	/*
+	 * Trailing data, compared as it is.
+	 */
+	if (Object{0x01,0x02}).Equal(Object{0x01,0x03}) || (Object{0x18,0x01,0x02}).Equal(Object{0x01,0x02}) {
+		t.Error("Expected inequality of objects having trailing data.")
+	}
+	if (Object{0x18}).Equal(Object{0x18,0x00}) {
+		t.Errorf("Expected inequality of '18' with '1800'.")
+	}
+}
+
+func TestReadTruncated(t *testing.T){
+	var r *bufio.Reader = bufio.NewReader(bytes.NewReader([]byte{0x39,0x01,0xF3,0x82,0x01}))
+	var o Object = Object{}
+	var e error
+
+	o, e = o.Read(r)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0x39,0x01,0xF3},o) {
+		t.Errorf("Expected '3901F3', found '%X'.",[]byte(o))
+	}
+	_, e = o.Read(r)
+	if !errors.Is(e,io.ErrUnexpectedEOF) {
+		t.Errorf("Expected '%v', found '%v'.",io.ErrUnexpectedEOF,e)
+	}
+	_, e = o.Read(r)
+	if io.EOF != e {
+		t.Errorf("Expected '%v', found '%v'.",io.EOF,e)
+	}
+
+	for _, code := range [][]byte{{0x19,0x01},{0x62,'a'},{0xA1,0x01},{0x5F,0x41,'a'}} {
+		_, e = o.Read(bytes.NewReader(code))
+		if !errors.Is(e,io.ErrUnexpectedEOF) {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",io.ErrUnexpectedEOF,code,e)
+		}
+	}
+}
+
+func TestReadMalformed(t *testing.T){
+	var o Object = Object{}
+	var e error
+
+	for _, code := range []byte{0x1C,0x1D,0x1E,0x3C,0x3D,0x3E,0x5C,0x5D,0x5E,0x7C,0x7D,0x7E,0x9C,0x9D,0x9E,0xBC,0xBD,0xBE,0xDC,0xDD,0xDE,0xFC,0xFD,0xFE} {
+		_, e = o.Read(bytes.NewReader([]byte{code}))
+		if ErrorReservedAdditionalInfo != e {
+			t.Errorf("Expected '%v' for '%02X', found '%v'.",ErrorReservedAdditionalInfo,code,e)
+		}
+		_, _, e = Object{code}.head()
+		if ErrorReservedAdditionalInfo != e {
+			t.Errorf("Expected '%v' for '%02X', found '%v'.",ErrorReservedAdditionalInfo,code,e)
+		}
+	}
+	for _, code := range []byte{0x1F,0x3F,0xDF} {
+		_, e = o.Read(bytes.NewReader([]byte{code}))
+		if ErrorReservedHead != e {
+			t.Errorf("Expected '%v' for '%02X', found '%v'.",ErrorReservedHead,code,e)
+		}
+	}
+	for _, code := range []byte{0x00,0x18,0x1F} {
+		_, e = o.Read(bytes.NewReader([]byte{0xF8,code}))
+		if ErrorInvalidSimple != e {
+			t.Errorf("Expected '%v' for 'F8%02X', found '%v'.",ErrorInvalidSimple,code,e)
+		}
+	}
+}
+
+func TestFloat(t *testing.T){
+	var cases = []struct{ value float64; code []byte }{
+		{1.5, []byte{0xF9,0x3E,0x00}},
+		{100000.0, []byte{0xFA,0x47,0xC3,0x50,0x00}},
+		{1.1, []byte{0xFB,0x3F,0xF1,0x99,0x99,0x99,0x99,0x99,0x9A}},
+	}
+	for _, c := range cases {
+		var o Object = Encode(c.value)
+		if !bytes.Equal(c.code,o) {
+			t.Errorf("Expected encoding '%X', found '%X'.",c.code,[]byte(o))
+		}
+		var check float64
+		var e error = Unmarshal(o,&check)
+		if nil != e {
+			t.Fatal(e)
+		} else if c.value != check {
+			t.Errorf("Expected '%v', found '%v'.",c.value,check)
+		}
+	}
+}
+
+func TestText(t *testing.T){
+	var s, e = Encode(TestStringDatum).Text()
+	if nil != e {
+		t.Fatal(e)
+	} else if TestStringDatum != s {
+		t.Errorf("Expected '%s', found '%s'.",TestStringDatum,s)
+	}
+
+	_, e = Encode(uint8(1)).Text()
+	if ErrorNotText != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorNotText,e)
+	}
+
+	defer func(){
+		if ErrorNotText != recover() {
+			t.Errorf("Expected panic '%v'.",ErrorNotText)
+		}
+	}()
+	Encode(uint8(1)).MustText()
+}
+
+func TestIndefiniteStrings(t *testing.T){
+	var o Object = Object{}
+	var e error
+
+	o, e = o.Read(bytes.NewReader([]byte{0x5F,0x42,0x01,0x02,0x43,0x03,0x04,0x05,0xFF}))
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{1,2,3,4,5},o.Decode().([]byte)) {
+		t.Errorf("Expected '0102030405', found '%X'.",o.Decode())
+	}
+
+	o, e = o.Read(bytes.NewReader([]byte{0x7F,0x65,'s','t','r','e','a',0x64,'m','i','n','g',0xFF}))
+	if nil != e {
+		t.Fatal(e)
+	} else if "streaming" != o.Decode() {
+		t.Errorf("Expected 'streaming', found '%v'.",o.Decode())
+	}
+
+	for _, code := range [][]byte{
+		{0x5F,0x61,'a',0xFF},
+		{0x7F,0x41,0x01,0xFF},
+		{0x5F,0x5F,0x41,0x01,0xFF,0xFF},
+		{0x7F,0x01,0xFF},
+	} {
+		_, e = o.Read(bytes.NewReader(code))
+		if ErrorChunk != e {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",ErrorChunk,code,e)
+		}
+	}
+}
+
+func TestReadBreak(t *testing.T){
+	var o Object = Object{}
+	var e error
+
+	o, e = o.Read(bytes.NewReader([]byte{0xBF,0x61,'a',0x01,0x61,'b',0x9F,0x02,0xFF,0xFF}))
+	if nil != e {
+		t.Fatal(e)
+	} else if 10 != len(o) {
+		t.Errorf("Expected 10 octets, found '%X'.",[]byte(o))
+	}
+
+	for _, code := range [][]byte{{0xBF,0x00,0xFF},{0xBF,0x00,0x00,0x00,0xFF}} {
+		_, e = o.Read(bytes.NewReader(code))
+		if ErrorMapIncomplete != e {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",ErrorMapIncomplete,code,e)
+		}
+	}
+	for _, code := range [][]byte{{0x81,0xFF},{0xA1,0xFF},{0xA1,0x00,0xFF},{0xC6,0xFF},{0x9F,0x81,0xFF},{0xBF,0x00,0x81,0xFF,0xFF}} {
+		_, e = o.Read(bytes.NewReader(code))
+		if !errors.Is(e,ErrorUnexpectedBreak) {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",ErrorUnexpectedBreak,code,e)
+		}
+	}
+}
+
+func TestBreak(t *testing.T){
+	var o Object = Object{}
+	var _, e = o.Read(bytes.NewReader([]byte{0xFF}))
+	if !errors.Is(e,Break) {
+		t.Errorf("Expected '%v', found '%v'.",Break,e)
+	}
+	var typed BreakError
+	if !errors.As(fmt.Errorf(ErrorWrapRead,e),&typed) {
+		t.Errorf("Expected 'BreakError', found '%T'.",e)
+	}
+
+	var a any = Object{0xFF}.Decode()
+	if (BreakMarker{}) != a {
+		t.Errorf("Expected 'BreakMarker', found '%T'.",a)
+	}
+}
+
+func TestConcatenate(t *testing.T){
+	var o Object = Object{0x82,0x01}
+	if !bytes.Equal([]byte{0x82,0x01},o.Concatenate(nil)) {
+		t.Errorf("Expected '8201', found '%X'.",[]byte(o.Concatenate(nil)))
+	}
+	if !bytes.Equal([]byte{0x02},Object{}.Concatenate([]byte{0x02})) {
+		t.Errorf("Expected '02', found '%X'.",[]byte(Object{}.Concatenate([]byte{0x02})))
+	}
+
+	var c Object = o[0:1].Concatenate([]byte{0x03,0x04})
+	if !bytes.Equal([]byte{0x82,0x03,0x04},c) || !bytes.Equal([]byte{0x82,0x01},o) {
+		t.Errorf("Expected '820304' and '8201', found '%X' and '%X'.",[]byte(c),[]byte(o))
+	}
+
+	if !bytes.Equal([]byte{0x40},Encode([]byte{})) || !bytes.Equal([]byte{0x60},Encode("")) {
+		t.Errorf("Expected '40' and '60', found '%X' and '%X'.",[]byte(Encode([]byte{})),[]byte(Encode("")))
+	}
+
+	var buffer []byte = Object{0x01}.AppendTo([]byte{0x82})
+	buffer = Object{0x61,'a'}.AppendTo(buffer)
+	if !bytes.Equal([]byte{0x82,0x01,0x61,'a'},buffer) {
+		t.Errorf("Expected '82016161', found '%X'.",buffer)
+	}
+}
+
+func TestEncodeIntegerWidths(t *testing.T){
+	var cases = []struct{ value any; expected string }{
+		{uint8(0), "00"},
+		{uint8(23), "17"},
+		{uint8(24), "1818"},
+		{uint8(math.MaxUint8), "18ff"},
+		{uint16(math.MaxUint8+1), "190100"},
+		{uint16(math.MaxUint16), "19ffff"},
+		{uint32(math.MaxUint16+1), "1a00010000"},
+		{uint32(math.MaxUint32), "1affffffff"},
+		{uint64(math.MaxUint32+1), "1b0000000100000000"},
+		{uint64(math.MaxUint64), "1bffffffffffffffff"},
+		{uint(24), "1818"},
+		{uint(math.MaxUint32+1), "1b0000000100000000"},
+		{uintptr(256), "190100"},
+		{int8(0), "00"},
+		{int8(-1), "20"},
+		{int8(-24), "37"},
+		{int8(-25), "3818"},
+		{int8(math.MinInt8), "387f"},
+		{int8(math.MaxInt8), "187f"},
+		{int16(-256), "38ff"},
+		{int16(-257), "390100"},
+		{int16(math.MinInt16), "397fff"},
+		{int16(math.MaxInt16), "197fff"},
+		{int32(-65536), "39ffff"},
+		{int32(-65537), "3a00010000"},
+		{int32(math.MinInt32), "3a7fffffff"},
+		{int32(math.MaxInt32), "1a7fffffff"},
+		{int64(-4294967296), "3affffffff"},
+		{int64(-4294967297), "3b0000000100000000"},
+		{int64(math.MinInt64), "3b7fffffffffffffff"},
+		{int64(math.MaxInt64), "1b7fffffffffffffff"},
+		{int(-100), "3863"},
+		{int(1000000), "1a000f4240"},
+		{int(-65537), "3a00010000"},
+	}
+	for _, c := range cases {
+		var found string = fmt.Sprintf("%x",[]byte(Encode(c.value)))
+		if c.expected != found {
+			t.Errorf("Expected '%s' for %T(%v), found '%s'.",c.expected,c.value,c.value,found)
+		}
+	}
+	for _, v := range []int64{0,-1,-24,-25,-256,-257,-65536,-65537,-4294967296,-4294967297,math.MinInt64,math.MaxInt64} {
+		var found, e = Encode(v).Int()
+		if nil != e {
+			t.Error(e)
+		} else if v != found {
+			t.Errorf("Expected '%d', found '%d'.",v,found)
+		}
+	}
+}
+
+func TestObjectString(t *testing.T){
+	var o Object = Object{0xA2,0x61,'a',0x01,0x61,'b',0x82,0x02,0x03}
+	var expected string = `{"a": 1, "b": [2, 3]}`
+	if expected != fmt.Sprint(o) {
+		t.Errorf("Expected '%s', found '%s'.",expected,fmt.Sprint(o))
+	}
+	expected = "map (0x00..0x17 pairs of data items follow)"
+	if expected != o.HeadString() {
+		t.Errorf("Expected '%s', found '%s'.",expected,o.HeadString())
+	}
+	var malformed string = Object{0xA2,0x61,'a'}.String()
+	if !strings.HasPrefix(malformed,Object{0xA2}.MajorString()+" (") {
+		t.Errorf("Expected major type and failure, found '%s'.",malformed)
+	}
+	if "" != (Object{}).String() {
+		t.Errorf("Expected empty, found '%s'.",Object{}.String())
+	}
+}
+
+func TestEncodeObject(t *testing.T){
+	var fragment Object = Encode(map[string]any{"a": uint8(1)})
+	var expected []byte = []byte{0x82,0xA1,0x61,'a',0x01,0xF6}
+	var code Object = Encode([]any{fragment,Object{}})
+	if !bytes.Equal(expected,code) {
+		t.Errorf("Expected '%X', found '%X'.",expected,[]byte(code))
+	}
+	expected = []byte{0xA1,0x61,'f',0xA1,0x61,'a',0x01}
+	code = Encode(map[string]any{"f": fragment})
+	if !bytes.Equal(expected,code) {
+		t.Errorf("Expected '%X', found '%X'.",expected,[]byte(code))
+	}
+	var indefinite Object = Object{0x9F,0x01,0xFF}
+	var options EncOptions = EncOptionsCoreDet()
+	code, _ = options.Encode([]any{indefinite})
+	expected = []byte{0x81,0x81,0x01}
+	if !bytes.Equal(expected,code) {
+		t.Errorf("Expected '%X', found '%X'.",expected,[]byte(code))
+	}
+}
+
+func TestObjectAliasing(t *testing.T){
+	var buffer []byte = []byte{0x65,'h','e','l','l','o'}
+	var expected []byte = append([]byte{},buffer...)
+	var read, e = Object{}.Read(bytes.NewReader(buffer))
+	if nil != e {
+		t.Fatal(e)
+	}
+	var decoded []byte = Object{0x45,'h','e','l','l','o'}.Decode().([]byte)
+	var produced []Object = []Object{
+		read,
+		Encode(Object(buffer)),
+		Encode(RawMessage(buffer)),
+		Object(buffer).Concatenate(nil),
+		Object(buffer).Clone(),
+	}
+	var canonical Object
+	canonical, e = Object{0xFB,0x3F,0xF1,0x99,0x99,0x99,0x99,0x99,0x9A}.Canonical()
+	if nil != e {
+		t.Fatal(e)
+	}
+	buffer[1] = 'j'
+	for n, o := range produced {
+		if !bytes.Equal(expected,o) {
+			t.Errorf("[%d] Expected '%X', found '%X'.",n,expected,[]byte(o))
+		}
+	}
+	produced[3][1] = 'y'
+	if 'j' != buffer[1] {
+		t.Errorf("Expected 'j', found '%c'.",buffer[1])
+	}
+	if "hello" != string(decoded) || 0xFB != canonical[0] {
+		t.Errorf("Expected 'hello' and a double, found '%s' and '%X'.",decoded,[]byte(canonical))
+	}
+	if nil != Object(nil).Clone() {
+		t.Error("Expected nil clone of nil.")
+	}
+
+	var shared Object = Encode(map[string]any{"a": []any{uint8(1),"b",1.5}})
+	var done chan Object = make(chan Object)
+	for n := 0; n < 8; n++ {
+		go func(){
+			var o, _ = Encode(shared.Decode()).Canonical()
+			done <- o
+		}()
+	}
+	var reference, _ = shared.Canonical()
+	for n := 0; n < 8; n++ {
+		var o Object = <-done
+		if !bytes.Equal(reference,o) {
+			t.Errorf("Expected '%X', found '%X'.",[]byte(reference),[]byte(o))
+		}
+	}
+}
+
+func TestErrorClasses(t *testing.T){
+	type TypeTestErrorClass struct {
+		code []byte
+		class error
+	}
+	for _, test := range []TypeTestErrorClass{
+		{[]byte{0x19,0x01}, ErrorTruncated},
+		{[]byte{0x82,0x01}, ErrorTruncated},
+		{[]byte{0x63,'a','b'}, ErrorTruncated},
+		{[]byte{0x1C}, ErrorReservedHead},
+		{[]byte{0x81,0x3F}, ErrorReservedHead},
+		{[]byte{0x62,0xC3,0x28}, ErrorInvalidUTF8},
+		{[]byte{0x7F,0x61,0xC3,0x61,0xA9,0xFF}, ErrorInvalidUTF8},
+		{[]byte{0x82,0x01,0xFF}, ErrorUnexpectedBreak},
+	} {
+		var _, e = Object{}.Read(bytes.NewReader(test.code))
+		if !errors.Is(e,test.class) {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",test.class,test.code,e)
+		}
+		var v any
+		e = Unmarshal(test.code,&v)
+		if !errors.Is(e,test.class) {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",test.class,test.code,e)
+		}
+	}
+	var _, e = Object{}.Read(bytes.NewReader([]byte{0x19,0x01}))
+	if !errors.Is(e,io.ErrUnexpectedEOF) {
+		t.Errorf("Expected '%v', found '%v'.",io.ErrUnexpectedEOF,e)
+	}
+
+	var v any
+	e = DecOptions{MaxDepth: 1}.Unmarshal([]byte{0x81,0x81,0x81,0x01},&v)
+	if !errors.Is(e,ErrorDepthExceeded) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorDepthExceeded,e)
+	}
+	e = DecOptions{MaxSize: 4}.Unmarshal([]byte{0x81,0x64,'a','b','c','d'},&v)
+	if !errors.Is(e,ErrorSizeExceeded) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorSizeExceeded,e)
+	}
+	e = DecOptions{MaxSize: 6}.Unmarshal([]byte{0x81,0x64,'a','b','c','d'},&v)
+	if nil != e {
+		t.Errorf("Expected 'nil', found '%v'.",e)
+	}
+	e = DecOptions{RejectDuplicateKeys: true}.Unmarshal([]byte{0x81,0xA2,0x61,'a',0x01,0x61,'a',0x02},&v)
+	if !errors.Is(e,ErrorDuplicateKey) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorDuplicateKey,e)
+	}
+	e = DecOptions{RejectDuplicateKeys: true}.Unmarshal([]byte{0xA2,0x61,'a',0x01,0x61,'b',0x02},&v)
+	if nil != e {
+		t.Errorf("Expected 'nil', found '%v'.",e)
+	}
+	/*
+	 * Keys of one canonical encoding, the second nested in an
+	 * indefinite length map, and keys of nested maps.
+	 */
+	for _, data := range [][]byte{
+		{0xA2,0x01,0xF6,0x18,0x01,0xF6},
+		{0xA1,0x61,'k',0xBF,0x7F,0x61,'a',0xFF,0x01,0x61,'a',0x02,0xFF},
+	} {
+		e = DecOptions{RejectDuplicateKeys: true}.Unmarshal(data,&v)
+		if !errors.Is(e,ErrorDuplicateKey) {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",ErrorDuplicateKey,data,e)
+		}
+	}
+	e = DecOptions{RejectDuplicateKeys: true}.Unmarshal([]byte{0xA2,0x01,0xA1,0x01,0x02,0x02,0xA1,0x01,0x02},&v)
+	if nil != e {
+		t.Errorf("Expected 'nil', found '%v'.",e)
+	}
+	if !errors.Is(ErrorFrameSize,ErrorSizeExceeded) || ErrorMissingData != ErrorTruncated {
+		t.Error("Expected classes of deprecated and specific errors.")
+	}
+}
+
+func TestWriteToReadFrom(t *testing.T){
+	var o Object = Encode([]any{"hello",uint8(1)})
+	var b bytes.Buffer
+	var writer io.WriterTo = o
+	var n, e = writer.WriteTo(&b)
+	if nil != e || int64(len(o)) != n {
+		t.Errorf("Expected %d octets, found %d '%v'.",len(o),n,e)
+	}
+	b.WriteByte(0x01)
+
+	var read Object
+	read, n, e = ReadFrom(&b)
+	if nil != e || !bytes.Equal(o,read) || int64(len(o)) != n || 1 != b.Len() {
+		t.Errorf("Expected '%X' of %d octets, found '%X' of %d '%v'.",[]byte(o),len(o),[]byte(read),n,e)
+	}
+	_, n, e = ReadFrom(bytes.NewReader(o[0:4]))
+	if !errors.Is(e,ErrorTruncated) || 4 != n {
+		t.Errorf("Expected '%v' having read 4 octets, found %d '%v'.",ErrorTruncated,n,e)
+	}
+	_, n, e = ReadFrom(bytes.NewReader(nil))
+	if io.EOF != e || 0 != n {
+		t.Errorf("Expected '%v', found %d '%v'.",io.EOF,n,e)
+	}
 }
@@ -0,0 +1,147 @@
+/*
+ * CBOR RFC8949 Date and Time
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.4.1
+ * https://tools.ietf.org/html/rfc8949#section-3.4.2
+ * https://tools.ietf.org/html/rfc3339
+ */
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+/*
+ * Treatment of the fractional seconds of a date and time
+ * decoded into "time.Time".
+ */
+type SubsecondMode uint8
+/*
+ * Fractional seconds are retained, to the nanosecond.
+ */
+const SubsecondKeep SubsecondMode = 0
+/*
+ * Fractional seconds are discarded.
+ */
+const SubsecondTruncate SubsecondMode = 1
+/*
+ * Fractional seconds are rejected with <ErrorSubsecond>.
+ */
+const SubsecondReject SubsecondMode = 2
+/*
+ * Decoding errors of <Object#Time>.
+ */
+var ErrorTime error = errors.New("CBOR date and time is not a standard or epoch date and time")
+var ErrorSubsecond error = errors.New("CBOR date and time has fractional seconds")
+/*
+ * Define the epoch date and time (tag 1) of the time, as an
+ * integer count of seconds, or as a float count of seconds
+ * for a time having fractional seconds.  The float represents
+ * microseconds, not nanoseconds, for the present era.
+ */
+func encodeTime(value time.Time) (Object) {
+	if 0 == value.Nanosecond() {
+		return tagging(TagDateTimeEpoch,EncodeInt64(nil,value.Unix()))
+	} else {
+		var seconds float64 = float64(value.Unix())+(float64(value.Nanosecond())/1e9)
+		return tagging(TagDateTimeEpoch,encodeFloatShortest(seconds))
+	}
+}
+/*
+ * Resolve the date and time of a standard date and time
+ * string (tag 0), of an epoch date and time (tag 1) in integer
+ * or float seconds, or of their untagged content.  A date
+ * (tags 100 and 1004) is midnight UTC.  Fractional
+ * seconds are retained, a string retains the offset of its
+ * time zone, and an epoch date and time is UTC.  See
+ * <DecOptions> "Subseconds" and "TimeLocation".
+ */
+func (this Object) Time() (time.Time, error) {
+	return this.time(&DecOptions{})
+}
+/*
+ * Resolve the date and time under decoding options.
+ */
+func (this Object) time(options *DecOptions) (value time.Time, e error) {
+	var o Object = this
+	var number, content, ok = this.tagged()
+	if ok {
+		switch number {
+		case TagDateTimeString:
+			if MajorText != content.Major() {
+				return value, ErrorTime
+			}
+		case TagDateTimeEpoch:
+			if MajorText == content.Major() {
+				return value, ErrorTime
+			}
+		case TagDays, TagDate:
+			var date Date
+			date, e = this.Date()
+			if nil != e {
+				return value, fmt.Errorf("%w: %w",ErrorTime,e)
+			} else {
+				return options.location(date.Time()), nil
+			}
+		default:
+			return value, ErrorTime
+		}
+		o = content
+	}
+	switch o.Major() {
+	case MajorText:
+		var text string
+		text, e = o.Text()
+		if nil == e {
+			value, e = time.Parse(time.RFC3339Nano,text)
+		}
+		if nil != e {
+			return value, fmt.Errorf("%w: %w",ErrorTime,e)
+		}
+	case MajorUint, MajorSint:
+		var seconds int64
+		seconds, e = o.Int()
+		if nil != e {
+			return value, fmt.Errorf("%w: %w",ErrorTime,e)
+		} else {
+			value = time.Unix(seconds,0).UTC()
+		}
+	case MajorSimple:
+		var seconds float64
+		seconds, e = o.Float()
+		if nil != e || math.IsNaN(seconds) || math.Abs(seconds) >= (1 << 62) {
+			return value, ErrorTime
+		} else {
+			var whole float64 = math.Floor(seconds)
+			var nanoseconds int64 = int64(math.Round((seconds-whole)*1e9))
+			value = time.Unix(int64(whole),nanoseconds).UTC()
+		}
+	default:
+		return value, ErrorTime
+	}
+	if 0 != value.Nanosecond() {
+		switch options.Subseconds {
+		case SubsecondTruncate:
+			value = value.Truncate(time.Second)
+		case SubsecondReject:
+			return time.Time{}, ErrorSubsecond
+		}
+	}
+	return options.location(value), nil
+}
+/*
+ * Resolve the time in the location of the options.
+ */
+func (this DecOptions) location(value time.Time) (time.Time) {
+	if nil != this.TimeLocation {
+		return value.In(this.TimeLocation)
+	} else {
+		return value
+	}
+}
@@ -0,0 +1,91 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR Date and Time Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T){
+	var expected time.Time = time.Date(2013,3,21,20,4,0,0,time.UTC)
+	/*
+	 * Appendix A [RFC8949].
+	 */
+	for _, c := range []struct{ code string; value time.Time }{
+		{"c074323031332d30332d32315432303a30343a30305a", expected},
+		{"c11a514b67b0", expected},
+		{"c1fb41d452d9ec200000", expected.Add(500*time.Millisecond)},
+	} {
+		var code, _ = hex.DecodeString(c.code)
+		var value, e = Object(code).Time()
+		if nil != e {
+			t.Errorf("[%s] %v",c.code,e)
+		} else if !c.value.Equal(value) {
+			t.Errorf("Expected '%v' for '%s', found '%v'.",c.value,c.code,value)
+		}
+	}
+	if "c11a514b67b0" != hex.EncodeToString(Encode(expected)) {
+		t.Errorf("Expected 'c11a514b67b0', found '%X'.",[]byte(Encode(expected)))
+	}
+	if "c1fb41d452d9ec200000" != hex.EncodeToString(Encode(expected.Add(500*time.Millisecond))) {
+		t.Errorf("Expected 'c1fb41d452d9ec200000', found '%X'.",[]byte(Encode(expected.Add(500*time.Millisecond))))
+	}
+	/*
+	 * Offset of a string.
+	 */
+	var offset Object = tagging(TagDateTimeString,Encode("2013-03-21T22:04:00.25+02:00"))
+	var value, e = offset.Time()
+	var _, seconds = value.Zone()
+	if nil != e || 7200 != seconds || !expected.Add(250*time.Millisecond).Equal(value) {
+		t.Errorf("Expected '%v' at offset 7200, found '%v' '%v'.",expected,value,e)
+	}
+	e = DecOptions{Subseconds: SubsecondTruncate, TimeLocation: time.UTC}.Unmarshal(offset,&value)
+	if nil != e || !expected.Equal(value) || time.UTC != value.Location() {
+		t.Errorf("Expected '%v', found '%v' '%v'.",expected,value,e)
+	}
+	e = DecOptions{Subseconds: SubsecondReject}.Unmarshal(offset,&value)
+	if ErrorSubsecond != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorSubsecond,e)
+	}
+	e = DecOptions{Subseconds: SubsecondReject}.Unmarshal(Encode(expected),&value)
+	if nil != e || !expected.Equal(value) {
+		t.Errorf("Expected '%v', found '%v' '%v'.",expected,value,e)
+	}
+	/*
+	 * Struct fields.
+	 */
+	type TypeTestEvent struct {
+		At time.Time `cbor:"at"`
+		Until *time.Time `cbor:"until"`
+	}
+	var until time.Time = expected.Add(time.Hour)
+	var event TypeTestEvent
+	e = Unmarshal(Encode(TypeTestEvent{expected,&until}),&event)
+	if nil != e {
+		t.Fatal(e)
+	} else if !expected.Equal(event.At) || nil == event.Until || !until.Equal(*event.Until) {
+		t.Errorf("Expected '%v' and '%v', found '%v' and '%v'.",expected,until,event.At,event.Until)
+	}
+	/*
+	 * Malformed content.
+	 */
+	for _, o := range []Object{
+		tagging(TagDateTimeEpoch,Encode("2013-03-21T20:04:00Z")),
+		tagging(TagDateTimeString,Encode(1363896240)),
+		tagging(TagDateTimeString,Encode("21 March 2013")),
+		tagging(TagDateTimeEpoch,Object{0xF9,0x7E,0x00}),
+		tagging(TagURI,Encode("2013-03-21T20:04:00Z")),
+	} {
+		_, e = o.Time()
+		if !errors.Is(e,ErrorTime) {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",ErrorTime,[]byte(o),e)
+		}
+	}
+}
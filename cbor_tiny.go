@@ -0,0 +1,346 @@
+//go:build cbor_tiny
+
+/*
+ * CBOR RFC8949 GOPL codec without reflection
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949
+ * https://tinygo.org/docs/reference/lang-support/stdlib/
+ */
+package cbor
+
+import (
+	"github.com/syntelos/go-endian"
+	"math"
+	"strconv"
+)
+/*
+ * Decoded content not representable by target type, including
+ * numeric content out of range of the target type.
+ */
+type UnmarshalTypeError struct {
+	/*
+	 * Description of CBOR content.
+	 */
+	Value string
+	/*
+	 * GOPL target type name.
+	 */
+	Type string
+}
+/*
+ * Target types of <UnmarshalTypeError>.
+ */
+const typeUint64 string = "uint64"
+const typeInt64 string = "int64"
+const typeFloat64 string = "float64"
+const typeBytes string = "[]uint8"
+const typeBool string = "bool"
+const typeItems string = "[]cbor.Object"
+const typeEntries string = "[][2]cbor.Object"
+const typeOrderedMap string = "cbor.OrderedMap"
+/*
+ * References are not identified without reflection.
+ */
+func reference(a any) (any, bool) {
+	return nil, false
+}
+/*
+ * Values not recognized by <Encode> are <ErrorReflection>.
+ */
+func encodeReflect(a any, state *encoding) (Object) {
+	state.fail(ErrorReflection)
+	return Object{0xF7}
+}
+/*
+ * Store object content into the value referenced by pointer,
+ * for the targets not requiring reflection: the values of
+ * <Object#Decode>, scalars by their typed accessors, <Object>,
+ * <RawMessage>, <OrderedMap>, <Unmarshaler> and <Coder>.
+ */
+func unmarshalPointer(o Object, v any, state *decoding) (e error) {
+	switch v.(type) {
+	case *string:
+		var target *string = v.(*string)
+		var value string
+		value, e = o.Text()
+		if nil == target {
+			return ErrorDecodeTarget
+		} else if nil == e {
+			*target = value
+		}
+		return e
+	case *[]byte:
+		var target *[]byte = v.(*[]byte)
+		var value []byte
+		value, e = o.Bytes()
+		if nil == target {
+			return ErrorDecodeTarget
+		} else if nil == e {
+			*target = append([]byte{},value...)
+		}
+		return e
+	case *bool:
+		var target *bool = v.(*bool)
+		var value bool
+		value, e = o.Bool()
+		if nil == target {
+			return ErrorDecodeTarget
+		} else if nil == e {
+			*target = value
+		}
+		return e
+	case *uint64:
+		var target *uint64 = v.(*uint64)
+		var value uint64
+		value, e = o.Uint()
+		if nil == target {
+			return ErrorDecodeTarget
+		} else if nil == e {
+			*target = value
+		}
+		return e
+	case *int64:
+		var target *int64 = v.(*int64)
+		var value int64
+		value, e = o.Int()
+		if nil == target {
+			return ErrorDecodeTarget
+		} else if nil == e {
+			*target = value
+		}
+		return e
+	case *float64:
+		var target *float64 = v.(*float64)
+		var value float64
+		value, e = o.numeric()
+		if nil == target {
+			return ErrorDecodeTarget
+		} else if nil == e {
+			*target = value
+		}
+		return e
+	case *any:
+		var target *any = v.(*any)
+		if nil == target {
+			return ErrorDecodeTarget
+		} else {
+			*target = state.decode(o)
+			return nil
+		}
+	case *Object:
+		var target *Object = v.(*Object)
+		if nil == target {
+			return ErrorDecodeTarget
+		} else {
+			*target = append(Object{},o...)
+			return nil
+		}
+	case *RawMessage:
+		var target *RawMessage = v.(*RawMessage)
+		if nil == target {
+			return ErrorDecodeTarget
+		} else {
+			*target = append(RawMessage{},o...)
+			return nil
+		}
+	case *OrderedMap:
+		var target *OrderedMap = v.(*OrderedMap)
+		if nil == target {
+			return ErrorDecodeTarget
+		} else if o.IsNull() {
+			*target = nil
+			return nil
+		} else if MajorMap != o.Major() {
+			return &UnmarshalTypeError{o.MajorString(),typeOrderedMap}
+		} else {
+			*target = o.decodeOrdered().(OrderedMap)
+			return nil
+		}
+	case Unmarshaler:
+		return v.(Unmarshaler).UnmarshalCBOR(append([]byte{},o...))
+	case Coder:
+		var coder Coder = v.(Coder)
+		return coder.Decode(coderContent(coder,o))
+	default:
+		return ErrorReflection
+	}
+}
+/*
+ * Bignums are not available without "math/big": "big.Int"
+ * values are <ErrorReflection>.
+ */
+func encodeBignum(a any) (this Object, ok bool) {
+	return nil, false
+}
+/*
+ * Produce the value of a negative integer argument beyond the
+ * range of "int64", as the negative bignum (tag 3) of equal
+ * value.
+ */
+func negativeValue(arg uint64) (any) {
+	return Tagged{TagNegativeBignum,endian.BigEndian.EncodeUint64(arg)}
+}
+/*
+ * Represent the value of a negative integer argument in
+ * decimal.
+ */
+func negativeString(arg uint64) (string) {
+	if math.MaxUint64 == arg {
+		return "-18446744073709551616"
+	} else {
+		return "-"+strconv.FormatUint(arg+1,10)
+	}
+}
+/*
+ * Produce the value of unsigned (tag 2) or negative (tag 3)
+ * bignum content, as <Tagged>.
+ */
+func bignumValue(negative bool, content []byte) (any) {
+	if negative {
+		return Tagged{TagNegativeBignum,content}
+	} else {
+		return Tagged{TagUnsignedBignum,content}
+	}
+}
+/*
+ * Represent a bignum value of <bignumValue> in decimal, when
+ * "ok", by long division of its octets.
+ */
+func bignumString(value any) (text string, ok bool) {
+	var tagged Tagged
+	tagged, ok = value.(Tagged)
+	if !ok {
+		return "", false
+	}
+	var content []byte
+	content, ok = tagged.Content.([]byte)
+	if !ok {
+		return "", false
+	}
+	var magnitude []byte = append([]byte{},content...)
+	if TagNegativeBignum == tagged.Number {
+		var n int = len(magnitude)-1
+		for ; 0 <= n; n-- {
+			magnitude[n] += 1
+			if 0 != magnitude[n] {
+				break
+			}
+		}
+		if 0 > n {
+			magnitude = append([]byte{1},magnitude...)
+		}
+	}
+	var digits []byte
+	for zero := false; !zero; {
+		var remainder int = 0
+		zero = true
+		for n, octet := range magnitude {
+			var dividend int = (remainder << 8) | int(octet)
+			magnitude[n] = byte(dividend/10)
+			remainder = (dividend%10)
+			if 0 != magnitude[n] {
+				zero = false
+			}
+		}
+		digits = append(digits,byte('0'+remainder))
+	}
+	if TagNegativeBignum == tagged.Number {
+		digits = append(digits,'-')
+	}
+	for a, b := 0, len(digits)-1; a < b; a, b = a+1, b-1 {
+		digits[a], digits[b] = digits[b], digits[a]
+	}
+	return string(digits), true
+}
+/*
+ * Resolve float object value, or integer object value
+ * converting to float64 without loss, as by reflection.
+ */
+func (this Object) numeric() (float64, error) {
+	switch this.Major() {
+	case MajorUint:
+		var u, e = this.Uint()
+		if nil != e {
+			return 0, e
+		} else if uint64(float64(u)) != u {
+			return 0, &UnmarshalTypeError{this.MajorString(),typeFloat64}
+		} else {
+			return float64(u), nil
+		}
+	case MajorSint:
+		var i, e = this.Int()
+		if nil != e {
+			return 0, e
+		} else if int64(float64(i)) != i {
+			return 0, &UnmarshalTypeError{this.MajorString(),typeFloat64}
+		} else {
+			return float64(i), nil
+		}
+	default:
+		return this.Float()
+	}
+}
+/*
+ * Equality of <OrderedMap> keys by their encoding.
+ */
+func keyEqual(a, b any) (bool) {
+	return Encode(a).Equal(Encode(b))
+}
+/*
+ * The tag numbers of Coder types are not available without
+ * reflection.
+ */
+func RegisterCoder(number uint64, factory func() (Coder)) (error) {
+	return ErrorReflection
+}
+/*
+ */
+func coderTag(coder Coder) (number uint64, ok bool) {
+	return 0, false
+}
+/*
+ * The decoding of registered types is not available without
+ * reflection.
+ */
+func RegisterType(number uint64, factory func() (any)) (error) {
+	return ErrorReflection
+}
+/*
+ */
+func RegisterTypeKey(key string, name string, factory func() (any)) (error) {
+	return ErrorReflection
+}
+/*
+ */
+func decodeType(number uint64, content Object) (value any, ok bool) {
+	return nil, false
+}
+/*
+ */
+func decodeTypeKey(o Object, table map[string]any) (value any, ok bool) {
+	return nil, false
+}
+/*
+ * Columnar decoding is not available without reflection.
+ */
+type Plan struct {
+}
+/*
+ */
+func (this DecOptions) NewPlan(v any) (*Plan, error) {
+	return nil, ErrorReflection
+}
+/*
+ */
+func NewPlan(v any) (*Plan, error) {
+	return nil, ErrorReflection
+}
+/*
+ */
+func (this *Plan) Unmarshal(data []byte, v any) (error) {
+	return ErrorReflection
+}
@@ -0,0 +1,29 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR GOPL codec without reflection Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"os/exec"
+	"testing"
+)
+/*
+ * The tests of the package pass under build tag "cbor_tiny".
+ */
+func TestTiny(t *testing.T){
+	if testing.Short() {
+		t.Skip("build tag cbor_tiny in short mode")
+	}
+	var tool, e = exec.LookPath("go")
+	if nil != e {
+		t.Skip(e)
+	}
+	var output []byte
+	output, e = exec.Command(tool,"test","-count=1","-tags","cbor_tiny",".").CombinedOutput()
+	if nil != e {
+		t.Errorf("Expected success under build tag cbor_tiny, found '%v'.\n%s",e,output)
+	}
+}
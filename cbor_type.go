@@ -0,0 +1,187 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR RFC8949 Type Registry
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3.4
+ * https://pkg.go.dev/encoding/gob#Register
+ */
+package cbor
+
+import (
+	"reflect"
+	"sync"
+)
+/*
+ * Type factories by tag number, and tag numbers by (element)
+ * type.
+ */
+var typeLock sync.RWMutex
+var typeFactory map[uint64]func() (any) = map[uint64]func() (any){}
+var typeNumber map[reflect.Type]uint64 = map[reflect.Type]uint64{}
+/*
+ * Type factories by the (text string) value of a discriminator
+ * key, in the order of registration of the discriminator keys.
+ */
+type typeKey struct {
+
+	key string
+
+	factory map[string]func() (any)
+}
+var typeKeys []typeKey
+/*
+ * Register the type produced by factory under tag number, so
+ * that its values are tagged on encode, and its tagged content
+ * decodes into a new value of the type, as by <Unmarshal>.  The
+ * factory produces a new pointer to a zero value of the type
+ * for each decode, which is the value of <Object#Decode> for the
+ * tagged content, and which is stored into interfaces by
+ * <Unmarshal>.  Compare <RegisterCoder>.
+ */
+func RegisterType(number uint64, factory func() (any)) (error) {
+	var t, e = typeOf(factory)
+	if nil != e {
+		return e
+	} else {
+		coderLock.RLock()
+		var _, coded = coderFactory[number]
+		coderLock.RUnlock()
+
+		typeLock.Lock()
+		defer typeLock.Unlock()
+
+		var _, tagged = tagRegistry[number]
+		var _, registered = typeFactory[number]
+		var _, typed = typeNumber[t]
+		if tagged || coded || registered || typed {
+			return ErrorTypeRegistered
+		} else {
+			typeFactory[number] = factory
+			typeNumber[t] = number
+			return nil
+		}
+	}
+}
+/*
+ * Register the type produced by factory under the text string
+ * name of discriminator key, so that maps having the entry
+ * "key: name" decode into a new value of the type, as by
+ * <Unmarshal>.  The discriminator entry is not produced on
+ * encode, and is expected to be a field of the type.  Compare
+ * <RegisterType>.
+ */
+func RegisterTypeKey(key string, name string, factory func() (any)) (error) {
+	var _, e = typeOf(factory)
+	if nil != e {
+		return e
+	} else {
+		typeLock.Lock()
+		defer typeLock.Unlock()
+
+		for _, registry := range typeKeys {
+			if key == registry.key {
+				var _, registered = registry.factory[name]
+				if registered {
+					return ErrorTypeRegistered
+				} else {
+					registry.factory[name] = factory
+					return nil
+				}
+			}
+		}
+		typeKeys = append(typeKeys,typeKey{key,map[string]func() (any){name: factory}})
+		return nil
+	}
+}
+/*
+ * Resolve the (element) type of the pointer produced by
+ * factory.
+ */
+func typeOf(factory func() (any)) (reflect.Type, error) {
+	if nil == factory {
+		return nil, ErrorTypeFactory
+	} else {
+		var v reflect.Value = reflect.ValueOf(factory())
+		if reflect.Pointer != v.Kind() || v.IsNil() {
+			return nil, ErrorTypeFactory
+		} else {
+			return v.Type().Elem(), nil
+		}
+	}
+}
+/*
+ * Resolve the tag number of registered type.
+ */
+func typeTag(t reflect.Type) (number uint64, ok bool) {
+	typeLock.RLock()
+	defer typeLock.RUnlock()
+
+	number, ok = typeNumber[t]
+	return number, ok
+}
+/*
+ * Resolve the content of object for registered type, removing
+ * its tag.
+ */
+func typeContent(t reflect.Type, o Object) (Object) {
+	var number, ok = typeTag(t)
+	if ok {
+		var tag, content, tagged = o.tagged()
+		if tagged && tag == number {
+			return content
+		}
+	}
+	return o
+}
+/*
+ * Decode tagged content into a new value of the type
+ * registered for tag number, when "ok".  Content that the
+ * type rejects is not "ok".
+ */
+func decodeType(number uint64, content Object) (value any, ok bool) {
+	typeLock.RLock()
+	var factory func() (any)
+	factory, ok = typeFactory[number]
+	typeLock.RUnlock()
+
+	if ok {
+		value = factory()
+		if nil != Unmarshal(content,value) {
+			return nil, false
+		}
+	}
+	return value, ok
+}
+/*
+ * Decode map into a new value of the type registered for the
+ * value of its discriminator key, when "ok".  A map that the
+ * type rejects is not "ok".
+ */
+func decodeTypeKey(o Object, table map[string]any) (value any, ok bool) {
+	typeLock.RLock()
+	var factory func() (any)
+	for _, registry := range typeKeys {
+		var name string
+		name, ok = table[registry.key].(string)
+		if ok {
+			factory, ok = registry.factory[name]
+			if ok {
+				break
+			}
+		}
+	}
+	typeLock.RUnlock()
+
+	if ok {
+		value = factory()
+		if nil != Unmarshal(o,value) {
+			return nil, false
+		}
+	}
+	return value, ok
+}
@@ -0,0 +1,123 @@
+//go:build !cbor_tiny
+
+/*
+ * CBOR Type Registry Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"testing"
+)
+
+type TypeTestCircle struct {
+
+	Radius uint16
+}
+
+type TypeTestSquare struct {
+
+	Side uint16
+}
+
+type TypeTestEvent struct {
+
+	Kind string
+
+	Name string
+}
+
+type TypeTestShape struct {
+
+	Shape *TypeTestCircle
+}
+
+func TestTypeRegistry(t *testing.T){
+	var e error = RegisterType(40200,func() (any) { return &TypeTestCircle{} })
+	if nil != e {
+		t.Fatal(e)
+	}
+	e = RegisterType(40201,func() (any) { return &TypeTestSquare{} })
+	if nil != e {
+		t.Fatal(e)
+	}
+	e = RegisterType(40200,func() (any) { return &TypeTestEvent{} })
+	if ErrorTypeRegistered != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorTypeRegistered,e)
+	}
+	e = RegisterType(40202,func() (any) { return &TypeTestCircle{} })
+	if ErrorTypeRegistered != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorTypeRegistered,e)
+	}
+	e = RegisterType(TagURI,func() (any) { return &TypeTestEvent{} })
+	if ErrorTypeRegistered != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorTypeRegistered,e)
+	}
+	e = RegisterType(40203,func() (any) { return TypeTestEvent{} })
+	if ErrorTypeFactory != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorTypeFactory,e)
+	}
+
+	var circle TypeTestCircle = TypeTestCircle{3}
+	var expected []byte = []byte{0xD9,0x9D,0x08,0xA1,0x66,'R','a','d','i','u','s',0x03}
+	for _, value := range []any{circle,&circle} {
+		var code Object = Encode(value)
+		if !bytes.Equal(expected,code) {
+			t.Errorf("Expected encoding '%X', found '%X'.",expected,[]byte(code))
+		}
+	}
+
+	var messages []any
+	e = Unmarshal(Encode([]any{circle,TypeTestSquare{4},"other"}),&messages)
+	if nil != e {
+		t.Fatal(e)
+	} else if 3 != len(messages) {
+		t.Fatalf("Expected 3 messages, found %d.",len(messages))
+	}
+	var c, isCircle = messages[0].(*TypeTestCircle)
+	if !isCircle || 3 != c.Radius {
+		t.Errorf("Expected '%v', found '%#v'.",circle,messages[0])
+	}
+	var s, isSquare = messages[1].(*TypeTestSquare)
+	if !isSquare || 4 != s.Side {
+		t.Errorf("Expected '%v', found '%#v'.",TypeTestSquare{4},messages[1])
+	}
+	if "other" != messages[2] {
+		t.Errorf("Expected 'other', found '%#v'.",messages[2])
+	}
+
+	var shape TypeTestShape
+	e = Unmarshal(Encode(TypeTestShape{&circle}),&shape)
+	if nil != e {
+		t.Fatal(e)
+	} else if nil == shape.Shape || 3 != shape.Shape.Radius {
+		t.Errorf("Expected '%v', found '%v'.",circle,shape.Shape)
+	}
+}
+
+func TestTypeRegistryKey(t *testing.T){
+	var e error = RegisterTypeKey("Kind","event",func() (any) { return &TypeTestEvent{} })
+	if nil != e {
+		t.Fatal(e)
+	}
+	e = RegisterTypeKey("Kind","event",func() (any) { return &TypeTestEvent{} })
+	if ErrorTypeRegistered != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorTypeRegistered,e)
+	}
+
+	var messages []any
+	e = Unmarshal(Encode([]any{TypeTestEvent{"event","start"},map[string]any{"Kind": "other"}}),&messages)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var event, ok = messages[0].(*TypeTestEvent)
+	if !ok || "start" != event.Name {
+		t.Errorf("Expected '%v', found '%#v'.",TypeTestEvent{"event","start"},messages[0])
+	}
+	var table map[string]any
+	table, ok = messages[1].(map[string]any)
+	if !ok || "other" != table["Kind"] {
+		t.Errorf("Expected 'map[Kind:other]', found '%#v'.",messages[1])
+	}
+}
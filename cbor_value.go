@@ -0,0 +1,254 @@
+/*
+ * CBOR RFC8949 Typed Accessors
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3
+ */
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+)
+/*
+ * Validation errors produced by <Object#RawItem>.
+ */
+var ErrorItemIndex error = errors.New("CBOR nested data item index out of range")
+/*
+ * Decoding error of numbers not represented by the target
+ * type, as of code generated by "cmd/cborgen".
+ */
+var ErrorNumericRange error = errors.New("CBOR number out of range of target type")
+/*
+ * Resolve unsigned integer object value from its head.
+ */
+func (this Object) Uint() (uint64, error) {
+	var arg, _, e = this.head()
+	if nil != e {
+		return 0, e
+	} else if MajorUint != this.Major() {
+		return 0, &UnmarshalTypeError{this.MajorString(),typeUint64}
+	} else {
+		return arg, nil
+	}
+}
+/*
+ * Resolve unsigned or negative integer object value from its
+ * head, failing for values out of the range of "int64".
+ */
+func (this Object) Int() (int64, error) {
+	var arg, _, e = this.head()
+	if nil != e {
+		return 0, e
+	} else {
+		switch this.Major() {
+		case MajorUint:
+			if math.MaxInt64 < arg {
+				return 0, &UnmarshalTypeError{fmt.Sprintf("unsigned integer %d",arg),typeInt64}
+			} else {
+				return int64(arg), nil
+			}
+		case MajorSint:
+			if math.MaxInt64 < arg {
+				return 0, &UnmarshalTypeError{fmt.Sprintf("negative integer -1-%d",arg),typeInt64}
+			} else {
+				return (-1-int64(arg)), nil
+			}
+		default:
+			return 0, &UnmarshalTypeError{this.MajorString(),typeInt64}
+		}
+	}
+}
+/*
+ * Resolve half, single, or double precision float object
+ * value.
+ */
+func (this Object) Float() (float64, error) {
+	var value, ok = this.float()
+	if ok {
+		return value, nil
+	} else if 0 == len(this) {
+		return 0, ErrorTruncated
+	} else {
+		return 0, &UnmarshalTypeError{this.MajorString(),typeFloat64}
+	}
+}
+/*
+ * Resolve the width in bits of the float object encoding: 16
+ * for half, 32 for single, and 64 for double precision.
+ */
+func (this Object) FloatWidth() (int, error) {
+	var _, ok = this.float()
+	if !ok && 0 == len(this) {
+		return 0, ErrorTruncated
+	} else if !ok {
+		return 0, &UnmarshalTypeError{this.MajorString(),typeFloat64}
+	} else {
+		switch this[0] {
+		case 0xF9:
+			return 16, nil
+		case 0xFA:
+			return 32, nil
+		default:
+			return 64, nil
+		}
+	}
+}
+/*
+ * Resolve byte string object content.  The content of a
+ * definite length byte string is shared with the object.
+ */
+func (this Object) Bytes() ([]byte, error) {
+	if 0 == len(this) {
+		return nil, ErrorTruncated
+	} else if MajorBlob != this.Major() {
+		return nil, &UnmarshalTypeError{this.MajorString(),typeBytes}
+	} else {
+		return this.payload()
+	}
+}
+/*
+ * Resolve boolean object value.
+ */
+func (this Object) Bool() (bool, error) {
+	if 0 == len(this) {
+		return false, ErrorTruncated
+	} else {
+		switch this[0] {
+		case 0xF4:
+			return false, nil
+		case 0xF5:
+			return true, nil
+		default:
+			return false, &UnmarshalTypeError{this.MajorString(),typeBool}
+		}
+	}
+}
+/*
+ * Resolve the data items of an array object, without decoding
+ * them, for partial or deferred processing.
+ */
+func (this Object) Items() ([]Object, error) {
+	if 0 == len(this) {
+		return nil, ErrorTruncated
+	} else if MajorArray != this.Major() {
+		return nil, &UnmarshalTypeError{this.MajorString(),typeItems}
+	} else {
+		return this.items()
+	}
+}
+/*
+ * Resolve the data items of an array, or the alternating keys
+ * and values of a map.
+ */
+func (this Object) items() (list []Object, e error) {
+	var arg uint64
+	var z int
+	arg, z, e = this.head()
+	if nil != e {
+		return nil, e
+	} else {
+		var major Major = this.Major()
+		if MajorMap == major {
+			arg *= 2
+		} else if MajorArray != major {
+			return nil, fmt.Errorf(ErrorWrapDecodeType,this.MajorString(),"container")
+		}
+		var indefinite bool = (0x1F == (this[0] & 0x1F))
+		var r *bytes.Reader = bytes.NewReader(this[z:])
+		var n uint64
+		for n = 0; indefinite || n < arg; n++ {
+			var o Object = Object{}
+			o, e = o.Read(r)
+			if nil == e {
+				list = append(list,o)
+			} else if indefinite && Break == e {
+				break
+			} else {
+				return nil, fmt.Errorf(ErrorWrapRead,e)
+			}
+		}
+		return list, nil
+	}
+}
+/*
+ * Resolve the key and value pairs of a map object, without
+ * decoding them, for partial or deferred processing.
+ */
+func (this Object) Entries() (list [][2]Object, e error) {
+	if 0 == len(this) {
+		return nil, ErrorTruncated
+	} else if MajorMap != this.Major() {
+		return nil, &UnmarshalTypeError{this.MajorString(),typeEntries}
+	} else {
+		var items []Object
+		items, e = this.items()
+		if nil != e {
+			return nil, e
+		} else {
+			for n := 0; (n+1) < len(items); n += 2 {
+				list = append(list,[2]Object{items[n],items[n+1]})
+			}
+			return list, nil
+		}
+	}
+}
+/*
+ * Resolve the encoding of the nested data item at index: the
+ * element of an array, the key (even) or value (odd) of the
+ * alternating keys and values of a map, or the content (zero)
+ * of a tag.
+ *
+ * The nested data item is the subslice of the object holding
+ * its encoding, byte for byte, and is not re-encoded.  An
+ * object decoded into <RawMessage> or <Object> is a byte exact
+ * copy, and a <RawMessage> encodes verbatim, such that a
+ * signature computed over a nested data item remains valid
+ * through <Unmarshal> and <Encode>.  Deterministic encoding
+ * options re-encode every data item, excepting the payload of
+ * a byte string, so that a signed data item carried through
+ * deterministic encoding is embedded as tag 24 (<Embedded>).
+ */
+func (this Object) RawItem(n int) (Object, error) {
+	var arg, z, e = this.head()
+	if nil != e {
+		return nil, e
+	}
+	var major Major = this.Major()
+	var indefinite bool = (0x1F == (this[0] & 0x1F))
+	switch major {
+	case MajorArray, MajorMap, MajorTagged:
+		if MajorMap == major {
+			arg *= 2
+		} else if MajorTagged == major {
+			arg = 1
+		}
+		if 0 > n || (!indefinite && uint64(n) >= arg) {
+			return nil, ErrorItemIndex
+		}
+		var offset int = z
+		for index := 0; ; index++ {
+			if offset >= len(this) {
+				return nil, ErrorTruncated
+			} else if indefinite && 0xFF == this[offset] {
+				return nil, ErrorItemIndex
+			}
+			var length int
+			length, e = Object(this[offset:]).ItemLen()
+			if nil != e {
+				return nil, fmt.Errorf(ErrorWrapRead,e)
+			} else if index == n {
+				return this[offset:offset+length], nil
+			} else {
+				offset += length
+			}
+		}
+	default:
+		return nil, &UnmarshalTypeError{this.MajorString(),typeItems}
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * CBOR Typed Accessors Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTypedAccessors(t *testing.T){
+	var u, e = Object{0x19,0x03,0xE8}.Uint()
+	if nil != e || 1000 != u {
+		t.Errorf("Expected '1000', found '%d' (%v).",u,e)
+	}
+	_, e = Object{0x61,'a'}.Uint()
+	if nil == e {
+		t.Errorf("Expected error for text.")
+	}
+
+	var i int64
+	for _, c := range []struct{ code Object; value int64 }{
+		{Object{0x0A}, 10},
+		{Object{0x29}, -10},
+		{Object{0x39,0x03,0xE7}, -1000},
+		{Object{0x3B,0x7F,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF,0xFF}, -9223372036854775808},
+	} {
+		i, e = c.code.Int()
+		if nil != e || c.value != i {
+			t.Errorf("Expected '%d', found '%d' (%v).",c.value,i,e)
+		}
+	}
+	_, e = Object{0x3B,0x80,0x00,0x00,0x00,0x00,0x00,0x00,0x00}.Int()
+	if nil == e {
+		t.Errorf("Expected error for -1-2^63.")
+	}
+
+	var f float64
+	f, e = Object{0xF9,0x3E,0x00}.Float()
+	if nil != e || 1.5 != f {
+		t.Errorf("Expected '1.5', found '%v' (%v).",f,e)
+	}
+	_, e = Object{0x01}.Float()
+	if nil == e {
+		t.Errorf("Expected error for integer.")
+	}
+	for width, code := range map[int]Object{16: {0xF9,0x3E,0x00}, 32: {0xFA,0x47,0xC3,0x50,0x00}, 64: {0xFB,0x3F,0xF1,0x99,0x99,0x99,0x99,0x99,0x9A}} {
+		var w int
+		w, e = code.FloatWidth()
+		if nil != e || width != w {
+			t.Errorf("Expected '%d' for '%X', found '%d' (%v).",width,[]byte(code),w,e)
+		}
+	}
+
+	var b []byte
+	b, e = Object{0x5F,0x41,0x01,0x42,0x02,0x03,0xFF}.Bytes()
+	if nil != e || !bytes.Equal([]byte{1,2,3},b) {
+		t.Errorf("Expected '010203', found '%X' (%v).",b,e)
+	}
+
+	var ok bool
+	ok, e = Object{0xF5}.Bool()
+	if nil != e || !ok {
+		t.Errorf("Expected 'true', found '%v' (%v).",ok,e)
+	}
+	_, e = Object{0xF6}.Bool()
+	if nil == e {
+		t.Errorf("Expected error for null.")
+	}
+}
+
+func TestContainers(t *testing.T){
+	var items, e = Object{0x83,0x01,0x82,0x02,0x03,0x61,'a'}.Items()
+	if nil != e {
+		t.Fatal(e)
+	} else if 3 != len(items) || !bytes.Equal([]byte{0x82,0x02,0x03},items[1]) || !bytes.Equal([]byte{0x61,'a'},items[2]) {
+		t.Errorf("Expected '01 820203 6161', found '%X'.",items)
+	}
+
+	var entries [][2]Object
+	entries, e = Object{0xBF,0x61,'a',0x01,0x61,'b',0x9F,0xFF,0xFF}.Entries()
+	if nil != e {
+		t.Fatal(e)
+	} else if 2 != len(entries) || !bytes.Equal([]byte{0x61,'b'},entries[1][0]) || !bytes.Equal([]byte{0x9F,0xFF},entries[1][1]) {
+		t.Errorf("Expected '6161:01 6162:9FFF', found '%X'.",entries)
+	}
+
+	_, e = Object{0xA0}.Items()
+	if nil == e {
+		t.Errorf("Expected error for map items.")
+	}
+	_, e = Object{0x80}.Entries()
+	if nil == e {
+		t.Errorf("Expected error for array entries.")
+	}
+}
+
+func TestRawItem(t *testing.T){
+	/*
+	 * Payload {"b": 1, "a": 24} with unsorted keys and a non
+	 * shortest argument.
+	 */
+	var payload Object = Object{0xA2,0x61,'b',0x18,0x01,0x61,'a',0x18,0x18}
+	var o Object = Object{0x9F,0x01}.Concatenate(payload).Concatenate([]byte{0xFF})
+
+	var item, e = o.RawItem(1)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(payload,item) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(payload),[]byte(item))
+	}
+	item, e = payload.RawItem(2)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0x61,'a'},item) {
+		t.Errorf("Expected '6161', found '%X'.",[]byte(item))
+	}
+	item, e = Object{0xD8,0x18,0x41,0x00}.RawItem(0)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal([]byte{0x41,0x00},item) {
+		t.Errorf("Expected '4100', found '%X'.",[]byte(item))
+	}
+	for _, n := range []int{-1,2} {
+		_, e = o.RawItem(n)
+		if ErrorItemIndex != e {
+			t.Errorf("Expected '%v', found '%v'.",ErrorItemIndex,e)
+		}
+	}
+	_, e = Object{0x61,'a'}.RawItem(0)
+	if nil == e {
+		t.Errorf("Expected error for text.")
+	}
+
+	var message RawMessage
+	e = Unmarshal(Encode([]any{RawMessage(payload),[]byte{0x5A}}),&message)
+	if nil != e {
+		t.Fatal(e)
+	}
+	item, e = Object(message).RawItem(0)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(payload,item) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(payload),[]byte(item))
+	}
+
+	var canonical Object
+	canonical, e = Encode([]any{Embedded(payload)}).Canonical()
+	if nil != e {
+		t.Fatal(e)
+	}
+	item, e = canonical.RawItem(0)
+	if nil != e {
+		t.Fatal(e)
+	}
+	item, e = item.RawItem(0)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var content []byte
+	content, e = item.Bytes()
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(payload,content) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(payload),content)
+	}
+}
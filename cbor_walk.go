@@ -0,0 +1,486 @@
+/*
+ * CBOR RFC8949 Traversal
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3
+ * https://tools.ietf.org/html/rfc8949#appendix-C
+ */
+package cbor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf8"
+)
+/*
+ * Limit of the payload buffer, such that the argument of a
+ * string head is not an allocation before the payload is
+ * available.
+ */
+const walkBuffer uint64 = 0x10000
+/*
+ * Visitor of a traversal by <walker>.  The head of each data
+ * item is visited on entering the data item, and the complete
+ * encoding of the data item is visited on exiting the data
+ * item, such that nested data items are visited between the
+ * two.  The break stop code is not visited, as it is included
+ * in the encoding of its indefinite length data item.
+ */
+type walkVisitor interface {
+	/*
+	 * Enter data item by its head.
+	 */
+	enter(head Object, depth int) (error)
+	/*
+	 * Exit data item by its encoding.
+	 */
+	exit(item Object, depth int) (error)
+}
+/*
+ * Well formed traversal of one data item from a source, per
+ * the algorithm of Appendix C [RFC8949], producing the
+ * encoding of the data item.
+ */
+type walker struct {
+
+	source io.Reader
+
+	visitor walkVisitor
+
+	octets Object
+}
+/*
+ * Traverse one data item from reader.  End of stream before the
+ * first byte of the data item is "io.EOF", and a break in place
+ * of the data item is <Break>.
+ */
+func walk(r io.Reader, visitor walkVisitor) (Object, error) {
+	var w walker = walker{r, visitor, Object{}}
+	var e error = w.item(0)
+	if nil != e {
+		return nil, e
+	} else {
+		return w.octets, nil
+	}
+}
+/*
+ * Traverse the (first) data item of object.
+ */
+func (this Object) walk(visitor walkVisitor) (Object, error) {
+	return walk(bytes.NewReader(this),visitor)
+}
+/*
+ * Data item having nested data items, in the traversal of
+ * <walker>.
+ */
+type walkFrame struct {
+	/*
+	 * Offset of the data item in the octets of the walker.
+	 */
+	start int
+
+	major Major
+
+	indefinite bool
+	/*
+	 * Count of nested data items of a definite length data
+	 * item, and of nested data items traversed.
+	 */
+	count, n uint64
+	/*
+	 * Indefinite length data item ended by break.
+	 */
+	broken bool
+}
+/*
+ * Determine whether the nested data items of the data item
+ * have been traversed.
+ */
+func (this *walkFrame) complete() (bool) {
+	if this.indefinite {
+		return this.broken
+	} else {
+		return (this.n == this.count)
+	}
+}
+/*
+ * Traverse the data item following the current position of the
+ * source, appending its encoding to the octets of the walker.
+ * Nested data items are traversed with an explicit stack
+ * rather than by recursion, so that the nesting depth of the
+ * data item is limited by its length, not by the stack of the
+ * goroutine.  The error of a nested data item is wrapped once,
+ * by its innermost enclosing data item, and is not wrapped again
+ * by the data items enclosing that one, so that the error of a
+ * deeply nested data item is not the length of its nesting.
+ */
+func (this *walker) item(depth int) (e error) {
+	var stack []walkFrame
+	var wrapped bool
+	for {
+		var frame walkFrame
+		var open bool
+		frame, open, e = this.head(depth+len(stack))
+		if nil == e && open && frame.complete() {
+			open = false
+			e = this.exit(frame.start,depth+len(stack))
+		}
+		for !open || nil != e {
+			open = false
+			var top int = len(stack)-1
+			if 0 > top {
+				return e
+			}
+			var parent *walkFrame = &stack[top]
+			var nested error = e
+			if wrapped {
+				/*
+				 * Error of a data item nested in the parent.
+				 */
+			} else if io.EOF == e {
+				e = fmt.Errorf(ErrorWrapRead,ErrorTruncated)
+			} else if Break == e && parent.indefinite {
+				if MajorMap == parent.major && 1 == (parent.n & 1) {
+					e = ErrorMapIncomplete
+				} else {
+					parent.broken = true
+					e = nil
+				}
+			} else if Break == e {
+				e = fmt.Errorf(ErrorWrapRead,ErrorUnexpectedBreak)
+			} else if nil != e {
+				e = fmt.Errorf(ErrorWrapRead,e)
+			} else if (MajorBlob == parent.major || MajorText == parent.major) && (Major(this.octets[frame.start] >> 5) != parent.major || 0x1F == (this.octets[frame.start] & 0x1F)) {
+				e = ErrorChunk
+			} else {
+				parent.n += 1
+			}
+
+			if nil == e && !parent.complete() {
+				break
+			} else {
+				frame = *parent
+				stack = stack[0:top]
+				if nil == e {
+					e = this.exit(frame.start,depth+len(stack))
+				} else if nil != nested {
+					wrapped = true
+				}
+			}
+		}
+		if open {
+			stack = append(stack,frame)
+		}
+	}
+}
+/*
+ * Read the head of the data item following the current
+ * position of the source, and the payload of a definite length
+ * string.  A data item having nested data items is "open" in
+ * the frame.
+ */
+func (this *walker) head(depth int) (frame walkFrame, open bool, e error) {
+	frame.start = len(this.octets)
+	var initial []byte = make([]byte,1)
+	_, e = io.ReadFull(this.source,initial)
+	if nil != e {
+		return frame, false, e
+	}
+	this.octets = append(this.octets,initial[0])
+
+	var ai byte = (initial[0] & 0x1F)
+	switch ai {
+	case 0x18, 0x19, 0x1A, 0x1B:
+		e = this.payload(uint64(1) << (ai-0x18))
+		if nil != e {
+			return frame, false, e
+		}
+	}
+	var arg uint64
+	var z int
+	frame.major, ai, arg, z, e = ParseHead(this.octets[frame.start:])
+	if nil != e {
+		return frame, false, e
+	}
+	frame.indefinite = (0x1F == ai)
+	if frame.indefinite {
+		switch frame.major {
+		case MajorUint, MajorSint, MajorTagged:
+			return frame, false, ErrorReservedHead
+		case MajorSimple:
+			return frame, false, Break
+		}
+	} else if MajorSimple == frame.major && 0x18 == ai && 32 > arg {
+		return frame, false, ErrorInvalidSimple
+	}
+	if nil != this.visitor {
+		e = this.visitor.enter(this.octets[frame.start:frame.start+z],depth)
+		if nil != e {
+			return frame, false, e
+		}
+	}
+
+	switch frame.major {
+	case MajorBlob, MajorText:
+		if frame.indefinite {
+			return frame, true, nil
+		} else {
+			e = this.payload(arg)
+			if nil != e {
+				return frame, false, e
+			} else if MajorText == frame.major && !utf8.Valid(this.octets[frame.start+z:]) {
+				return frame, false, ErrorInvalidUTF8
+			} else {
+				return frame, false, this.exit(frame.start,depth)
+			}
+		}
+	case MajorArray:
+		frame.count = arg
+		return frame, true, nil
+	case MajorMap:
+		if (math.MaxUint64/2) < arg {
+			frame.count = math.MaxUint64
+		} else {
+			frame.count = (2*arg)
+		}
+		return frame, true, nil
+	case MajorTagged:
+		frame.count = 1
+		return frame, true, nil
+	default:
+		return frame, false, this.exit(frame.start,depth)
+	}
+}
+/*
+ * Visit the complete data item at offset.
+ */
+func (this *walker) exit(start int, depth int) (error) {
+	if nil != this.visitor {
+		return this.visitor.exit(this.octets[start:],depth)
+	} else {
+		return nil
+	}
+}
+/*
+ * Append count octets from the source, for which end of stream
+ * is truncation.
+ */
+func (this *walker) payload(count uint64) (e error) {
+	for 0 < count {
+		var z uint64 = count
+		if walkBuffer < z {
+			z = walkBuffer
+		}
+		var d []byte = make([]byte,z)
+		e = readFull(this.source,d)
+		if nil != e {
+			return e
+		} else {
+			this.octets = append(this.octets,d...)
+			count -= z
+		}
+	}
+	return nil
+}
+/*
+ * Visitor producing the GOPL value of a data item.  See
+ * <Object#Decode>.
+ */
+type walkDecoder struct {
+
+	stack [][]any
+
+	value any
+	/*
+	 * Decoding state interning definite length text strings,
+	 * or nil.
+	 */
+	interning *decoding
+	/*
+	 * Arena of <Object#DecodeArena>, or nil, and the length
+	 * of the object decoded.
+	 */
+	arena *Arena
+
+	limit int
+}
+/*
+ * Open the list of nested values of a data item having nested
+ * data items.
+ */
+func (this *walkDecoder) enter(head Object, depth int) (error) {
+	switch head.Major() {
+	case MajorBlob, MajorText:
+		if 0x1F == (head[0] & 0x1F) {
+			this.stack = append(this.stack,nil)
+		}
+	case MajorArray, MajorMap, MajorTagged:
+		if nil != this.arena {
+			this.stack = append(this.stack,this.list(head))
+		} else {
+			this.stack = append(this.stack,[]any{})
+		}
+	}
+	return nil
+}
+/*
+ * Close the list of nested values, and produce the value of the
+ * data item.
+ */
+func (this *walkDecoder) exit(item Object, depth int) (error) {
+	var nested []any
+	var major Major = item.Major()
+	switch major {
+	case MajorBlob, MajorText:
+		if 0x1F == (item[0] & 0x1F) {
+			nested, this.stack = this.pop()
+		}
+	case MajorArray, MajorMap, MajorTagged:
+		nested, this.stack = this.pop()
+	}
+	var value any
+	if nil != this.interning && MajorText == major && 0x1F != (item[0] & 0x1F) {
+		var _, _, _, z, _ = ParseHead(item)
+		value = this.interning.intern(item[z:])
+	} else if nil != this.arena && MajorText == major && 0x1F != (item[0] & 0x1F) {
+		var _, _, _, z, _ = ParseHead(item)
+		value = this.arena.text(item[z:])
+	} else {
+		value = item.decodeItem(nested)
+	}
+	var top int = len(this.stack)-1
+	if 0 <= top {
+		this.stack[top] = append(this.stack[top],value)
+	} else {
+		this.value = value
+	}
+	return nil
+}
+/*
+ * Remove the list of nested values of the current data item.
+ */
+func (this *walkDecoder) pop() ([]any, [][]any) {
+	var top int = len(this.stack)-1
+	return this.stack[top], this.stack[0:top]
+}
+/*
+ * Produce the GOPL value of the data item from the values of its
+ * nested data items.
+ */
+func (this Object) decodeItem(nested []any) (any) {
+	var major, ai, arg, z, e = ParseHead(this)
+	if nil != e {
+		return nil
+	}
+	switch major {
+	case MajorUint:
+		switch ai {
+		case 0x19:
+			return uint16(arg)
+		case 0x1A:
+			return uint32(arg)
+		case 0x1B:
+			return arg
+		default:
+			return uint8(arg)
+		}
+	case MajorSint:
+		switch ai {
+		case 0x18:
+			return int16(-1-int64(arg))
+		case 0x19:
+			return int32(-1-int64(arg))
+		case 0x1A:
+			return (-1-int64(arg))
+		case 0x1B:
+			if math.MaxInt64 >= arg {
+				return (-1-int64(arg))
+			} else {
+				return negativeValue(arg)
+			}
+		default:
+			return (-1-int(arg))
+		}
+	case MajorBlob, MajorText:
+		var text []byte
+		if 0x1F == ai {
+			text, e = this.payload()
+			if nil != e {
+				return nil
+			}
+		} else {
+			text = this[z:z+int(arg)]
+		}
+		if MajorText == major {
+			return string(text)
+		} else {
+			return text
+		}
+	case MajorArray:
+		if nil == nested {
+			return []any{}
+		} else {
+			return nested
+		}
+	case MajorMap:
+		var o map[string]any = make(map[string]any,len(nested)/2)
+		for n := 0; (n+1) < len(nested); n += 2 {
+			var k, ok = nested[n].(string)
+			if ok {
+				o[k] = nested[n+1]
+			} else {
+				return orderedEntries(nested)
+			}
+		}
+		var value, ok = decodeTypeKey(this,o)
+		if ok {
+			return value
+		} else {
+			return o
+		}
+	case MajorTagged:
+		switch this[0] {
+		case 0xC0, 0xC1:
+			var value, e = this.Time()
+			if nil != e {
+				return Tagged{uint64(this[0] & 0x1F),nested[0]}
+			} else {
+				return value
+			}
+		case 0xC2, 0xC3:
+			var _, content, _ = this.tagged()
+			var data, ok = nested[0].([]byte)
+			if !ok || MajorBlob != content.Major() {
+				return Tagged{uint64(this[0] & 0x1F),nested[0]}
+			} else {
+				return bignumValue(0xC3 == this[0],data)
+			}
+		default:
+			return this.decodeTagged(nested[0])
+		}
+	default:
+		switch this[0] {
+		case 0xF4:
+			return false
+		case 0xF5:
+			return true
+		case 0xF6:
+			return nil
+		case 0xF7:
+			return Undefined{}
+		case 0xF9, 0xFA:
+			var value, _ = this.float()
+			return float32(value)
+		case 0xFB:
+			var value, _ = this.float()
+			return value
+		default:
+			return SimpleValue(arg)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * CBOR Traversal Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cbor
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWalk(t *testing.T){
+	var cases = []struct{ code []byte; value any; desc string }{
+		{[]byte{0x39,0x01,0xF3}, int32(-500), "<tag:signed integer><uint16>"},
+		{[]byte{0x59,0x00,0x02,0x01,0x02}, []byte{1,2}, "<tag:blob><uint16><byte[2]>"},
+		{[]byte{0x99,0x00,0x02,0x01,0x02}, []any{uint8(1),uint8(2)}, "<tag:array><uint16[2]><tag:unsigned integer><tag:unsigned integer>"},
+		{[]byte{0xB9,0x00,0x01,0x61,'a',0x9F,0xFF}, map[string]any{"a": []any{}}, "<tag:map><uint16[1]><tag:text><byte[1]><tag:array><break>"},
+	}
+	for _, c := range cases {
+		var o Object = Object(c.code)
+		if e := o.Valid(); nil != e {
+			t.Errorf("Expected valid '%X', found '%v'.",c.code,e)
+		}
+		if !reflect.DeepEqual(c.value,o.Decode()) {
+			t.Errorf("Expected '%v' for '%X', found '%v'.",c.value,c.code,o.Decode())
+		}
+		if c.desc != o.Describe() {
+			t.Errorf("Expected '%s' for '%X', found '%s'.",c.desc,c.code,o.Describe())
+		}
+	}
+
+	if e := (Object{0x99,0x00,0x02,0x01}).Valid(); !errors.Is(e,io.ErrUnexpectedEOF) {
+		t.Errorf("Expected '%v', found '%v'.",io.ErrUnexpectedEOF,e)
+	}
+	if e := (Object{}).Valid(); ErrorMissingData != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorMissingData,e)
+	}
+}
+
+func TestDecodeAppendixA(t *testing.T){
+	var cases = []struct{ code string; value any }{
+		{"00", uint8(0)},
+		{"17", uint8(23)},
+		{"1818", uint8(24)},
+		{"1864", uint8(100)},
+		{"1903e8", uint16(1000)},
+		{"1a000f4240", uint32(1000000)},
+		{"1b000000e8d4a51000", uint64(1000000000000)},
+		{"1bffffffffffffffff", uint64(18446744073709551615)},
+		{"20", int(-1)},
+		{"29", int(-10)},
+		{"3863", int16(-100)},
+		{"3903e7", int32(-1000)},
+		{"3a000f423f", int64(-1000000)},
+		{"3b000000e8d4a50fff", int64(-1000000000000)},
+		{"f90000", float32(0.0)},
+		{"f93c00", float32(1.0)},
+		{"f97bff", float32(65504.0)},
+		{"fa47c35000", float32(100000.0)},
+		{"fb3ff199999999999a", float64(1.1)},
+		{"fbc010666666666666", float64(-4.1)},
+		{"f4", false},
+		{"f5", true},
+		{"f6", nil},
+		{"f7", Undefined{}},
+		{"f0", SimpleValue(16)},
+		{"f8ff", SimpleValue(255)},
+		{"40", []byte{}},
+		{"4401020304", []byte{1,2,3,4}},
+		{"60", ""},
+		{"6161", "a"},
+		{"6449455446", "IETF"},
+		{"62225c", "\"\\"},
+		{"62c3bc", "ü"},
+		{"63e6b0b4", "水"},
+		{"64f0908591", "\U00010151"},
+		{"7818" + hex.EncodeToString([]byte("abcdefghijklmnopqrstuvwx")), "abcdefghijklmnopqrstuvwx"},
+		{"79000161" + "61", "a"},
+		{"80", []any{}},
+		{"83010203", []any{uint8(1),uint8(2),uint8(3)}},
+		{"8301820203820405", []any{uint8(1),[]any{uint8(2),uint8(3)},[]any{uint8(4),uint8(5)}}},
+		{"98190102030405060708090a0b0c0d0e0f101112131415161718181819", []any{uint8(1),uint8(2),uint8(3),uint8(4),uint8(5),uint8(6),uint8(7),uint8(8),uint8(9),uint8(10),uint8(11),uint8(12),uint8(13),uint8(14),uint8(15),uint8(16),uint8(17),uint8(18),uint8(19),uint8(20),uint8(21),uint8(22),uint8(23),uint8(24),uint8(25)}},
+		{"990002" + "0102", []any{uint8(1),uint8(2)}},
+		{"a0", map[string]any{}},
+		{"a26161016162820203", map[string]any{"a": uint8(1), "b": []any{uint8(2),uint8(3)}}},
+		{"826161a161626163", []any{"a",map[string]any{"b": "c"}}},
+		{"a56161614161626142616361436164614461656145", map[string]any{"a": "A", "b": "B", "c": "C", "d": "D", "e": "E"}},
+		{"b90001" + "6161" + "01", map[string]any{"a": uint8(1)}},
+		{"5f42010243030405ff", []byte{1,2,3,4,5}},
+		{"7f657374726561646d696e67ff", "streaming"},
+		{"9fff", []any{}},
+		{"9f018202039f0405ffff", []any{uint8(1),[]any{uint8(2),uint8(3)},[]any{uint8(4),uint8(5)}}},
+		{"bf61610161629f0203ffff", map[string]any{"a": uint8(1), "b": []any{uint8(2),uint8(3)}}},
+		{"bf6346756ef563416d7421ff", map[string]any{"Fun": true, "Amt": int(-2)}},
+		{"c074323031332d30332d32315432303a30343a30305a", time.Date(2013,3,21,20,4,0,0,time.UTC)},
+		{"c11a514b67b0", time.Date(2013,3,21,20,4,0,0,time.UTC)},
+		{"c1fb41d452d9ec200000", time.Date(2013,3,21,20,4,0,500000000,time.UTC)},
+		{"c06161", Tagged{TagDateTimeString,"a"}},
+	}
+	for _, c := range cases {
+		var code, _ = hex.DecodeString(c.code)
+		var value any = Object(code).Decode()
+		if !reflect.DeepEqual(c.value,value) {
+			t.Errorf("Expected '%v' (%T) for '%s', found '%v' (%T).",c.value,c.value,c.code,value,value)
+		}
+	}
+}
+
+func TestItemLen(t *testing.T){
+	var z, e = Object{0x82,0x01,0x61,'a',0x02,0x03}.ItemLen()
+	if nil != e || 4 != z {
+		t.Errorf("Expected '4', found '%d' (%v).",z,e)
+	}
+	_, e = Object{0x82,0x01}.ItemLen()
+	if !errors.Is(e,io.ErrUnexpectedEOF) {
+		t.Errorf("Expected '%v', found '%v'.",io.ErrUnexpectedEOF,e)
+	}
+
+	var value any = map[string]any{"list": []any{uint8(1),"two",3.5}}
+	z, e = EncodedLen(value)
+	if nil != e || len(Encode(value)) != z {
+		t.Errorf("Expected '%d', found '%d' (%v).",len(Encode(value)),z,e)
+	}
+}
+
+func TestWalkDepth(t *testing.T){
+	var depth int = 100000
+	var code []byte = make([]byte,depth+1)
+	for n := 0; n < depth; n++ {
+		code[n] = 0x81
+	}
+	code[depth] = 0xF6
+
+	var o Object = Object(code)
+	var z, e = o.ItemLen()
+	if nil != e {
+		t.Fatal(e)
+	} else if len(code) != z {
+		t.Errorf("Expected %d, found %d.",len(code),z)
+	}
+	var stats Stats
+	stats, e = Analyze(o)
+	if nil != e {
+		t.Fatal(e)
+	} else if depth != stats.Depth {
+		t.Errorf("Expected depth %d, found %d.",depth,stats.Depth)
+	}
+
+	var value any
+	e = DecOptions{MaxDepth: 64}.Unmarshal(code,&value)
+	if !errors.Is(e,ErrorDepthExceeded) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorDepthExceeded,e)
+	}
+	e = DecOptions{MaxDepth: 2}.Unmarshal([]byte{0x81,0x81,0x01},&value)
+	if nil != e {
+		t.Errorf("Expected depth 2, found '%v'.",e)
+	}
+
+	code[depth] = 0xFF
+	_, e = o.ItemLen()
+	if !errors.Is(e,ErrorUnexpectedBreak) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorUnexpectedBreak,e)
+	}
+}
@@ -0,0 +1,643 @@
+/*
+ * CBOR Code Generator
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-3
+ * https://pkg.go.dev/cmd/go#hdr-Generate_Go_files_by_processing_source
+ */
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+/*
+ */
+func usage(){
+	fmt.Fprint(os.Stderr,`
+Synopsis
+
+    cborgen [-type T[,T]] [-o <file.go>] <file.go>
+
+Description
+
+    Read the struct declarations of the Go source file, and
+    generate the methods "AppendCBOR", "MarshalCBOR" and
+    "UnmarshalCBOR" of each struct type (or of the named struct
+    types), for coding without reflection by package cbor.
+
+    Struct fields are coded as by package cbor, following their
+    "cbor" field tags: "-", name, and "omitempty".  Field types
+    are bool, string, []byte, integer and float types, the
+    struct types of the file, pointers to these struct types,
+    slices of these types, and types of the file having these
+    underlying types.
+
+    The generated file is named <file>_cbor.go by default, as
+    for
+
+        //go:generate cborgen -type Reading reading.go
+
+    Map keys decode by exact match of field names, and numbers
+    decode exactly into their field types, or fail with
+    cbor.ErrorNumericRange.
+
+`)
+	os.Exit(1)
+}
+var ErrorUnsupported error = errors.New("cborgen field type unsupported")
+/*
+ * Coding form of a field type.
+ */
+type form int
+const (
+	formBool form = iota
+	formString
+	formBytes
+	formInt
+	formUint
+	formFloat
+	formStruct
+	formPointer
+	formSlice
+)
+/*
+ * Basic types by coding form.
+ */
+var basic map[string]form = map[string]form{
+	"bool": formBool,
+	"string": formString,
+	"int": formInt, "int8": formInt, "int16": formInt, "int32": formInt, "int64": formInt,
+	"uint": formUint, "uint8": formUint, "uint16": formUint, "uint32": formUint, "uint64": formUint, "byte": formUint, "uintptr": formUint,
+	"float32": formFloat, "float64": formFloat,
+}
+/*
+ * Field type by coding form, basic type, and declared type.
+ * The element of a slice is its nested kind.
+ */
+type kind struct {
+
+	form form
+
+	basic string
+
+	named string
+
+	elem *kind
+}
+/*
+ * Struct field coding.
+ */
+type member struct {
+
+	field string
+
+	key string
+
+	omitempty bool
+
+	kind kind
+}
+/*
+ * Struct type coding.
+ */
+type codec struct {
+
+	name string
+
+	members []member
+}
+/*
+ * Declarations of the source file.
+ */
+type source struct {
+
+	pkg string
+
+	structs map[string]*ast.StructType
+
+	types map[string]ast.Expr
+
+	order []string
+}
+/*
+ */
+func main(){
+	var types, output string
+	flag.StringVar(&types,"type","","struct types to generate (comma separated), default all")
+	flag.StringVar(&output,"o","","generated file, default <file>_cbor.go")
+	flag.Usage = usage
+	flag.Parse()
+
+	if 1 != flag.NArg() {
+		usage()
+	} else {
+		var input string = flag.Arg(0)
+		if "" == output {
+			output = strings.TrimSuffix(input,".go")+"_cbor.go"
+		}
+		var code []byte
+		var e error
+		code, e = generate(input,types)
+		if nil == e {
+			e = os.WriteFile(output,code,0644)
+		}
+		if nil != e {
+			fmt.Fprintf(os.Stderr,"cborgen: %v\n",e)
+			os.Exit(1)
+		}
+	}
+}
+/*
+ * Generate the coding methods of the named struct types (or of
+ * all struct types) of the Go source file.
+ */
+func generate(input string, types string) ([]byte, error) {
+	var src source
+	var e error
+	src, e = read(input)
+	if nil != e {
+		return nil, e
+	}
+	var names []string = src.order
+	if "" != types {
+		names = strings.Split(types,",")
+	}
+	var list []codec
+	for _, name := range names {
+		var st, ok = src.structs[name]
+		if !ok {
+			return nil, fmt.Errorf("cborgen struct type '%s' not found in '%s'",name,input)
+		} else {
+			var c codec
+			c, e = src.codec(name,st)
+			if nil != e {
+				return nil, e
+			} else {
+				list = append(list,c)
+			}
+		}
+	}
+	return write(src.pkg,list), nil
+}
+/*
+ * Parse the type declarations of the Go source file.
+ */
+func read(input string) (src source, e error) {
+	var file *ast.File
+	file, e = parser.ParseFile(token.NewFileSet(),input,nil,parser.SkipObjectResolution)
+	if nil != e {
+		return src, e
+	}
+	src.pkg = file.Name.Name
+	src.structs = map[string]*ast.StructType{}
+	src.types = map[string]ast.Expr{}
+	for _, decl := range file.Decls {
+		var gen, ok = decl.(*ast.GenDecl)
+		if ok && token.TYPE == gen.Tok {
+			for _, spec := range gen.Specs {
+				var ts *ast.TypeSpec = spec.(*ast.TypeSpec)
+				if nil != ts.TypeParams {
+					continue
+				}
+				var st *ast.StructType
+				st, ok = ts.Type.(*ast.StructType)
+				if ok {
+					src.structs[ts.Name.Name] = st
+					src.order = append(src.order,ts.Name.Name)
+				} else {
+					src.types[ts.Name.Name] = ts.Type
+				}
+			}
+		}
+	}
+	return src, nil
+}
+/*
+ * Resolve the coding of the exported fields of struct type.
+ */
+func (this source) codec(name string, st *ast.StructType) (c codec, e error) {
+	c.name = name
+	for _, f := range st.Fields.List {
+		var key string
+		var omitempty bool
+		if nil != f.Tag {
+			var tag string
+			tag, e = strconv.Unquote(f.Tag.Value)
+			if nil != e {
+				return c, e
+			}
+			var options []string = strings.Split(reflect.StructTag(tag).Get("cbor"),",")
+			if "-" == options[0] && 1 == len(options) {
+				continue
+			}
+			key = options[0]
+			for _, option := range options[1:] {
+				if "omitempty" == option {
+					omitempty = true
+				}
+			}
+		}
+		for _, id := range f.Names {
+			if id.IsExported() {
+				var k kind
+				k, e = this.resolve(f.Type)
+				if nil != e {
+					return c, fmt.Errorf("%w: %s.%s",e,name,id.Name)
+				}
+				var m member = member{id.Name,key,omitempty,k}
+				if "" == m.key {
+					m.key = id.Name
+				}
+				c.members = append(c.members,m)
+			}
+		}
+	}
+	return c, nil
+}
+/*
+ * Resolve the coding of field type.
+ */
+func (this source) resolve(expr ast.Expr) (k kind, e error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		var f, ok = basic[t.Name]
+		if ok {
+			return kind{f,t.Name,t.Name,nil}, nil
+		} else if _, ok = this.structs[t.Name]; ok {
+			return kind{formStruct,"",t.Name,nil}, nil
+		} else if underlying, ok := this.types[t.Name]; ok {
+			k, e = this.resolve(underlying)
+			if nil == e && formStruct != k.form && formPointer != k.form {
+				k.named = t.Name
+				return k, nil
+			}
+		}
+	case *ast.StarExpr:
+		var id, ok = t.X.(*ast.Ident)
+		if ok {
+			if _, ok = this.structs[id.Name]; ok {
+				return kind{formPointer,"","*"+id.Name,nil}, nil
+			}
+		}
+	case *ast.ArrayType:
+		if nil == t.Len {
+			var id, ok = t.Elt.(*ast.Ident)
+			if ok && ("byte" == id.Name || "uint8" == id.Name) {
+				return kind{formBytes,"[]byte","[]"+id.Name,nil}, nil
+			} else {
+				var elem kind
+				elem, e = this.resolve(t.Elt)
+				if nil == e {
+					return kind{formSlice,"","[]"+elem.named,&elem}, nil
+				}
+			}
+		}
+	}
+	return k, ErrorUnsupported
+}
+/*
+ * Conversion of expression to the basic type of kind.
+ */
+func (this kind) basicOf(x string) (string) {
+	if this.basic == this.named {
+		return x
+	} else {
+		return this.basic+"("+x+")"
+	}
+}
+/*
+ * Conversion of expression of the basic type to the declared
+ * type of kind.
+ */
+func (this kind) namedOf(x string) (string) {
+	if this.basic == this.named {
+		return x
+	} else {
+		return this.named+"("+x+")"
+	}
+}
+/*
+ * Determine whether the kind encodes fallibly.
+ */
+func (this kind) fallible() (bool) {
+	switch this.form {
+	case formStruct, formPointer:
+		return true
+	case formSlice:
+		return this.elem.fallible()
+	default:
+		return false
+	}
+}
+/*
+ * Determine whether decoding the kind checks the range of
+ * float32, as by package math.
+ */
+func (this kind) ranged() (bool) {
+	switch this.form {
+	case formFloat:
+		return ("float32" == this.basic)
+	case formSlice:
+		return this.elem.ranged()
+	default:
+		return false
+	}
+}
+/*
+ * Condition of the non empty value of expression, as by the
+ * "omitempty" of package cbor, or empty for struct values,
+ * which are never empty.
+ */
+func (this kind) nonempty(x string) (string) {
+	switch this.form {
+	case formBool:
+		return x
+	case formString:
+		return "\"\" != "+x
+	case formBytes, formSlice:
+		return "0 != len("+x+")"
+	case formInt, formUint, formFloat:
+		return "0 != "+x
+	case formPointer:
+		return "nil != "+x
+	default:
+		return ""
+	}
+}
+/*
+ * Generated source, by line, with indentation.
+ */
+type writer struct {
+
+	bytes.Buffer
+}
+/*
+ */
+func (this *writer) line(indent int, format string, args ...any) {
+	this.WriteString(strings.Repeat("\t",indent))
+	fmt.Fprintf(this,format,args...)
+	this.WriteByte('\n')
+}
+/*
+ * Produce the generated source file.
+ */
+func write(pkg string, list []codec) ([]byte) {
+	var w writer
+	w.line(0,"// Code generated by cborgen; DO NOT EDIT.")
+	w.line(0,"")
+	w.line(0,"package %s",pkg)
+	w.line(0,"")
+	var ranged bool
+	for _, c := range list {
+		for _, m := range c.members {
+			ranged = ranged || m.kind.ranged()
+		}
+	}
+	w.line(0,"import (")
+	if ranged {
+		w.line(1,"%q","math")
+	}
+	w.line(1,"%q","github.com/syntelos/go-cbor")
+	w.line(0,")")
+	for _, c := range list {
+		c.writeAppend(&w)
+		c.writeMarshal(&w)
+		c.writeUnmarshal(&w)
+	}
+	return w.Bytes()
+}
+/*
+ */
+func (this codec) writeAppend(w *writer) {
+	var fallible bool
+	for _, m := range this.members {
+		fallible = fallible || m.kind.fallible()
+	}
+	w.line(0,"/*")
+	w.line(0," * Append the encoding of %s, without reflection.",this.name)
+	w.line(0," */")
+	w.line(0,"func (this *%s) AppendCBOR(dst []byte) ([]byte, error) {",this.name)
+	if fallible {
+		w.line(1,"var e error")
+	}
+	w.line(1,"var count uint64")
+	for _, m := range this.members {
+		var condition string
+		if m.omitempty {
+			condition = m.kind.nonempty("this."+m.field)
+		}
+		if "" == condition {
+			w.line(1,"count += 1")
+		} else {
+			w.line(1,"if %s {",condition)
+			w.line(2,"count += 1")
+			w.line(1,"}")
+		}
+	}
+	w.line(1,"dst = cbor.AppendHead(dst,cbor.MajorMap,count)")
+	for _, m := range this.members {
+		var condition string
+		if m.omitempty {
+			condition = m.kind.nonempty("this."+m.field)
+		}
+		var indent int = 1
+		if "" != condition {
+			w.line(1,"if %s {",condition)
+			indent = 2
+		}
+		w.line(indent,"dst = cbor.EncodeString(dst,%q)",m.key)
+		var k kind = m.kind
+		if "" != condition && formPointer == k.form {
+			/*
+			 * Pointer not nil, by the condition.
+			 */
+			k.form = formStruct
+		}
+		k.writeEncode(w,indent,"this."+m.field,0)
+		if "" != condition {
+			w.line(1,"}")
+		}
+	}
+	w.line(1,"return dst, nil")
+	w.line(0,"}")
+}
+/*
+ * Encode the value of expression.
+ */
+func (this kind) writeEncode(w *writer, indent int, x string, level int) {
+	switch this.form {
+	case formBool:
+		w.line(indent,"dst = cbor.EncodeBool(dst,%s)",this.basicOf(x))
+	case formString:
+		w.line(indent,"dst = cbor.EncodeString(dst,%s)",this.basicOf(x))
+	case formBytes:
+		w.line(indent,"dst = cbor.EncodeBytes(dst,%s)",this.basicOf(x))
+	case formInt:
+		w.line(indent,"dst = cbor.EncodeInt64(dst,int64(%s))",x)
+	case formUint:
+		w.line(indent,"dst = cbor.EncodeUint64(dst,uint64(%s))",x)
+	case formFloat:
+		w.line(indent,"dst = cbor.EncodeFloat64(dst,float64(%s))",x)
+	case formStruct:
+		w.line(indent,"dst, e = %s.AppendCBOR(dst)",x)
+		w.line(indent,"if nil != e {")
+		w.line(indent+1,"return dst, e")
+		w.line(indent,"}")
+	case formPointer:
+		w.line(indent,"if nil == %s {",x)
+		w.line(indent+1,"dst = append(dst,byte(cbor.HeadNull))")
+		w.line(indent,"} else {")
+		w.line(indent+1,"dst, e = %s.AppendCBOR(dst)",x)
+		w.line(indent+1,"if nil != e {")
+		w.line(indent+2,"return dst, e")
+		w.line(indent+1,"}")
+		w.line(indent,"}")
+	case formSlice:
+		var n string = fmt.Sprintf("n%d",level)
+		w.line(indent,"dst = cbor.AppendHead(dst,cbor.MajorArray,uint64(len(%s)))",x)
+		w.line(indent,"for %s := range %s {",n,x)
+		this.elem.writeEncode(w,indent+1,x+"["+n+"]",level+1)
+		w.line(indent,"}")
+	}
+}
+/*
+ */
+func (this codec) writeMarshal(w *writer) {
+	w.line(0,"/*")
+	w.line(0," * Encode %s, without reflection.",this.name)
+	w.line(0," */")
+	w.line(0,"func (this *%s) MarshalCBOR() ([]byte, error) {",this.name)
+	w.line(1,"return this.AppendCBOR(nil)")
+	w.line(0,"}")
+}
+/*
+ */
+func (this codec) writeUnmarshal(w *writer) {
+	w.line(0,"/*")
+	w.line(0," * Decode %s from the entries of a map, without reflection.",this.name)
+	w.line(0," */")
+	w.line(0,"func (this *%s) UnmarshalCBOR(code []byte) (error) {",this.name)
+	w.line(1,"var entries, e = cbor.Object(code).Entries()")
+	w.line(1,"if nil != e {")
+	w.line(2,"return e")
+	w.line(1,"}")
+	w.line(1,"for _, entry := range entries {")
+	w.line(2,"var name string")
+	w.line(2,"name, e = entry[0].Text()")
+	w.line(2,"if nil != e {")
+	w.line(3,"continue")
+	w.line(2,"}")
+	w.line(2,"switch name {")
+	for _, m := range this.members {
+		w.line(2,"case %q:",m.key)
+		m.kind.writeDecode(w,3,"this."+m.field,"entry[1]",0)
+	}
+	w.line(2,"}")
+	w.line(1,"}")
+	w.line(1,"return nil")
+	w.line(0,"}")
+}
+/*
+ * Decode the value of source expression into target
+ * expression.
+ */
+func (this kind) writeDecode(w *writer, indent int, x string, src string, level int) {
+	var value string = "value"
+	if 0 < level {
+		value = fmt.Sprintf("value%d",level)
+	}
+	switch this.form {
+	case formBool:
+		this.writeValue(w,indent,x,value,"bool",src+".Bool()","")
+	case formString:
+		this.writeValue(w,indent,x,value,"string",src+".Text()","")
+	case formInt:
+		var check string
+		if "int64" != this.basic {
+			check = fmt.Sprintf("int64(%s(%s)) != %s",this.basic,value,value)
+		}
+		this.writeValue(w,indent,x,value,"int64",src+".Int()",check)
+	case formUint:
+		var check string
+		if "uint64" != this.basic {
+			check = fmt.Sprintf("uint64(%s(%s)) != %s",this.basic,value,value)
+		}
+		this.writeValue(w,indent,x,value,"uint64",src+".Uint()",check)
+	case formFloat:
+		var check string
+		if "float32" == this.basic {
+			check = fmt.Sprintf("math.MaxFloat32 < math.Abs(%s) && !math.IsInf(%s,0)",value,value)
+		}
+		this.writeValue(w,indent,x,value,"float64",src+".Float()",check)
+	case formBytes:
+		w.line(indent,"if cbor.HeadNull == %s.Tag() {",src)
+		w.line(indent+1,"%s = nil",x)
+		w.line(indent,"} else {")
+		this.writeValue(w,indent+1,x,value,"[]byte",src+".Bytes()","")
+		w.line(indent,"}")
+	case formStruct:
+		w.line(indent,"e = %s.UnmarshalCBOR(%s)",x,src)
+		w.line(indent,"if nil != e {")
+		w.line(indent+1,"return e")
+		w.line(indent,"}")
+	case formPointer:
+		w.line(indent,"if cbor.HeadNull == %s.Tag() {",src)
+		w.line(indent+1,"%s = nil",x)
+		w.line(indent,"} else {")
+		w.line(indent+1,"%s = new(%s)",x,strings.TrimPrefix(this.named,"*"))
+		w.line(indent+1,"e = %s.UnmarshalCBOR(%s)",x,src)
+		w.line(indent+1,"if nil != e {")
+		w.line(indent+2,"return e")
+		w.line(indent+1,"}")
+		w.line(indent,"}")
+	case formSlice:
+		var items, n string = fmt.Sprintf("items%d",level), fmt.Sprintf("n%d",level)
+		w.line(indent,"if cbor.HeadNull == %s.Tag() {",src)
+		w.line(indent+1,"%s = nil",x)
+		w.line(indent,"} else {")
+		w.line(indent+1,"var %s []cbor.Object",items)
+		w.line(indent+1,"%s, e = %s.Items()",items,src)
+		w.line(indent+1,"if nil != e {")
+		w.line(indent+2,"return e")
+		w.line(indent+1,"}")
+		w.line(indent+1,"%s = make(%s,len(%s))",x,this.named,items)
+		w.line(indent+1,"for %s := range %s {",n,items)
+		this.elem.writeDecode(w,indent+2,x+"["+n+"]",items+"["+n+"]",level+1)
+		w.line(indent+1,"}")
+		w.line(indent,"}")
+	}
+}
+/*
+ * Decode a value of type by accessor into target expression,
+ * failing for the (range) check.
+ */
+func (this kind) writeValue(w *writer, indent int, x string, value string, typ string, accessor string, check string) {
+	w.line(indent,"var %s %s",value,typ)
+	w.line(indent,"%s, e = %s",value,accessor)
+	w.line(indent,"if nil != e {")
+	w.line(indent+1,"return e")
+	if "" != check {
+		w.line(indent,"} else if %s {",check)
+		w.line(indent+1,"return cbor.ErrorNumericRange")
+	}
+	w.line(indent,"} else {")
+	if typ == this.basic {
+		w.line(indent+1,"%s = %s",x,this.namedOf(value))
+	} else {
+		w.line(indent+1,"%s = %s",x,this.named+"("+value+")")
+	}
+	w.line(indent,"}")
+}
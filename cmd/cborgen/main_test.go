@@ -0,0 +1,158 @@
+/*
+ * CBOR Code Generator Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package main
+
+import (
+	"bytes"
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerate(t *testing.T){
+	var code, e = generate("testdata/reading.go","")
+	if nil != e {
+		t.Fatal(e)
+	}
+	var expected []byte
+	expected, e = os.ReadFile("testdata/reading_cbor.go")
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(expected,code) {
+		t.Errorf("Expected 'testdata/reading_cbor.go', found\n%s",code)
+	}
+
+	var fset *token.FileSet = token.NewFileSet()
+	var files []*ast.File
+	for _, file := range []string{"testdata/reading.go","testdata/reading_cbor.go"} {
+		var f *ast.File
+		f, e = parser.ParseFile(fset,file,nil,0)
+		if nil != e {
+			t.Fatal(e)
+		}
+		files = append(files,f)
+	}
+	var config types.Config = types.Config{Importer: importer.ForCompiler(fset,"source",nil)}
+	_, e = config.Check("reading",fset,files,nil)
+	if nil != e {
+		t.Error(e)
+	}
+}
+
+func TestGenerateUnsupported(t *testing.T){
+	var _, e = generate("testdata/reading.go","Unknown")
+	if nil == e {
+		t.Error("Expected error, found success.")
+	}
+	var file string = t.TempDir()+"/table.go"
+	e = os.WriteFile(file,[]byte("package table\n\ntype Table struct {\n\n\tEntries map[string]int\n}\n"),0644)
+	if nil != e {
+		t.Fatal(e)
+	}
+	_, e = generate(file,"")
+	if !errors.Is(e,ErrorUnsupported) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorUnsupported,e)
+	}
+}
+/*
+ * Round trip of the generated methods, in a package of the
+ * module, agreeing with the coding of package cbor.
+ */
+const roundTrip string = `package reading
+
+import (
+	"reflect"
+	"testing"
+	"github.com/syntelos/go-cbor"
+)
+
+func TestRoundTrip(t *testing.T){
+	var batch Batch = Batch{
+		Readings: []Reading{
+			{Sensor: "a", Time: 1, Value: 21.5, Offset: -2, Valid: true, Raw: []byte{1,2}, Location: &Location{1.5,-2.5}, Tags: []string{"x"}},
+			{Sensor: "b", Time: 2, Value: 1.1, Tags: []string{}},
+		},
+		Origin: Location{3,4},
+		Path: []*Location{{5,6},nil},
+	}
+	var code, e = batch.MarshalCBOR()
+	if nil != e {
+		t.Fatal(e)
+	}
+	var generated, reflected Batch
+	e = generated.UnmarshalCBOR(code)
+	if nil != e {
+		t.Fatal(e)
+	} else if !reflect.DeepEqual(batch,generated) {
+		t.Errorf("Expected '%v', found '%v'.",batch,generated)
+	}
+	e = cbor.Unmarshal(code,&reflected)
+	if nil != e {
+		t.Fatal(e)
+	} else if !reflect.DeepEqual(batch,reflected) {
+		t.Errorf("Expected '%v', found '%v'.",batch,reflected)
+	}
+
+	code = cbor.Encode(map[string]any{"v": 1.1})
+	var r, s Reading
+	e = r.UnmarshalCBOR(code)
+	if nil != e {
+		t.Fatal(e)
+	}
+	e = cbor.Unmarshal(code,&s)
+	if nil != e {
+		t.Fatal(e)
+	} else if r.Value != s.Value {
+		t.Errorf("Expected '%v', found '%v'.",s.Value,r.Value)
+	}
+	code = cbor.Encode(map[string]any{"v": 1e300})
+	if nil == r.UnmarshalCBOR(code) || nil == cbor.Unmarshal(code,&s) {
+		t.Error("Expected float32 range error, found success.")
+	}
+}
+`
+
+func TestGenerateRoundTrip(t *testing.T){
+	if testing.Short() {
+		t.Skip("generated package build in short mode")
+	}
+	var tool, e = exec.LookPath("go")
+	if nil != e {
+		t.Skip(e)
+	}
+	var dir string
+	dir, e = os.MkdirTemp(".","_roundtrip")
+	if nil != e {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	for _, file := range []string{"reading.go","reading_cbor.go"} {
+		var code []byte
+		code, e = os.ReadFile(filepath.Join("testdata",file))
+		if nil != e {
+			t.Fatal(e)
+		}
+		e = os.WriteFile(filepath.Join(dir,file),code,0644)
+		if nil != e {
+			t.Fatal(e)
+		}
+	}
+	e = os.WriteFile(filepath.Join(dir,"reading_test.go"),[]byte(roundTrip),0644)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var output []byte
+	output, e = exec.Command(tool,"test","-count=1","./"+filepath.Base(dir)).CombinedOutput()
+	if nil != e {
+		t.Errorf("Expected generated round trip, found '%v'.\n%s",e,output)
+	}
+}
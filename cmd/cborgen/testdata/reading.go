@@ -0,0 +1,44 @@
+/*
+ * CBOR Code Generator Test Declarations
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package reading
+
+type Celsius float32
+
+type Reading struct {
+
+	Sensor string `cbor:"sensor"`
+
+	Time uint32 `cbor:"t"`
+
+	Value Celsius `cbor:"v"`
+
+	Offset int16 `cbor:"offset,omitempty"`
+
+	Valid bool `cbor:"valid"`
+
+	Raw []byte `cbor:"raw,omitempty"`
+
+	Location *Location `cbor:"loc,omitempty"`
+
+	Tags []string `cbor:"tags"`
+
+	Hidden string `cbor:"-"`
+
+	internal int
+}
+
+type Location struct {
+
+	Lat, Lon float64
+}
+
+type Batch struct {
+
+	Readings []Reading `cbor:"readings"`
+
+	Origin Location `cbor:"origin"`
+
+	Path []*Location `cbor:"path,omitempty"`
+}
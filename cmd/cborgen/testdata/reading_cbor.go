@@ -0,0 +1,337 @@
+// Code generated by cborgen; DO NOT EDIT.
+
+package reading
+
+import (
+	"math"
+	"github.com/syntelos/go-cbor"
+)
+/*
+ * Append the encoding of Reading, without reflection.
+ */
+func (this *Reading) AppendCBOR(dst []byte) ([]byte, error) {
+	var e error
+	var count uint64
+	count += 1
+	count += 1
+	count += 1
+	if 0 != this.Offset {
+		count += 1
+	}
+	count += 1
+	if 0 != len(this.Raw) {
+		count += 1
+	}
+	if nil != this.Location {
+		count += 1
+	}
+	count += 1
+	dst = cbor.AppendHead(dst,cbor.MajorMap,count)
+	dst = cbor.EncodeString(dst,"sensor")
+	dst = cbor.EncodeString(dst,this.Sensor)
+	dst = cbor.EncodeString(dst,"t")
+	dst = cbor.EncodeUint64(dst,uint64(this.Time))
+	dst = cbor.EncodeString(dst,"v")
+	dst = cbor.EncodeFloat64(dst,float64(this.Value))
+	if 0 != this.Offset {
+		dst = cbor.EncodeString(dst,"offset")
+		dst = cbor.EncodeInt64(dst,int64(this.Offset))
+	}
+	dst = cbor.EncodeString(dst,"valid")
+	dst = cbor.EncodeBool(dst,this.Valid)
+	if 0 != len(this.Raw) {
+		dst = cbor.EncodeString(dst,"raw")
+		dst = cbor.EncodeBytes(dst,this.Raw)
+	}
+	if nil != this.Location {
+		dst = cbor.EncodeString(dst,"loc")
+		dst, e = this.Location.AppendCBOR(dst)
+		if nil != e {
+			return dst, e
+		}
+	}
+	dst = cbor.EncodeString(dst,"tags")
+	dst = cbor.AppendHead(dst,cbor.MajorArray,uint64(len(this.Tags)))
+	for n0 := range this.Tags {
+		dst = cbor.EncodeString(dst,this.Tags[n0])
+	}
+	return dst, nil
+}
+/*
+ * Encode Reading, without reflection.
+ */
+func (this *Reading) MarshalCBOR() ([]byte, error) {
+	return this.AppendCBOR(nil)
+}
+/*
+ * Decode Reading from the entries of a map, without reflection.
+ */
+func (this *Reading) UnmarshalCBOR(code []byte) (error) {
+	var entries, e = cbor.Object(code).Entries()
+	if nil != e {
+		return e
+	}
+	for _, entry := range entries {
+		var name string
+		name, e = entry[0].Text()
+		if nil != e {
+			continue
+		}
+		switch name {
+		case "sensor":
+			var value string
+			value, e = entry[1].Text()
+			if nil != e {
+				return e
+			} else {
+				this.Sensor = value
+			}
+		case "t":
+			var value uint64
+			value, e = entry[1].Uint()
+			if nil != e {
+				return e
+			} else if uint64(uint32(value)) != value {
+				return cbor.ErrorNumericRange
+			} else {
+				this.Time = uint32(value)
+			}
+		case "v":
+			var value float64
+			value, e = entry[1].Float()
+			if nil != e {
+				return e
+			} else if math.MaxFloat32 < math.Abs(value) && !math.IsInf(value,0) {
+				return cbor.ErrorNumericRange
+			} else {
+				this.Value = Celsius(value)
+			}
+		case "offset":
+			var value int64
+			value, e = entry[1].Int()
+			if nil != e {
+				return e
+			} else if int64(int16(value)) != value {
+				return cbor.ErrorNumericRange
+			} else {
+				this.Offset = int16(value)
+			}
+		case "valid":
+			var value bool
+			value, e = entry[1].Bool()
+			if nil != e {
+				return e
+			} else {
+				this.Valid = value
+			}
+		case "raw":
+			if cbor.HeadNull == entry[1].Tag() {
+				this.Raw = nil
+			} else {
+				var value []byte
+				value, e = entry[1].Bytes()
+				if nil != e {
+					return e
+				} else {
+					this.Raw = value
+				}
+			}
+		case "loc":
+			if cbor.HeadNull == entry[1].Tag() {
+				this.Location = nil
+			} else {
+				this.Location = new(Location)
+				e = this.Location.UnmarshalCBOR(entry[1])
+				if nil != e {
+					return e
+				}
+			}
+		case "tags":
+			if cbor.HeadNull == entry[1].Tag() {
+				this.Tags = nil
+			} else {
+				var items0 []cbor.Object
+				items0, e = entry[1].Items()
+				if nil != e {
+					return e
+				}
+				this.Tags = make([]string,len(items0))
+				for n0 := range items0 {
+					var value1 string
+					value1, e = items0[n0].Text()
+					if nil != e {
+						return e
+					} else {
+						this.Tags[n0] = value1
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+/*
+ * Append the encoding of Location, without reflection.
+ */
+func (this *Location) AppendCBOR(dst []byte) ([]byte, error) {
+	var count uint64
+	count += 1
+	count += 1
+	dst = cbor.AppendHead(dst,cbor.MajorMap,count)
+	dst = cbor.EncodeString(dst,"Lat")
+	dst = cbor.EncodeFloat64(dst,float64(this.Lat))
+	dst = cbor.EncodeString(dst,"Lon")
+	dst = cbor.EncodeFloat64(dst,float64(this.Lon))
+	return dst, nil
+}
+/*
+ * Encode Location, without reflection.
+ */
+func (this *Location) MarshalCBOR() ([]byte, error) {
+	return this.AppendCBOR(nil)
+}
+/*
+ * Decode Location from the entries of a map, without reflection.
+ */
+func (this *Location) UnmarshalCBOR(code []byte) (error) {
+	var entries, e = cbor.Object(code).Entries()
+	if nil != e {
+		return e
+	}
+	for _, entry := range entries {
+		var name string
+		name, e = entry[0].Text()
+		if nil != e {
+			continue
+		}
+		switch name {
+		case "Lat":
+			var value float64
+			value, e = entry[1].Float()
+			if nil != e {
+				return e
+			} else {
+				this.Lat = value
+			}
+		case "Lon":
+			var value float64
+			value, e = entry[1].Float()
+			if nil != e {
+				return e
+			} else {
+				this.Lon = value
+			}
+		}
+	}
+	return nil
+}
+/*
+ * Append the encoding of Batch, without reflection.
+ */
+func (this *Batch) AppendCBOR(dst []byte) ([]byte, error) {
+	var e error
+	var count uint64
+	count += 1
+	count += 1
+	if 0 != len(this.Path) {
+		count += 1
+	}
+	dst = cbor.AppendHead(dst,cbor.MajorMap,count)
+	dst = cbor.EncodeString(dst,"readings")
+	dst = cbor.AppendHead(dst,cbor.MajorArray,uint64(len(this.Readings)))
+	for n0 := range this.Readings {
+		dst, e = this.Readings[n0].AppendCBOR(dst)
+		if nil != e {
+			return dst, e
+		}
+	}
+	dst = cbor.EncodeString(dst,"origin")
+	dst, e = this.Origin.AppendCBOR(dst)
+	if nil != e {
+		return dst, e
+	}
+	if 0 != len(this.Path) {
+		dst = cbor.EncodeString(dst,"path")
+		dst = cbor.AppendHead(dst,cbor.MajorArray,uint64(len(this.Path)))
+		for n0 := range this.Path {
+			if nil == this.Path[n0] {
+				dst = append(dst,byte(cbor.HeadNull))
+			} else {
+				dst, e = this.Path[n0].AppendCBOR(dst)
+				if nil != e {
+					return dst, e
+				}
+			}
+		}
+	}
+	return dst, nil
+}
+/*
+ * Encode Batch, without reflection.
+ */
+func (this *Batch) MarshalCBOR() ([]byte, error) {
+	return this.AppendCBOR(nil)
+}
+/*
+ * Decode Batch from the entries of a map, without reflection.
+ */
+func (this *Batch) UnmarshalCBOR(code []byte) (error) {
+	var entries, e = cbor.Object(code).Entries()
+	if nil != e {
+		return e
+	}
+	for _, entry := range entries {
+		var name string
+		name, e = entry[0].Text()
+		if nil != e {
+			continue
+		}
+		switch name {
+		case "readings":
+			if cbor.HeadNull == entry[1].Tag() {
+				this.Readings = nil
+			} else {
+				var items0 []cbor.Object
+				items0, e = entry[1].Items()
+				if nil != e {
+					return e
+				}
+				this.Readings = make([]Reading,len(items0))
+				for n0 := range items0 {
+					e = this.Readings[n0].UnmarshalCBOR(items0[n0])
+					if nil != e {
+						return e
+					}
+				}
+			}
+		case "origin":
+			e = this.Origin.UnmarshalCBOR(entry[1])
+			if nil != e {
+				return e
+			}
+		case "path":
+			if cbor.HeadNull == entry[1].Tag() {
+				this.Path = nil
+			} else {
+				var items0 []cbor.Object
+				items0, e = entry[1].Items()
+				if nil != e {
+					return e
+				}
+				this.Path = make([]*Location,len(items0))
+				for n0 := range items0 {
+					if cbor.HeadNull == items0[n0].Tag() {
+						this.Path[n0] = nil
+					} else {
+						this.Path[n0] = new(Location)
+						e = this.Path[n0].UnmarshalCBOR(items0[n0])
+						if nil != e {
+							return e
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+ * CBOR CoAP
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc7252#section-12.3
+ * https://tools.ietf.org/html/rfc7959#section-2.2
+ * https://tools.ietf.org/html/rfc8949#section-9.2
+ * https://docs.oasis-open.org/mqtt/mqtt/v5.0/os/mqtt-v5.0-os.html#_Toc3901118
+ */
+package coapcbor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/syntelos/go-cbor"
+)
+/*
+ * CoAP Content-Format of "application/cbor".
+ */
+const ContentFormat uint16 = 60
+/*
+ * MQTT 5 "Content Type" property of CBOR payloads.
+ */
+const ContentType string = "application/cbor"
+/*
+ * Upper bound on payload octet count accepted by <Assembler>.
+ */
+var PayloadSizeMax int = (1024*1024)
+/*
+ * Validation errors produced by block-wise transfer.
+ */
+var ErrorContentFormat error = errors.New("CoAP Content-Format is not CBOR")
+var ErrorBlockSize error = errors.New("CoAP block size is not 16 to 1024 by powers of two")
+var ErrorBlockSequence error = errors.New("CoAP block out of sequence")
+var ErrorPayloadSize error = fmt.Errorf("CoAP CBOR payload exceeds size maximum: %w",cbor.ErrorSizeExceeded)
+var ErrorPayloadContent error = errors.New("CoAP CBOR payload is not one data item")
+/*
+ * Block1 or Block2 option value of block-wise transfer.
+ */
+type Block struct {
+	/*
+	 * Block number.
+	 */
+	Num uint32
+	/*
+	 * More blocks follow.
+	 */
+	More bool
+	/*
+	 * Size exponent, for block size (1 << (SZX+4)).
+	 */
+	SZX uint8
+}
+/*
+ * Block octet count.
+ */
+func (this Block) Size() (int) {
+	return (1 << (this.SZX+4))
+}
+/*
+ * Define the option value of block.
+ */
+func (this Block) Encode() (uint32) {
+	var value uint32 = (this.Num << 4) | uint32(this.SZX & 0x7)
+	if this.More {
+		value |= 0x8
+	}
+	return value
+}
+/*
+ * Resolve the block of option value.
+ */
+func DecodeBlock(value uint32) (Block, error) {
+	var block Block = Block{value >> 4, (0 != (value & 0x8)), uint8(value & 0x7)}
+	if 7 == block.SZX {
+		return block, ErrorBlockSize
+	} else {
+		return block, nil
+	}
+}
+/*
+ * Resolve the size exponent of block octet count.
+ */
+func BlockSZX(size int) (uint8, error) {
+	var szx uint8
+	for szx = 0; szx < 7; szx++ {
+		if size == (1 << (szx+4)) {
+			return szx, nil
+		}
+	}
+	return 0, ErrorBlockSize
+}
+/*
+ * Validate CoAP Content-Format option value as CBOR.
+ */
+func Check(format uint16) (error) {
+	if ContentFormat == format {
+		return nil
+	} else {
+		return ErrorContentFormat
+	}
+}
+/*
+ * Divide the encoding of object into blocks of octet count
+ * size, calling function with each block option and block
+ * payload in sequence.
+ */
+func Split(o cbor.Object, size int, fn func(Block, []byte) (error)) (error) {
+	var szx, e = BlockSZX(size)
+	if nil != e {
+		return e
+	}
+	var num uint32
+	var x, z int = 0, len(o)
+	for {
+		var end int = x+size
+		if end > z {
+			end = z
+		}
+		e = fn(Block{num, (end < z), szx},o[x:end])
+		if nil != e {
+			return e
+		} else if end == z {
+			return nil
+		}
+		x = end
+		num += 1
+	}
+}
+/*
+ * Reassembly of a CBOR payload from the blocks of a block-wise
+ * transfer.
+ */
+type Assembler struct {
+
+	buffer bytes.Buffer
+
+	next uint32
+}
+/*
+ * Accept the next block in sequence.  The final block produces
+ * the data item of the payload, which must be exactly one data
+ * item.
+ */
+func (this *Assembler) Add(block Block, payload []byte) (o cbor.Object, done bool, e error) {
+	if block.Num != this.next {
+		return nil, false, ErrorBlockSequence
+
+	} else if block.More && len(payload) != block.Size() {
+		return nil, false, ErrorBlockSize
+
+	} else if this.buffer.Len()+len(payload) > PayloadSizeMax {
+		return nil, false, ErrorPayloadSize
+	} else {
+		this.buffer.Write(payload)
+		this.next += 1
+		if block.More {
+			return nil, false, nil
+		} else {
+			var r *bytes.Reader = bytes.NewReader(this.buffer.Bytes())
+			o, e = cbor.NewDecoder(r).Read()
+			this.buffer.Reset()
+			this.next = 0
+			if nil != e {
+				return nil, true, e
+			} else if 0 != r.Len() {
+				return nil, true, ErrorPayloadContent
+			} else {
+				return o, true, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * CBOR CoAP Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package coapcbor
+
+import (
+	"bytes"
+	"github.com/syntelos/go-cbor"
+	"strings"
+	"testing"
+)
+
+func TestBlock(t *testing.T){
+	var block Block = Block{5,true,2}
+	if 0x5A != block.Encode() {
+		t.Errorf("Expected '5A', found '%X'.",block.Encode())
+	}
+	var check, e = DecodeBlock(0x5A)
+	if nil != e {
+		t.Fatal(e)
+	} else if block != check || 64 != check.Size() {
+		t.Errorf("Expected '%v', found '%v'.",block,check)
+	}
+	_, e = BlockSZX(100)
+	if ErrorBlockSize != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorBlockSize,e)
+	}
+}
+
+func TestSplit(t *testing.T){
+	var o cbor.Object = cbor.Encode(strings.Repeat("telemetry ",20))
+	var assembler Assembler
+	var result cbor.Object
+	var count int
+
+	var e error = Split(o,16,func(block Block, payload []byte) (error) {
+		count += 1
+		var r cbor.Object
+		var done bool
+		var e error
+		r, done, e = assembler.Add(block,payload)
+		if done {
+			result = r
+		}
+		return e
+	})
+	if nil != e {
+		t.Fatal(e)
+	} else if 13 != count {
+		t.Errorf("Expected 13 blocks, found %d.",count)
+	} else if !bytes.Equal(o,result) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(o),[]byte(result))
+	}
+
+	_, _, e = assembler.Add(Block{1,false,0},[]byte{0x01})
+	if ErrorBlockSequence != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorBlockSequence,e)
+	}
+	_, _, e = assembler.Add(Block{0,false,0},[]byte{0x01,0x02})
+	if ErrorPayloadContent != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorPayloadContent,e)
+	}
+}
@@ -0,0 +1,726 @@
+/*
+ * CoRIM CBOR Representation
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://datatracker.ietf.org/doc/draft-ietf-rats-corim/
+ * https://tools.ietf.org/html/rfc9393
+ * https://tools.ietf.org/html/rfc9052#section-4.2
+ */
+package corim
+
+import (
+	"errors"
+	"fmt"
+	"github.com/syntelos/go-cbor"
+	"github.com/syntelos/go-cbor/coswid"
+	"reflect"
+	"time"
+)
+/*
+ * Media type of unsigned CoRIM content.
+ */
+const ContentType string = "application/rim+cbor"
+/*
+ * Tag numbers of the unsigned CoRIM map, of the signed CoRIM
+ * "COSE_Sign1", and of the concise tags of a CoRIM.
+ */
+const TagCoRIM uint64 = 501
+const TagCOSESign1 uint64 = 18
+const TagCoSWID uint64 = 505
+const TagCoMID uint64 = 506
+const TagCoTL uint64 = 508
+/*
+ * Entity role values of the CoRIM map, and of the CoMID tag.
+ */
+const RoleManifestCreator int64 = 1
+const RoleTagCreator int64 = 0
+const RoleCreator int64 = 1
+const RoleMaintainer int64 = 2
+/*
+ * Linked tag relation values.
+ */
+const RelSupplements int64 = 0
+const RelReplaces int64 = 1
+/*
+ * Validation errors produced by decoding.
+ */
+var ErrorCoRIM error = errors.New("CoRIM structure malformed")
+var ErrorMissing error = errors.New("CoRIM required member missing")
+var ErrorSigned error = errors.New("CoRIM signed envelope unrecognized or payload detached")
+/*
+ * Digest "[algorithm, value]", by the algorithm identifiers of
+ * the IANA "Named Information Hash Algorithm Registry".
+ */
+type Digest struct {
+
+	Algorithm int64
+
+	Value []byte
+}
+/*
+ * Entity of a CoRIM or CoMID, with its roles.
+ */
+type Entity struct {
+
+	Name string
+
+	RegID string
+
+	Roles []int64
+}
+/*
+ * Locator of a dependent CoRIM.
+ */
+type Locator struct {
+
+	Href string
+
+	Thumbprint *Digest
+}
+/*
+ * Validity period of a CoRIM.
+ */
+type Validity struct {
+
+	NotBefore *time.Time
+
+	NotAfter time.Time
+}
+/*
+ * Concise tag of a CoRIM, as a CoSWID, a CoMID, or the encoded
+ * data item of another tag, as a CoTL.
+ */
+type ConciseTag struct {
+
+	CoSWID *coswid.Tag
+
+	CoMID *CoMID
+
+	Raw cbor.RawMessage
+}
+/*
+ * Unsigned CoRIM.  The identifier is a text string or a 16
+ * octet UUID byte string.  The profile is the encoded URI or
+ * OID data item.
+ */
+type CoRIM struct {
+
+	ID any
+
+	Tags []ConciseTag
+
+	DependentRIMs []Locator
+
+	Profile cbor.RawMessage
+
+	Validity *Validity
+
+	Entities []Entity
+
+	Extra map[int64]cbor.RawMessage
+}
+/*
+ * Identity of a CoMID.
+ */
+type TagIdentity struct {
+
+	TagID any
+
+	TagVersion uint64
+}
+/*
+ * Link of a CoMID to another tag.
+ */
+type LinkedTag struct {
+
+	TagID any
+
+	Rel int64
+}
+/*
+ * Concise module identifier tag.
+ */
+type CoMID struct {
+
+	Language string
+
+	TagIdentity TagIdentity
+
+	Entities []Entity
+
+	LinkedTags []LinkedTag
+
+	Triples Triples
+
+	Extra map[int64]cbor.RawMessage
+}
+/*
+ * Triples of a CoMID.  The reference and endorsed value
+ * triples are modeled, and the others are retained in "Extra".
+ */
+type Triples struct {
+
+	Reference []Triple
+
+	Endorsed []Triple
+
+	Extra map[int64]cbor.RawMessage
+}
+/*
+ * Triple "[environment, [+ measurement]]" of reference or
+ * endorsed values.
+ */
+type Triple struct {
+
+	Environment Environment
+
+	Measurements []Measurement
+}
+/*
+ * Environment of a triple.  The instance and group are their
+ * encoded data items.
+ */
+type Environment struct {
+
+	Class *Class
+
+	Instance cbor.RawMessage
+
+	Group cbor.RawMessage
+}
+/*
+ * Class of an environment.  The class identifier is its
+ * encoded tagged OID, UUID or bytes data item.
+ */
+type Class struct {
+
+	ClassID cbor.RawMessage
+
+	Vendor string
+
+	Model string
+
+	Layer uint64
+
+	Index uint64
+}
+/*
+ * Measurement of a triple.  The key and authorizing keys are
+ * their encoded data items.
+ */
+type Measurement struct {
+
+	Key cbor.RawMessage
+
+	Values MeasurementValues
+
+	AuthorizedBy cbor.RawMessage
+}
+/*
+ * Version of measurement values.
+ */
+type Version struct {
+
+	Version string
+
+	Scheme int64
+}
+/*
+ * Measurement values.  The values of types having several
+ * choices are their encoded data items, and values unknown to
+ * this model are retained in "Extra".
+ */
+type MeasurementValues struct {
+
+	Version *Version
+
+	SVN cbor.RawMessage
+
+	Digests []Digest
+
+	Flags cbor.RawMessage
+
+	RawValue cbor.RawMessage
+
+	RawValueMask []byte
+
+	MACAddr []byte
+
+	IPAddr []byte
+
+	SerialNumber string
+
+	UEID []byte
+
+	UUID []byte
+
+	Name string
+
+	Extra map[int64]cbor.RawMessage
+}
+/*
+ * Map member of an integer key, having the pointer to its
+ * field.  A member is omitted from encoding when zero, unless
+ * required.
+ */
+type member struct {
+
+	key int64
+
+	value any
+
+	required bool
+}
+/*
+ */
+func (this *Entity) members() ([]member) {
+	return []member{{0, &this.Name, true}, {1, &this.RegID, false}, {2, &this.Roles, true}}
+}
+/*
+ */
+func (this *Locator) members() ([]member) {
+	return []member{{0, &this.Href, true}, {1, &this.Thumbprint, false}}
+}
+/*
+ */
+func (this *Validity) members() ([]member) {
+	return []member{{0, &this.NotBefore, false}, {1, &this.NotAfter, true}}
+}
+/*
+ */
+func (this *CoRIM) members() ([]member) {
+	return []member{{0, &this.ID, true}, {1, &this.Tags, true}, {2, &this.DependentRIMs, false}, {3, &this.Profile, false}, {4, &this.Validity, false}, {5, &this.Entities, false}}
+}
+/*
+ */
+func (this *TagIdentity) members() ([]member) {
+	return []member{{0, &this.TagID, true}, {1, &this.TagVersion, false}}
+}
+/*
+ */
+func (this *LinkedTag) members() ([]member) {
+	return []member{{0, &this.TagID, true}, {1, &this.Rel, true}}
+}
+/*
+ */
+func (this *CoMID) members() ([]member) {
+	return []member{{0, &this.Language, false}, {1, &this.TagIdentity, true}, {2, &this.Entities, false}, {3, &this.LinkedTags, false}, {4, &this.Triples, true}}
+}
+/*
+ */
+func (this *Triples) members() ([]member) {
+	return []member{{0, &this.Reference, false}, {1, &this.Endorsed, false}}
+}
+/*
+ */
+func (this *Environment) members() ([]member) {
+	return []member{{0, &this.Class, false}, {1, &this.Instance, false}, {2, &this.Group, false}}
+}
+/*
+ */
+func (this *Class) members() ([]member) {
+	return []member{{0, &this.ClassID, false}, {1, &this.Vendor, false}, {2, &this.Model, false}, {3, &this.Layer, false}, {4, &this.Index, false}}
+}
+/*
+ */
+func (this *Measurement) members() ([]member) {
+	return []member{{0, &this.Key, false}, {1, &this.Values, true}, {2, &this.AuthorizedBy, false}}
+}
+/*
+ */
+func (this *Version) members() ([]member) {
+	return []member{{0, &this.Version, true}, {1, &this.Scheme, false}}
+}
+/*
+ */
+func (this *MeasurementValues) members() ([]member) {
+	return []member{{0, &this.Version, false}, {1, &this.SVN, false}, {2, &this.Digests, false}, {3, &this.Flags, false}, {4, &this.RawValue, false}, {5, &this.RawValueMask, false},
+		{6, &this.MACAddr, false}, {7, &this.IPAddr, false}, {8, &this.SerialNumber, false}, {9, &this.UEID, false}, {10, &this.UUID, false}, {11, &this.Name, false}}
+}
+/*
+ */
+func (this Entity) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),nil)
+}
+/*
+ */
+func (this *Entity) UnmarshalCBOR(code []byte) (error) {
+	*this = Entity{}
+	return unmarshalMembers(code,this.members(),nil)
+}
+/*
+ */
+func (this Locator) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),nil)
+}
+/*
+ */
+func (this *Locator) UnmarshalCBOR(code []byte) (error) {
+	*this = Locator{}
+	return unmarshalMembers(code,this.members(),nil)
+}
+/*
+ */
+func (this Validity) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),nil)
+}
+/*
+ */
+func (this *Validity) UnmarshalCBOR(code []byte) (error) {
+	*this = Validity{}
+	return unmarshalMembers(code,this.members(),nil)
+}
+/*
+ */
+func (this TagIdentity) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),nil)
+}
+/*
+ */
+func (this *TagIdentity) UnmarshalCBOR(code []byte) (error) {
+	*this = TagIdentity{}
+	return unmarshalMembers(code,this.members(),nil)
+}
+/*
+ */
+func (this LinkedTag) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),nil)
+}
+/*
+ */
+func (this *LinkedTag) UnmarshalCBOR(code []byte) (error) {
+	*this = LinkedTag{}
+	return unmarshalMembers(code,this.members(),nil)
+}
+/*
+ */
+func (this CoMID) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),this.Extra)
+}
+/*
+ */
+func (this *CoMID) UnmarshalCBOR(code []byte) (error) {
+	*this = CoMID{}
+	return unmarshalMembers(code,this.members(),&this.Extra)
+}
+/*
+ */
+func (this Triples) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),this.Extra)
+}
+/*
+ */
+func (this *Triples) UnmarshalCBOR(code []byte) (error) {
+	*this = Triples{}
+	return unmarshalMembers(code,this.members(),&this.Extra)
+}
+/*
+ */
+func (this Environment) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),nil)
+}
+/*
+ */
+func (this *Environment) UnmarshalCBOR(code []byte) (error) {
+	*this = Environment{}
+	return unmarshalMembers(code,this.members(),nil)
+}
+/*
+ */
+func (this Class) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),nil)
+}
+/*
+ */
+func (this *Class) UnmarshalCBOR(code []byte) (error) {
+	*this = Class{}
+	return unmarshalMembers(code,this.members(),nil)
+}
+/*
+ */
+func (this Measurement) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),nil)
+}
+/*
+ */
+func (this *Measurement) UnmarshalCBOR(code []byte) (error) {
+	*this = Measurement{}
+	return unmarshalMembers(code,this.members(),nil)
+}
+/*
+ */
+func (this Version) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),nil)
+}
+/*
+ */
+func (this *Version) UnmarshalCBOR(code []byte) (error) {
+	*this = Version{}
+	return unmarshalMembers(code,this.members(),nil)
+}
+/*
+ */
+func (this MeasurementValues) MarshalCBOR() ([]byte, error) {
+	return marshalMembers(this.members(),this.Extra)
+}
+/*
+ */
+func (this *MeasurementValues) UnmarshalCBOR(code []byte) (error) {
+	*this = MeasurementValues{}
+	return unmarshalMembers(code,this.members(),&this.Extra)
+}
+/*
+ * Encode the unsigned CoRIM, tagged with <TagCoRIM>.
+ */
+func (this CoRIM) MarshalCBOR() ([]byte, error) {
+	var code, e = marshalMembers(this.members(),this.Extra)
+	if nil != e {
+		return nil, e
+	} else {
+		return append(cbor.AppendHead(nil,cbor.MajorTagged,TagCoRIM),code...), nil
+	}
+}
+/*
+ * Decode the unsigned CoRIM, optionally tagged with
+ * <TagCoRIM>.
+ */
+func (this *CoRIM) UnmarshalCBOR(code []byte) (error) {
+	var major, _, arg, z, e = cbor.ParseHead(code)
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorCoRIM,e)
+	} else if cbor.MajorTagged == major {
+		if TagCoRIM != arg {
+			return fmt.Errorf("%w: tag %d",ErrorCoRIM,arg)
+		} else {
+			code = code[z:]
+		}
+	}
+	*this = CoRIM{}
+	return unmarshalMembers(code,this.members(),&this.Extra)
+}
+/*
+ * Encode the unsigned CoRIM.
+ */
+func (this CoRIM) Encode() (cbor.Object, error) {
+	var code, e = this.MarshalCBOR()
+	return cbor.Object(code), e
+}
+/*
+ * Decode the CoRIM, unsigned, or the embedded payload of its
+ * (tagged) "COSE_Sign1".  The signature is not verified, and
+ * its verification is the user's, as by <cbor.SigStructure>.
+ */
+func Decode(code cbor.Object) (rim CoRIM, e error) {
+	var major, _, arg, z, _ = cbor.ParseHead(code)
+	if cbor.MajorTagged == major && TagCOSESign1 == arg {
+		code = code[z:]
+	}
+	if cbor.MajorArray == code.Major() {
+		var list []cbor.Object
+		list, e = code.Items()
+		if nil != e {
+			return rim, e
+		} else if 4 != len(list) || cbor.MajorBlob != list[2].Major() {
+			return rim, ErrorSigned
+		}
+		code, e = list[2].Bytes()
+		if nil != e {
+			return rim, e
+		}
+	}
+	e = rim.UnmarshalCBOR(code)
+	return rim, e
+}
+/*
+ * Encode the concise tag, as the byte string of its encoding
+ * tagged with <TagCoSWID> or <TagCoMID>, or as the raw data
+ * item.
+ */
+func (this ConciseTag) MarshalCBOR() ([]byte, error) {
+	var number uint64
+	var code []byte
+	var e error
+	switch {
+	case nil != this.CoSWID:
+		number = TagCoSWID
+		code, e = this.CoSWID.MarshalCBOR()
+	case nil != this.CoMID:
+		number = TagCoMID
+		code, e = this.CoMID.MarshalCBOR()
+	case nil != this.Raw:
+		return this.Raw, nil
+	default:
+		return nil, fmt.Errorf("%w: empty concise tag",ErrorCoRIM)
+	}
+	if nil != e {
+		return nil, e
+	} else {
+		return append(cbor.AppendHead(nil,cbor.MajorTagged,number),cbor.Encode(code)...), nil
+	}
+}
+/*
+ * Decode the concise tag, retaining unrecognized tags as raw
+ * data items.
+ */
+func (this *ConciseTag) UnmarshalCBOR(code []byte) (error) {
+	*this = ConciseTag{}
+	var major, _, arg, z, e = cbor.ParseHead(code)
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorCoRIM,e)
+	} else if cbor.MajorTagged != major || (TagCoSWID != arg && TagCoMID != arg) {
+		this.Raw = append(cbor.RawMessage{},code...)
+		return nil
+	}
+	var content []byte
+	content, e = cbor.Object(code[z:]).Bytes()
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorCoRIM,e)
+	} else if TagCoSWID == arg {
+		var tag coswid.Tag
+		e = tag.UnmarshalCBOR(content)
+		this.CoSWID = &tag
+	} else {
+		var tag CoMID
+		e = tag.UnmarshalCBOR(content)
+		this.CoMID = &tag
+	}
+	return e
+}
+/*
+ * Encode triple as "[environment, [+ measurement]]".
+ */
+func (this Triple) MarshalCBOR() ([]byte, error) {
+	var environment, e = this.Environment.MarshalCBOR()
+	if nil != e {
+		return nil, e
+	} else {
+		return cbor.Encode([]any{cbor.RawMessage(environment),this.Measurements}), nil
+	}
+}
+/*
+ * Decode triple from "[environment, [+ measurement]]".
+ */
+func (this *Triple) UnmarshalCBOR(code []byte) (e error) {
+	var list []cbor.Object
+	list, e = cbor.Object(code).Items()
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorCoRIM,e)
+	} else if 2 != len(list) {
+		return fmt.Errorf("%w: triple",ErrorCoRIM)
+	}
+	*this = Triple{}
+	e = this.Environment.UnmarshalCBOR(list[0])
+	if nil == e {
+		e = cbor.Unmarshal(list[1],&this.Measurements)
+	}
+	return e
+}
+/*
+ * Encode digest as "[algorithm, value]".
+ */
+func (this Digest) MarshalCBOR() ([]byte, error) {
+	return cbor.Encode([]any{this.Algorithm,this.Value}), nil
+}
+/*
+ * Decode digest from "[algorithm, value]".
+ */
+func (this *Digest) UnmarshalCBOR(code []byte) (e error) {
+	var list []cbor.Object
+	list, e = cbor.Object(code).Items()
+	if nil != e || 2 != len(list) {
+		return fmt.Errorf("%w: digest",ErrorCoRIM)
+	}
+	this.Algorithm, e = list[0].Int()
+	if nil == e {
+		this.Value, e = list[1].Bytes()
+	}
+	return e
+}
+/*
+ * Encode the members and extra entries as a map with integer
+ * keys in deterministic order.
+ */
+func marshalMembers(members []member, extra map[int64]cbor.RawMessage) ([]byte, error) {
+	var entries map[int64]any = map[int64]any{}
+	for key, value := range extra {
+		entries[key] = value
+	}
+	for _, m := range members {
+		var value reflect.Value = reflect.ValueOf(m.value).Elem()
+		if m.required || !value.IsZero() {
+			entries[m.key] = value.Interface()
+		}
+	}
+	var code, e = cbor.EncOptionsCoreDet().Encode(entries)
+	if nil != e {
+		return nil, e
+	} else {
+		return code, nil
+	}
+}
+/*
+ * Decode the map with integer keys into the members, retaining
+ * the entries of other integer keys in extra, when present.
+ */
+func unmarshalMembers(code []byte, members []member, extra *map[int64]cbor.RawMessage) (e error) {
+	var entries map[any]cbor.RawMessage
+	e = cbor.Unmarshal(code,&entries)
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorCoRIM,e)
+	}
+	var found map[int64]bool = map[int64]bool{}
+	for key, value := range entries {
+		var label, ok = label(key)
+		if !ok {
+			continue
+		}
+		var m member
+		for _, m = range members {
+			if label == m.key {
+				break
+			}
+		}
+		if label == m.key {
+			found[label] = true
+			e = cbor.Unmarshal(value,m.value)
+			if nil != e {
+				return fmt.Errorf("%w: %d: %w",ErrorCoRIM,label,e)
+			}
+		} else if nil != extra {
+			if nil == *extra {
+				*extra = map[int64]cbor.RawMessage{}
+			}
+			(*extra)[label] = value
+		}
+	}
+	for _, m := range members {
+		if m.required && !found[m.key] {
+			return fmt.Errorf("%w: %d",ErrorMissing,m.key)
+		}
+	}
+	return nil
+}
+/*
+ * Resolve the integer of a decoded map key.
+ */
+func label(key any) (int64, bool) {
+	switch k := key.(type) {
+	case uint8:
+		return int64(k), true
+	case uint16:
+		return int64(k), true
+	case uint32:
+		return int64(k), true
+	case int:
+		return int64(k), true
+	case int32:
+		return int64(k), true
+	case int64:
+		return int64(k), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * CoRIM CBOR Representation Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package corim
+
+import (
+	"bytes"
+	"errors"
+	"github.com/syntelos/go-cbor"
+	"github.com/syntelos/go-cbor/coswid"
+	"testing"
+	"time"
+)
+
+func TestCoRIM(t *testing.T){
+	var digest []byte = bytes.Repeat([]byte{0x5A},32)
+	var comid CoMID = CoMID{
+		TagIdentity: TagIdentity{TagID: "acme-firmware-comid", TagVersion: 2},
+		Entities: []Entity{{Name: "ACME Ltd", RegID: "https://acme.example", Roles: []int64{RoleTagCreator, RoleCreator}}},
+		LinkedTags: []LinkedTag{{TagID: "acme-firmware-comid-v1", Rel: RelSupplements}},
+		Triples: Triples{
+			Reference: []Triple{{
+				Environment: Environment{Class: &Class{Vendor: "ACME", Model: "RoadRunner", Layer: 1}},
+				Measurements: []Measurement{{
+					Values: MeasurementValues{Version: &Version{Version: "1.0.0", Scheme: 16384}, Digests: []Digest{{1, digest}}},
+				}},
+			}},
+		},
+	}
+	var swid coswid.Tag = coswid.Tag{
+		TagID: "acme-firmware-swid",
+		SoftwareName: "RoadRunner firmware",
+		Entity: coswid.Entities{{EntityName: "ACME Ltd", Role: coswid.Roles{coswid.RoleTagCreator}}},
+	}
+	var notAfter time.Time = time.Unix(1800000000,0).UTC()
+	var rim CoRIM = CoRIM{
+		ID: "acme-rim",
+		Tags: []ConciseTag{{CoMID: &comid}, {CoSWID: &swid}},
+		Validity: &Validity{NotAfter: notAfter},
+		Entities: []Entity{{Name: "ACME Ltd", Roles: []int64{RoleManifestCreator}}},
+	}
+	var code, e = rim.Encode()
+	if nil != e {
+		t.Fatal(e)
+	} else if 0xD9 != code[0] || 0x01 != code[1] || 0xF5 != code[2] {
+		t.Errorf("Expected 'D901F5...', found '%X'.",[]byte(code[0:3]))
+	} else if e = code[3:].ConformsCoreDet(); nil != e {
+		t.Errorf("Expected deterministic encoding, found '%v'.",e)
+	}
+
+	var check CoRIM
+	check, e = Decode(code)
+	if nil != e {
+		t.Fatal(e)
+	}
+	if "acme-rim" != check.ID || 2 != len(check.Tags) || nil == check.Validity || !notAfter.Equal(check.Validity.NotAfter) {
+		t.Errorf("Expected '%v', found '%v'.",rim,check)
+	}
+	var mid *CoMID = check.Tags[0].CoMID
+	if nil == mid || "acme-firmware-comid" != mid.TagIdentity.TagID || 2 != mid.TagIdentity.TagVersion {
+		t.Fatalf("Expected CoMID, found '%v'.",check.Tags[0])
+	}
+	if 1 != len(mid.LinkedTags) || RelSupplements != mid.LinkedTags[0].Rel || 2 != len(mid.Entities[0].Roles) {
+		t.Errorf("Expected linked tags and entities, found '%v' '%v'.",mid.LinkedTags,mid.Entities)
+	}
+	if 1 != len(mid.Triples.Reference) || nil == mid.Triples.Reference[0].Environment.Class || "RoadRunner" != mid.Triples.Reference[0].Environment.Class.Model {
+		t.Fatalf("Expected reference triple, found '%v'.",mid.Triples)
+	}
+	var values MeasurementValues = mid.Triples.Reference[0].Measurements[0].Values
+	if nil == values.Version || "1.0.0" != values.Version.Version || 1 != len(values.Digests) || !bytes.Equal(digest,values.Digests[0].Value) {
+		t.Errorf("Expected measurement values, found '%v'.",values)
+	}
+	if nil == check.Tags[1].CoSWID || "RoadRunner firmware" != check.Tags[1].CoSWID.SoftwareName {
+		t.Errorf("Expected CoSWID, found '%v'.",check.Tags[1])
+	}
+
+	var again cbor.Object
+	again, e = check.Encode()
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(code,again) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(code),[]byte(again))
+	}
+
+	var signed []byte = []byte{0xD2}
+	signed = append(signed,cbor.Encode([]any{[]byte{0xA1, 0x01, 0x26}, map[int]int{}, []byte(code), make([]byte,64)})...)
+	check, e = Decode(signed)
+	if nil != e {
+		t.Fatal(e)
+	} else if "acme-rim" != check.ID {
+		t.Errorf("Expected signed CoRIM, found '%v'.",check)
+	}
+}
+
+func TestCoRIMErrors(t *testing.T){
+	var _, e = Decode(cbor.Encode(map[int]any{0: "id"}))
+	if !errors.Is(e,ErrorMissing) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorMissing,e)
+	}
+	_, e = Decode(cbor.Encode([]any{[]byte{}, map[int]int{}, nil, []byte{}}))
+	if !errors.Is(e,ErrorSigned) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorSigned,e)
+	}
+	/*
+	 * CoTL tag retained raw, with an extension member.
+	 */
+	var cotl []byte = append([]byte{0xD9, 0x01, 0xFC},cbor.Encode([]byte{0xA0})...)
+	var rim CoRIM
+	rim, e = Decode(cbor.Encode(map[int]any{0: "id", 1: []cbor.RawMessage{cotl}, 9: "ext"}))
+	if nil != e {
+		t.Fatal(e)
+	} else if 1 != len(rim.Tags) || !bytes.Equal(cotl,rim.Tags[0].Raw) || 1 != len(rim.Extra) {
+		t.Errorf("Expected raw tag and extension, found '%v'.",rim)
+	}
+}
@@ -0,0 +1,689 @@
+/*
+ * COSE_Key CBOR Representation
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc9052#section-7
+ * https://tools.ietf.org/html/rfc9053#section-7
+ * https://tools.ietf.org/html/rfc8230#section-4
+ * https://tools.ietf.org/html/rfc8747#section-3
+ */
+package cosekey
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"github.com/syntelos/go-cbor"
+	"math/big"
+)
+/*
+ * CoAP Content-Format of "application/cose-key".
+ */
+const ContentFormat uint16 = 101
+/*
+ * Media type of COSE_Key content.
+ */
+const ContentType string = "application/cose-key"
+/*
+ * COSE_Key common parameter labels.  See Table 4 [RFC9052].
+ */
+const LabelKty int = 1
+const LabelKid int = 2
+const LabelAlg int = 3
+const LabelKeyOps int = 4
+const LabelBaseIV int = 5
+/*
+ * Key type parameter labels of "OKP" and "EC2".  See Tables
+ * 18 and 19 [RFC9053].
+ */
+const LabelCrv int = -1
+const LabelX int = -2
+const LabelY int = -3
+const LabelD int = -4
+/*
+ * Key type parameter labels of "RSA".  See Table 4 [RFC8230].
+ */
+const LabelN int = -1
+const LabelE int = -2
+const LabelRSAD int = -3
+const LabelP int = -4
+const LabelQ int = -5
+const LabelDP int = -6
+const LabelDQ int = -7
+const LabelQInv int = -8
+/*
+ * Key type parameter label of "Symmetric".  See Table 20
+ * [RFC9053].
+ */
+const LabelK int = -1
+/*
+ * Key type values.  See Table 17 [RFC9053] and Section 4
+ * [RFC8230].
+ */
+const KtyOKP int64 = 1
+const KtyEC2 int64 = 2
+const KtyRSA int64 = 3
+const KtySymmetric int64 = 4
+/*
+ * Elliptic curve values.  See Table 18 [RFC9053].
+ */
+const CrvP256 int64 = 1
+const CrvP384 int64 = 2
+const CrvP521 int64 = 3
+const CrvX25519 int64 = 4
+const CrvX448 int64 = 5
+const CrvEd25519 int64 = 6
+const CrvEd448 int64 = 7
+/*
+ * Validation errors produced by decoding and conversion.
+ */
+var ErrorKey error = errors.New("COSE_Key is not a map")
+var ErrorKeyType error = errors.New("COSE_Key type or curve unsupported")
+var ErrorKeyMaterial error = errors.New("COSE_Key parameters missing or invalid")
+var ErrorConfirmation error = errors.New("COSE_Key confirmation method missing or unsupported")
+/*
+ * CWT claim key of the confirmation claim "cnf".  See Section
+ * 3.1 [RFC8747].
+ */
+const ClaimCnf int = 8
+/*
+ * Confirmation method labels of the "cnf" claim.  See Section
+ * 3.1 [RFC8747].
+ */
+const LabelCnfCOSEKey int = 1
+const LabelCnfEncryptedCOSEKey int = 2
+const LabelCnfKid int = 3
+/*
+ * COSE_Key of the key types "OKP", "EC2", "RSA" and
+ * "Symmetric".  The fields of each key type are those of its
+ * parameter labels, and "D" is the private key parameter of
+ * "OKP", "EC2" and "RSA".  Zero fields are absent.  Key
+ * operations are the integer values of Table 5 [RFC9052], and
+ * the compressed point of a boolean "y" is unsupported.
+ */
+type Key struct {
+
+	Kty int64
+
+	Kid []byte
+
+	Alg int64
+
+	KeyOps []int64
+
+	BaseIV []byte
+
+	Crv int64
+
+	X []byte
+
+	Y []byte
+
+	D []byte
+
+	N []byte
+
+	E []byte
+
+	P []byte
+
+	Q []byte
+
+	DP []byte
+
+	DQ []byte
+
+	QInv []byte
+
+	K []byte
+}
+/*
+ * Encode key as a map with integer labels in deterministic
+ * order, omitting zero fields.
+ */
+func (this Key) MarshalCBOR() ([]byte, error) {
+	var entries cbor.OrderedMap
+	var add = func(label int, value any) {
+		entries = append(entries,cbor.MapEntry{Key: label, Value: value})
+	}
+	add(LabelKty,this.Kty)
+	if nil != this.Kid {
+		add(LabelKid,this.Kid)
+	}
+	if 0 != this.Alg {
+		add(LabelAlg,this.Alg)
+	}
+	if nil != this.KeyOps {
+		add(LabelKeyOps,this.KeyOps)
+	}
+	if nil != this.BaseIV {
+		add(LabelBaseIV,this.BaseIV)
+	}
+	switch this.Kty {
+	case KtyOKP, KtyEC2:
+		add(LabelCrv,this.Crv)
+		if nil != this.X {
+			add(LabelX,this.X)
+		}
+		if nil != this.Y {
+			add(LabelY,this.Y)
+		}
+		if nil != this.D {
+			add(LabelD,this.D)
+		}
+	case KtyRSA:
+		var params = [][]byte{this.N, this.E, this.D, this.P, this.Q, this.DP, this.DQ, this.QInv}
+		for n, param := range params {
+			if nil != param {
+				add(LabelN-n,param)
+			}
+		}
+	case KtySymmetric:
+		add(LabelK,this.K)
+	default:
+		return nil, fmt.Errorf("%w: %d",ErrorKeyType,this.Kty)
+	}
+	return cbor.EncOptions{}.Encode(entries)
+}
+/*
+ * Decode key from a map with integer labels.  The labels of
+ * key type parameters are resolved by the key type, and text
+ * labels are ignored.
+ */
+func (this *Key) UnmarshalCBOR(code []byte) (e error) {
+	var entries map[any]cbor.RawMessage
+	e = cbor.Unmarshal(code,&entries)
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorKey,e)
+	}
+	*this = Key{}
+	var labels map[int]cbor.RawMessage = map[int]cbor.RawMessage{}
+	for key, value := range entries {
+		switch k := key.(type) {
+		case uint8:
+			labels[int(k)] = value
+		case int:
+			labels[k] = value
+		}
+	}
+	var kty, ok = labels[LabelKty]
+	if !ok {
+		return fmt.Errorf("%w: kty",ErrorKeyMaterial)
+	}
+	e = cbor.Unmarshal(kty,&this.Kty)
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorKeyType,e)
+	}
+	for label, value := range labels {
+		var target any
+		switch {
+		case LabelKty == label:
+			continue
+		case LabelKid == label:
+			target = &this.Kid
+		case LabelAlg == label:
+			target = &this.Alg
+		case LabelKeyOps == label:
+			target = &this.KeyOps
+		case LabelBaseIV == label:
+			target = &this.BaseIV
+		case 0 < label:
+			continue
+		default:
+			target = this.parameter(label)
+			if nil == target {
+				continue
+			}
+		}
+		e = cbor.Unmarshal(value,target)
+		if nil != e {
+			return fmt.Errorf("%w: %d: %w",ErrorKeyMaterial,label,e)
+		}
+	}
+	return nil
+}
+/*
+ * Resolve the field of the key type parameter label, or nil.
+ */
+func (this *Key) parameter(label int) (any) {
+	switch this.Kty {
+	case KtyOKP, KtyEC2:
+		switch label {
+		case LabelCrv:
+			return &this.Crv
+		case LabelX:
+			return &this.X
+		case LabelY:
+			return &this.Y
+		case LabelD:
+			return &this.D
+		}
+	case KtyRSA:
+		var params = []*[]byte{&this.N, &this.E, &this.D, &this.P, &this.Q, &this.DP, &this.DQ, &this.QInv}
+		var n int = LabelN-label
+		if 0 <= n && n < len(params) {
+			return params[n]
+		}
+	case KtySymmetric:
+		if LabelK == label {
+			return &this.K
+		}
+	}
+	return nil
+}
+/*
+ * Define the key of a public or private key of the types
+ * "*ecdsa.PublicKey", "*ecdsa.PrivateKey", "ed25519.PublicKey",
+ * "ed25519.PrivateKey", "*rsa.PublicKey", "*rsa.PrivateKey",
+ * "*ecdh.PublicKey" and "*ecdh.PrivateKey", or the symmetric
+ * key of a byte slice.
+ */
+func New(key any) (Key, error) {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var crv, size, e = curveOf(k.Curve)
+		if nil != e {
+			return Key{}, e
+		} else {
+			return Key{Kty: KtyEC2, Crv: crv, X: k.X.FillBytes(make([]byte,size)), Y: k.Y.FillBytes(make([]byte,size))}, nil
+		}
+	case *ecdsa.PrivateKey:
+		var ck, e = New(&k.PublicKey)
+		if nil != e {
+			return Key{}, e
+		} else {
+			ck.D = k.D.FillBytes(make([]byte,len(ck.X)))
+			return ck, nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.PublicKeySize != len(k) {
+			return Key{}, ErrorKeyMaterial
+		} else {
+			return Key{Kty: KtyOKP, Crv: CrvEd25519, X: append([]byte{},k...)}, nil
+		}
+	case ed25519.PrivateKey:
+		if ed25519.PrivateKeySize != len(k) {
+			return Key{}, ErrorKeyMaterial
+		} else {
+			return Key{Kty: KtyOKP, Crv: CrvEd25519, X: append([]byte{},k[32:]...), D: k.Seed()}, nil
+		}
+	case *rsa.PublicKey:
+		return Key{Kty: KtyRSA, N: k.N.Bytes(), E: big.NewInt(int64(k.E)).Bytes()}, nil
+	case *rsa.PrivateKey:
+		if 2 != len(k.Primes) {
+			return Key{}, fmt.Errorf("%w: multi-prime RSA",ErrorKeyType)
+		}
+		/*
+		 * Precomputed on a copy, as the key of the caller may
+		 * be in use concurrently.
+		 */
+		var pk *rsa.PrivateKey = &rsa.PrivateKey{PublicKey: k.PublicKey, D: k.D, Primes: k.Primes}
+		pk.Precompute()
+		var ck Key = Key{Kty: KtyRSA, N: k.N.Bytes(), E: big.NewInt(int64(k.E)).Bytes(), D: k.D.Bytes()}
+		ck.P = k.Primes[0].Bytes()
+		ck.Q = k.Primes[1].Bytes()
+		ck.DP = pk.Precomputed.Dp.Bytes()
+		ck.DQ = pk.Precomputed.Dq.Bytes()
+		ck.QInv = pk.Precomputed.Qinv.Bytes()
+		return ck, nil
+	case *ecdh.PublicKey:
+		return ecdhKey(k.Curve(),k.Bytes(),nil)
+	case *ecdh.PrivateKey:
+		return ecdhKey(k.Curve(),k.PublicKey().Bytes(),k.Bytes())
+	case []byte:
+		return Key{Kty: KtySymmetric, K: append([]byte{},k...)}, nil
+	default:
+		return Key{}, fmt.Errorf("%w: %T",ErrorKeyType,key)
+	}
+}
+/*
+ * Define the public key of the key, as "*ecdsa.PublicKey" for
+ * "EC2", "ed25519.PublicKey" and "*ecdh.PublicKey" for the
+ * "OKP" curves "Ed25519" and "X25519", and "*rsa.PublicKey"
+ * for "RSA".  The point of an "EC2" key is validated.
+ */
+func (this Key) PublicKey() (any, error) {
+	switch this.Kty {
+	case KtyEC2:
+		var curve, size, e = this.curve()
+		if nil != e {
+			return nil, e
+		}
+		var x, y []byte = this.X, this.Y
+		if nil == x && nil == y && nil != this.D {
+			var priv *ecdh.PrivateKey
+			priv, e = this.ecdhCurve().NewPrivateKey(this.D)
+			if nil != e {
+				return nil, fmt.Errorf("%w: %w",ErrorKeyMaterial,e)
+			}
+			var point []byte = priv.PublicKey().Bytes()
+			x, y = point[1:1+size], point[1+size:]
+		} else if size != len(x) || size != len(y) {
+			return nil, ErrorKeyMaterial
+		}
+		var point []byte = append(append([]byte{0x04},x...),y...)
+		_, e = this.ecdhCurve().NewPublicKey(point)
+		if nil != e {
+			return nil, fmt.Errorf("%w: %w",ErrorKeyMaterial,e)
+		} else {
+			return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+		}
+	case KtyOKP:
+		switch this.Crv {
+		case CrvEd25519:
+			if nil == this.X && ed25519.SeedSize == len(this.D) {
+				return ed25519.NewKeyFromSeed(this.D).Public(), nil
+			} else if ed25519.PublicKeySize != len(this.X) {
+				return nil, ErrorKeyMaterial
+			} else {
+				return ed25519.PublicKey(append([]byte{},this.X...)), nil
+			}
+		case CrvX25519:
+			if nil == this.X && nil != this.D {
+				var priv, e = ecdh.X25519().NewPrivateKey(this.D)
+				if nil != e {
+					return nil, fmt.Errorf("%w: %w",ErrorKeyMaterial,e)
+				} else {
+					return priv.PublicKey(), nil
+				}
+			} else {
+				var pub, e = ecdh.X25519().NewPublicKey(this.X)
+				if nil != e {
+					return nil, fmt.Errorf("%w: %w",ErrorKeyMaterial,e)
+				} else {
+					return pub, nil
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%w: curve %d",ErrorKeyType,this.Crv)
+		}
+	case KtyRSA:
+		if 0 == len(this.N) || 0 == len(this.E) || 4 < len(this.E) {
+			return nil, ErrorKeyMaterial
+		}
+		var exponent int64 = new(big.Int).SetBytes(this.E).Int64()
+		if 2 > exponent || 0x7FFFFFFF < exponent {
+			return nil, ErrorKeyMaterial
+		} else {
+			return &rsa.PublicKey{N: new(big.Int).SetBytes(this.N), E: int(exponent)}, nil
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d",ErrorKeyType,this.Kty)
+	}
+}
+/*
+ * Define the private key of the key, as "*ecdsa.PrivateKey"
+ * for "EC2", "ed25519.PrivateKey" and "*ecdh.PrivateKey" for
+ * the "OKP" curves "Ed25519" and "X25519", and
+ * "*rsa.PrivateKey" for "RSA".  A public key present with the
+ * private key must correspond to it.
+ */
+func (this Key) PrivateKey() (any, error) {
+	if 0 == len(this.D) {
+		return nil, fmt.Errorf("%w: d",ErrorKeyMaterial)
+	}
+	switch this.Kty {
+	case KtyEC2:
+		var derived Key = this
+		derived.X, derived.Y = nil, nil
+		var pub, e = derived.PublicKey()
+		if nil != e {
+			return nil, e
+		}
+		var ecpub *ecdsa.PublicKey = pub.(*ecdsa.PublicKey)
+		if nil != this.X || nil != this.Y {
+			var size int = len(derived.D)
+			if !bytes.Equal(this.X,ecpub.X.FillBytes(make([]byte,size))) || !bytes.Equal(this.Y,ecpub.Y.FillBytes(make([]byte,size))) {
+				return nil, fmt.Errorf("%w: public key",ErrorKeyMaterial)
+			}
+		}
+		return &ecdsa.PrivateKey{PublicKey: *ecpub, D: new(big.Int).SetBytes(this.D)}, nil
+	case KtyOKP:
+		switch this.Crv {
+		case CrvEd25519:
+			if ed25519.SeedSize != len(this.D) {
+				return nil, ErrorKeyMaterial
+			}
+			var priv ed25519.PrivateKey = ed25519.NewKeyFromSeed(this.D)
+			if nil != this.X && !bytes.Equal(this.X,priv[32:]) {
+				return nil, fmt.Errorf("%w: public key",ErrorKeyMaterial)
+			} else {
+				return priv, nil
+			}
+		case CrvX25519:
+			var priv, e = ecdh.X25519().NewPrivateKey(this.D)
+			if nil != e {
+				return nil, fmt.Errorf("%w: %w",ErrorKeyMaterial,e)
+			} else if nil != this.X && !bytes.Equal(this.X,priv.PublicKey().Bytes()) {
+				return nil, fmt.Errorf("%w: public key",ErrorKeyMaterial)
+			} else {
+				return priv, nil
+			}
+		default:
+			return nil, fmt.Errorf("%w: curve %d",ErrorKeyType,this.Crv)
+		}
+	case KtyRSA:
+		var pub, e = this.PublicKey()
+		if nil != e {
+			return nil, e
+		} else if 0 == len(this.P) || 0 == len(this.Q) {
+			return nil, fmt.Errorf("%w: primes",ErrorKeyMaterial)
+		}
+		var priv *rsa.PrivateKey = &rsa.PrivateKey{PublicKey: *pub.(*rsa.PublicKey), D: new(big.Int).SetBytes(this.D)}
+		priv.Primes = []*big.Int{new(big.Int).SetBytes(this.P), new(big.Int).SetBytes(this.Q)}
+		e = priv.Validate()
+		if nil != e {
+			return nil, fmt.Errorf("%w: %w",ErrorKeyMaterial,e)
+		} else {
+			priv.Precompute()
+			return priv, nil
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d",ErrorKeyType,this.Kty)
+	}
+}
+/*
+ * Encode key.
+ */
+func (this Key) Encode() (cbor.Object, error) {
+	var code, e = this.MarshalCBOR()
+	return cbor.Object(code), e
+}
+/*
+ * Decode key.
+ */
+func Decode(code cbor.Object) (key Key, e error) {
+	e = key.UnmarshalCBOR(code)
+	return key, e
+}
+/*
+ * Resolve the "EC2" curve and its coordinate size.
+ */
+func (this Key) curve() (elliptic.Curve, int, error) {
+	switch this.Crv {
+	case CrvP256:
+		return elliptic.P256(), 32, nil
+	case CrvP384:
+		return elliptic.P384(), 48, nil
+	case CrvP521:
+		return elliptic.P521(), 66, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: curve %d",ErrorKeyType,this.Crv)
+	}
+}
+/*
+ * Resolve the ECDH curve of an "EC2" key for point validation.
+ */
+func (this Key) ecdhCurve() (ecdh.Curve) {
+	switch this.Crv {
+	case CrvP384:
+		return ecdh.P384()
+	case CrvP521:
+		return ecdh.P521()
+	default:
+		return ecdh.P256()
+	}
+}
+/*
+ * Resolve the curve value and coordinate size of an ECDSA
+ * curve.
+ */
+func curveOf(curve elliptic.Curve) (int64, int, error) {
+	switch curve {
+	case elliptic.P256():
+		return CrvP256, 32, nil
+	case elliptic.P384():
+		return CrvP384, 48, nil
+	case elliptic.P521():
+		return CrvP521, 66, nil
+	default:
+		return 0, 0, ErrorKeyType
+	}
+}
+/*
+ * Define the key of an ECDH public point and optional private
+ * scalar.
+ */
+func ecdhKey(curve ecdh.Curve, point, d []byte) (Key, error) {
+	var key Key
+	switch curve {
+	case ecdh.X25519():
+		key = Key{Kty: KtyOKP, Crv: CrvX25519, X: point}
+	case ecdh.P256(), ecdh.P384(), ecdh.P521():
+		var size int = (len(point)-1)/2
+		key = Key{Kty: KtyEC2, X: point[1:1+size], Y: point[1+size:]}
+		switch size {
+		case 32:
+			key.Crv = CrvP256
+		case 48:
+			key.Crv = CrvP384
+		default:
+			key.Crv = CrvP521
+		}
+	default:
+		return Key{}, ErrorKeyType
+	}
+	key.D = d
+	return key, nil
+}
+/*
+ * Confirmation claim "cnf" of a proof-of-possession token,
+ * having one of the confirmation methods: the (public) key, the
+ * encoded "COSE_Encrypt0" or "COSE_Encrypt" of an encrypted key,
+ * or the key identifier.  The encrypted key is not decrypted by
+ * this package.
+ */
+type Confirmation struct {
+
+	Key *Key
+
+	EncryptedKey cbor.RawMessage
+
+	Kid []byte
+}
+/*
+ * Encode confirmation as a map of its one confirmation method.
+ */
+func (this Confirmation) MarshalCBOR() ([]byte, error) {
+	var method cbor.MapEntry
+	switch {
+	case nil != this.Key:
+		method = cbor.MapEntry{Key: LabelCnfCOSEKey, Value: this.Key}
+	case nil != this.EncryptedKey:
+		method = cbor.MapEntry{Key: LabelCnfEncryptedCOSEKey, Value: this.EncryptedKey}
+	case nil != this.Kid:
+		method = cbor.MapEntry{Key: LabelCnfKid, Value: this.Kid}
+	default:
+		return nil, ErrorConfirmation
+	}
+	return cbor.EncOptions{}.Encode(cbor.OrderedMap{method})
+}
+/*
+ * Decode confirmation from a map of confirmation methods.
+ * Unknown methods are ignored, and one known method is
+ * required.
+ */
+func (this *Confirmation) UnmarshalCBOR(code []byte) (e error) {
+	var entries map[any]cbor.RawMessage
+	e = cbor.Unmarshal(code,&entries)
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorConfirmation,e)
+	}
+	*this = Confirmation{}
+	for key, value := range entries {
+		var label int
+		switch k := key.(type) {
+		case uint8:
+			label = int(k)
+		case int:
+			label = k
+		default:
+			continue
+		}
+		switch label {
+		case LabelCnfCOSEKey:
+			var key Key
+			e = key.UnmarshalCBOR(value)
+			if nil != e {
+				return e
+			} else {
+				this.Key = &key
+			}
+		case LabelCnfEncryptedCOSEKey:
+			this.EncryptedKey = value
+		case LabelCnfKid:
+			e = cbor.Unmarshal(value,&this.Kid)
+			if nil != e {
+				return fmt.Errorf("%w: %w",ErrorConfirmation,e)
+			}
+		}
+	}
+	if nil == this.Key && nil == this.EncryptedKey && nil == this.Kid {
+		return ErrorConfirmation
+	} else {
+		return nil
+	}
+}
+/*
+ * Resolve the confirmation claim of an encoded CWT claims set.
+ */
+func ConfirmationOf(claims cbor.Object) (cnf Confirmation, e error) {
+	var entries map[any]cbor.RawMessage
+	e = cbor.Unmarshal(claims,&entries)
+	if nil != e {
+		return cnf, fmt.Errorf("%w: %w",ErrorConfirmation,e)
+	}
+	var value, ok = entries[uint8(ClaimCnf)]
+	if !ok {
+		return cnf, fmt.Errorf("%w: cnf",ErrorConfirmation)
+	} else {
+		e = cnf.UnmarshalCBOR(value)
+		return cnf, e
+	}
+}
+/*
+ * Validate the presented key as the proof-of-possession key of
+ * the confirmation, by the public parameters of its key or by
+ * the key identifier.  The private parameters of the presented
+ * key are ignored, and an encrypted key is unsupported.
+ */
+func (this Confirmation) Confirms(key Key) (bool, error) {
+	switch {
+	case nil != this.Key:
+		var k *Key = this.Key
+		return k.Kty == key.Kty && k.Crv == key.Crv && bytes.Equal(k.X,key.X) && bytes.Equal(k.Y,key.Y) &&
+			bytes.Equal(k.N,key.N) && bytes.Equal(k.E,key.E) && bytes.Equal(k.K,key.K), nil
+	case nil != this.Kid:
+		return 0 != len(key.Kid) && bytes.Equal(this.Kid,key.Kid), nil
+	default:
+		return false, ErrorConfirmation
+	}
+}
@@ -0,0 +1,238 @@
+/*
+ * COSE_Key CBOR Representation Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package cosekey
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"github.com/syntelos/go-cbor"
+	"math/big"
+	"testing"
+)
+/*
+ * Private key "meriadoc.brandybuck@buckland.example" of
+ * Appendix C.7.2 [RFC9052].
+ */
+var keyMeriadoc Key = Key{
+	Kty: KtyEC2,
+	Kid: []byte("meriadoc.brandybuck@buckland.example"),
+	Crv: CrvP256,
+	X: unhex("65eda5a12577c2bae829437fe338701a10aaa375e1bb5b5de108de439c08551d"),
+	Y: unhex("1e52ed75701163f7f9e40ddf9f341b3dc9ba860af7e0ca7ca7e9eecd0084d19c"),
+	D: unhex("aff907c99f9ad3aae6c4cdf21122bce2bd68b5283e6907154ad911840fa208cf"),
+}
+
+func unhex(s string) ([]byte) {
+	var b, _ = hex.DecodeString(s)
+	return b
+}
+
+func TestKeyEC2(t *testing.T){
+	var code, e = keyMeriadoc.Encode()
+	if nil != e {
+		t.Fatal(e)
+	} else if 0xA6 != code[0] || 0x01 != code[1] || 0x02 != code[2] {
+		t.Errorf("Expected 'A60102...', found '%X'.",[]byte(code[0:3]))
+	}
+
+	var check Key
+	check, e = Decode(code)
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(keyMeriadoc.D,check.D) || !bytes.Equal(keyMeriadoc.Kid,check.Kid) || CrvP256 != check.Crv {
+		t.Errorf("Expected '%v', found '%v'.",keyMeriadoc,check)
+	}
+
+	var priv any
+	priv, e = check.PrivateKey()
+	if nil != e {
+		t.Fatal(e)
+	}
+	var ecpriv *ecdsa.PrivateKey = priv.(*ecdsa.PrivateKey)
+	var digest []byte = make([]byte,32)
+	var sig []byte
+	sig, e = ecdsa.SignASN1(rand.Reader,ecpriv,digest)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var public Key = check
+	public.D = nil
+	var pub any
+	pub, e = public.PublicKey()
+	if nil != e {
+		t.Fatal(e)
+	} else if !ecdsa.VerifyASN1(pub.(*ecdsa.PublicKey),digest,sig) {
+		t.Error("Expected signature verified by public key.")
+	}
+
+	var wrong Key = check
+	wrong.Y = append([]byte{},check.Y...)
+	wrong.Y[31] ^= 1
+	_, e = wrong.PublicKey()
+	if !errors.Is(e,ErrorKeyMaterial) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorKeyMaterial,e)
+	}
+	_, e = wrong.PrivateKey()
+	if !errors.Is(e,ErrorKeyMaterial) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorKeyMaterial,e)
+	}
+}
+
+func TestKeyRoundTrip(t *testing.T){
+	var ec, _ = ecdsa.GenerateKey(elliptic.P384(),rand.Reader)
+	var _, ed, _ = ed25519.GenerateKey(rand.Reader)
+	var rs, _ = rsa.GenerateKey(rand.Reader,2048)
+	var x, _ = ecdh.X25519().GenerateKey(rand.Reader)
+
+	for _, priv := range []any{ec, ed, rs, x} {
+		var key, e = New(priv)
+		if nil != e {
+			t.Fatal(e)
+		}
+		var code cbor.Object
+		code, e = key.Encode()
+		if nil != e {
+			t.Fatal(e)
+		}
+		var check Key
+		check, e = Decode(code)
+		if nil != e {
+			t.Fatal(e)
+		}
+		var back any
+		back, e = check.PrivateKey()
+		if nil != e {
+			t.Fatalf("%T: %v",priv,e)
+		}
+		var equal bool
+		switch k := priv.(type) {
+		case *ecdsa.PrivateKey:
+			equal = k.Equal(back)
+		case ed25519.PrivateKey:
+			equal = k.Equal(back)
+		case *rsa.PrivateKey:
+			equal = k.Equal(back)
+		case *ecdh.PrivateKey:
+			equal = k.Equal(back)
+		}
+		if !equal {
+			t.Errorf("Expected '%T' round trip, found '%X'.",priv,[]byte(code))
+		}
+
+		var pub any
+		pub, e = check.PublicKey()
+		if nil != e {
+			t.Fatal(e)
+		}
+		var public Key
+		public, e = New(pub)
+		if nil != e {
+			t.Fatal(e)
+		} else if nil != public.D || 0 == len(public.X)+len(public.N) {
+			t.Errorf("Expected public key, found '%v'.",public)
+		}
+	}
+}
+
+func TestKeyRSANotPrecomputed(t *testing.T){
+	var rs, _ = rsa.GenerateKey(rand.Reader,2048)
+	var caller *rsa.PrivateKey = &rsa.PrivateKey{PublicKey: rs.PublicKey, D: rs.D, Primes: rs.Primes}
+	var key, e = New(caller)
+	if nil != e {
+		t.Fatal(e)
+	} else if nil != caller.Precomputed.Dp {
+		t.Errorf("Expected the key of the caller not precomputed.")
+	} else if 0 != rs.Precomputed.Dp.Cmp(new(big.Int).SetBytes(key.DP)) || 0 != rs.Precomputed.Qinv.Cmp(new(big.Int).SetBytes(key.QInv)) {
+		t.Errorf("Expected precomputed values, found '%X' and '%X'.",key.DP,key.QInv)
+	}
+}
+
+func TestKeySymmetric(t *testing.T){
+	var key, e = New([]byte("secret key material"))
+	if nil != e {
+		t.Fatal(e)
+	}
+	var code cbor.Object
+	code, e = key.Encode()
+	if nil != e {
+		t.Fatal(e)
+	}
+	var check Key
+	check, e = Decode(code)
+	if nil != e {
+		t.Fatal(e)
+	} else if KtySymmetric != check.Kty || "secret key material" != string(check.K) {
+		t.Errorf("Expected symmetric key, found '%v'.",check)
+	}
+	_, e = check.PublicKey()
+	if !errors.Is(e,ErrorKeyType) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorKeyType,e)
+	}
+
+	_, e = Decode(cbor.Encode([]int{1}))
+	if !errors.Is(e,ErrorKey) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorKey,e)
+	}
+}
+
+func TestConfirmation(t *testing.T){
+	var public Key = keyMeriadoc
+	public.D = nil
+	var claims cbor.Object = cbor.Encode(map[int]any{1: "coap://as.example.com", ClaimCnf: Confirmation{Key: &public}})
+
+	var cnf, e = ConfirmationOf(claims)
+	if nil != e {
+		t.Fatal(e)
+	} else if nil == cnf.Key || !bytes.Equal(keyMeriadoc.X,cnf.Key.X) {
+		t.Errorf("Expected confirmation key, found '%v'.",cnf)
+	}
+	var ok bool
+	ok, e = cnf.Confirms(keyMeriadoc)
+	if nil != e || !ok {
+		t.Errorf("Expected key confirmed, found '%v' '%v'.",ok,e)
+	}
+	var other, _ = New(ed25519.NewKeyFromSeed(make([]byte,32)))
+	ok, _ = cnf.Confirms(other)
+	if ok {
+		t.Error("Expected other key unconfirmed.")
+	}
+
+	var code []byte
+	code, e = Confirmation{Kid: []byte("dfd1aa97")}.MarshalCBOR()
+	if nil != e {
+		t.Fatal(e)
+	} else if "a1034864666431616139" != hex.EncodeToString(code)[0:20] {
+		t.Errorf("Expected 'a10348...', found '%x'.",code)
+	}
+	cnf = Confirmation{}
+	e = cnf.UnmarshalCBOR(code)
+	if nil != e {
+		t.Fatal(e)
+	}
+	ok, e = cnf.Confirms(keyMeriadoc)
+	if nil != e || ok {
+		t.Errorf("Expected kid unconfirmed, found '%v' '%v'.",ok,e)
+	}
+
+	_, e = Confirmation{}.MarshalCBOR()
+	if !errors.Is(e,ErrorConfirmation) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorConfirmation,e)
+	}
+	_, e = ConfirmationOf(cbor.Encode(map[int]any{1: "coap://as.example.com"}))
+	if !errors.Is(e,ErrorConfirmation) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorConfirmation,e)
+	}
+	e = cnf.UnmarshalCBOR([]byte{0xA1, 0x02, 0x83, 0x40, 0xA0, 0x40})
+	if nil != e || nil == cnf.EncryptedKey {
+		t.Errorf("Expected encrypted key, found '%v'.",e)
+	}
+}
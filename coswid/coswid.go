@@ -0,0 +1,470 @@
+/*
+ * CoSWID CBOR Representation
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc9393#section-2
+ * https://tools.ietf.org/html/rfc9393#section-6.1
+ */
+package coswid
+
+import (
+	"errors"
+	"fmt"
+	"github.com/syntelos/go-cbor"
+	"time"
+)
+/*
+ * Tag number of a tagged CoSWID.  See Section 8 [RFC9393].
+ */
+const TagCoSWID uint64 = 1398229316
+/*
+ * Media type of CoSWID content.
+ */
+const ContentType string = "application/swid+cbor"
+/*
+ * CoAP Content-Format of "application/swid+cbor".
+ */
+const ContentFormat uint16 = 258
+/*
+ * Integer keys of CoSWID map member names.  See Section 6.1
+ * [RFC9393].
+ */
+var Keys map[string]uint64 = map[string]uint64{
+	"tag-id": 0, "software-name": 1, "entity": 2, "evidence": 3,
+	"link": 4, "software-meta": 5, "payload": 6, "hash": 7,
+	"corpus": 8, "patch": 9, "media": 10, "supplemental": 11,
+	"tag-version": 12, "software-version": 13, "version-scheme": 14, "lang": 15,
+	"directory": 16, "file": 17, "process": 18, "resource": 19,
+	"size": 20, "file-version": 21, "key": 22, "location": 23,
+	"fs-name": 24, "root": 25, "path-elements": 26, "process-name": 27,
+	"pid": 28, "type": 29, "entity-name": 31, "reg-id": 32,
+	"role": 33, "thumbprint": 34, "date": 35, "device-id": 36,
+	"artifact": 37, "href": 38, "ownership": 39, "rel": 40,
+	"media-type": 41, "use": 42, "activation-status": 43, "channel-type": 44,
+	"colloquial-version": 45, "description": 46, "edition": 47, "entitlement-data-required": 48,
+	"entitlement-key": 49, "generator": 50, "persistent-id": 51, "product": 52,
+	"product-family": 53, "revision": 54, "summary": 55, "unspsc-code": 56,
+	"unspsc-version": 57,
+}
+/*
+ * Entity role values.  See Section 4.2 [RFC9393].
+ */
+const RoleTagCreator int64 = 1
+const RoleSoftwareCreator int64 = 2
+const RoleAggregator int64 = 3
+const RoleDistributor int64 = 4
+const RoleLicensor int64 = 5
+const RoleMaintainer int64 = 6
+/*
+ * Version scheme values.  See Section 4.1 [RFC9393].
+ */
+const VersionMultipartNumeric uint64 = 1
+const VersionMultipartNumericSuffix uint64 = 2
+const VersionAlphanumeric uint64 = 3
+const VersionDecimal uint64 = 4
+const VersionSemver uint64 = 16384
+/*
+ * Link relation values.  See Section 4.6 [RFC9393].
+ */
+const RelAncestor int64 = 1
+const RelComponent int64 = 2
+const RelFeature int64 = 3
+const RelInstallationMedia int64 = 4
+const RelPackageInstaller int64 = 5
+const RelParent int64 = 6
+const RelPatches int64 = 7
+const RelRequires int64 = 8
+const RelSeeAlso int64 = 9
+const RelSupersedes int64 = 10
+const RelSupplemental int64 = 11
+/*
+ * Validation errors produced by decoding.
+ */
+var ErrorTag error = errors.New("CoSWID tag malformed")
+var ErrorHash error = errors.New("CoSWID hash entry is not [algorithm, value]")
+/*
+ * Key table of <Keys>.
+ */
+var keys *cbor.KeyTable
+/*
+ */
+func init(){
+	var e error
+	keys, e = cbor.NewKeyTable(Keys)
+	if nil != e {
+		panic(e)
+	}
+}
+/*
+ * Hash entry "[algorithm, value]" of a file or thumbprint, by
+ * the algorithm identifiers of the IANA "Named Information Hash
+ * Algorithm Registry", as "1" for "sha-256".
+ */
+type Hash struct {
+
+	Algorithm int64
+
+	Value []byte
+}
+/*
+ * One or more entity roles.
+ */
+type Roles []int64
+/*
+ * Entity of a tag.
+ */
+type Entity struct {
+
+	EntityName string `cbor:"entity-name"`
+
+	RegID string `cbor:"reg-id,omitempty"`
+
+	Role Roles `cbor:"role"`
+
+	Thumbprint *Hash `cbor:"thumbprint,omitempty"`
+}
+/*
+ * One or more entities.
+ */
+type Entities []Entity
+/*
+ * Link of a tag to a resource, having the relation of an
+ * integer value or text string.
+ */
+type Link struct {
+
+	Artifact string `cbor:"artifact,omitempty"`
+
+	Href string `cbor:"href"`
+
+	Media string `cbor:"media,omitempty"`
+
+	Ownership uint64 `cbor:"ownership,omitempty"`
+
+	Rel any `cbor:"rel"`
+
+	MediaType string `cbor:"media-type,omitempty"`
+
+	Use uint64 `cbor:"use,omitempty"`
+}
+/*
+ * One or more links.
+ */
+type Links []Link
+/*
+ * Descriptive metadata of a tag.
+ */
+type SoftwareMeta struct {
+
+	ActivationStatus string `cbor:"activation-status,omitempty"`
+
+	ChannelType string `cbor:"channel-type,omitempty"`
+
+	ColloquialVersion string `cbor:"colloquial-version,omitempty"`
+
+	Description string `cbor:"description,omitempty"`
+
+	Edition string `cbor:"edition,omitempty"`
+
+	EntitlementDataRequired bool `cbor:"entitlement-data-required,omitempty"`
+
+	EntitlementKey string `cbor:"entitlement-key,omitempty"`
+
+	Generator string `cbor:"generator,omitempty"`
+
+	PersistentID string `cbor:"persistent-id,omitempty"`
+
+	Product string `cbor:"product,omitempty"`
+
+	ProductFamily string `cbor:"product-family,omitempty"`
+
+	Revision string `cbor:"revision,omitempty"`
+
+	Summary string `cbor:"summary,omitempty"`
+
+	UNSPSCCode string `cbor:"unspsc-code,omitempty"`
+
+	UNSPSCVersion string `cbor:"unspsc-version,omitempty"`
+}
+/*
+ * One or more software metadata.
+ */
+type SoftwareMetas []SoftwareMeta
+/*
+ * File of a payload or evidence.
+ */
+type File struct {
+
+	Key bool `cbor:"key,omitempty"`
+
+	Location string `cbor:"location,omitempty"`
+
+	FSName string `cbor:"fs-name"`
+
+	Root string `cbor:"root,omitempty"`
+
+	Size uint64 `cbor:"size,omitempty"`
+
+	FileVersion string `cbor:"file-version,omitempty"`
+
+	Hash *Hash `cbor:"hash,omitempty"`
+}
+/*
+ * One or more files.
+ */
+type Files []File
+/*
+ * Directory of a payload or evidence, having its files and
+ * directories as path elements.
+ */
+type Directory struct {
+
+	Key bool `cbor:"key,omitempty"`
+
+	Location string `cbor:"location,omitempty"`
+
+	FSName string `cbor:"fs-name"`
+
+	Root string `cbor:"root,omitempty"`
+
+	PathElements *Resources `cbor:"path-elements,omitempty"`
+}
+/*
+ * One or more directories.
+ */
+type Directories []Directory
+/*
+ * Files and directories of a payload or path elements.
+ */
+type Resources struct {
+
+	Directory Directories `cbor:"directory,omitempty"`
+
+	File Files `cbor:"file,omitempty"`
+}
+/*
+ * Evidence of the software found on a device.
+ */
+type Evidence struct {
+
+	Directory Directories `cbor:"directory,omitempty"`
+
+	File Files `cbor:"file,omitempty"`
+
+	Date *time.Time `cbor:"date,omitempty"`
+
+	DeviceID string `cbor:"device-id,omitempty"`
+}
+/*
+ * Concise software identification tag.  The tag identifier is
+ * a text string or a 16 octet UUID byte string.  Members
+ * unknown to this model are retained in "Extensions", by their
+ * integer keys.
+ */
+type Tag struct {
+
+	TagID any `cbor:"tag-id"`
+
+	SoftwareName string `cbor:"software-name"`
+
+	Entity Entities `cbor:"entity"`
+
+	Evidence *Evidence `cbor:"evidence,omitempty"`
+
+	Link Links `cbor:"link,omitempty"`
+
+	SoftwareMeta SoftwareMetas `cbor:"software-meta,omitempty"`
+
+	Payload *Resources `cbor:"payload,omitempty"`
+
+	Corpus bool `cbor:"corpus,omitempty"`
+
+	Patch bool `cbor:"patch,omitempty"`
+
+	Media string `cbor:"media,omitempty"`
+
+	Supplemental bool `cbor:"supplemental,omitempty"`
+
+	TagVersion uint64 `cbor:"tag-version"`
+
+	SoftwareVersion string `cbor:"software-version,omitempty"`
+
+	VersionScheme uint64 `cbor:"version-scheme,omitempty"`
+
+	Lang string `cbor:"lang,omitempty"`
+
+	Extensions map[any]cbor.RawMessage `cbor:",unknown"`
+}
+/*
+ * Coding of a tag by the reflection of its fields, without
+ * its methods.
+ */
+type tag Tag
+/*
+ * Encode tag as a map with the integer keys of <Keys>, in
+ * deterministic order.
+ */
+func (this Tag) MarshalCBOR() ([]byte, error) {
+	var options cbor.EncOptions = cbor.EncOptionsCoreDet()
+	options.KeyMapper = keys
+	var code, e = options.Encode(tag(this))
+	if nil != e {
+		return nil, e
+	} else {
+		return code, nil
+	}
+}
+/*
+ * Decode tag from a map with the integer keys of <Keys>,
+ * optionally tagged with <TagCoSWID>.
+ */
+func (this *Tag) UnmarshalCBOR(code []byte) (error) {
+	var major, _, arg, z, e = cbor.ParseHead(code)
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorTag,e)
+	} else if cbor.MajorTagged == major {
+		if TagCoSWID != arg {
+			return fmt.Errorf("%w: tag %d",ErrorTag,arg)
+		} else {
+			code = code[z:]
+		}
+	}
+	e = cbor.DecOptions{KeyMapper: keys}.Unmarshal(code,(*tag)(this))
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorTag,e)
+	} else {
+		return nil
+	}
+}
+/*
+ * Encode tag.
+ */
+func (this Tag) Encode() (cbor.Object, error) {
+	var code, e = this.MarshalCBOR()
+	return cbor.Object(code), e
+}
+/*
+ * Decode tag.
+ */
+func Decode(code cbor.Object) (tag Tag, e error) {
+	e = tag.UnmarshalCBOR(code)
+	return tag, e
+}
+/*
+ * Encode hash entry as "[algorithm, value]".
+ */
+func (this Hash) MarshalCBOR() ([]byte, error) {
+	return cbor.Encode([]any{this.Algorithm,this.Value}), nil
+}
+/*
+ * Decode hash entry from "[algorithm, value]".
+ */
+func (this *Hash) UnmarshalCBOR(code []byte) (e error) {
+	var list []cbor.Object
+	list, e = cbor.Object(code).Items()
+	if nil != e || 2 != len(list) {
+		return ErrorHash
+	}
+	this.Algorithm, e = list[0].Int()
+	if nil == e {
+		this.Value, e = list[1].Bytes()
+	}
+	if nil != e {
+		return ErrorHash
+	} else {
+		return nil
+	}
+}
+/*
+ * Encode one role as the role, or more as their array.
+ */
+func (this Roles) MarshalCBOR() ([]byte, error) {
+	return oneOrMore(len(this),[]int64(this))
+}
+/*
+ * Decode role or roles.
+ */
+func (this *Roles) UnmarshalCBOR(code []byte) (error) {
+	return unmarshalOneOrMore(code,(*[]int64)(this))
+}
+/*
+ */
+func (this Entities) MarshalCBOR() ([]byte, error) {
+	return oneOrMore(len(this),[]Entity(this))
+}
+/*
+ */
+func (this *Entities) UnmarshalCBOR(code []byte) (error) {
+	return unmarshalOneOrMore(code,(*[]Entity)(this))
+}
+/*
+ */
+func (this Links) MarshalCBOR() ([]byte, error) {
+	return oneOrMore(len(this),[]Link(this))
+}
+/*
+ */
+func (this *Links) UnmarshalCBOR(code []byte) (error) {
+	return unmarshalOneOrMore(code,(*[]Link)(this))
+}
+/*
+ */
+func (this SoftwareMetas) MarshalCBOR() ([]byte, error) {
+	return oneOrMore(len(this),[]SoftwareMeta(this))
+}
+/*
+ */
+func (this *SoftwareMetas) UnmarshalCBOR(code []byte) (error) {
+	return unmarshalOneOrMore(code,(*[]SoftwareMeta)(this))
+}
+/*
+ */
+func (this Files) MarshalCBOR() ([]byte, error) {
+	return oneOrMore(len(this),[]File(this))
+}
+/*
+ */
+func (this *Files) UnmarshalCBOR(code []byte) (error) {
+	return unmarshalOneOrMore(code,(*[]File)(this))
+}
+/*
+ */
+func (this Directories) MarshalCBOR() ([]byte, error) {
+	return oneOrMore(len(this),[]Directory(this))
+}
+/*
+ */
+func (this *Directories) UnmarshalCBOR(code []byte) (error) {
+	return unmarshalOneOrMore(code,(*[]Directory)(this))
+}
+/*
+ * Encode one of the list as the element, or more as the
+ * array, for the "one-or-more" groups of Section 2.3
+ * [RFC9393].
+ */
+func oneOrMore(n int, list any) ([]byte, error) {
+	var code cbor.Object = cbor.Encode(list)
+	if 1 == n {
+		var items, e = code.Items()
+		if nil != e {
+			return nil, e
+		} else {
+			return items[0], nil
+		}
+	} else {
+		return code, nil
+	}
+}
+/*
+ * Decode one element into the list, or the array of more.
+ */
+func unmarshalOneOrMore(code []byte, list any) (error) {
+	if cbor.MajorArray == cbor.Object(code).Major() {
+		return cbor.Unmarshal(code,list)
+	} else {
+		return cbor.Unmarshal(append([]byte{0x81},code...),list)
+	}
+}
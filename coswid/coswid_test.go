@@ -0,0 +1,113 @@
+/*
+ * CoSWID CBOR Representation Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package coswid
+
+import (
+	"bytes"
+	"errors"
+	"github.com/syntelos/go-cbor"
+	"testing"
+	"time"
+)
+
+func TestTag(t *testing.T){
+	var date time.Time = time.Unix(1700000000,0).UTC()
+	var swid Tag = Tag{
+		TagID: "example.acme.roadrunner-sw-v1-0-0",
+		SoftwareName: "Roadrunner software bundle",
+		SoftwareVersion: "1.0.0",
+		VersionScheme: VersionSemver,
+		TagVersion: 0,
+		Entity: Entities{
+			{EntityName: "ACME Ltd", RegID: "acme.example", Role: Roles{RoleTagCreator, RoleSoftwareCreator}},
+			{EntityName: "Distributor Inc", Role: Roles{RoleDistributor}},
+		},
+		Link: Links{{Href: "https://acme.example/rr", Rel: RelSeeAlso}},
+		SoftwareMeta: SoftwareMetas{{Product: "Roadrunner", Summary: "Detection of roadrunners"}},
+		Payload: &Resources{
+			Directory: Directories{{
+				FSName: "rrdetector",
+				Root: "%programdata%",
+				PathElements: &Resources{File: Files{{FSName: "rrdetector.exe", Size: 532712, Hash: &Hash{1, bytes.Repeat([]byte{0xA3},32)}}}},
+			}},
+		},
+		Evidence: &Evidence{Date: &date, DeviceID: "device-17"},
+	}
+	var code, e = swid.Encode()
+	if nil != e {
+		t.Fatal(e)
+	} else if 0xAA != code[0] || 0x00 != code[1] {
+		t.Errorf("Expected 'AA00...', found '%X'.",[]byte(code[0:2]))
+	} else if e = code.ConformsCoreDet(); nil != e {
+		t.Errorf("Expected deterministic encoding, found '%v'.",e)
+	} else if bytes.Contains(code,[]byte("software-name")) {
+		t.Errorf("Expected integer keys, found '%s'.",code.String())
+	}
+
+	var check Tag
+	check, e = Decode(code)
+	if nil != e {
+		t.Fatal(e)
+	}
+	if "example.acme.roadrunner-sw-v1-0-0" != check.TagID || "Roadrunner software bundle" != check.SoftwareName || VersionSemver != check.VersionScheme {
+		t.Errorf("Expected '%v', found '%v'.",swid,check)
+	}
+	if 2 != len(check.Entity) || 2 != len(check.Entity[0].Role) || 1 != len(check.Entity[1].Role) || RoleDistributor != check.Entity[1].Role[0] {
+		t.Errorf("Expected entities, found '%v'.",check.Entity)
+	}
+	if 1 != len(check.Link) || "https://acme.example/rr" != check.Link[0].Href || 1 != len(check.SoftwareMeta) {
+		t.Errorf("Expected link and meta, found '%v' '%v'.",check.Link,check.SoftwareMeta)
+	}
+	if nil == check.Payload || 1 != len(check.Payload.Directory) || nil == check.Payload.Directory[0].PathElements {
+		t.Fatalf("Expected payload, found '%v'.",check.Payload)
+	}
+	var file File = check.Payload.Directory[0].PathElements.File[0]
+	if "rrdetector.exe" != file.FSName || 532712 != file.Size || nil == file.Hash || 1 != file.Hash.Algorithm || 32 != len(file.Hash.Value) {
+		t.Errorf("Expected file, found '%v'.",file)
+	}
+	if nil == check.Evidence || nil == check.Evidence.Date || !date.Equal(*check.Evidence.Date) {
+		t.Errorf("Expected evidence, found '%v'.",check.Evidence)
+	}
+
+	var again cbor.Object
+	again, e = check.Encode()
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(code,again) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(code),[]byte(again))
+	}
+}
+
+func TestTagDecode(t *testing.T){
+	/*
+	 * 1398229316({0: h'00..', 1: "x", 2: {31: "e", 33: 1}, 12: 3, 99: "ext"})
+	 */
+	var code []byte = []byte{0xDA, 0x53, 0x57, 0x49, 0x44}
+	code = append(code,cbor.Encode(map[int]any{
+		0: make([]byte,16),
+		1: "x",
+		2: map[int]any{31: "e", 33: 1},
+		12: 3,
+		99: "ext",
+	})...)
+	var check, e = Decode(code)
+	if nil != e {
+		t.Fatal(e)
+	}
+	if id, ok := check.TagID.([]byte); !ok || 16 != len(id) {
+		t.Errorf("Expected UUID tag-id, found '%v'.",check.TagID)
+	}
+	if 1 != len(check.Entity) || "e" != check.Entity[0].EntityName || 1 != len(check.Entity[0].Role) || RoleTagCreator != check.Entity[0].Role[0] {
+		t.Errorf("Expected one entity, found '%v'.",check.Entity)
+	}
+	if 3 != check.TagVersion || 1 != len(check.Extensions) {
+		t.Errorf("Expected tag version and extension, found '%v'.",check)
+	}
+
+	_, e = Decode([]byte{0xC1, 0xA0})
+	if !errors.Is(e,ErrorTag) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorTag,e)
+	}
+}
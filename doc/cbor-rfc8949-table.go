@@ -10,6 +10,7 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"strings"
 )
 /*
  */
@@ -18,10 +19,12 @@ func usage(){
 Synopsis
 
     table [print|enumerate|list]
+    table generate <file.go>
 
 Description
 
-    Read table, and print or enumerate.
+    Read table, and print or enumerate, or generate the head
+    and tag number constants of package cbor.
 
 `)
 	os.Exit(1)
@@ -139,6 +142,115 @@ func (this Line) list(){
 		}
 	}
 }
+/*
+ * Major type names of constants by description prefix.
+ */
+var generateMajor [][2]string = [][2]string{
+	{"unsigned integer","Uint"},
+	{"negative integer","Sint"},
+	{"byte string","Blob"},
+	{"UTF-8 string","Text"},
+	{"array","Array"},
+	{"map","Map"},
+	{"(more tags","Tag"},
+	{"(simple value","Simple"},
+	{"half-precision float","Float"},
+	{"single-precision float","Float"},
+	{"double-precision float","Float"},
+}
+/*
+ * Argument widths of constants by description.
+ */
+var generateWidth [][2]string = [][2]string{
+	{"one-byte","8"},
+	{"one byte","8"},
+	{"two-byte","16"},
+	{"four-byte","32"},
+	{"eight-byte","64"},
+	{"1/2/4/8 bytes","8"},
+}
+/*
+ * Tag numbers of the tag rows, which the table describes
+ * without a name.
+ */
+var generateTag map[uint8]string = map[uint8]string{
+	0xC0: "DateTimeString",
+	0xC1: "DateTimeEpoch",
+	0xC2: "UnsignedBignum",
+	0xC3: "NegativeBignum",
+	0xC4: "DecimalFraction",
+	0xC5: "Bigfloat",
+	0xD5: "ExpectBase64URL",
+	0xD6: "ExpectBase64",
+	0xD7: "ExpectBase16",
+}
+/*
+ * Write the constant declaration of name and value.
+ */
+func declare(w io.Writer, description, name, typ string, value uint64){
+	fmt.Fprintf(w,"/*\n * %s\n */\n",description)
+	if "Tag" == typ {
+		fmt.Fprintf(w,"const %s %s = 0x%02X\n",name,typ,value)
+	} else {
+		fmt.Fprintf(w,"const %s %s = %d\n",name,typ,value)
+	}
+}
+/*
+ * Write the head constants (type Tag), and the tag number
+ * constants (type uint64), of the line.
+ */
+func (this Line) generate(w io.Writer){
+	var major string
+	for _, prefix := range generateMajor {
+		if strings.HasPrefix(this.description,prefix[0]) {
+			major = prefix[1]
+			break
+		}
+	}
+	var width string
+	for _, follow := range generateWidth {
+		if strings.Contains(this.description,follow[0]) {
+			width = follow[1]
+			break
+		}
+	}
+	if 0xC0 <= this.first && 0xD7 >= this.last {
+		if 0xC0 == this.first {
+			declare(w,"tag (data item follows)","HeadTag","Tag",uint64(this.first))
+		}
+		var x uint8
+		for x = this.first; x <= this.last; x++ {
+			var name, ok = generateTag[x]
+			if ok {
+				declare(w,this.description,"Tag"+name,"uint64",uint64(x-0xC0))
+			}
+		}
+	} else if "Tag" == major {
+		var x uint8
+		for x = this.first; x <= this.last; x++ {
+			declare(w,this.description,"HeadTag"+width+"Follow","Tag",uint64(x))
+			width = strconv.Itoa(2*atoi(width))
+		}
+	} else if "" != major && this.first != this.last {
+		declare(w,this.description,"Head"+major,"Tag",uint64(this.first))
+	} else if "Float" == major {
+		declare(w,this.description,"Head"+major+width,"Tag",uint64(this.first))
+	} else if "" != major && strings.Contains(this.description,"'break'") {
+		declare(w,this.description,"Head"+major+"Indefinite","Tag",uint64(this.first))
+	} else if "" != major {
+		declare(w,this.description,"Head"+major+width+"Follow","Tag",uint64(this.first))
+	} else if this.first == this.last {
+		var name string = strings.Fields(this.description)[0]
+		name = strings.Trim(name,"'")
+		declare(w,this.description,"Head"+strings.ToUpper(name[0:1])+name[1:],"Tag",uint64(this.first))
+	}
+}
+/*
+ */
+func atoi(s string) (int) {
+	var n, _ = strconv.Atoi(s)
+	return n
+}
 /*
  */
 type Table struct {
@@ -215,6 +327,26 @@ func (this *Table) list(){
 		this.records[index].list()
 	}
 }
+func (this *Table) generate(filename string) (e error){
+	var file *os.File
+	file, e = os.Create(filename)
+	if nil != e {
+		return fmt.Errorf("Error creating '%s': %w",filename,e)
+	} else {
+		defer file.Close()
+
+		fmt.Fprintf(file,"// Code generated by doc/cbor-rfc8949-table.go from %s; DO NOT EDIT.\n\n",location_doc)
+		fmt.Fprintf(file,"/*\n * CBOR RFC8949 Head and Tag Number Constants\n * Copyright 2023 John Douglas Pritchard, Syntelos\n *\n *\n * References\n *\n * https://tools.ietf.org/html/rfc8949#appendix-B\n */\n")
+		fmt.Fprintf(file,"package cbor\n\n")
+
+		var count int = table.size()
+		var index int = 0
+		for ; index < count; index++ {
+			this.records[index].generate(file)
+		}
+		return nil
+	}
+}
 /*
  */
 const location_rel string = "cbor-rfc8949-table.txt"
@@ -287,6 +419,20 @@ func main(){
 					os.Exit(0)
 				}
 
+			case "generate":
+				e := table.read(fin)
+				if nil == e && (argx+1) < argc {
+					e = table.generate(os.Args[argx+1])
+				} else if nil == e {
+					usage()
+				}
+				if nil != e {
+					fmt.Fprintf(os.Stderr,"table: %v\n",e);
+					os.Exit(1)
+				} else {
+					os.Exit(0)
+				}
+
 			default:
 				usage()
 			}
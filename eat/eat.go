@@ -0,0 +1,477 @@
+/*
+ * EAT CBOR Representation
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc9711#section-4
+ * https://tools.ietf.org/html/rfc8392#section-3
+ * https://tools.ietf.org/html/rfc9052#section-4.2
+ */
+package eat
+
+import (
+	"errors"
+	"fmt"
+	"github.com/syntelos/go-cbor"
+	"sort"
+)
+/*
+ * Media type of EAT CWT content.
+ */
+const ContentType string = "application/eat+cwt"
+/*
+ * Tag numbers of the CWT, "COSE_Mac0" and "COSE_Sign1"
+ * envelopes of a token.
+ */
+const TagCWT uint64 = 61
+const TagCOSEMac0 uint64 = 17
+const TagCOSESign1 uint64 = 18
+/*
+ * CWT claim keys.  See Section 3.1 [RFC8392].
+ */
+const ClaimIssuer int = 1
+const ClaimSubject int = 2
+const ClaimAudience int = 3
+const ClaimExpiration int = 4
+const ClaimNotBefore int = 5
+const ClaimIssuedAt int = 6
+const ClaimCWTID int = 7
+/*
+ * EAT claim keys.  See Section 10.2 [RFC9711].
+ */
+const ClaimNonce int = 10
+const ClaimUEID int = 256
+const ClaimOEMID int = 258
+const ClaimHWModel int = 259
+const ClaimHWVersion int = 260
+const ClaimUptime int = 261
+const ClaimOEMBoot int = 262
+const ClaimDbgStat int = 263
+const ClaimProfile int = 265
+const ClaimSubmodules int = 266
+const ClaimBootCount int = 267
+const ClaimBootSeed int = 268
+const ClaimSWName int = 270
+const ClaimSWVersion int = 271
+const ClaimMeasurements int = 273
+/*
+ * Debug status of claim "dbgstat".  See Section 4.2.9
+ * [RFC9711].
+ */
+type DebugStatus uint8
+/*
+ * Debug is enabled.
+ */
+const DebugEnabled DebugStatus = 0
+/*
+ * Debug is disabled.
+ */
+const DebugDisabled DebugStatus = 1
+/*
+ * Debug is disabled, and has been since boot.
+ */
+const DebugDisabledSinceBoot DebugStatus = 2
+/*
+ * Debug is disabled permanently.
+ */
+const DebugDisabledPermanently DebugStatus = 3
+/*
+ * Debug is disabled permanently, for all parties.
+ */
+const DebugDisabledFullyAndPermanently DebugStatus = 4
+/*
+ * Validation errors produced by decoding.
+ */
+var ErrorClaims error = errors.New("EAT claims set is not a map")
+var ErrorClaim error = errors.New("EAT claim value invalid")
+var ErrorToken error = errors.New("EAT token envelope unrecognized or payload detached")
+/*
+ * Measurement of claim "measurements", the content of a
+ * CoAP Content-Format, as a CoSWID.
+ */
+type Measurement struct {
+
+	ContentFormat uint16
+
+	Content []byte
+}
+/*
+ * Submodule of claim "submods", as a claims set, or as the
+ * encoded data item of a nested token or detached digest.
+ */
+type Submodule struct {
+
+	Claims *Claims
+
+	Token cbor.RawMessage
+}
+/*
+ * EAT claims set.  Zero fields are absent, and "Nonce" has one
+ * or more nonces.  The "OEMID" is the random or IEEE form, and
+ * "OEMPEN" the IANA private enterprise number form.  Claims
+ * unknown to this package are retained in "Extra".
+ */
+type Claims struct {
+
+	Issuer string
+
+	Subject string
+
+	Audience string
+
+	Expiration int64
+
+	NotBefore int64
+
+	IssuedAt int64
+
+	CWTID []byte
+
+	Nonce [][]byte
+
+	UEID []byte
+
+	OEMID []byte
+
+	OEMPEN uint64
+
+	HWModel []byte
+
+	HWVersion string
+
+	Uptime uint64
+
+	OEMBoot *bool
+
+	DbgStat *DebugStatus
+
+	Profile string
+
+	Submodules map[string]Submodule
+
+	BootCount uint64
+
+	BootSeed []byte
+
+	SWName string
+
+	SWVersion string
+
+	Measurements []Measurement
+
+	Extra map[int]cbor.RawMessage
+}
+/*
+ * Encode claims set as a map with integer keys in deterministic
+ * order, omitting zero fields.
+ */
+func (this Claims) MarshalCBOR() ([]byte, error) {
+	var claims map[int]any = map[int]any{}
+	for key, value := range this.Extra {
+		claims[key] = value
+	}
+	var add = func(key int, value any, present bool) {
+		if present {
+			claims[key] = value
+		}
+	}
+	add(ClaimIssuer,this.Issuer,"" != this.Issuer)
+	add(ClaimSubject,this.Subject,"" != this.Subject)
+	add(ClaimAudience,this.Audience,"" != this.Audience)
+	add(ClaimExpiration,this.Expiration,0 != this.Expiration)
+	add(ClaimNotBefore,this.NotBefore,0 != this.NotBefore)
+	add(ClaimIssuedAt,this.IssuedAt,0 != this.IssuedAt)
+	add(ClaimCWTID,this.CWTID,nil != this.CWTID)
+	if 1 == len(this.Nonce) {
+		claims[ClaimNonce] = this.Nonce[0]
+	} else {
+		add(ClaimNonce,this.Nonce,0 != len(this.Nonce))
+	}
+	add(ClaimUEID,this.UEID,nil != this.UEID)
+	add(ClaimOEMID,this.OEMID,nil != this.OEMID)
+	add(ClaimOEMID,this.OEMPEN,nil == this.OEMID && 0 != this.OEMPEN)
+	add(ClaimHWModel,this.HWModel,nil != this.HWModel)
+	add(ClaimHWVersion,[]any{this.HWVersion},"" != this.HWVersion)
+	add(ClaimUptime,this.Uptime,0 != this.Uptime)
+	if nil != this.OEMBoot {
+		claims[ClaimOEMBoot] = *this.OEMBoot
+	}
+	if nil != this.DbgStat {
+		claims[ClaimDbgStat] = uint8(*this.DbgStat)
+	}
+	add(ClaimProfile,this.Profile,"" != this.Profile)
+	if 0 != len(this.Submodules) {
+		var submods map[string]any = map[string]any{}
+		for name, submod := range this.Submodules {
+			if nil != submod.Claims {
+				submods[name] = *submod.Claims
+			} else if nil != submod.Token {
+				submods[name] = submod.Token
+			} else {
+				return nil, fmt.Errorf("%w: submodule %s",ErrorClaim,name)
+			}
+		}
+		claims[ClaimSubmodules] = submods
+	}
+	add(ClaimBootCount,this.BootCount,0 != this.BootCount)
+	add(ClaimBootSeed,this.BootSeed,nil != this.BootSeed)
+	add(ClaimSWName,this.SWName,"" != this.SWName)
+	add(ClaimSWVersion,[]any{this.SWVersion},"" != this.SWVersion)
+	add(ClaimMeasurements,this.Measurements,0 != len(this.Measurements))
+
+	var options cbor.EncOptions = cbor.EncOptionsCoreDet()
+	var code, e = options.Encode(claims)
+	if nil != e {
+		return nil, e
+	} else {
+		return code, nil
+	}
+}
+/*
+ * Decode claims set from a map with integer keys.  Text keys
+ * are ignored.
+ */
+func (this *Claims) UnmarshalCBOR(code []byte) (e error) {
+	var entries map[any]cbor.RawMessage
+	e = cbor.Unmarshal(code,&entries)
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorClaims,e)
+	}
+	*this = Claims{}
+	for key, value := range entries {
+		var claim, ok = label(key)
+		if !ok {
+			continue
+		}
+		var target any
+		switch claim {
+		case ClaimIssuer:
+			target = &this.Issuer
+		case ClaimSubject:
+			target = &this.Subject
+		case ClaimAudience:
+			target = &this.Audience
+		case ClaimExpiration:
+			target = &this.Expiration
+		case ClaimNotBefore:
+			target = &this.NotBefore
+		case ClaimIssuedAt:
+			target = &this.IssuedAt
+		case ClaimCWTID:
+			target = &this.CWTID
+		case ClaimNonce:
+			if cbor.MajorBlob == cbor.Object(value).Major() {
+				this.Nonce = [][]byte{nil}
+				target = &this.Nonce[0]
+			} else {
+				target = &this.Nonce
+			}
+		case ClaimUEID:
+			target = &this.UEID
+		case ClaimOEMID:
+			if cbor.MajorUint == cbor.Object(value).Major() {
+				target = &this.OEMPEN
+			} else {
+				target = &this.OEMID
+			}
+		case ClaimHWModel:
+			target = &this.HWModel
+		case ClaimHWVersion:
+			this.HWVersion, e = version(value)
+		case ClaimUptime:
+			target = &this.Uptime
+		case ClaimOEMBoot:
+			target = &this.OEMBoot
+		case ClaimDbgStat:
+			target = &this.DbgStat
+		case ClaimProfile:
+			target = &this.Profile
+		case ClaimSubmodules:
+			this.Submodules, e = submodules(value)
+		case ClaimBootCount:
+			target = &this.BootCount
+		case ClaimBootSeed:
+			target = &this.BootSeed
+		case ClaimSWName:
+			target = &this.SWName
+		case ClaimSWVersion:
+			this.SWVersion, e = version(value)
+		case ClaimMeasurements:
+			target = &this.Measurements
+		default:
+			if nil == this.Extra {
+				this.Extra = map[int]cbor.RawMessage{}
+			}
+			this.Extra[claim] = value
+		}
+		if nil != target {
+			e = cbor.Unmarshal(value,target)
+		}
+		if nil != e {
+			return fmt.Errorf("%w: %d: %w",ErrorClaim,claim,e)
+		}
+	}
+	return nil
+}
+/*
+ * Encode measurement as "[content-type, content]".
+ */
+func (this Measurement) MarshalCBOR() ([]byte, error) {
+	return cbor.Encode([]any{this.ContentFormat,this.Content}), nil
+}
+/*
+ * Decode measurement from "[content-type, content]".
+ */
+func (this *Measurement) UnmarshalCBOR(code []byte) (e error) {
+	var list []cbor.Object
+	list, e = cbor.Object(code).Items()
+	if nil != e {
+		return e
+	} else if 2 != len(list) {
+		return ErrorClaim
+	}
+	var format uint64
+	format, e = list[0].Uint()
+	if nil != e {
+		return e
+	} else if 0xFFFF < format {
+		return ErrorClaim
+	}
+	this.ContentFormat = uint16(format)
+	this.Content, e = list[1].Bytes()
+	return e
+}
+/*
+ * Encode claims set.
+ */
+func (this Claims) Encode() (cbor.Object, error) {
+	var code, e = this.MarshalCBOR()
+	return cbor.Object(code), e
+}
+/*
+ * Decode the claims set of a token, being the claims set map,
+ * or its (tagged) "COSE_Sign1" or "COSE_Mac0", optionally
+ * tagged as a CWT.  The signature or MAC is not verified, see
+ * <Payload>.
+ */
+func Decode(token cbor.Object) (claims Claims, e error) {
+	var payload cbor.Object
+	payload, e = Payload(token)
+	if nil == e {
+		e = claims.UnmarshalCBOR(payload)
+	}
+	return claims, e
+}
+/*
+ * Resolve the claims set of a token, being the claims set map,
+ * or the embedded payload of its (tagged) "COSE_Sign1" or
+ * "COSE_Mac0", optionally tagged as a CWT.  The verification of
+ * the envelope is the user's, as by <cbor.SigStructure>.
+ */
+func Payload(token cbor.Object) (cbor.Object, error) {
+	for {
+		var major, _, arg, z, e = cbor.ParseHead(token)
+		if nil != e {
+			return nil, e
+		} else if cbor.MajorTagged != major {
+			break
+		}
+		switch arg {
+		case TagCWT, TagCOSESign1, TagCOSEMac0:
+			token = token[z:]
+		default:
+			return nil, fmt.Errorf("%w: tag %d",ErrorToken,arg)
+		}
+	}
+	switch token.Major() {
+	case cbor.MajorMap:
+		return token, nil
+	case cbor.MajorArray:
+		var list, e = token.Items()
+		if nil != e {
+			return nil, e
+		} else if 4 != len(list) || cbor.MajorBlob != list[2].Major() {
+			return nil, ErrorToken
+		}
+		var payload []byte
+		payload, e = list[2].Bytes()
+		if nil != e {
+			return nil, e
+		} else {
+			return cbor.Object(payload), nil
+		}
+	default:
+		return nil, ErrorToken
+	}
+}
+/*
+ * Names of submodules in order.
+ */
+func (this Claims) SubmoduleNames() (names []string) {
+	for name := range this.Submodules {
+		names = append(names,name)
+	}
+	sort.Strings(names)
+	return names
+}
+/*
+ * Decode submodules, as claims sets or encoded tokens.
+ */
+func submodules(code cbor.RawMessage) (map[string]Submodule, error) {
+	var entries map[string]cbor.RawMessage
+	var e error = cbor.Unmarshal(code,&entries)
+	if nil != e {
+		return nil, e
+	}
+	var submods map[string]Submodule = map[string]Submodule{}
+	for name, value := range entries {
+		if cbor.MajorMap == cbor.Object(value).Major() {
+			var claims Claims
+			e = claims.UnmarshalCBOR(value)
+			if nil != e {
+				return nil, fmt.Errorf("submodule %s: %w",name,e)
+			} else {
+				submods[name] = Submodule{Claims: &claims}
+			}
+		} else {
+			submods[name] = Submodule{Token: value}
+		}
+	}
+	return submods, nil
+}
+/*
+ * Decode the version text of "[version, ?scheme]".
+ */
+func version(code cbor.RawMessage) (string, error) {
+	var list, e = cbor.Object(code).Items()
+	if nil != e {
+		return "", e
+	} else if 1 > len(list) || 2 < len(list) {
+		return "", ErrorClaim
+	} else {
+		return list[0].Text()
+	}
+}
+/*
+ * Resolve the integer of a decoded map key.
+ */
+func label(key any) (int, bool) {
+	switch k := key.(type) {
+	case uint8:
+		return int(k), true
+	case uint16:
+		return int(k), true
+	case uint32:
+		return int(k), true
+	case int:
+		return k, true
+	case int32:
+		return int(k), true
+	case int64:
+		return int(k), true
+	default:
+		return 0, false
+	}
+}
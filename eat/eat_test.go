@@ -0,0 +1,103 @@
+/*
+ * EAT CBOR Representation Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package eat
+
+import (
+	"bytes"
+	"errors"
+	"github.com/syntelos/go-cbor"
+	"testing"
+)
+
+func TestClaims(t *testing.T){
+	var boot bool = true
+	var dbg DebugStatus = DebugDisabledSinceBoot
+	var claims Claims = Claims{
+		Issuer: "device.example",
+		IssuedAt: 1700000000,
+		Nonce: [][]byte{[]byte("0123456789abcdef")},
+		UEID: []byte{0x01, 0xDE, 0xAD, 0xBE, 0xEF, 0xDE, 0xAD, 0xBE, 0xEF},
+		OEMPEN: 76543,
+		HWVersion: "3.1",
+		OEMBoot: &boot,
+		DbgStat: &dbg,
+		Measurements: []Measurement{{ContentFormat: 258, Content: []byte{0xA0}}},
+		Submodules: map[string]Submodule{
+			"radio": {Claims: &Claims{SWName: "baseband", SWVersion: "1.2.3"}},
+			"tee": {Token: cbor.RawMessage(cbor.Encode([]any{-16, []byte{1, 2, 3}}))},
+		},
+		Extra: map[int]cbor.RawMessage{-70000: cbor.RawMessage(cbor.Encode("private"))},
+	}
+	var payload, e = claims.Encode()
+	if nil != e {
+		t.Fatal(e)
+	} else if e = payload.ConformsCoreDet(); nil != e {
+		t.Errorf("Expected deterministic encoding, found '%v'.",e)
+	}
+	/*
+	 * 61(18([h'A10126', {}, payload, signature]))
+	 */
+	var token []byte = []byte{0xD8, 0x3D, 0xD2}
+	token = append(token,cbor.Encode([]any{[]byte{0xA1, 0x01, 0x26}, map[int]int{}, []byte(payload), make([]byte,64)})...)
+
+	var check Claims
+	check, e = Decode(token)
+	if nil != e {
+		t.Fatal(e)
+	}
+	if "device.example" != check.Issuer || 1700000000 != check.IssuedAt || 76543 != check.OEMPEN || "3.1" != check.HWVersion {
+		t.Errorf("Expected '%v', found '%v'.",claims,check)
+	}
+	if 1 != len(check.Nonce) || !bytes.Equal(claims.Nonce[0],check.Nonce[0]) || !bytes.Equal(claims.UEID,check.UEID) {
+		t.Errorf("Expected nonce and ueid, found '%v'.",check)
+	}
+	if nil == check.OEMBoot || !*check.OEMBoot || nil == check.DbgStat || DebugDisabledSinceBoot != *check.DbgStat {
+		t.Errorf("Expected oemboot and dbgstat, found '%v'.",check)
+	}
+	if 1 != len(check.Measurements) || 258 != check.Measurements[0].ContentFormat {
+		t.Errorf("Expected measurements, found '%v'.",check.Measurements)
+	}
+	var names []string = check.SubmoduleNames()
+	if 2 != len(names) || "radio" != names[0] {
+		t.Errorf("Expected '[radio tee]', found '%v'.",names)
+	} else if nil == check.Submodules["radio"].Claims || "1.2.3" != check.Submodules["radio"].Claims.SWVersion {
+		t.Errorf("Expected submodule claims, found '%v'.",check.Submodules["radio"])
+	} else if nil == check.Submodules["tee"].Token {
+		t.Errorf("Expected submodule token, found '%v'.",check.Submodules["tee"])
+	}
+	if 1 != len(check.Extra) || "private" != cbor.Object(check.Extra[-70000]).MustText() {
+		t.Errorf("Expected extra claim, found '%v'.",check.Extra)
+	}
+
+	var again cbor.Object
+	again, e = check.Encode()
+	if nil != e {
+		t.Fatal(e)
+	} else if !bytes.Equal(payload,again) {
+		t.Errorf("Expected '%X', found '%X'.",[]byte(payload),[]byte(again))
+	}
+}
+
+func TestPayload(t *testing.T){
+	var _, e = Payload(cbor.Encode([]any{[]byte{}, map[int]int{}, nil, []byte{}}))
+	if !errors.Is(e,ErrorToken) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorToken,e)
+	}
+	_, e = Payload([]byte{0xD8, 0x62, 0xA0})
+	if !errors.Is(e,ErrorToken) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorToken,e)
+	}
+	var claims Claims
+	claims, e = Decode(cbor.Encode(map[int]any{ClaimNonce: [][]byte{{1}, {2}}}))
+	if nil != e {
+		t.Fatal(e)
+	} else if 2 != len(claims.Nonce) {
+		t.Errorf("Expected two nonces, found '%v'.",claims.Nonce)
+	}
+	_, e = Decode(cbor.Encode(map[int]any{ClaimUptime: "long"}))
+	if !errors.Is(e,ErrorClaim) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorClaim,e)
+	}
+}
@@ -2,7 +2,6 @@ module github.com/syntelos/go-cbor
 
 go 1.20
 
-require (
-	github.com/syntelos/go-endian v0.0.0-20231216185931-3b37b1ee7029 // indirect
-	golang.org/x/sys v0.15.0 // indirect
-)
+require github.com/syntelos/go-endian v0.0.0-20231216185931-3b37b1ee7029
+
+require golang.org/x/sys v0.15.0 // indirect
@@ -0,0 +1,71 @@
+/*
+ * CBOR gRPC
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://pkg.go.dev/google.golang.org/grpc/encoding#Codec
+ * https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-HTTP2.md
+ */
+package grpccbor
+
+import (
+	"bytes"
+	"errors"
+	"github.com/syntelos/go-cbor"
+)
+/*
+ * Content subtype of gRPC messages, as in
+ * "application/grpc+cbor".
+ */
+const Name string = "cbor"
+/*
+ * Validation errors produced by <Codec#Unmarshal>.
+ */
+var ErrorMessageContent error = errors.New("gRPC CBOR message is not one data item")
+/*
+ * Message codec satisfying "encoding.Codec" of package
+ * "google.golang.org/grpc/encoding", without depending on
+ * that package.  Register with
+ *
+ *     encoding.RegisterCodec(grpccbor.Codec{})
+ *
+ * and select with "grpc.CallContentSubtype(grpccbor.Name)".
+ */
+type Codec struct {
+
+	Options cbor.EncOptions
+}
+/*
+ * Encode message.
+ */
+func (this Codec) Marshal(v any) ([]byte, error) {
+	var o, e = this.Options.Encode(v)
+	if nil != e {
+		return nil, e
+	} else {
+		return o, nil
+	}
+}
+/*
+ * Decode message, which must be exactly one data item, into
+ * the value referenced by pointer.
+ */
+func (this Codec) Unmarshal(data []byte, v any) (e error) {
+	var r *bytes.Reader = bytes.NewReader(data)
+	e = cbor.NewDecoder(r).Decode(v)
+	if nil != e {
+		return e
+	} else if 0 != r.Len() {
+		return ErrorMessageContent
+	} else {
+		return nil
+	}
+}
+/*
+ * Content subtype of codec.
+ */
+func (this Codec) Name() (string) {
+	return Name
+}
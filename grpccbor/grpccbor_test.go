@@ -0,0 +1,52 @@
+/*
+ * CBOR gRPC Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package grpccbor
+
+import (
+	"testing"
+)
+
+type TypeTestRequest struct {
+
+	Method string `cbor:"method"`
+
+	Count uint32 `cbor:"count"`
+}
+/*
+ * Method set of "encoding.Codec".
+ */
+type codec interface {
+
+	Marshal(v any) ([]byte, error)
+
+	Unmarshal(data []byte, v any) (error)
+
+	Name() (string)
+}
+
+func TestCodec(t *testing.T){
+	var c codec = Codec{}
+	if "cbor" != c.Name() {
+		t.Errorf("Expected 'cbor', found '%s'.",c.Name())
+	}
+
+	var request TypeTestRequest = TypeTestRequest{"Echo",3}
+	var data, e = c.Marshal(request)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var check TypeTestRequest
+	e = c.Unmarshal(data,&check)
+	if nil != e {
+		t.Fatal(e)
+	} else if request != check {
+		t.Errorf("Expected '%v', found '%v'.",request,check)
+	}
+
+	e = c.Unmarshal(append(data,0x00),&check)
+	if ErrorMessageContent != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorMessageContent,e)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * CBOR HTTP
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8949#section-9.1
+ * https://tools.ietf.org/html/rfc9110#section-12.5.1
+ */
+package httpcbor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/syntelos/go-cbor"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+/*
+ * Media type of CBOR message content.
+ */
+const ContentType string = "application/cbor"
+/*
+ * Upper bound on request body octet count accepted by
+ * <DecodeRequest>.
+ */
+var BodySizeMax int64 = (1024*1024)
+/*
+ * Validation errors produced by <DecodeRequest>.
+ */
+var ErrorContentType error = errors.New("HTTP CBOR Content-Type is not "+ContentType)
+var ErrorBodySize error = fmt.Errorf("HTTP CBOR body exceeds size maximum: %w",cbor.ErrorSizeExceeded)
+var ErrorBodyContent error = errors.New("HTTP CBOR body is not one data item")
+/*
+ * Determine whether the request "Accept" header permits a CBOR
+ * response.  An absent header accepts any media type.
+ */
+func Accepts(r *http.Request) (bool) {
+	var accept []string = r.Header.Values("Accept")
+	if 0 == len(accept) {
+		return true
+	} else {
+		for _, header := range accept {
+			for _, field := range strings.Split(header,",") {
+				var mediatype string
+				var params map[string]string
+				var e error
+				mediatype, params, e = mime.ParseMediaType(field)
+				if nil == e && acceptable(params) {
+					switch mediatype {
+					case ContentType, "application/*", "*/*":
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+}
+/*
+ * Determine whether the quality value ("q") of a media range
+ * is not zero, which is "not acceptable" (Section 12.4.2
+ * [RFC9110]).  An absent quality value is one, and an invalid
+ * quality value is not acceptable.
+ */
+func acceptable(params map[string]string) (bool) {
+	var q, ok = params["q"]
+	if !ok {
+		return true
+	} else {
+		var quality, e = strconv.ParseFloat(q,64)
+		return (nil == e && 0 < quality && 1 >= quality)
+	}
+}
+/*
+ * Write value as a CBOR response body.
+ */
+func EncodeResponse(w http.ResponseWriter, v any) (e error) {
+	var o cbor.Object = cbor.Encode(v)
+
+	w.Header().Set("Content-Type",ContentType)
+	w.Header().Set("Content-Length",fmt.Sprint(len(o)))
+	w.WriteHeader(http.StatusOK)
+
+	return o.Write(w)
+}
+/*
+ * Read a CBOR request body into the value referenced by
+ * pointer.  The request "Content-Type" must be CBOR, and the
+ * body is limited by "Content-Length" and <BodySizeMax>.
+ */
+func DecodeRequest(r *http.Request, v any) (e error) {
+	var mediatype string
+	mediatype, _, e = mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if nil != e || ContentType != mediatype {
+		return ErrorContentType
+
+	} else if BodySizeMax < r.ContentLength {
+		return ErrorBodySize
+
+	} else {
+		var limit int64 = BodySizeMax
+		if 0 <= r.ContentLength {
+			limit = r.ContentLength
+		}
+		var body []byte
+		body, e = io.ReadAll(io.LimitReader(r.Body,limit+1))
+		if nil != e {
+			return e
+		} else if limit < int64(len(body)) {
+			return ErrorBodySize
+		} else {
+			var b *bytes.Reader = bytes.NewReader(body)
+
+			e = cbor.NewDecoder(b).Decode(v)
+			if nil != e {
+				return fmt.Errorf("%w: %w",ErrorBodyContent,e)
+			} else if 0 != b.Len() {
+				return ErrorBodyContent
+			} else {
+				return nil
+			}
+		}
+	}
+}
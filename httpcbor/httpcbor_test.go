@@ -0,0 +1,93 @@
+/*
+ * CBOR HTTP Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package httpcbor
+
+import (
+	"bytes"
+	"errors"
+	"github.com/syntelos/go-cbor"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const TestStringDatum string = "hello, world."
+
+func TestAccepts(t *testing.T){
+	var r *http.Request = httptest.NewRequest("GET","/",nil)
+	if !Accepts(r) {
+		t.Error("Expected acceptance of absent Accept header.")
+	}
+	r.Header.Set("Accept","text/html, application/cbor;q=0.5")
+	if !Accepts(r) {
+		t.Error("Expected acceptance of application/cbor.")
+	}
+	for _, rejected := range []string{"0","0.0","0.000","x"} {
+		r.Header.Set("Accept","text/html, application/cbor;q="+rejected)
+		if Accepts(r) {
+			t.Errorf("Expected rejection of application/cbor;q=%s.",rejected)
+		}
+	}
+	r.Header.Set("Accept","application/cbor;q=0.001")
+	if !Accepts(r) {
+		t.Error("Expected acceptance of application/cbor;q=0.001.")
+	}
+}
+
+func TestRequestResponse(t *testing.T){
+	var handler http.HandlerFunc = func(w http.ResponseWriter, r *http.Request){
+		var text string
+		var e error = DecodeRequest(r,&text)
+		if nil != e {
+			http.Error(w,e.Error(),http.StatusBadRequest)
+		} else {
+			EncodeResponse(w,text)
+		}
+	}
+	var body []byte = cbor.Encode(TestStringDatum)
+
+	var r *http.Request = httptest.NewRequest("POST","/",bytes.NewReader(body))
+	r.Header.Set("Content-Type",ContentType)
+	var w *httptest.ResponseRecorder = httptest.NewRecorder()
+	handler(w,r)
+
+	if http.StatusOK != w.Code {
+		t.Fatalf("Expected status (200), found (%d) '%s'.",w.Code,w.Body.String())
+	} else if ContentType != w.Header().Get("Content-Type") {
+		t.Errorf("Expected Content-Type '%s', found '%s'.",ContentType,w.Header().Get("Content-Type"))
+	} else if TestStringDatum != cbor.Object(w.Body.Bytes()).MustText() {
+		t.Errorf("Expected '%s', found '%s'.",TestStringDatum,cbor.Object(w.Body.Bytes()).MustText())
+	}
+}
+
+func TestDecodeRequestErrors(t *testing.T){
+	var text string
+	var body []byte = cbor.Encode(TestStringDatum)
+
+	var r *http.Request = httptest.NewRequest("POST","/",bytes.NewReader(body))
+	r.Header.Set("Content-Type","application/json")
+	var e error = DecodeRequest(r,&text)
+	if !errors.Is(e,ErrorContentType) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorContentType,e)
+	}
+
+	r = httptest.NewRequest("POST","/",bytes.NewReader(append(body,body...)))
+	r.Header.Set("Content-Type",ContentType)
+	e = DecodeRequest(r,&text)
+	if !errors.Is(e,ErrorBodyContent) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorBodyContent,e)
+	}
+
+	var max int64 = BodySizeMax
+	defer func(){ BodySizeMax = max }()
+	BodySizeMax = 4
+
+	r = httptest.NewRequest("POST","/",bytes.NewReader(body))
+	r.Header.Set("Content-Type",ContentType)
+	e = DecodeRequest(r,&text)
+	if !errors.Is(e,ErrorBodySize) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorBodySize,e)
+	}
+}
@@ -0,0 +1,331 @@
+/*
+ * SenML CBOR Representation
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc8428#section-6
+ * https://tools.ietf.org/html/rfc8428#section-4.6
+ */
+package senml
+
+import (
+	"errors"
+	"fmt"
+	"github.com/syntelos/go-cbor"
+	"strings"
+	"time"
+)
+/*
+ * CoAP Content-Format of "application/senml+cbor".
+ */
+const ContentFormat uint16 = 112
+/*
+ * Media type of SenML CBOR content.
+ */
+const ContentType string = "application/senml+cbor"
+/*
+ * SenML CBOR labels.  See Table 6 [RFC8428].
+ */
+const LabelBaseVersion int = -1
+const LabelBaseName int = -2
+const LabelBaseTime int = -3
+const LabelBaseUnit int = -4
+const LabelBaseValue int = -5
+const LabelBaseSum int = -6
+const LabelName int = 0
+const LabelUnit int = 1
+const LabelValue int = 2
+const LabelStringValue int = 3
+const LabelBoolValue int = 4
+const LabelSum int = 5
+const LabelTime int = 6
+const LabelUpdateTime int = 7
+const LabelDataValue int = 8
+/*
+ * Times less than (2^28) are relative to the current time.
+ * See Section 4.5.3 [RFC8428].
+ */
+const TimeRelative float64 = (1 << 28)
+/*
+ * Validation errors produced by decoding and resolution.
+ */
+var ErrorRecord error = errors.New("SenML record is not a map")
+var ErrorMustUnderstand error = errors.New("SenML label must be understood")
+var ErrorName error = errors.New("SenML resolved name invalid")
+var ErrorValue error = errors.New("SenML record has more than one value")
+/*
+ * SenML record.  Base fields apply to this record and to those
+ * following it in a pack, until redefined.
+ */
+type Record struct {
+
+	BaseVersion uint64
+
+	BaseName string
+
+	BaseTime float64
+
+	BaseUnit string
+
+	BaseValue float64
+
+	BaseSum float64
+
+	Name string
+
+	Unit string
+
+	Value *float64
+
+	StringValue *string
+
+	BoolValue *bool
+
+	DataValue []byte
+
+	Sum *float64
+
+	Time float64
+
+	UpdateTime float64
+}
+/*
+ * SenML pack, encoded as an array of records.
+ */
+type Pack []Record
+/*
+ * Encode record as a map with numeric labels, omitting absent
+ * and zero fields.
+ */
+func (this Record) MarshalCBOR() ([]byte, error) {
+	var entries cbor.OrderedMap
+	var add = func(label int, value any) {
+		entries = append(entries,cbor.MapEntry{Key: label, Value: value})
+	}
+	if 0 != this.BaseVersion {
+		add(LabelBaseVersion,this.BaseVersion)
+	}
+	if "" != this.BaseName {
+		add(LabelBaseName,this.BaseName)
+	}
+	if 0 != this.BaseTime {
+		add(LabelBaseTime,this.BaseTime)
+	}
+	if "" != this.BaseUnit {
+		add(LabelBaseUnit,this.BaseUnit)
+	}
+	if 0 != this.BaseValue {
+		add(LabelBaseValue,this.BaseValue)
+	}
+	if 0 != this.BaseSum {
+		add(LabelBaseSum,this.BaseSum)
+	}
+	if "" != this.Name {
+		add(LabelName,this.Name)
+	}
+	if "" != this.Unit {
+		add(LabelUnit,this.Unit)
+	}
+	if nil != this.Value {
+		add(LabelValue,*this.Value)
+	}
+	if nil != this.StringValue {
+		add(LabelStringValue,*this.StringValue)
+	}
+	if nil != this.BoolValue {
+		add(LabelBoolValue,*this.BoolValue)
+	}
+	if nil != this.Sum {
+		add(LabelSum,*this.Sum)
+	}
+	if 0 != this.Time {
+		add(LabelTime,this.Time)
+	}
+	if 0 != this.UpdateTime {
+		add(LabelUpdateTime,this.UpdateTime)
+	}
+	if nil != this.DataValue {
+		add(LabelDataValue,this.DataValue)
+	}
+	return cbor.EncOptions{}.Encode(entries)
+}
+/*
+ * Decode record from a map with numeric labels.  Unknown
+ * labels are ignored, except for text labels ending with "_"
+ * which must be understood.
+ */
+func (this *Record) UnmarshalCBOR(code []byte) (e error) {
+	var entries map[any]cbor.RawMessage
+	e = cbor.Unmarshal(code,&entries)
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorRecord,e)
+	}
+	*this = Record{}
+	for key, value := range entries {
+		var label int
+		switch k := key.(type) {
+		case uint8:
+			label = int(k)
+		case int:
+			label = k
+		case string:
+			if strings.HasSuffix(k,"_") {
+				return fmt.Errorf("%w: %s",ErrorMustUnderstand,k)
+			} else {
+				continue
+			}
+		default:
+			continue
+		}
+		var target any
+		switch label {
+		case LabelBaseVersion:
+			target = &this.BaseVersion
+		case LabelBaseName:
+			target = &this.BaseName
+		case LabelBaseTime:
+			target = &this.BaseTime
+		case LabelBaseUnit:
+			target = &this.BaseUnit
+		case LabelBaseValue:
+			target = &this.BaseValue
+		case LabelBaseSum:
+			target = &this.BaseSum
+		case LabelName:
+			target = &this.Name
+		case LabelUnit:
+			target = &this.Unit
+		case LabelValue:
+			target = &this.Value
+		case LabelStringValue:
+			target = &this.StringValue
+		case LabelBoolValue:
+			target = &this.BoolValue
+		case LabelSum:
+			target = &this.Sum
+		case LabelTime:
+			target = &this.Time
+		case LabelUpdateTime:
+			target = &this.UpdateTime
+		case LabelDataValue:
+			target = &this.DataValue
+		default:
+			continue
+		}
+		e = cbor.Unmarshal(value,target)
+		if nil != e {
+			return e
+		}
+	}
+	return nil
+}
+/*
+ * Encode pack.
+ */
+func (this Pack) Encode() (cbor.Object) {
+	return cbor.Encode([]Record(this))
+}
+/*
+ * Decode pack.
+ */
+func Decode(code cbor.Object) (pack Pack, e error) {
+	var records []Record
+	e = cbor.Unmarshal(code,&records)
+	if nil != e {
+		return nil, e
+	} else {
+		return Pack(records), nil
+	}
+}
+/*
+ * Resolve the records of the pack, applying base fields to
+ * each record and removing them, and converting relative times
+ * to absolute with respect to "now".  See Section 4.6
+ * [RFC8428].
+ */
+func (this Pack) Resolve(now time.Time) (list []Record, e error) {
+	var base Record
+	var epoch float64 = float64(now.UnixNano())/1e9
+	for _, record := range this {
+		if 0 != record.BaseVersion {
+			base.BaseVersion = record.BaseVersion
+		}
+		if "" != record.BaseName {
+			base.BaseName = record.BaseName
+		}
+		if 0 != record.BaseTime {
+			base.BaseTime = record.BaseTime
+		}
+		if "" != record.BaseUnit {
+			base.BaseUnit = record.BaseUnit
+		}
+		if 0 != record.BaseValue {
+			base.BaseValue = record.BaseValue
+		}
+		if 0 != record.BaseSum {
+			base.BaseSum = record.BaseSum
+		}
+		var resolved Record = Record{
+			BaseVersion: base.BaseVersion,
+			Name: base.BaseName+record.Name,
+			Unit: record.Unit,
+			StringValue: record.StringValue,
+			BoolValue: record.BoolValue,
+			DataValue: record.DataValue,
+			Time: base.BaseTime+record.Time,
+			UpdateTime: record.UpdateTime,
+		}
+		if "" == resolved.Unit {
+			resolved.Unit = base.BaseUnit
+		}
+		if nil != record.Value {
+			var value float64 = base.BaseValue+*record.Value
+			resolved.Value = &value
+		}
+		if nil != record.Sum {
+			var sum float64 = base.BaseSum+*record.Sum
+			resolved.Sum = &sum
+		}
+		if resolved.Time < TimeRelative {
+			resolved.Time += epoch
+		}
+		if !validName(resolved.Name) {
+			return nil, fmt.Errorf("%w: %q",ErrorName,resolved.Name)
+
+		} else if 1 < count(resolved.Value != nil, resolved.StringValue != nil, resolved.BoolValue != nil, resolved.DataValue != nil) {
+			return nil, fmt.Errorf("%w: %q",ErrorValue,resolved.Name)
+		} else {
+			list = append(list,resolved)
+		}
+	}
+	return list, nil
+}
+/*
+ * Validate resolved name.  See Section 4.5.1 [RFC8428].
+ */
+func validName(name string) (bool) {
+	if 0 == len(name) {
+		return false
+	}
+	for n, c := range name {
+		var alnum bool = ('a' <= c && 'z' >= c) || ('A' <= c && 'Z' >= c) || ('0' <= c && '9' >= c)
+		if 0 == n && !alnum {
+			return false
+		} else if !alnum && '-' != c && ':' != c && '.' != c && '/' != c && '_' != c {
+			return false
+		}
+	}
+	return true
+}
+/*
+ */
+func count(list ...bool) (n int) {
+	for _, b := range list {
+		if b {
+			n += 1
+		}
+	}
+	return n
+}
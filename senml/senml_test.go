@@ -0,0 +1,62 @@
+/*
+ * SenML CBOR Representation Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package senml
+
+import (
+	"bytes"
+	"errors"
+	"github.com/syntelos/go-cbor"
+	"testing"
+	"time"
+)
+
+func TestPack(t *testing.T){
+	var v1, v2 float64 = 23.1, 23.5
+	var pack Pack = Pack{
+		{BaseName: "urn:dev:ow:10e2073a0108006:", BaseTime: 1.276020076001e+09, BaseUnit: "Cel", Name: "temperature", Value: &v1},
+		{Name: "temperature", Time: 5, Value: &v2},
+	}
+	var code cbor.Object = pack.Encode()
+
+	if 0x82 != code[0] || 0xA5 != code[1] || 0x21 != code[2] {
+		t.Errorf("Expected '82A521...', found '%X'.",[]byte(code[0:3]))
+	}
+
+	var check, e = Decode(code)
+	if nil != e {
+		t.Fatal(e)
+	} else if 2 != len(check) || "urn:dev:ow:10e2073a0108006:" != check[0].BaseName || nil == check[1].Value || 23.5 != *check[1].Value {
+		t.Errorf("Expected '%v', found '%v'.",pack,check)
+	}
+
+	var list []Record
+	list, e = check.Resolve(time.Now())
+	if nil != e {
+		t.Fatal(e)
+	} else if "urn:dev:ow:10e2073a0108006:temperature" != list[1].Name || "Cel" != list[1].Unit || 1.276020081001e+09 != list[1].Time {
+		t.Errorf("Expected resolved record, found '%v'.",list[1])
+	}
+}
+
+func TestRecordLabels(t *testing.T){
+	var code []byte = []byte{0x81,0xA2,0x00,0x61,'x',0x62,'z','_',0x01}
+	var _, e = Decode(code)
+	if !errors.Is(e,ErrorMustUnderstand) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorMustUnderstand,e)
+	}
+
+	var b bool = true
+	var text string = "on"
+	_, e = Pack{{Name: "x", BoolValue: &b, StringValue: &text}}.Resolve(time.Now())
+	if !errors.Is(e,ErrorValue) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorValue,e)
+	}
+
+	var record Record = Record{Name: "x", DataValue: []byte{1}}
+	var encoded, _ = record.MarshalCBOR()
+	if !bytes.Equal([]byte{0xA2,0x00,0x61,'x',0x08,0x41,0x01},encoded) {
+		t.Errorf("Expected 'A2006178084101', found '%X'.",encoded)
+	}
+}
@@ -0,0 +1,609 @@
+/*
+ * SUIT Manifest CBOR Representation
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://datatracker.ietf.org/doc/draft-ietf-suit-manifest/
+ * https://tools.ietf.org/html/rfc9124
+ */
+package suit
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"errors"
+	"fmt"
+	"github.com/syntelos/go-cbor"
+)
+/*
+ * Tag numbers of "SUIT_Envelope_Tagged" and
+ * "SUIT_Manifest_Tagged".
+ */
+const TagEnvelope uint64 = 107
+const TagManifest uint64 = 1070
+/*
+ * Envelope member labels.
+ */
+const LabelAuthentication int64 = 2
+const LabelManifest int64 = 3
+/*
+ * Manifest member labels.  The labels of the severable members
+ * "payload-fetch", "install" and "text" are also envelope member
+ * labels, when severed.
+ */
+const LabelVersion int64 = 1
+const LabelSequenceNumber int64 = 2
+const LabelCommon int64 = 3
+const LabelReferenceURI int64 = 4
+const LabelValidate int64 = 7
+const LabelLoad int64 = 8
+const LabelInvoke int64 = 9
+const LabelPayloadFetch int64 = 16
+const LabelInstall int64 = 20
+const LabelText int64 = 23
+/*
+ * Common member labels.
+ */
+const LabelComponents int64 = 2
+const LabelSharedSequence int64 = 4
+/*
+ * Condition codes of commands.
+ */
+const ConditionVendorIdentifier int64 = 1
+const ConditionClassIdentifier int64 = 2
+const ConditionImageMatch int64 = 3
+const ConditionComponentSlot int64 = 5
+const ConditionCheckContent int64 = 6
+const ConditionAbort int64 = 14
+const ConditionDeviceIdentifier int64 = 24
+/*
+ * Directive codes of commands.
+ */
+const DirectiveProcessDependency int64 = 11
+const DirectiveSetComponentIndex int64 = 12
+const DirectiveTryEach int64 = 15
+const DirectiveWrite int64 = 18
+const DirectiveSetParameters int64 = 19
+const DirectiveOverrideParameters int64 = 20
+const DirectiveFetch int64 = 21
+const DirectiveCopy int64 = 22
+const DirectiveInvoke int64 = 23
+const DirectiveSwap int64 = 31
+const DirectiveRunSequence int64 = 32
+/*
+ * Parameter labels of the directives "set-parameters" and
+ * "override-parameters".
+ */
+const ParameterVendorIdentifier int64 = 1
+const ParameterClassIdentifier int64 = 2
+const ParameterImageDigest int64 = 3
+const ParameterComponentSlot int64 = 5
+const ParameterStrictOrder int64 = 12
+const ParameterSoftFailure int64 = 13
+const ParameterImageSize int64 = 14
+const ParameterContent int64 = 18
+const ParameterURI int64 = 21
+const ParameterSourceComponent int64 = 22
+const ParameterInvokeArgs int64 = 23
+const ParameterDeviceIdentifier int64 = 24
+/*
+ * COSE algorithm identifiers of "SUIT_Digest".  See Table 5
+ * [RFC9054].
+ */
+const DigestSHA256 int64 = -16
+const DigestSHA384 int64 = -43
+const DigestSHA512 int64 = -44
+/*
+ * Validation errors produced by decoding and verification.
+ */
+var ErrorEnvelope error = errors.New("SUIT envelope malformed")
+var ErrorManifest error = errors.New("SUIT manifest malformed")
+var ErrorCommand error = errors.New("SUIT command sequence malformed")
+var ErrorDigest error = errors.New("SUIT digest unsupported or mismatched")
+/*
+ * Digest "[algorithm, bytes]" of a manifest, image or severed
+ * member.
+ */
+type Digest struct {
+
+	Algorithm int64
+
+	Bytes []byte
+}
+/*
+ * Component identifier, as the list of its byte strings.
+ */
+type ComponentID [][]byte
+/*
+ * Command of a sequence, being a condition or directive with
+ * its argument.  The argument of "set-parameters" and
+ * "override-parameters" is resolved into "Parameters", and
+ * those of "try-each" and "run-sequence" into "Sequences".
+ */
+type Command struct {
+
+	Code int64
+
+	Argument cbor.Object
+
+	Parameters map[int64]cbor.Object
+
+	Sequences []Sequence
+}
+/*
+ * Sequence of commands.
+ */
+type Sequence []Command
+/*
+ * Components and shared sequence of a manifest.
+ */
+type Common struct {
+
+	Components []ComponentID
+
+	Shared Sequence
+}
+/*
+ * Manifest.  The command sequences present in the manifest are
+ * in "Sequences" by member label, and the severed members are
+ * in "Severed" by the digests of their envelope members.  Other
+ * members are retained in "Extra".
+ */
+type Manifest struct {
+
+	Version uint64
+
+	SequenceNumber uint64
+
+	Common Common
+
+	ReferenceURI string
+
+	Sequences map[int64]Sequence
+
+	Severed map[int64]Digest
+
+	Extra map[int64]cbor.Object
+}
+/*
+ * Envelope.  The "Code" of the manifest is the encoded manifest
+ * of the authentication digest, and "Members" has the severed
+ * members and other members of the envelope.  Integrated
+ * payloads are in "Payloads" by name.
+ */
+type Envelope struct {
+
+	Digest Digest
+
+	Authentication []cbor.Object
+
+	Manifest Manifest
+
+	Code cbor.Object
+
+	Members map[int64]cbor.Object
+
+	Payloads map[string][]byte
+}
+/*
+ * Decode the envelope, optionally tagged, with its manifest.
+ * Neither digest nor authentication is verified, see
+ * <Envelope#Verify>.
+ */
+func Decode(code cbor.Object) (envelope Envelope, e error) {
+	code, e = untag(code,TagEnvelope)
+	if nil != e {
+		return envelope, fmt.Errorf("%w: %w",ErrorEnvelope,e)
+	}
+	var entries [][2]cbor.Object
+	entries, e = code.Entries()
+	if nil != e {
+		return envelope, fmt.Errorf("%w: %w",ErrorEnvelope,e)
+	}
+	envelope.Members = map[int64]cbor.Object{}
+	envelope.Payloads = map[string][]byte{}
+	for _, entry := range entries {
+		if cbor.MajorText == entry[0].Major() {
+			var name string
+			name, e = entry[0].Text()
+			if nil == e {
+				envelope.Payloads[name], e = entry[1].Bytes()
+			}
+			if nil != e {
+				return envelope, fmt.Errorf("%w: %w",ErrorEnvelope,e)
+			} else {
+				continue
+			}
+		}
+		var label int64
+		label, e = entry[0].Int()
+		if nil != e {
+			return envelope, fmt.Errorf("%w: %w",ErrorEnvelope,e)
+		}
+		switch label {
+		case LabelAuthentication:
+			e = envelope.authentication(entry[1])
+		case LabelManifest:
+			envelope.Code, e = entry[1].Bytes()
+			if nil == e {
+				envelope.Manifest, e = DecodeManifest(envelope.Code)
+			}
+		default:
+			envelope.Members[label] = entry[1]
+		}
+		if nil != e {
+			return envelope, e
+		}
+	}
+	if nil == envelope.Code {
+		return envelope, fmt.Errorf("%w: manifest",ErrorEnvelope)
+	} else {
+		return envelope, nil
+	}
+}
+/*
+ * Decode the authentication wrapper, "[digest, *block]".
+ */
+func (this *Envelope) authentication(wrapper cbor.Object) (e error) {
+	var content []byte
+	content, e = wrapper.Bytes()
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorEnvelope,e)
+	}
+	var list []cbor.Object
+	list, e = cbor.Object(content).Items()
+	if nil != e {
+		return fmt.Errorf("%w: %w",ErrorEnvelope,e)
+	} else if 0 == len(list) {
+		return fmt.Errorf("%w: authentication",ErrorEnvelope)
+	}
+	this.Digest, e = wrappedDigest(list[0])
+	if nil != e {
+		return e
+	}
+	for _, block := range list[1:] {
+		content, e = block.Bytes()
+		if nil != e {
+			return fmt.Errorf("%w: %w",ErrorEnvelope,e)
+		} else {
+			this.Authentication = append(this.Authentication,cbor.Object(content))
+		}
+	}
+	return nil
+}
+/*
+ * Verify the digest of the encoded manifest, and the digests
+ * of the severed members of the manifest present in the
+ * envelope.  The authentication blocks, as "COSE_Sign1" over
+ * the digest, are verified by the user, as by
+ * <cbor.SigStructure>.
+ */
+func (this Envelope) Verify() (e error) {
+	e = this.Digest.Check(this.Code)
+	if nil != e {
+		return e
+	}
+	for label, digest := range this.Manifest.Severed {
+		var member, ok = this.Members[label]
+		if ok {
+			var content []byte
+			content, e = member.Bytes()
+			if nil == e {
+				e = digest.Check(content)
+			}
+			if nil != e {
+				return fmt.Errorf("%w: member %d",ErrorDigest,label)
+			}
+		}
+	}
+	return nil
+}
+/*
+ * Resolve the command sequence of the manifest by member label,
+ * as embedded in the manifest, or severed into the envelope.
+ */
+func (this Envelope) Sequence(label int64) (Sequence, error) {
+	var sequence, ok = this.Manifest.Sequences[label]
+	if ok {
+		return sequence, nil
+	}
+	var member cbor.Object
+	member, ok = this.Members[label]
+	if !ok {
+		return nil, nil
+	}
+	var content, e = member.Bytes()
+	if nil != e {
+		return nil, fmt.Errorf("%w: %w",ErrorCommand,e)
+	}
+	var digest Digest
+	digest, ok = this.Manifest.Severed[label]
+	if ok {
+		e = digest.Check(content)
+		if nil != e {
+			return nil, e
+		}
+	}
+	return DecodeSequence(content)
+}
+/*
+ * Decode the manifest, optionally tagged.
+ */
+func DecodeManifest(code cbor.Object) (manifest Manifest, e error) {
+	code, e = untag(code,TagManifest)
+	if nil != e {
+		return manifest, fmt.Errorf("%w: %w",ErrorManifest,e)
+	}
+	var entries [][2]cbor.Object
+	entries, e = code.Entries()
+	if nil != e {
+		return manifest, fmt.Errorf("%w: %w",ErrorManifest,e)
+	}
+	manifest.Sequences = map[int64]Sequence{}
+	manifest.Severed = map[int64]Digest{}
+	manifest.Extra = map[int64]cbor.Object{}
+	for _, entry := range entries {
+		var label int64
+		label, e = entry[0].Int()
+		if nil != e {
+			return manifest, fmt.Errorf("%w: %w",ErrorManifest,e)
+		}
+		var value cbor.Object = entry[1]
+		switch label {
+		case LabelVersion:
+			manifest.Version, e = value.Uint()
+		case LabelSequenceNumber:
+			manifest.SequenceNumber, e = value.Uint()
+		case LabelReferenceURI:
+			manifest.ReferenceURI, e = value.Text()
+		case LabelCommon:
+			var content []byte
+			content, e = value.Bytes()
+			if nil == e {
+				manifest.Common, e = decodeCommon(content)
+			}
+		case LabelValidate, LabelLoad, LabelInvoke, LabelPayloadFetch, LabelInstall:
+			if cbor.MajorArray == value.Major() {
+				manifest.Severed[label], e = decodeDigest(value)
+			} else {
+				var content []byte
+				content, e = value.Bytes()
+				if nil == e {
+					manifest.Sequences[label], e = DecodeSequence(content)
+				}
+			}
+		case LabelText:
+			if cbor.MajorArray == value.Major() {
+				manifest.Severed[label], e = decodeDigest(value)
+			} else {
+				manifest.Extra[label] = value
+			}
+		default:
+			manifest.Extra[label] = value
+		}
+		if nil != e {
+			return manifest, fmt.Errorf("%w: %d: %w",ErrorManifest,label,e)
+		}
+	}
+	if 1 != manifest.Version {
+		return manifest, fmt.Errorf("%w: version %d",ErrorManifest,manifest.Version)
+	} else {
+		return manifest, nil
+	}
+}
+/*
+ * Decode the common member of a manifest.
+ */
+func decodeCommon(code cbor.Object) (common Common, e error) {
+	var entries [][2]cbor.Object
+	entries, e = code.Entries()
+	if nil != e {
+		return common, e
+	}
+	for _, entry := range entries {
+		var label int64
+		label, e = entry[0].Int()
+		if nil != e {
+			return common, e
+		}
+		switch label {
+		case LabelComponents:
+			e = cbor.Unmarshal(entry[1],&common.Components)
+		case LabelSharedSequence:
+			var content []byte
+			content, e = entry[1].Bytes()
+			if nil == e {
+				common.Shared, e = DecodeSequence(content)
+			}
+		}
+		if nil != e {
+			return common, e
+		}
+	}
+	return common, nil
+}
+/*
+ * Decode the command sequence, the encoded array of commands
+ * and their arguments in pairs.
+ */
+func DecodeSequence(code cbor.Object) (sequence Sequence, e error) {
+	var list []cbor.Object
+	list, e = code.Items()
+	if nil != e {
+		return nil, fmt.Errorf("%w: %w",ErrorCommand,e)
+	} else if 0 == len(list) || 0 != (len(list) & 1) {
+		return nil, ErrorCommand
+	}
+	for n := 0; n < len(list); n += 2 {
+		var command Command = Command{Argument: list[n+1]}
+		command.Code, e = list[n].Int()
+		if nil != e {
+			return nil, fmt.Errorf("%w: %w",ErrorCommand,e)
+		}
+		switch command.Code {
+		case DirectiveSetParameters, DirectiveOverrideParameters:
+			command.Parameters, e = decodeParameters(command.Argument)
+		case DirectiveTryEach:
+			var options []cbor.Object
+			options, e = command.Argument.Items()
+			for _, option := range options {
+				if nil != e {
+					break
+				} else if option.IsNull() {
+					command.Sequences = append(command.Sequences,nil)
+				} else {
+					var content []byte
+					content, e = option.Bytes()
+					if nil == e {
+						var nested Sequence
+						nested, e = DecodeSequence(content)
+						command.Sequences = append(command.Sequences,nested)
+					}
+				}
+			}
+		case DirectiveRunSequence:
+			var content []byte
+			content, e = command.Argument.Bytes()
+			if nil == e {
+				var nested Sequence
+				nested, e = DecodeSequence(content)
+				command.Sequences = []Sequence{nested}
+			}
+		}
+		if nil != e {
+			return nil, fmt.Errorf("%w: %d: %w",ErrorCommand,command.Code,e)
+		} else {
+			sequence = append(sequence,command)
+		}
+	}
+	return sequence, nil
+}
+/*
+ * Decode the parameters map of a directive.
+ */
+func decodeParameters(code cbor.Object) (map[int64]cbor.Object, error) {
+	var entries, e = code.Entries()
+	if nil != e {
+		return nil, e
+	}
+	var parameters map[int64]cbor.Object = map[int64]cbor.Object{}
+	for _, entry := range entries {
+		var label int64
+		label, e = entry[0].Int()
+		if nil != e {
+			return nil, e
+		} else {
+			parameters[label] = entry[1]
+		}
+	}
+	return parameters, nil
+}
+/*
+ * Resolve the parameter of a "set-parameters" or
+ * "override-parameters" command.
+ */
+func (this Command) Parameter(label int64) (cbor.Object, bool) {
+	var value, ok = this.Parameters[label]
+	return value, ok
+}
+/*
+ * Resolve the image digest parameter of a command.
+ */
+func (this Command) ImageDigest() (Digest, error) {
+	var value, ok = this.Parameters[ParameterImageDigest]
+	if !ok {
+		return Digest{}, ErrorDigest
+	} else {
+		return wrappedDigest(value)
+	}
+}
+/*
+ * Visit the commands of the sequence in order, descending into
+ * the sequences of "try-each" and "run-sequence" commands after
+ * visiting the command.  Visiting stops at the first error.
+ */
+func (this Sequence) Walk(visit func(Command) error) (e error) {
+	for _, command := range this {
+		e = visit(command)
+		if nil != e {
+			return e
+		}
+		for _, nested := range command.Sequences {
+			e = nested.Walk(visit)
+			if nil != e {
+				return e
+			}
+		}
+	}
+	return nil
+}
+/*
+ * Verify the digest of content.
+ */
+func (this Digest) Check(content []byte) (error) {
+	var hash crypto.Hash
+	switch this.Algorithm {
+	case DigestSHA256:
+		hash = crypto.SHA256
+	case DigestSHA384:
+		hash = crypto.SHA384
+	case DigestSHA512:
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("%w: algorithm %d",ErrorDigest,this.Algorithm)
+	}
+	var h = hash.New()
+	h.Write(content)
+	if bytes.Equal(this.Bytes,h.Sum(nil)) {
+		return nil
+	} else {
+		return ErrorDigest
+	}
+}
+/*
+ * Decode digest "[algorithm, bytes]".
+ */
+func decodeDigest(code cbor.Object) (digest Digest, e error) {
+	var list []cbor.Object
+	list, e = code.Items()
+	if nil != e {
+		return digest, e
+	} else if 2 != len(list) {
+		return digest, ErrorDigest
+	}
+	digest.Algorithm, e = list[0].Int()
+	if nil == e {
+		digest.Bytes, e = list[1].Bytes()
+	}
+	return digest, e
+}
+/*
+ * Decode digest wrapped in a byte string.
+ */
+func wrappedDigest(code cbor.Object) (Digest, error) {
+	var content, e = code.Bytes()
+	if nil != e {
+		return Digest{}, fmt.Errorf("%w: %w",ErrorDigest,e)
+	} else {
+		return decodeDigest(content)
+	}
+}
+/*
+ * Remove the optional tag from the data item.
+ */
+func untag(code cbor.Object, tag uint64) (cbor.Object, error) {
+	var major, _, arg, z, e = cbor.ParseHead(code)
+	if nil != e {
+		return nil, e
+	} else if cbor.MajorTagged != major {
+		return code, nil
+	} else if tag != arg {
+		return nil, fmt.Errorf("tag %d",arg)
+	} else {
+		return code[z:], nil
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * SUIT Manifest CBOR Representation Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package suit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"github.com/syntelos/go-cbor"
+	"testing"
+)
+/*
+ * Envelope of one component, having a shared sequence, an
+ * embedded validate sequence, and a severed install sequence.
+ */
+func envelope(image []byte) ([]byte, cbor.Object) {
+	var vendor []byte = []byte{0xFA, 0x6B, 0x4A, 0x53, 0xD5, 0xAD, 0x5F, 0xDF, 0xBE, 0x9D, 0xE6, 0x63, 0xE4, 0xD4, 0x1F, 0xFE}
+	var imageDigest [32]byte = sha256.Sum256(image)
+	var shared cbor.Object = cbor.Encode([]any{
+		DirectiveOverrideParameters, map[int64]any{
+			ParameterVendorIdentifier: vendor,
+			ParameterImageDigest: []byte(cbor.Encode([]any{DigestSHA256, imageDigest[:]})),
+			ParameterImageSize: len(image),
+		},
+		ConditionVendorIdentifier, 15,
+	})
+	var common cbor.Object = cbor.Encode(map[int64]any{
+		LabelComponents: [][][]byte{{{0x00}}},
+		LabelSharedSequence: []byte(shared),
+	})
+	var validate cbor.Object = cbor.Encode([]any{ConditionImageMatch, 15})
+	var install cbor.Object = cbor.Encode([]any{
+		DirectiveTryEach, []any{
+			[]byte(cbor.Encode([]any{DirectiveOverrideParameters, map[int64]any{ParameterURI: "http://example.com/file.bin"}})),
+			nil,
+		},
+		DirectiveFetch, 2,
+		ConditionImageMatch, 15,
+	})
+	var installDigest [32]byte = sha256.Sum256(install)
+	var manifest cbor.Object = cbor.Encode(map[int64]any{
+		LabelVersion: 1,
+		LabelSequenceNumber: 7,
+		LabelCommon: []byte(common),
+		LabelValidate: []byte(validate),
+		LabelInstall: []any{DigestSHA256, installDigest[:]},
+	})
+	var manifestDigest [32]byte = sha256.Sum256(manifest)
+	var auth cbor.Object = cbor.Encode([]any{[]byte(cbor.Encode([]any{DigestSHA256, manifestDigest[:]})), []byte{0xD2, 0x84, 0x40, 0xA0, 0xF6, 0x40}})
+
+	var code []byte = []byte{0xD8, 0x6B}
+	code = append(code,cbor.Encode(map[int64]any{
+		LabelAuthentication: []byte(auth),
+		LabelManifest: []byte(manifest),
+		LabelInstall: []byte(install),
+	})...)
+	return code, manifest
+}
+
+func TestEnvelope(t *testing.T){
+	var code, manifest = envelope([]byte("firmware image"))
+
+	var env, e = Decode(code)
+	if nil != e {
+		t.Fatal(e)
+	} else if e = env.Verify(); nil != e {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(manifest,env.Code) || 7 != env.Manifest.SequenceNumber || 1 != len(env.Authentication) {
+		t.Errorf("Expected manifest, found '%v'.",env.Manifest)
+	}
+	var common Common = env.Manifest.Common
+	if 1 != len(common.Components) || 2 != len(common.Shared) {
+		t.Errorf("Expected common, found '%v'.",common)
+	}
+	var digest Digest
+	digest, e = common.Shared[0].ImageDigest()
+	if nil != e {
+		t.Fatal(e)
+	} else if e = digest.Check([]byte("firmware image")); nil != e {
+		t.Errorf("Expected image digest, found '%v'.",e)
+	}
+	var size, ok = common.Shared[0].Parameter(ParameterImageSize)
+	if n, _ := size.Uint(); !ok || 14 != n {
+		t.Errorf("Expected image size '14', found '%v'.",size)
+	}
+
+	var validate, install Sequence
+	validate, e = env.Sequence(LabelValidate)
+	if nil != e {
+		t.Fatal(e)
+	} else if 1 != len(validate) || ConditionImageMatch != validate[0].Code {
+		t.Errorf("Expected validate sequence, found '%v'.",validate)
+	}
+	install, e = env.Sequence(LabelInstall)
+	if nil != e {
+		t.Fatal(e)
+	}
+	var codes []int64
+	e = install.Walk(func(c Command) error {
+		codes = append(codes,c.Code)
+		return nil
+	})
+	var expected []int64 = []int64{DirectiveTryEach, DirectiveOverrideParameters, DirectiveFetch, ConditionImageMatch}
+	if nil != e || len(expected) != len(codes) {
+		t.Errorf("Expected '%v', found '%v'.",expected,codes)
+	} else if uri, _ := install[0].Sequences[0][0].Parameter(ParameterURI); "http://example.com/file.bin" != uri.MustText() {
+		t.Errorf("Expected uri, found '%v'.",uri)
+	} else if 2 != len(install[0].Sequences) || nil != install[0].Sequences[1] {
+		t.Errorf("Expected try-each with empty option, found '%v'.",install[0].Sequences)
+	}
+	var invoke Sequence
+	invoke, e = env.Sequence(LabelInvoke)
+	if nil != e || nil != invoke {
+		t.Errorf("Expected no invoke sequence, found '%v' '%v'.",invoke,e)
+	}
+}
+
+func TestEnvelopeErrors(t *testing.T){
+	var code, _ = envelope(nil)
+	var env, e = Decode(code)
+	if nil != e {
+		t.Fatal(e)
+	}
+	env.Members[LabelInstall] = cbor.Encode([]byte(cbor.Encode([]any{DirectiveFetch, 2})))
+	e = env.Verify()
+	if !errors.Is(e,ErrorDigest) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorDigest,e)
+	}
+	_, e = env.Sequence(LabelInstall)
+	if !errors.Is(e,ErrorDigest) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorDigest,e)
+	}
+	env.Code = append(cbor.Object{},env.Code...)
+	env.Code[len(env.Code)-1] ^= 1
+	e = env.Verify()
+	if !errors.Is(e,ErrorDigest) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorDigest,e)
+	}
+
+	_, e = DecodeManifest(cbor.Encode(map[int64]any{LabelVersion: 2}))
+	if !errors.Is(e,ErrorManifest) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorManifest,e)
+	}
+	_, e = DecodeSequence(cbor.Encode([]any{DirectiveFetch}))
+	if !errors.Is(e,ErrorCommand) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorCommand,e)
+	}
+	_, e = Decode(cbor.Encode(map[int64]any{LabelAuthentication: []byte{0x80}}))
+	if !errors.Is(e,ErrorEnvelope) {
+		t.Errorf("Expected '%v', found '%v'.",ErrorEnvelope,e)
+	}
+}
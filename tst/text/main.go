@@ -14,5 +14,5 @@ func main(){
 
 	object.Encode("hello, world.")
 
-	fmt.Println(object.Text())
+	fmt.Println(object.MustText())
 }
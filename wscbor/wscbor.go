@@ -0,0 +1,304 @@
+/*
+ * CBOR WebSocket
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ *
+ *
+ * References
+ *
+ * https://tools.ietf.org/html/rfc6455#section-5
+ * https://tools.ietf.org/html/rfc8949
+ */
+package wscbor
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/syntelos/go-cbor"
+	"github.com/syntelos/go-endian"
+	"io"
+	"sync"
+)
+/*
+ * Value of "Sec-WebSocket-Protocol" selecting CBOR messages.
+ */
+const Subprotocol string = "cbor"
+/*
+ * Upper bound on message octet count accepted by <Conn#Read>.
+ */
+var MessageSizeMax uint64 = (1024*1024)
+/*
+ * Validation errors produced by <Conn>.
+ */
+var ErrorMessageSize error = fmt.Errorf("WebSocket CBOR message exceeds size maximum: %w",cbor.ErrorSizeExceeded)
+var ErrorMessageType error = errors.New("WebSocket CBOR message is not binary")
+var ErrorMessageContent error = errors.New("WebSocket CBOR message is not one data item")
+var ErrorFrame error = errors.New("WebSocket frame malformed")
+/*
+ * WebSocket frame opcodes.
+ */
+const opContinuation byte = 0x0
+const opText byte = 0x1
+const opBinary byte = 0x2
+const opClose byte = 0x8
+const opPing byte = 0x9
+const opPong byte = 0xA
+/*
+ * WebSocket message connection carrying one CBOR data item per
+ * binary message, following the opening handshake.  Client
+ * connections mask the frames they write, as required by
+ * Section 5.3 [RFC6455].
+ */
+type Conn struct {
+
+	rw io.ReadWriter
+
+	client bool
+	/*
+	 * Frames are written whole, by the caller and by <Conn#Read>
+	 * replying to pings and close, one at a time.
+	 */
+	lock sync.Mutex
+	/*
+	 * Close message written, after which no other is written.
+	 */
+	closed bool
+	/*
+	 * Called with the application data of each ping received,
+	 * after the pong reply is written.
+	 */
+	OnPing func([]byte)
+	/*
+	 * Called with the application data of each pong received,
+	 * for keepalive.
+	 */
+	OnPong func([]byte)
+}
+/*
+ * Construct the client side of a WebSocket connection.
+ */
+func NewClient(rw io.ReadWriter) (*Conn) {
+	return &Conn{rw: rw, client: true}
+}
+/*
+ * Construct the server side of a WebSocket connection.
+ */
+func NewServer(rw io.ReadWriter) (*Conn) {
+	return &Conn{rw: rw, client: false}
+}
+/*
+ * Write object as one binary message.
+ */
+func (this *Conn) Write(o cbor.Object) (error) {
+	return this.writeFrame(opBinary,o)
+}
+/*
+ * Write the encoding of value as one binary message.
+ */
+func (this *Conn) Encode(v any) (error) {
+	return this.Write(cbor.Encode(v))
+}
+/*
+ * Write keepalive ping with application data of at most 125
+ * octets.
+ */
+func (this *Conn) Ping(data []byte) (error) {
+	if 125 < len(data) {
+		return ErrorFrame
+	} else {
+		return this.writeFrame(opPing,data)
+	}
+}
+/*
+ * Write close message, once.
+ */
+func (this *Conn) Close() (error) {
+	return this.writeFrame(opClose,nil)
+}
+/*
+ * Read the next binary message as one data item, replying to
+ * pings and reporting pongs on the way.  A close message is
+ * replied to with its status code, unless this side closed
+ * first, and is "io.EOF".  See Section 5.5.1 [RFC6455].
+ */
+func (this *Conn) Read() (o cbor.Object, e error) {
+	var message []byte
+	var started bool
+	for {
+		var fin bool
+		var op byte
+		var payload []byte
+		fin, op, payload, e = this.readFrame()
+		if nil != e {
+			return nil, e
+		}
+		switch op {
+		case opPing:
+			e = this.writeFrame(opPong,payload)
+			if nil != e {
+				return nil, e
+			} else if nil != this.OnPing {
+				this.OnPing(payload)
+			}
+
+		case opPong:
+			if nil != this.OnPong {
+				this.OnPong(payload)
+			}
+
+		case opClose:
+			if 1 == len(payload) {
+				return nil, ErrorFrame
+			} else if 2 < len(payload) {
+				payload = payload[0:2]
+			}
+			e = this.writeFrame(opClose,payload)
+			if nil != e {
+				return nil, e
+			} else {
+				return nil, io.EOF
+			}
+
+		case opBinary, opContinuation:
+			if (opBinary == op) == started {
+				return nil, ErrorFrame
+			}
+			started = true
+			if uint64(len(message)+len(payload)) > MessageSizeMax {
+				return nil, ErrorMessageSize
+			}
+			message = append(message,payload...)
+			if fin {
+				return decodeMessage(message)
+			}
+
+		case opText:
+			return nil, ErrorMessageType
+
+		default:
+			return nil, ErrorFrame
+		}
+	}
+}
+/*
+ * Read the next binary message into the value referenced by
+ * pointer.
+ */
+func (this *Conn) Decode(v any) (error) {
+	var o, e = this.Read()
+	if nil != e {
+		return e
+	} else {
+		return cbor.Unmarshal(o,v)
+	}
+}
+/*
+ * Validate message content as exactly one data item, as by
+ * <cbor.Object#Valid>.
+ */
+func decodeMessage(message []byte) (cbor.Object, error) {
+	var o cbor.Object = cbor.Object(message)
+	var z, e = o.ItemLen()
+	if nil != e {
+		return nil, e
+	} else if z != len(message) {
+		return nil, ErrorMessageContent
+	} else {
+		return o, nil
+	}
+}
+/*
+ * Write one frame, and at most one close frame.
+ */
+func (this *Conn) writeFrame(op byte, payload []byte) (e error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if opClose == op {
+		if this.closed {
+			return nil
+		} else {
+			this.closed = true
+		}
+	}
+	var z int = len(payload)
+	var head []byte = []byte{0x80|op,0}
+	switch {
+	case 126 > z:
+		head[1] = byte(z)
+	case 0x10000 > z:
+		head[1] = 126
+		head = append(head,endian.BigEndian.EncodeUint16(uint16(z))...)
+	default:
+		head[1] = 127
+		head = append(head,endian.BigEndian.EncodeUint64(uint64(z))...)
+	}
+	var frame []byte
+	if this.client {
+		var mask []byte = make([]byte,4)
+		_, e = rand.Read(mask)
+		if nil != e {
+			return e
+		}
+		head[1] |= 0x80
+		frame = append(head,mask...)
+		for n, b := range payload {
+			frame = append(frame,b^mask[n%4])
+		}
+	} else {
+		frame = append(head,payload...)
+	}
+	_, e = this.rw.Write(frame)
+	return e
+}
+/*
+ * Read one frame.  Control frames have at most 125 octets of
+ * payload, and are not fragmented.  See Section 5.5 [RFC6455].
+ */
+func (this *Conn) readFrame() (fin bool, op byte, payload []byte, e error) {
+	var head []byte = make([]byte,2)
+	_, e = io.ReadFull(this.rw,head)
+	if nil != e {
+		return false, 0, nil, e
+	}
+	fin = (0 != (head[0] & 0x80))
+	op = (head[0] & 0x0F)
+	var masked bool = (0 != (head[1] & 0x80))
+	if 0 != (head[0] & 0x70) || masked == this.client {
+		return false, 0, nil, ErrorFrame
+	}
+	var z uint64 = uint64(head[1] & 0x7F)
+	switch z {
+	case 126:
+		var ext []byte = make([]byte,2)
+		_, e = io.ReadFull(this.rw,ext)
+		z = uint64(endian.BigEndian.DecodeUint16(ext))
+	case 127:
+		var ext []byte = make([]byte,8)
+		_, e = io.ReadFull(this.rw,ext)
+		z = endian.BigEndian.DecodeUint64(ext)
+	}
+	if nil != e {
+		return false, 0, nil, e
+	} else if 0 != (op & 0x08) && (!fin || 125 < z) {
+		return false, 0, nil, ErrorFrame
+	} else if z > MessageSizeMax {
+		return false, 0, nil, ErrorMessageSize
+	}
+	var mask []byte = make([]byte,4)
+	if masked {
+		_, e = io.ReadFull(this.rw,mask)
+		if nil != e {
+			return false, 0, nil, e
+		}
+	}
+	payload = make([]byte,z)
+	_, e = io.ReadFull(this.rw,payload)
+	if nil != e {
+		return false, 0, nil, e
+	} else if masked {
+		for n := range payload {
+			payload[n] ^= mask[n%4]
+		}
+	}
+	return fin, op, payload, nil
+}
@@ -0,0 +1,136 @@
+/*
+ * CBOR WebSocket Test
+ * Copyright 2023 John Douglas Pritchard, Syntelos
+ */
+package wscbor
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+type TypeTestPipe struct {
+
+	io.Reader
+
+	io.Writer
+}
+
+func TestConn(t *testing.T){
+	var up, down bytes.Buffer
+	var client *Conn = NewClient(TypeTestPipe{&down,&up})
+	var server *Conn = NewServer(TypeTestPipe{&up,&down})
+
+	var e error = client.Encode("hello")
+	if nil != e {
+		t.Fatal(e)
+	} else if 0 == (up.Bytes()[1] & 0x80) {
+		t.Errorf("Expected client frame masked.")
+	}
+	var text string
+	e = server.Decode(&text)
+	if nil != e {
+		t.Fatal(e)
+	} else if "hello" != text {
+		t.Errorf("Expected 'hello', found '%s'.",text)
+	}
+
+	e = server.Encode("reply")
+	if nil != e {
+		t.Fatal(e)
+	}
+	var pong []byte
+	client.OnPong = func(data []byte){ pong = data }
+
+	e = client.Ping([]byte("k"))
+	if nil != e {
+		t.Fatal(e)
+	}
+	e = client.Close()
+	if nil != e {
+		t.Fatal(e)
+	}
+	_, e = server.Read()
+	if io.EOF != e {
+		t.Fatalf("Expected '%v', found '%v'.",io.EOF,e)
+	}
+	e = client.Decode(&text)
+	if nil != e {
+		t.Fatal(e)
+	} else if "reply" != text {
+		t.Errorf("Expected 'reply', found '%s'.",text)
+	}
+	/*
+	 * Pong, and the close reply, to which the client, having
+	 * closed, does not reply.
+	 */
+	_, e = client.Read()
+	if io.EOF != e {
+		t.Errorf("Expected '%v', found '%v'.",io.EOF,e)
+	} else if "k" != string(pong) {
+		t.Errorf("Expected 'k', found '%s'.",pong)
+	} else if 0 != up.Len() {
+		t.Errorf("Expected no reply to close, found %d octets.",up.Len())
+	}
+}
+
+func TestConnControl(t *testing.T){
+	var up, down bytes.Buffer
+	var server *Conn = NewServer(TypeTestPipe{&up,&down})
+
+	var mask []byte = []byte{0,0,0,0}
+	up.Write(append([]byte{0x88,0x83},mask...))
+	up.Write([]byte{0x03,0xE8,'x'})
+	var _, e = server.Read()
+	if io.EOF != e {
+		t.Fatalf("Expected '%v', found '%v'.",io.EOF,e)
+	}
+	var expected []byte = []byte{0x88,0x02,0x03,0xE8}
+	if !bytes.Equal(expected,down.Bytes()) {
+		t.Errorf("Expected close reply '%X', found '%X'.",expected,down.Bytes())
+	}
+
+	for _, frame := range [][]byte{
+		append([]byte{0x09,0x80},mask...),
+		append([]byte{0x89,0xFE,0x00,0x7E},mask...),
+	} {
+		up.Reset()
+		up.Write(frame)
+		_, e = NewServer(TypeTestPipe{&up,io.Discard}).Read()
+		if ErrorFrame != e {
+			t.Errorf("Expected '%v', found '%v'.",ErrorFrame,e)
+		}
+	}
+}
+
+func TestConnFragmented(t *testing.T){
+	var up bytes.Buffer
+	var server *Conn = NewServer(TypeTestPipe{&up,io.Discard})
+
+	var mask []byte = []byte{0,0,0,0}
+	up.Write(append([]byte{0x02,0x82},mask...))
+	up.Write([]byte{0x62,'h'})
+	up.Write(append([]byte{0x80,0x81},mask...))
+	up.Write([]byte{'i'})
+
+	var o, e = server.Read()
+	if nil != e {
+		t.Fatal(e)
+	} else if "hi" != o.MustText() {
+		t.Errorf("Expected 'hi', found '%s'.",o.MustText())
+	}
+
+	up.Write(append([]byte{0x82,0x82},mask...))
+	up.Write([]byte{0x01,0x02})
+	_, e = server.Read()
+	if ErrorMessageContent != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorMessageContent,e)
+	}
+
+	up.Write([]byte{0x82,0x01,0x01})
+	_, e = server.Read()
+	if ErrorFrame != e {
+		t.Errorf("Expected '%v', found '%v'.",ErrorFrame,e)
+	}
+}